@@ -0,0 +1,52 @@
+package tly
+
+import (
+	"context"
+	"fmt"
+)
+
+// AbuseReason is why a short link is reported.
+type AbuseReason string
+
+// Abuse report reasons.
+const (
+	AbuseSpam     AbuseReason = "spam"
+	AbusePhishing AbuseReason = "phishing"
+	AbuseMalware  AbuseReason = "malware"
+	AbuseOther    AbuseReason = "other"
+)
+
+// Valid reports whether r is one of the known abuse reasons.
+func (r AbuseReason) Valid() bool {
+	switch r {
+	case AbuseSpam, AbusePhishing, AbuseMalware, AbuseOther:
+		return true
+	}
+	return false
+}
+
+// AbuseReport is a report filed against a short link for T.LY's trust and
+// safety review.
+type AbuseReport struct {
+	ID        int         `json:"id"`
+	ShortURL  string      `json:"short_url"`
+	Reason    AbuseReason `json:"reason"`
+	Status    string      `json:"status"`
+	CreatedAt Timestamp   `json:"created_at"`
+}
+
+// ReportLink flags shortURL as abusive, e.g. when a scanner finds that its
+// destination serves malware. The link may be on any domain, not only the
+// account's own.
+func (c *Client) ReportLink(ctx context.Context, shortURL string, reason AbuseReason) (*AbuseReport, error) {
+	if !reason.Valid() {
+		return nil, fmt.Errorf("tly: unknown abuse reason %q", reason)
+	}
+	body := map[string]string{"short_url": shortURL, "reason": string(reason)}
+	var report AbuseReport
+	err := c.doRequestContext(ctx, "POST", "/api/v1/link/report", "", body, &report)
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
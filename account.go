@@ -0,0 +1,73 @@
+package tly
+
+import "context"
+
+// Account is the profile of the account an API key belongs to.
+type Account struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Plan      string    `json:"plan"`
+	CreatedAt Timestamp `json:"created_at"`
+}
+
+// GetAccount retrieves the profile of the account the client's API key
+// belongs to, e.g. to check at startup that a key is for the expected
+// account.
+func (c *Client) GetAccount(ctx context.Context) (*Account, error) {
+	var account Account
+	err := c.doRequestContext(ctx, "GET", "/api/v1/account", "", nil, &account)
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// UsageCounter is the consumption of one quota. A Limit of zero means the
+// plan has no limit.
+type UsageCounter struct {
+	Used  int `json:"used"`
+	Limit int `json:"limit"`
+}
+
+// Remaining returns how much of the quota is left, or -1 when it is
+// unlimited.
+func (u UsageCounter) Remaining() int {
+	if u.Limit == 0 {
+		return -1
+	}
+	if u.Used >= u.Limit {
+		return 0
+	}
+	return u.Limit - u.Used
+}
+
+// Fraction returns the share of the quota used, or 0 when it is unlimited.
+func (u UsageCounter) Fraction() float64 {
+	if u.Limit == 0 {
+		return 0
+	}
+	return float64(u.Used) / float64(u.Limit)
+}
+
+// Usage is the plan's limits and the consumption in the current billing
+// period.
+type Usage struct {
+	Plan        string       `json:"plan"`
+	PeriodStart Timestamp    `json:"period_start"`
+	PeriodEnd   Timestamp    `json:"period_end"`
+	Links       UsageCounter `json:"links"`
+	APICalls    UsageCounter `json:"api_calls"`
+	Domains     UsageCounter `json:"domains"`
+}
+
+// GetUsage retrieves the plan limits and current consumption, so bulk jobs
+// can check they fit in the remaining quota before starting.
+func (c *Client) GetUsage(ctx context.Context) (*Usage, error) {
+	var usage Usage
+	err := c.doRequestContext(ctx, "GET", "/api/v1/account/usage", "", nil, &usage)
+	if err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
@@ -0,0 +1,142 @@
+package tly
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// AlertCondition reports whether the stats of a link should raise an alert.
+type AlertCondition func(stats *Stats, now time.Time) bool
+
+// ClicksPerDayAbove triggers when today's bucket of the daily series has
+// more than n clicks. Days are calendar dates of now, as in
+// DaysSinceLastClick; a series that does not reach today counts as no
+// clicks today, so stale data never triggers.
+func ClicksPerDayAbove(n int) AlertCondition {
+	return func(stats *Stats, now time.Time) bool {
+		today := now.Format(dateLayout)
+		clicks := 0
+		for _, d := range stats.DailySeries() {
+			if t, err := parseDate(d.Date); err == nil && t.Format(dateLayout) == today {
+				clicks += d.Total
+			}
+		}
+		return clicks > n
+	}
+}
+
+// NoClicksFor triggers when the link has had no clicks for at least days
+// days, including when the daily series has no clicks at all.
+func NoClicksFor(days int) AlertCondition {
+	return func(stats *Stats, now time.Time) bool {
+		since, ok := stats.DaysSinceLastClick(now)
+		return !ok || since >= days
+	}
+}
+
+// Alert describes a triggered alert rule.
+type Alert struct {
+	Rule     string
+	ShortURL string
+	Stats    *Stats
+	FiredAt  time.Time
+}
+
+// AlertRule pairs a condition on one link's stats with a callback.
+type AlertRule struct {
+	// Name identifies the rule in fired alerts.
+	Name     string
+	ShortURL string
+	// Stats narrows the stats fetched for the rule, e.g. to the last week.
+	Stats     StatsOptions
+	Condition AlertCondition
+	Callback  func(Alert)
+}
+
+// alertState tracks whether a rule is currently firing.
+type alertState struct {
+	rule   AlertRule
+	firing bool
+}
+
+// Alerts polls link stats and fires registered rules. A rule fires once
+// when its condition becomes true and again only after the condition has
+// cleared, so a sustained condition does not repeat the callback.
+type Alerts struct {
+	client   *Client
+	interval time.Duration
+
+	mu    sync.Mutex
+	rules []*alertState
+}
+
+// DefaultAlertInterval is the polling interval of NewAlerts when it is
+// given none.
+const DefaultAlertInterval = 5 * time.Minute
+
+// NewAlerts creates an alert poller that checks every interval. A zero or
+// negative interval uses DefaultAlertInterval.
+func NewAlerts(client *Client, interval time.Duration) *Alerts {
+	if interval <= 0 {
+		interval = DefaultAlertInterval
+	}
+	return &Alerts{client: client, interval: interval}
+}
+
+// Register adds a rule to the poller.
+func (a *Alerts) Register(rule AlertRule) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.rules = append(a.rules, &alertState{rule: rule})
+}
+
+// Check evaluates every rule once, firing callbacks for newly triggered
+// conditions. Rules whose stats cannot be fetched are skipped and the first
+// such error is returned after all rules are evaluated.
+func (a *Alerts) Check(ctx context.Context) error {
+	a.mu.Lock()
+	rules := append([]*alertState(nil), a.rules...)
+	a.mu.Unlock()
+
+	var firstErr error
+	for _, st := range rules {
+		stats, err := a.client.GetStatsWithOptions(ctx, st.rule.ShortURL, st.rule.Stats)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("alert %s: %w", st.rule.Name, err)
+			}
+			continue
+		}
+		now := time.Now()
+		triggered := st.rule.Condition(stats, now)
+
+		a.mu.Lock()
+		fire := triggered && !st.firing
+		st.firing = triggered
+		a.mu.Unlock()
+
+		if fire && st.rule.Callback != nil {
+			st.rule.Callback(Alert{Rule: st.rule.Name, ShortURL: st.rule.ShortURL, Stats: stats, FiredAt: now})
+		}
+	}
+	return firstErr
+}
+
+// Run checks the rules immediately and then every interval until ctx is
+// done. Check errors are ignored so a transient failure does not stop
+// polling.
+func (a *Alerts) Run(ctx context.Context) error {
+	a.Check(ctx)
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			a.Check(ctx)
+		}
+	}
+}
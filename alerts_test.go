@@ -0,0 +1,42 @@
+package tly
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestClicksPerDayAbove(t *testing.T) {
+	now := time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		days map[string]int
+		want bool
+	}{
+		{"today above", map[string]int{"2024-03-09": 1, "2024-03-10": 11}, true},
+		{"today at the threshold", map[string]int{"2024-03-10": 10}, false},
+		{"busy yesterday, quiet today", map[string]int{"2024-03-09": 50, "2024-03-10": 2}, false},
+		{"stale series", map[string]int{"2024-03-01": 50, "2024-03-02": 40}, false},
+		{"timestamped dates", map[string]int{"2024-03-10 00:00:00": 20}, true},
+		{"empty series", map[string]int{}, false},
+	}
+	for _, tt := range tests {
+		if got := ClicksPerDayAbove(10)(dailyStats(tt.days), now); got != tt.want {
+			t.Errorf("%s: ClicksPerDayAbove(10) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewAlertsInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		a := NewAlerts(NewClient("test-key"), interval)
+		if a.interval != DefaultAlertInterval {
+			t.Errorf("NewAlerts(%v) polls every %v, want %v", interval, a.interval, DefaultAlertInterval)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		if err := a.Run(ctx); err != context.DeadlineExceeded {
+			t.Errorf("Run = %v, want %v", err, context.DeadlineExceeded)
+		}
+		cancel()
+	}
+}
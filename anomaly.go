@@ -0,0 +1,92 @@
+package tly
+
+import "math"
+
+// AnomalyOptions configures DetectAnomalies.
+type AnomalyOptions struct {
+	// Threshold is the absolute z-score at which a day is flagged.
+	// Defaults to 3.
+	Threshold float64
+	// Window is the number of preceding days forming the baseline.
+	// Defaults to 28.
+	Window int
+	// Seasonal restricts the baseline to the same weekday within the
+	// window, so regular weekend dips are not flagged.
+	Seasonal bool
+	// MinHistory is the minimum number of baseline days needed before a
+	// day is evaluated. Defaults to 7, or 3 when Seasonal is set.
+	MinHistory int
+}
+
+// Anomaly is a day whose clicks deviate from its baseline.
+type Anomaly struct {
+	Date     string  `json:"date"`
+	Clicks   int     `json:"clicks"`
+	Expected float64 `json:"expected"`
+	ZScore   float64 `json:"z_score"`
+	// Spike is true for unusually high clicks and false for drops.
+	Spike bool `json:"spike"`
+}
+
+// DetectAnomalies flags days in the daily click series whose z-score against
+// a trailing baseline exceeds the threshold. Missing days count as zero.
+// The baseline standard deviation is floored at 1 so that flat, low-volume
+// series do not flag every single extra click.
+func DetectAnomalies(stats *Stats, opts AnomalyOptions) []Anomaly {
+	if opts.Threshold <= 0 {
+		opts.Threshold = 3
+	}
+	if opts.Window < 1 {
+		opts.Window = 28
+	}
+	if opts.MinHistory < 1 {
+		opts.MinHistory = 7
+		if opts.Seasonal {
+			opts.MinHistory = 3
+		}
+	}
+	series, err := stats.Chart(ChartOptions{Granularity: GranularityDay})
+	if err != nil {
+		return nil
+	}
+	var anomalies []Anomaly
+	for i, v := range series.Values {
+		var baseline []float64
+		for j := i - 1; j >= 0 && j >= i-opts.Window; j-- {
+			if opts.Seasonal && (i-j)%7 != 0 {
+				continue
+			}
+			baseline = append(baseline, series.Values[j])
+		}
+		if len(baseline) < opts.MinHistory {
+			continue
+		}
+		mean, std := meanStd(baseline)
+		if std < 1 {
+			std = 1
+		}
+		z := (v - mean) / std
+		if math.Abs(z) >= opts.Threshold {
+			anomalies = append(anomalies, Anomaly{
+				Date:     series.Labels[i],
+				Clicks:   int(v),
+				Expected: mean,
+				ZScore:   z,
+				Spike:    z > 0,
+			})
+		}
+	}
+	return anomalies
+}
+
+// meanStd returns the mean and population standard deviation of values.
+func meanStd(values []float64) (mean, std float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		std += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(std / float64(len(values)))
+}
@@ -0,0 +1,116 @@
+package tly
+
+import (
+	"testing"
+	"time"
+)
+
+// series returns consecutive daily clicks starting on 2024-01-01, a Monday.
+// Negative values leave the day out of the series.
+func series(clicks ...int) map[string]int {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	days := map[string]int{}
+	for i, c := range clicks {
+		if c >= 0 {
+			days[start.AddDate(0, 0, i).Format(dateLayout)] = c
+		}
+	}
+	return days
+}
+
+// repeat returns n copies of v.
+func repeat(v, n int) []int {
+	values := make([]int, n)
+	for i := range values {
+		values[i] = v
+	}
+	return values
+}
+
+// weeks returns n weeks of weekday clicks followed by weekend clicks.
+func weeks(n, weekday, weekend int) []int {
+	var values []int
+	for i := 0; i < n; i++ {
+		values = append(values, repeat(weekday, 5)...)
+		values = append(values, weekend, weekend)
+	}
+	return values
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	tests := []struct {
+		name   string
+		clicks []int
+		opts   AnomalyOptions
+		want   []Anomaly
+	}{
+		{
+			name:   "spike",
+			clicks: append(repeat(10, 14), 30),
+			want:   []Anomaly{{Date: "2024-01-15", Clicks: 30, Expected: 10, ZScore: 20, Spike: true}},
+		},
+		{
+			name:   "drop",
+			clicks: append(repeat(100, 14), 0),
+			want:   []Anomaly{{Date: "2024-01-15", Clicks: 0, Expected: 100, ZScore: -100}},
+		},
+		{
+			name:   "below threshold",
+			clicks: append(repeat(10, 14), 12),
+		},
+		{
+			name:   "custom threshold",
+			clicks: append(repeat(10, 14), 12),
+			opts:   AnomalyOptions{Threshold: 2},
+			want:   []Anomaly{{Date: "2024-01-15", Clicks: 12, Expected: 10, ZScore: 2, Spike: true}},
+		},
+		{
+			name:   "too little history",
+			clicks: append(repeat(10, 6), 30),
+		},
+		{
+			name:   "missing days count as zero",
+			clicks: append(repeat(10, 10), -1, 10),
+			want:   []Anomaly{{Date: "2024-01-11", Clicks: 0, Expected: 10, ZScore: -10}},
+		},
+		{
+			name:   "window limits the baseline",
+			clicks: append(append(repeat(1000, 7), repeat(10, 7)...), 30),
+			opts:   AnomalyOptions{Window: 7},
+			want: []Anomaly{
+				{Date: "2024-01-08", Clicks: 10, Expected: 1000, ZScore: -990},
+				{Date: "2024-01-15", Clicks: 30, Expected: 10, ZScore: 20, Spike: true},
+			},
+		},
+		{
+			name:   "weekend spike hidden without seasonality",
+			clicks: append(weeks(4, 100, 10), repeat(100, 6)...),
+		},
+		{
+			name:   "seasonal baseline flags a busy Saturday",
+			clicks: append(weeks(4, 100, 10), repeat(100, 6)...),
+			opts:   AnomalyOptions{Seasonal: true},
+			want:   []Anomaly{{Date: "2024-02-03", Clicks: 100, Expected: 10, ZScore: 90, Spike: true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectAnomalies(dailyStats(series(tt.clicks...)), tt.opts)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d anomalies %+v, want %d", len(got), got, len(tt.want))
+			}
+			for i, a := range got {
+				if a != tt.want[i] {
+					t.Errorf("anomaly %d = %+v, want %+v", i, a, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMeanStd(t *testing.T) {
+	mean, std := meanStd([]float64{2, 4, 4, 4, 5, 5, 7, 9})
+	if mean != 5 || std != 2 {
+		t.Errorf("meanStd = %v, %v; want 5, 2", mean, std)
+	}
+}
@@ -0,0 +1,111 @@
+package tly
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// AuditAction is what an audit log event records being done.
+type AuditAction string
+
+// Audit log actions.
+const (
+	AuditCreated AuditAction = "created"
+	AuditUpdated AuditAction = "updated"
+	AuditDeleted AuditAction = "deleted"
+)
+
+// AuditResource is the kind of object an audit log event is about.
+type AuditResource string
+
+// Audited resource kinds.
+const (
+	AuditLink  AuditResource = "link"
+	AuditTag   AuditResource = "tag"
+	AuditPixel AuditResource = "pixel"
+)
+
+// AuditActor is the user or API key behind an audit log event.
+type AuditActor struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// AuditEvent records one change made in the account.
+type AuditEvent struct {
+	ID       int           `json:"id"`
+	Action   AuditAction   `json:"action"`
+	Resource AuditResource `json:"resource_type"`
+	// ResourceID is the short URL of a link, or the ID of a tag or pixel.
+	ResourceID string     `json:"resource_id"`
+	Actor      AuditActor `json:"actor"`
+	IPAddress  string     `json:"ip_address"`
+	CreatedAt  Timestamp  `json:"created_at"`
+}
+
+// AuditLogOptions filters the audit log. Zero values are omitted from the
+// query.
+type AuditLogOptions struct {
+	Action    AuditAction
+	Resource  AuditResource
+	StartDate time.Time
+	EndDate   time.Time
+	Page      int
+	PerPage   int
+}
+
+// values encodes the options as query parameters.
+func (o AuditLogOptions) values() url.Values {
+	v := ListOptions{Page: o.Page, PerPage: o.PerPage}.values()
+	if o.Action != "" {
+		v.Set("action", string(o.Action))
+	}
+	if o.Resource != "" {
+		v.Set("resource_type", string(o.Resource))
+	}
+	if !o.StartDate.IsZero() {
+		v.Set("start_date", o.StartDate.Format(dateLayout))
+	}
+	if !o.EndDate.IsZero() {
+		v.Set("end_date", o.EndDate.Format(dateLayout))
+	}
+	return v
+}
+
+// AuditLogPage is a single page of the audit log, newest events first.
+type AuditLogPage struct {
+	PageInfo
+	Data []AuditEvent `json:"data"`
+}
+
+// ListAuditLogs retrieves one page of the account's audit log.
+func (c *Client) ListAuditLogs(ctx context.Context, opts AuditLogOptions) (*AuditLogPage, error) {
+	var page AuditLogPage
+	err := c.doRequestContext(ctx, "GET", "/api/v1/audit-log", opts.values().Encode(), nil, &page)
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// ListAllAuditLogs follows pagination from opts.Page (or the first page)
+// and returns every matching audit log event.
+func (c *Client) ListAllAuditLogs(ctx context.Context, opts AuditLogOptions) ([]AuditEvent, error) {
+	if opts.Page < 1 {
+		opts.Page = 1
+	}
+	var events []AuditEvent
+	for {
+		page, err := c.ListAuditLogs(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, page.Data...)
+		if len(page.Data) == 0 || !page.HasNext() {
+			return events, nil
+		}
+		opts.Page = page.CurrentPage + 1
+	}
+}
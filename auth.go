@@ -0,0 +1,26 @@
+package tly
+
+import "context"
+
+// TokenSource supplies the bearer token for each request. It lets a client
+// authenticate with credentials that change over time, such as refreshing
+// OAuth access tokens (see the oauth package), instead of a fixed APIKey.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// WithTokenSource makes the client ask ts for the bearer token of every
+// request instead of using APIKey.
+func WithTokenSource(ts TokenSource) ClientOption {
+	return func(c *Client) {
+		c.tokens = ts
+	}
+}
+
+// bearerToken returns the token for the next request.
+func (c *Client) bearerToken(ctx context.Context) (string, error) {
+	if c.tokens != nil {
+		return c.tokens.Token(ctx)
+	}
+	return c.APIKey, nil
+}
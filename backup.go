@@ -0,0 +1,329 @@
+package tly
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Kinds of BackupRecord.
+const (
+	BackupHeader = "header"
+	BackupTag    = "tag"
+	BackupPixel  = "pixel"
+	BackupLink   = "link"
+)
+
+// backupVersion is the version of the backup format written by
+// ExportAccount.
+const backupVersion = 1
+
+// BackupRecord is one line of an account backup. Kind says which field is
+// set; the header, always the first line, carries Version and CreatedAt.
+type BackupRecord struct {
+	Kind      string     `json:"kind"`
+	Version   int        `json:"version,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	Tag       *Tag       `json:"tag,omitempty"`
+	Pixel     *Pixel     `json:"pixel,omitempty"`
+	Link      *ShortLink `json:"link,omitempty"`
+}
+
+// BackupCounts is the number of resources of each kind in a backup.
+type BackupCounts struct {
+	Tags   int `json:"tags"`
+	Pixels int `json:"pixels"`
+	Links  int `json:"links"`
+}
+
+// ExportAccount writes every tag, pixel and short link of the account to w
+// as newline-delimited JSON, one BackupRecord per line, and returns how
+// many of each it wrote. Tags and pixels come first so a restore can map
+// them before the links that refer to them. Links are written a page at a
+// time and never held in memory together. Passwords cannot be read back
+// from the API and are not part of the backup.
+func (c *Client) ExportAccount(ctx context.Context, w io.Writer) (*BackupCounts, error) {
+	tags, err := c.ListAllTags(ctx, ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pixels, err := c.ListAllPixels(ctx, ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	enc := json.NewEncoder(w)
+	now := time.Now().UTC()
+	if err := enc.Encode(BackupRecord{Kind: BackupHeader, Version: backupVersion, CreatedAt: &now}); err != nil {
+		return nil, err
+	}
+	counts := &BackupCounts{}
+	for i := range tags {
+		if err := enc.Encode(BackupRecord{Kind: BackupTag, Tag: &tags[i]}); err != nil {
+			return counts, err
+		}
+		counts.Tags++
+	}
+	for i := range pixels {
+		if err := enc.Encode(BackupRecord{Kind: BackupPixel, Pixel: &pixels[i]}); err != nil {
+			return counts, err
+		}
+		counts.Pixels++
+	}
+	opts := ShortLinkListOptions{Page: 1}
+	for {
+		page, err := c.ListShortLinksPage(ctx, opts)
+		if err != nil {
+			return counts, err
+		}
+		for i := range page.Data {
+			if err := enc.Encode(BackupRecord{Kind: BackupLink, Link: &page.Data[i]}); err != nil {
+				return counts, err
+			}
+			counts.Links++
+		}
+		if len(page.Data) == 0 || page.CurrentPage >= page.LastPage {
+			return counts, nil
+		}
+		opts.Page = page.CurrentPage + 1
+	}
+}
+
+// RestoreOptions configures RestoreAccount.
+type RestoreOptions struct {
+	// DryRun reports what would be created without creating anything.
+	DryRun bool
+	// Match controls how tag names are compared with existing tags.
+	Match TagMatch
+	// Progress, when set, is called after each record is restored.
+	Progress func(kind, name string, outcome RestoreOutcome)
+}
+
+// RestoreOutcome is what RestoreAccount did with a record.
+type RestoreOutcome string
+
+// Restore outcomes.
+const (
+	// RestoreCreated means the resource was created, or would be in a dry
+	// run.
+	RestoreCreated RestoreOutcome = "created"
+	// RestoreExisting means a matching resource already existed and was
+	// left untouched.
+	RestoreExisting RestoreOutcome = "existing"
+	// RestoreFailed means the resource could not be created.
+	RestoreFailed RestoreOutcome = "failed"
+)
+
+// RestoreFailure is a record RestoreAccount could not restore.
+type RestoreFailure struct {
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	Err   error  `json:"-"`
+	Error string `json:"error"`
+}
+
+// RestoreReport describes the outcome of RestoreAccount.
+type RestoreReport struct {
+	DryRun   bool             `json:"dry_run"`
+	Created  BackupCounts     `json:"created"`
+	Existing BackupCounts     `json:"existing"`
+	Failed   []RestoreFailure `json:"failed"`
+}
+
+// RestoreAccount reads a backup written by ExportAccount from r, one line
+// at a time, and recreates what the account is missing. Tags are matched
+// by name and pixels by type and platform pixel ID; links are matched by
+// short URL, which costs one lookup per link. Existing resources are left
+// untouched. Links are created with the same alias and domain, and their
+// tags and pixels are mapped to the IDs of the restored ones. Failures are
+// reported and do not stop the restore; a malformed backup does.
+func (c *Client) RestoreAccount(ctx context.Context, r io.Reader, opts RestoreOptions) (*RestoreReport, error) {
+	tags, err := c.listTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pixels, err := c.listPixels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rs := &restorer{
+		c:        c,
+		opts:     opts,
+		equal:    c.tagEqual(opts.Match),
+		tags:     tags,
+		pixels:   pixels,
+		tagIDs:   map[int]int{},
+		pixelIDs: map[int]int{},
+		report:   &RestoreReport{DryRun: opts.DryRun},
+	}
+	dec := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		var rec BackupRecord
+		if err := dec.Decode(&rec); err == io.EOF {
+			break
+		} else if err != nil {
+			return rs.report, fmt.Errorf("tly: backup record %d: %w", line, err)
+		}
+		if err := ctx.Err(); err != nil {
+			return rs.report, err
+		}
+		if err := rs.restore(ctx, line, rec); err != nil {
+			return rs.report, err
+		}
+	}
+	return rs.report, nil
+}
+
+// restorer holds the state of a RestoreAccount run: the account's tags
+// and pixels, and the IDs they have in the account keyed by their IDs in
+// the backup.
+type restorer struct {
+	c      *Client
+	opts   RestoreOptions
+	equal  func(a, b string) bool
+	tags   []Tag
+	pixels []Pixel
+
+	tagIDs, pixelIDs map[int]int
+	report           *RestoreReport
+}
+
+// restore restores one record. It returns an error only for records that
+// are malformed.
+func (rs *restorer) restore(ctx context.Context, line int, rec BackupRecord) error {
+	switch {
+	case rec.Kind == BackupHeader:
+		if rec.Version > backupVersion {
+			return fmt.Errorf("tly: backup version %d is newer than supported (%d)", rec.Version, backupVersion)
+		}
+	case rec.Kind == BackupTag && rec.Tag != nil:
+		rs.restoreTag(ctx, *rec.Tag)
+	case rec.Kind == BackupPixel && rec.Pixel != nil:
+		rs.restorePixel(ctx, *rec.Pixel)
+	case rec.Kind == BackupLink && rec.Link != nil:
+		rs.restoreLink(ctx, *rec.Link)
+	default:
+		return fmt.Errorf("tly: backup record %d: unknown or empty record of kind %q", line, rec.Kind)
+	}
+	return nil
+}
+
+func (rs *restorer) restoreTag(ctx context.Context, t Tag) {
+	if found, err := findTag(rs.tags, t.Tag, rs.equal); err == nil {
+		rs.tagIDs[t.ID] = found.ID
+		rs.report.Existing.Tags++
+		rs.progress(BackupTag, t.Tag, RestoreExisting)
+		return
+	} else if !errors.Is(err, ErrTagNotFound) {
+		rs.fail(BackupTag, t.Tag, err)
+		return
+	}
+	created := &Tag{Tag: t.Tag}
+	if !rs.opts.DryRun {
+		var err error
+		if created, err = rs.c.createTag(ctx, t.Tag); err != nil {
+			rs.fail(BackupTag, t.Tag, err)
+			return
+		}
+	}
+	rs.tags = append(rs.tags, *created)
+	rs.tagIDs[t.ID] = created.ID
+	rs.report.Created.Tags++
+	rs.progress(BackupTag, t.Tag, RestoreCreated)
+}
+
+func (rs *restorer) restorePixel(ctx context.Context, p Pixel) {
+	if found, ok := findPixel(rs.pixels, p.PixelType, p.PixelID); ok {
+		rs.pixelIDs[p.ID] = found.ID
+		rs.report.Existing.Pixels++
+		rs.progress(BackupPixel, p.Name, RestoreExisting)
+		return
+	}
+	created := &Pixel{Name: p.Name, PixelID: p.PixelID, PixelType: p.PixelType}
+	if !rs.opts.DryRun {
+		var err error
+		created, err = rs.c.createPixel(ctx, PixelCreateRequest{Name: p.Name, PixelID: p.PixelID, PixelType: p.PixelType})
+		if err != nil {
+			rs.fail(BackupPixel, p.Name, err)
+			return
+		}
+	}
+	rs.pixels = append(rs.pixels, *created)
+	rs.pixelIDs[p.ID] = created.ID
+	rs.report.Created.Pixels++
+	rs.progress(BackupPixel, p.Name, RestoreCreated)
+}
+
+func (rs *restorer) restoreLink(ctx context.Context, l ShortLink) {
+	_, err := rs.c.fetchShortLink(ctx, l.ShortURL)
+	if err == nil {
+		rs.report.Existing.Links++
+		rs.progress(BackupLink, l.ShortURL, RestoreExisting)
+		return
+	}
+	if !IsNotFound(err) {
+		rs.fail(BackupLink, l.ShortURL, err)
+		return
+	}
+	req := createRequestFrom(l)
+	for _, t := range l.Tags {
+		if id, ok := rs.tagIDs[t.ID]; ok && id != 0 {
+			req.Tags = append(req.Tags, id)
+		}
+	}
+	for _, p := range l.Pixels {
+		if id, ok := rs.pixelIDs[p.ID]; ok && id != 0 {
+			req.Pixels = append(req.Pixels, id)
+		}
+	}
+	if !rs.opts.DryRun {
+		if _, err := rs.c.createShortLink(ctx, req); err != nil {
+			rs.fail(BackupLink, l.ShortURL, err)
+			return
+		}
+	}
+	rs.report.Created.Links++
+	rs.progress(BackupLink, l.ShortURL, RestoreCreated)
+}
+
+func (rs *restorer) fail(kind, name string, err error) {
+	rs.report.Failed = append(rs.report.Failed, RestoreFailure{Kind: kind, Name: name, Err: err, Error: err.Error()})
+	rs.progress(kind, name, RestoreFailed)
+}
+
+func (rs *restorer) progress(kind, name string, outcome RestoreOutcome) {
+	if rs.opts.Progress != nil {
+		rs.opts.Progress(kind, name, outcome)
+	}
+}
+
+// createRequestFrom builds a request recreating link with its alias,
+// domain and settings, without tags or pixels.
+func createRequestFrom(link ShortLink) ShortLinkCreateRequest {
+	req := ShortLinkCreateRequest{
+		LongURL: link.LongURL,
+		Domain:  link.Domain,
+		Meta:    link.Meta,
+	}
+	if link.ShortID != "" {
+		shortID := link.ShortID
+		req.ShortID = &shortID
+	}
+	if link.PublicStats {
+		publicStats := true
+		req.PublicStats = &publicStats
+	}
+	if link.Description != "" {
+		description := link.Description
+		req.Description = &description
+	}
+	if v, ok := link.ExpireAtDatetime.(string); ok && v != "" {
+		req.ExpireAtDatetime = &v
+	}
+	if views := toInt(link.ExpireAtViews); views > 0 {
+		req.ExpireAtViews = &views
+	}
+	return req
+}
@@ -0,0 +1,62 @@
+package tly
+
+import "context"
+
+// Subscription is the account's plan subscription. Amount is in the
+// currency's minor unit, e.g. cents.
+type Subscription struct {
+	Plan     string `json:"plan"`
+	Status   string `json:"status"`
+	Interval string `json:"interval"`
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	// CancelAtPeriodEnd is set when the subscription ends with the
+	// current period instead of renewing.
+	CancelAtPeriodEnd  bool      `json:"cancel_at_period_end"`
+	CurrentPeriodStart Timestamp `json:"current_period_start"`
+	CurrentPeriodEnd   Timestamp `json:"current_period_end"`
+}
+
+// Invoice is one invoice of the account. Amounts are in the currency's
+// minor unit, e.g. cents.
+type Invoice struct {
+	ID       string    `json:"id"`
+	Number   string    `json:"number"`
+	Status   string    `json:"status"`
+	Subtotal int64     `json:"subtotal"`
+	Tax      int64     `json:"tax"`
+	Total    int64     `json:"total"`
+	Currency string    `json:"currency"`
+	Date     Timestamp `json:"date"`
+	PaidAt   Timestamp `json:"paid_at"`
+	PDFURL   string    `json:"pdf_url"`
+}
+
+// GetSubscription retrieves the account's current subscription.
+func (c *Client) GetSubscription(ctx context.Context) (*Subscription, error) {
+	var sub Subscription
+	err := c.doRequestContext(ctx, "GET", "/api/v1/account/subscription", "", nil, &sub)
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// ListInvoices retrieves the account's invoice history, newest first,
+// following pagination.
+func (c *Client) ListInvoices(ctx context.Context) ([]Invoice, error) {
+	opts := ListOptions{Page: 1}
+	var invoices []Invoice
+	for {
+		var data []Invoice
+		info, err := c.getPage(ctx, "/api/v1/account/invoices", opts, &data)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, data...)
+		if len(data) == 0 || !info.HasNext() {
+			return invoices, nil
+		}
+		opts.Page = info.CurrentPage + 1
+	}
+}
@@ -0,0 +1,190 @@
+package tly
+
+import (
+	"context"
+	"fmt"
+)
+
+// BioPageService is the link-in-bio page part of the API.
+type BioPageService interface {
+	ListBioPages(ctx context.Context) ([]BioPage, error)
+	GetBioPage(ctx context.Context, id int) (*BioPage, error)
+	CreateBioPage(ctx context.Context, reqData BioPageRequest) (*BioPage, error)
+	UpdateBioPage(ctx context.Context, id int, reqData BioPageRequest) (*BioPage, error)
+	DeleteBioPage(ctx context.Context, id int) error
+	AddBioBlock(ctx context.Context, pageID int, reqData BioBlockRequest) (*BioBlock, error)
+	UpdateBioBlock(ctx context.Context, pageID, blockID int, reqData BioBlockRequest) (*BioBlock, error)
+	DeleteBioBlock(ctx context.Context, pageID, blockID int) error
+	ReorderBioBlocks(ctx context.Context, pageID int, blockIDs []int) (*BioPage, error)
+}
+
+var _ BioPageService = (*Client)(nil)
+
+// BioBlockType is the kind of a bio page block.
+type BioBlockType string
+
+// Bio page block types.
+const (
+	BioBlockLink   BioBlockType = "link"
+	BioBlockButton BioBlockType = "button"
+	BioBlockHeader BioBlockType = "header"
+	BioBlockText   BioBlockType = "text"
+)
+
+// BioPage is a link-in-bio landing page.
+type BioPage struct {
+	ID          int        `json:"id"`
+	Slug        string     `json:"slug"`
+	URL         string     `json:"url"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	AvatarURL   string     `json:"avatar_url"`
+	Theme       string     `json:"theme"`
+	Blocks      []BioBlock `json:"blocks"`
+	CreatedAt   Timestamp  `json:"created_at"`
+	UpdatedAt   Timestamp  `json:"updated_at"`
+}
+
+// BioBlock is one entry on a bio page. URL is empty for header and text
+// blocks. Blocks are shown by ascending Position.
+type BioBlock struct {
+	ID       int          `json:"id"`
+	Type     BioBlockType `json:"type"`
+	Label    string       `json:"label"`
+	URL      string       `json:"url,omitempty"`
+	Position int          `json:"position"`
+	Enabled  bool         `json:"enabled"`
+}
+
+// BioPageRequest is used to create or update a bio page. Blocks are managed
+// separately with AddBioBlock and friends.
+type BioPageRequest struct {
+	Slug        string `json:"slug"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+	Theme       string `json:"theme,omitempty"`
+}
+
+// BioBlockRequest is used to add or update a bio page block. A nil Enabled
+// is treated as true when adding and left unchanged when updating.
+type BioBlockRequest struct {
+	Type     BioBlockType `json:"type"`
+	Label    string       `json:"label"`
+	URL      string       `json:"url,omitempty"`
+	Position *int         `json:"position,omitempty"`
+	Enabled  *bool        `json:"enabled,omitempty"`
+}
+
+// validate checks that link and button blocks have a URL.
+func (r BioBlockRequest) validate() error {
+	switch r.Type {
+	case BioBlockLink, BioBlockButton:
+		if r.URL == "" {
+			return fmt.Errorf("tly: %s block needs a URL", r.Type)
+		}
+	case BioBlockHeader, BioBlockText:
+	default:
+		return fmt.Errorf("tly: unknown bio block type %q", r.Type)
+	}
+	return nil
+}
+
+// ListBioPages retrieves every bio page in the account.
+func (c *Client) ListBioPages(ctx context.Context) ([]BioPage, error) {
+	var pages []BioPage
+	err := c.doRequestContext(ctx, "GET", "/api/v1/bio-page", "", nil, &pages)
+	if err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// GetBioPage retrieves a bio page with its blocks.
+func (c *Client) GetBioPage(ctx context.Context, id int) (*BioPage, error) {
+	path := fmt.Sprintf("/api/v1/bio-page/%d", id)
+	var page BioPage
+	err := c.doRequestContext(ctx, "GET", path, "", nil, &page)
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// CreateBioPage creates a bio page.
+func (c *Client) CreateBioPage(ctx context.Context, reqData BioPageRequest) (*BioPage, error) {
+	if reqData.Slug == "" {
+		return nil, fmt.Errorf("tly: bio page slug is required")
+	}
+	var page BioPage
+	err := c.doRequestContext(ctx, "POST", "/api/v1/bio-page", "", reqData, &page)
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// UpdateBioPage updates the settings of a bio page.
+func (c *Client) UpdateBioPage(ctx context.Context, id int, reqData BioPageRequest) (*BioPage, error) {
+	path := fmt.Sprintf("/api/v1/bio-page/%d", id)
+	var page BioPage
+	err := c.doRequestContext(ctx, "PUT", path, "", reqData, &page)
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// DeleteBioPage deletes a bio page and its blocks.
+func (c *Client) DeleteBioPage(ctx context.Context, id int) error {
+	path := fmt.Sprintf("/api/v1/bio-page/%d", id)
+	return c.doRequestContext(ctx, "DELETE", path, "", nil, nil)
+}
+
+// AddBioBlock adds a block to a bio page, at the end unless a Position is
+// given.
+func (c *Client) AddBioBlock(ctx context.Context, pageID int, reqData BioBlockRequest) (*BioBlock, error) {
+	if err := reqData.validate(); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/api/v1/bio-page/%d/block", pageID)
+	var block BioBlock
+	err := c.doRequestContext(ctx, "POST", path, "", reqData, &block)
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// UpdateBioBlock updates a block of a bio page.
+func (c *Client) UpdateBioBlock(ctx context.Context, pageID, blockID int, reqData BioBlockRequest) (*BioBlock, error) {
+	if err := reqData.validate(); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/api/v1/bio-page/%d/block/%d", pageID, blockID)
+	var block BioBlock
+	err := c.doRequestContext(ctx, "PUT", path, "", reqData, &block)
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// DeleteBioBlock removes a block from a bio page.
+func (c *Client) DeleteBioBlock(ctx context.Context, pageID, blockID int) error {
+	path := fmt.Sprintf("/api/v1/bio-page/%d/block/%d", pageID, blockID)
+	return c.doRequestContext(ctx, "DELETE", path, "", nil, nil)
+}
+
+// ReorderBioBlocks sets the order of a page's blocks to blockIDs and
+// returns the updated page.
+func (c *Client) ReorderBioBlocks(ctx context.Context, pageID int, blockIDs []int) (*BioPage, error) {
+	path := fmt.Sprintf("/api/v1/bio-page/%d/block/order", pageID)
+	body := map[string][]int{"blocks": blockIDs}
+	var page BioPage
+	err := c.doRequestContext(ctx, "PUT", path, "", body, &page)
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
@@ -0,0 +1,231 @@
+package tly
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Cache stores raw API responses for the read calls that support caching:
+// GetShortLink, GetStats and GetStatsWithOptions.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte)
+	Delete(key string)
+}
+
+// MemoryCache is an in-memory Cache whose entries expire after a TTL.
+type MemoryCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache creates an in-memory cache. A zero ttl never expires
+// entries.
+func NewMemoryCache(ttl time.Duration) *MemoryCache {
+	return &MemoryCache{ttl: ttl, entries: map[string]memoryEntry{}}
+}
+
+// Get implements Cache.
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set implements Cache.
+func (m *MemoryCache) Set(key string, value []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := memoryEntry{value: value}
+	if m.ttl > 0 {
+		e.expires = time.Now().Add(m.ttl)
+	}
+	m.entries[key] = e
+}
+
+// Delete implements Cache.
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+}
+
+// DefaultCacheTTL is how long WithCache serves a cached response before
+// fetching it again, unless WithCacheTTL sets another duration.
+const DefaultCacheTTL = 5 * time.Minute
+
+// maxCachedKeys bounds the responses a client keeps track of. Beyond it the
+// oldest are dropped from the cache.
+const maxCachedKeys = 10000
+
+// WithCache enables response caching for GetShortLink and the stats calls.
+// Cached entries for a short URL are invalidated automatically after
+// UpdateShortLink or DeleteShortLink on it, and are served for at most
+// DefaultCacheTTL. Keys include the API server, credentials and workspace,
+// so clients of different accounts can share one Cache.
+func WithCache(cache Cache) ClientOption {
+	return func(c *Client) {
+		c.cache = newResponseCache(cache, DefaultCacheTTL, maxCachedKeys)
+	}
+}
+
+// WithCacheTTL sets how long WithCache serves a cached response. A zero ttl
+// serves it until it is invalidated or evicted.
+func WithCacheTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.cacheTTL = &ttl
+	}
+}
+
+// responseCache wraps a Cache with an index of the keys stored, oldest
+// first and per short URL, so every cached variant of a link can be
+// invalidated together and old entries expire and are evicted.
+type responseCache struct {
+	cache Cache
+	ttl   time.Duration
+	max   int
+
+	mu      sync.Mutex
+	order   *list.List // of *cachedKey, oldest first
+	entries map[string]*list.Element
+	keys    map[string]map[string]bool
+}
+
+// cachedKey is an entry of the responseCache index.
+type cachedKey struct {
+	key      string
+	shortURL string
+	stored   time.Time
+}
+
+func newResponseCache(cache Cache, ttl time.Duration, max int) *responseCache {
+	return &responseCache{
+		cache:   cache,
+		ttl:     ttl,
+		max:     max,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+		keys:    map[string]map[string]bool{},
+	}
+}
+
+// get returns the response stored under key if it is still fresh.
+func (r *responseCache) get(key string, ttl time.Duration, now time.Time) ([]byte, bool) {
+	r.mu.Lock()
+	e, ok := r.entries[key]
+	if ok && ttl > 0 && now.Sub(e.Value.(*cachedKey).stored) >= ttl {
+		r.remove(e)
+		ok = false
+	}
+	r.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	return r.cache.Get(key)
+}
+
+// set stores a response for shortURL under key, evicting the oldest
+// entries beyond r.max.
+func (r *responseCache) set(shortURL, key string, value []byte, now time.Time) {
+	r.cache.Set(key, value)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e, ok := r.entries[key]; ok {
+		r.remove(e)
+	}
+	r.entries[key] = r.order.PushBack(&cachedKey{key: key, shortURL: shortURL, stored: now})
+	if r.keys[shortURL] == nil {
+		r.keys[shortURL] = map[string]bool{}
+	}
+	r.keys[shortURL][key] = true
+	for r.max > 0 && r.order.Len() > r.max {
+		r.remove(r.order.Front())
+	}
+}
+
+// remove drops e from the index and its response from the cache. The
+// caller holds r.mu.
+func (r *responseCache) remove(e *list.Element) {
+	k := r.order.Remove(e).(*cachedKey)
+	delete(r.entries, k.key)
+	if keys := r.keys[k.shortURL]; keys != nil {
+		delete(keys, k.key)
+		if len(keys) == 0 {
+			delete(r.keys, k.shortURL)
+		}
+	}
+	r.cache.Delete(k.key)
+}
+
+// invalidate drops every response stored for shortURL.
+func (r *responseCache) invalidate(shortURL string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key := range r.keys[shortURL] {
+		r.remove(r.entries[key])
+	}
+}
+
+// cacheKey returns the key of a GET of path with query. It names the API
+// server, the credentials and the workspace, so clients of different
+// accounts or workspaces sharing a Cache never see each other's responses.
+// The API key is hashed rather than stored.
+func (c *Client) cacheKey(path, query string) string {
+	identity := "key:" + c.APIKey
+	if c.tokens != nil {
+		identity = fmt.Sprintf("tokens:%p", c.tokens)
+	}
+	sum := sha256.Sum256([]byte(identity))
+	return fmt.Sprintf("%s %x %d %s?%s", c.BaseURL, sum[:8], c.workspace, path, query)
+}
+
+// cachedGet performs a GET for shortURL, serving and storing the response
+// body through the cache when one is configured.
+func (c *Client) cachedGet(ctx context.Context, shortURL, path, query string, result interface{}) error {
+	if c.cache == nil {
+		return c.doRequestContext(ctx, "GET", path, query, nil, result)
+	}
+	ttl := c.cache.ttl
+	if c.cacheTTL != nil {
+		ttl = *c.cacheTTL
+	}
+	key := c.cacheKey(path, query)
+	if data, ok := c.cache.get(key, ttl, time.Now()); ok {
+		return json.Unmarshal(data, result)
+	}
+	var raw json.RawMessage
+	if err := c.doRequestContext(ctx, "GET", path, query, nil, &raw); err != nil {
+		return err
+	}
+	c.cache.set(shortURL, key, raw, time.Now())
+	return json.Unmarshal(raw, result)
+}
+
+// Invalidate drops every cached response for shortURL. It is a no-op when
+// caching is disabled.
+func (c *Client) Invalidate(shortURL string) {
+	if c.cache == nil || shortURL == "" {
+		return
+	}
+	c.cache.invalidate(shortURL)
+}
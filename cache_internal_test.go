@@ -0,0 +1,43 @@
+package tly
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestResponseCacheEviction(t *testing.T) {
+	store := NewMemoryCache(0)
+	r := newResponseCache(store, 0, 3)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 1; i <= 5; i++ {
+		r.set(fmt.Sprintf("https://t.ly/%d", i), fmt.Sprintf("k%d", i), []byte("{}"), now)
+	}
+	for i := 1; i <= 5; i++ {
+		key := fmt.Sprintf("k%d", i)
+		_, ok := r.get(key, 0, now)
+		_, stored := store.Get(key)
+		if want := i > 2; ok != want || stored != want {
+			t.Errorf("%s: indexed %v, stored %v; want %v", key, ok, stored, want)
+		}
+	}
+	if len(r.entries) != 3 || len(r.keys) != 3 || r.order.Len() != 3 {
+		t.Errorf("index holds %d entries, %d short URLs and %d keys in order, want 3 each", len(r.entries), len(r.keys), r.order.Len())
+	}
+}
+
+func TestResponseCacheExpiry(t *testing.T) {
+	store := NewMemoryCache(0)
+	r := newResponseCache(store, time.Minute, 10)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.set("https://t.ly/a", "k", []byte("{}"), now)
+	if _, ok := r.get("k", time.Minute, now.Add(59*time.Second)); !ok {
+		t.Error("entry expired before its TTL")
+	}
+	if _, ok := r.get("k", time.Minute, now.Add(time.Minute)); ok {
+		t.Error("entry served after its TTL")
+	}
+	if _, ok := store.Get("k"); ok || len(r.keys) != 0 {
+		t.Error("expired entry was not dropped")
+	}
+}
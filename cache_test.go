@@ -0,0 +1,109 @@
+package tly_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+	"github.com/timleland/t.ly-go-url-shortener-api/tlytest"
+)
+
+func TestMemoryCache(t *testing.T) {
+	m := tly.NewMemoryCache(0)
+	m.Set("a", []byte("1"))
+	if v, ok := m.Get("a"); !ok || string(v) != "1" {
+		t.Fatalf("Get(a) = %q, %v", v, ok)
+	}
+	m.Delete("a")
+	if _, ok := m.Get("a"); ok {
+		t.Error("Get after Delete found the entry")
+	}
+
+	expiring := tly.NewMemoryCache(time.Millisecond)
+	expiring.Set("a", []byte("1"))
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := expiring.Get("a"); ok {
+		t.Error("Get after the TTL found the entry")
+	}
+}
+
+func TestClientCache(t *testing.T) {
+	srv := tlytest.NewServer(tlytest.Options{})
+	defer srv.Close()
+	c := srv.Client(tly.WithCache(tly.NewMemoryCache(0)))
+	ctx := context.Background()
+	link, err := c.CreateShortLink(tly.ShortLinkCreateRequest{LongURL: "https://example.com/a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name        string
+		call        func() error
+		links       int // GET /api/v1/link requests so far
+		stats       int // /api/v1/link/stats requests so far
+		wantLongURL string
+	}{
+		{"first get", func() error { _, err := c.GetShortLink(link.ShortURL); return err }, 1, 0, ""},
+		{"cached get", func() error { _, err := c.GetShortLink(link.ShortURL); return err }, 1, 0, ""},
+		{"first stats", func() error { _, err := c.GetStats(link.ShortURL); return err }, 1, 1, ""},
+		{"cached stats", func() error { _, err := c.GetStats(link.ShortURL); return err }, 1, 1, ""},
+		{"first stats with options", func() error {
+			_, err := c.GetStatsWithOptions(ctx, link.ShortURL, tly.StatsOptions{})
+			return err
+		}, 1, 2, ""},
+		{"cached stats with options", func() error {
+			_, err := c.GetStatsWithOptions(ctx, link.ShortURL, tly.StatsOptions{})
+			return err
+		}, 1, 2, ""},
+		{"stats with a password bypass the cache", func() error {
+			_, err := c.GetStatsWithOptions(ctx, link.ShortURL, tly.StatsOptions{Password: "pw"})
+			return err
+		}, 1, 3, ""},
+		{"update invalidates", func() error {
+			_, err := c.UpdateShortLink(tly.ShortLinkUpdateRequest{ShortURL: link.ShortURL, LongURL: "https://example.com/b"})
+			return err
+		}, 1, 3, ""},
+		{"get after update", func() error { _, err := c.GetShortLink(link.ShortURL); return err }, 2, 3, "https://example.com/b"},
+		{"stats after update", func() error { _, err := c.GetStats(link.ShortURL); return err }, 2, 4, ""},
+	}
+	for _, s := range steps {
+		if err := s.call(); err != nil {
+			t.Fatalf("%s: %v", s.name, err)
+		}
+		if got := srv.Requests("GET /api/v1/link"); got != s.links {
+			t.Errorf("%s: %d link requests, want %d", s.name, got, s.links)
+		}
+		if got := srv.Requests("GET /api/v1/link/stats") + srv.Requests("POST /api/v1/link/stats"); got != s.stats {
+			t.Errorf("%s: %d stats requests, want %d", s.name, got, s.stats)
+		}
+		if s.wantLongURL != "" {
+			got, err := c.GetShortLink(link.ShortURL)
+			if err != nil || got.LongURL != s.wantLongURL {
+				t.Errorf("%s: long URL = %v, %v; want %s", s.name, got, err, s.wantLongURL)
+			}
+		}
+	}
+}
+
+func TestClientCacheTTL(t *testing.T) {
+	srv := tlytest.NewServer(tlytest.Options{})
+	defer srv.Close()
+	c := srv.Client(tly.WithCache(tly.NewMemoryCache(0)), tly.WithCacheTTL(20*time.Millisecond))
+	link, err := c.CreateShortLink(tly.ShortLinkCreateRequest{LongURL: "https://example.com/a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int{1, 1, 2} {
+		if i == 2 {
+			time.Sleep(30 * time.Millisecond)
+		}
+		if _, err := c.GetStats(link.ShortURL); err != nil {
+			t.Fatal(err)
+		}
+		if got := srv.Requests("GET /api/v1/link/stats"); got != want {
+			t.Errorf("call %d: %d stats requests, want %d", i+1, got, want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package tly
+
+import (
+	"context"
+	"fmt"
+)
+
+// CampaignService is the campaign part of the API.
+type CampaignService interface {
+	ListCampaigns(ctx context.Context) ([]Campaign, error)
+	GetCampaign(ctx context.Context, id int) (*Campaign, error)
+	CreateCampaign(ctx context.Context, reqData CampaignRequest) (*Campaign, error)
+	UpdateCampaign(ctx context.Context, id int, reqData CampaignRequest) (*Campaign, error)
+	DeleteCampaign(ctx context.Context, id int) error
+	AddLinkToCampaign(ctx context.Context, id int, shortURL string) error
+	ListCampaignLinks(ctx context.Context, id int) ([]ShortLink, error)
+	GetCampaignStats(ctx context.Context, id int, opts StatsOptions) (*CampaignStats, error)
+}
+
+var _ CampaignService = (*Client)(nil)
+
+// Campaign groups short links for reporting. Unlike tags, a link belongs to
+// at most one campaign.
+type Campaign struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	LinkCount   int       `json:"links_count"`
+	CreatedAt   Timestamp `json:"created_at"`
+	UpdatedAt   Timestamp `json:"updated_at"`
+}
+
+// CampaignRequest is used to create or update a campaign.
+type CampaignRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// ListCampaigns retrieves every campaign, following pagination.
+func (c *Client) ListCampaigns(ctx context.Context) ([]Campaign, error) {
+	opts := ListOptions{Page: 1}
+	var campaigns []Campaign
+	for {
+		var data []Campaign
+		info, err := c.getPage(ctx, "/api/v1/campaign", opts, &data)
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, data...)
+		if len(data) == 0 || !info.HasNext() {
+			return campaigns, nil
+		}
+		opts.Page = info.CurrentPage + 1
+	}
+}
+
+// GetCampaign retrieves a campaign by its ID.
+func (c *Client) GetCampaign(ctx context.Context, id int) (*Campaign, error) {
+	path := fmt.Sprintf("/api/v1/campaign/%d", id)
+	var campaign Campaign
+	err := c.doRequestContext(ctx, "GET", path, "", nil, &campaign)
+	if err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// CreateCampaign creates a campaign.
+func (c *Client) CreateCampaign(ctx context.Context, reqData CampaignRequest) (*Campaign, error) {
+	if reqData.Name == "" {
+		return nil, fmt.Errorf("tly: campaign name is required")
+	}
+	var campaign Campaign
+	err := c.doRequestContext(ctx, "POST", "/api/v1/campaign", "", reqData, &campaign)
+	if err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// UpdateCampaign changes the name and description of a campaign.
+func (c *Client) UpdateCampaign(ctx context.Context, id int, reqData CampaignRequest) (*Campaign, error) {
+	if reqData.Name == "" {
+		return nil, fmt.Errorf("tly: campaign name is required")
+	}
+	path := fmt.Sprintf("/api/v1/campaign/%d", id)
+	var campaign Campaign
+	err := c.doRequestContext(ctx, "PUT", path, "", reqData, &campaign)
+	if err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// DeleteCampaign deletes a campaign. Its links are kept and no longer
+// belong to any campaign.
+func (c *Client) DeleteCampaign(ctx context.Context, id int) error {
+	path := fmt.Sprintf("/api/v1/campaign/%d", id)
+	return c.doRequestContext(ctx, "DELETE", path, "", nil, nil)
+}
+
+// AddLinkToCampaign moves a short link into a campaign, taking it out of
+// the campaign it was in before.
+func (c *Client) AddLinkToCampaign(ctx context.Context, id int, shortURL string) error {
+	path := fmt.Sprintf("/api/v1/campaign/%d/link", id)
+	body := map[string]string{"short_url": shortURL}
+	return c.doRequestContext(ctx, "POST", path, "", body, nil)
+}
+
+// ListCampaignLinks returns every link in a campaign, following
+// pagination.
+func (c *Client) ListCampaignLinks(ctx context.Context, id int) ([]ShortLink, error) {
+	return c.ListAllShortLinks(ctx, ShortLinkListOptions{CampaignID: id})
+}
+
+// CampaignStats is the stats rollup for every link in a campaign.
+type CampaignStats struct {
+	CampaignID int
+	// Total merges the stats of every link; see MergeStats.
+	Total *Stats
+	// Links holds the per-link stats keyed by short URL.
+	Links map[string]*Stats
+}
+
+// GetCampaignStats returns the merged stats of every link in a campaign
+// along with a per-link breakdown.
+func (c *Client) GetCampaignStats(ctx context.Context, id int, opts StatsOptions) (*CampaignStats, error) {
+	links, err := c.ListCampaignLinks(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := c.fetchStats(ctx, links, opts, defaultConcurrency)
+	if err != nil {
+		return nil, err
+	}
+	result := &CampaignStats{CampaignID: id, Links: make(map[string]*Stats, len(links))}
+	for i, link := range links {
+		result.Links[link.ShortURL] = stats[i]
+	}
+	result.Total = MergeStats(stats...)
+	return result, nil
+}
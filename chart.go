@@ -0,0 +1,132 @@
+package tly
+
+import "time"
+
+// ChartOptions configures Stats.Chart.
+type ChartOptions struct {
+	// Granularity of the series. Defaults to GranularityDay.
+	Granularity Granularity
+	// Location aligns buckets to local time. Defaults to UTC.
+	Location *time.Location
+	// Start and End widen the series to a fixed range; zero values use
+	// the first and last bucket with data.
+	Start time.Time
+	End   time.Time
+	// Cumulative turns the series into a running total.
+	Cumulative bool
+	// MovingAverage smooths the series with a trailing window of this
+	// many buckets, applied after Cumulative. Zero or one disables it.
+	MovingAverage int
+	// LabelFormat is the time layout of the labels. It defaults to a
+	// layout suited to the granularity.
+	LabelFormat string
+}
+
+// ChartSeries is a chart-ready series with one label per value.
+type ChartSeries struct {
+	Labels []string  `json:"labels"`
+	Values []float64 `json:"values"`
+}
+
+// Chart converts the click series into sorted buckets with every gap filled
+// by zero, ready for charting libraries that expect parallel label and
+// value arrays.
+func (s *Stats) Chart(opts ChartOptions) (*ChartSeries, error) {
+	g := opts.Granularity
+	if g == "" {
+		g = GranularityDay
+	}
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	buckets, err := s.BucketsIn(g, loc)
+	if err != nil {
+		return nil, err
+	}
+	start, end := opts.Start, opts.End
+	if len(buckets) > 0 {
+		if start.IsZero() {
+			start = buckets[0].Start
+		}
+		if end.IsZero() {
+			end = buckets[len(buckets)-1].Start
+		}
+	}
+	series := &ChartSeries{Labels: []string{}, Values: []float64{}}
+	if start.IsZero() || end.IsZero() {
+		return series, nil
+	}
+	align, step := bucketFuncs(g)
+	totals := make(map[int64]int, len(buckets))
+	for _, b := range buckets {
+		totals[b.Start.Unix()] = b.Total
+	}
+	layout := opts.LabelFormat
+	if layout == "" {
+		layout = labelLayout(g)
+	}
+	for t := align(start.In(loc)); !t.After(end.In(loc)); t = step(t) {
+		series.Labels = append(series.Labels, t.Format(layout))
+		series.Values = append(series.Values, float64(totals[t.Unix()]))
+	}
+	if opts.Cumulative {
+		for i := 1; i < len(series.Values); i++ {
+			series.Values[i] += series.Values[i-1]
+		}
+	}
+	if opts.MovingAverage > 1 {
+		series.Values = movingAverage(series.Values, opts.MovingAverage)
+	}
+	return series, nil
+}
+
+// bucketFuncs returns functions aligning a time to the start of its bucket
+// and advancing to the next bucket.
+func bucketFuncs(g Granularity) (align, step func(time.Time) time.Time) {
+	switch g {
+	case GranularityHour:
+		return func(t time.Time) time.Time {
+				return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+			}, func(t time.Time) time.Time {
+				return time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			}
+	case GranularityWeek:
+		return weekStart, func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	case GranularityMonth:
+		return func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		}, func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+	}
+	return dayStart, func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+}
+
+// labelLayout is the default label layout for a granularity.
+func labelLayout(g Granularity) string {
+	switch g {
+	case GranularityHour:
+		return "2006-01-02 15:00"
+	case GranularityMonth:
+		return "2006-01"
+	}
+	return dateLayout
+}
+
+// movingAverage returns the trailing mean over window values; the first
+// values average over the buckets seen so far.
+func movingAverage(values []float64, window int) []float64 {
+	out := make([]float64, len(values))
+	var sum float64
+	for i, v := range values {
+		sum += v
+		if i >= window {
+			sum -= values[i-window]
+		}
+		n := i + 1
+		if n > window {
+			n = window
+		}
+		out[i] = sum / float64(n)
+	}
+	return out
+}
@@ -2,10 +2,17 @@ package tly
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 // Client is the main API client for T.LY.
@@ -13,19 +20,60 @@ type Client struct {
 	APIKey  string
 	BaseURL string
 	Client  *http.Client
+
+	cache    *responseCache
+	cacheTTL *time.Duration
+	tags     *TagResolver
+	pixels   *PixelResolver
+
+	normalizeTag TagNormalizer
+	tagMetadata  TagMetadataStore
+	workspace    int
+	tokens       TokenSource
+	screener     DestinationScreener
+	domain       string
+
+	rateMu sync.Mutex
+	rate   RateLimit
 }
 
+// ClientOption configures optional Client behavior in NewClient.
+type ClientOption func(*Client)
+
 // NewClient creates a new T.LY API client.
-func NewClient(apiKey string) *Client {
-	return &Client{
+func NewClient(apiKey string, opts ...ClientOption) *Client {
+	c := &Client{
 		APIKey:  apiKey,
 		BaseURL: "https://api.t.ly",
 		Client:  &http.Client{},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // doRequest is an internal helper for making API calls.
 func (c *Client) doRequest(method, path, query string, body interface{}, result interface{}) error {
+	return c.doRequestContext(context.Background(), method, path, query, body, result)
+}
+
+// doRequestContext is like doRequest but binds the request to ctx.
+func (c *Client) doRequestContext(ctx context.Context, method, path, query string, body interface{}, result interface{}) error {
+	resp, err := c.send(ctx, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if result != nil {
+		return json.NewDecoder(resp.Body).Decode(result)
+	}
+	return nil
+}
+
+// send makes an API call and returns the response of a 2xx status for the
+// caller to read and close. Other statuses are returned as an *APIError.
+func (c *Client) send(ctx context.Context, method, path, query string, body interface{}) (*http.Response, error) {
 	url := c.BaseURL + path
 	if query != "" {
 		url += "?" + query
@@ -34,32 +82,68 @@ func (c *Client) doRequest(method, path, query string, body interface{}, result
 	if body != nil {
 		data, err := json.Marshal(body)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		buf = bytes.NewBuffer(data)
 	} else {
 		buf = bytes.NewBuffer(nil)
 	}
-	req, err := http.NewRequest(method, url, buf)
+	req, err := http.NewRequestWithContext(ctx, method, url, buf)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	token, err := c.bearerToken(ctx)
+	if err != nil {
+		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
+	if c.workspace != 0 {
+		req.Header.Set(workspaceHeader, strconv.Itoa(c.workspace))
+	}
 	resp, err := c.Client.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer resp.Body.Close()
+	c.observeRateLimit(resp.Header, time.Now())
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
 		data, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("API error: %s", string(data))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(data)}
 	}
-	if result != nil {
-		return json.NewDecoder(resp.Body).Decode(result)
+	return resp, nil
+}
+
+// APIError is returned when the API responds with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: %s", e.Body)
+}
+
+// Message returns the error message of the response: the "message" field
+// of a JSON body, else the body itself, else the status text.
+func (e *APIError) Message() string {
+	var body struct {
+		Message string `json:"message"`
 	}
-	return nil
+	if json.Unmarshal([]byte(e.Body), &body) == nil && body.Message != "" {
+		return body.Message
+	}
+	if msg := strings.TrimSpace(e.Body); msg != "" {
+		return msg
+	}
+	return http.StatusText(e.StatusCode)
+}
+
+// IsNotFound reports whether err is an APIError with status 404.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
 }
 
 // =====================
@@ -68,33 +152,41 @@ func (c *Client) doRequest(method, path, query string, body interface{}, result
 
 // Pixel represents a pixel object.
 type Pixel struct {
-	ID        int    `json:"id"`
-	Name      string `json:"name"`
-	PixelID   string `json:"pixel_id"`
-	PixelType string `json:"pixel_type"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	PixelID   string    `json:"pixel_id"`
+	PixelType PixelType `json:"pixel_type"`
+	CreatedAt Timestamp `json:"created_at"`
+	UpdatedAt Timestamp `json:"updated_at"`
 }
 
 // PixelCreateRequest is used to create a new pixel.
 type PixelCreateRequest struct {
-	Name      string `json:"name"`
-	PixelID   string `json:"pixel_id"`
-	PixelType string `json:"pixel_type"`
+	Name      string    `json:"name"`
+	PixelID   string    `json:"pixel_id"`
+	PixelType PixelType `json:"pixel_type"`
 }
 
 // PixelUpdateRequest is used to update a pixel.
 type PixelUpdateRequest struct {
-	ID        int    `json:"id"`
-	Name      string `json:"name"`
-	PixelID   string `json:"pixel_id"`
-	PixelType string `json:"pixel_type"`
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	PixelID   string    `json:"pixel_id"`
+	PixelType PixelType `json:"pixel_type"`
 }
 
 // CreatePixel calls the API to create a new pixel.
 func (c *Client) CreatePixel(reqData PixelCreateRequest) (*Pixel, error) {
+	return c.createPixel(context.Background(), reqData)
+}
+
+// createPixel is CreatePixel with a context.
+func (c *Client) createPixel(ctx context.Context, reqData PixelCreateRequest) (*Pixel, error) {
+	if err := validatePixel(reqData.Name, reqData.PixelID, reqData.PixelType); err != nil {
+		return nil, err
+	}
 	var pixel Pixel
-	err := c.doRequest("POST", "/api/v1/link/pixel", "", reqData, &pixel)
+	err := c.doRequestContext(ctx, "POST", "/api/v1/link/pixel", "", reqData, &pixel)
 	if err != nil {
 		return nil, err
 	}
@@ -113,9 +205,14 @@ func (c *Client) ListPixels() ([]Pixel, error) {
 
 // GetPixel retrieves a pixel by its ID.
 func (c *Client) GetPixel(id int) (*Pixel, error) {
+	return c.getPixel(context.Background(), id)
+}
+
+// getPixel is GetPixel with a context.
+func (c *Client) getPixel(ctx context.Context, id int) (*Pixel, error) {
 	path := fmt.Sprintf("/api/v1/link/pixel/%d", id)
 	var pixel Pixel
-	err := c.doRequest("GET", path, "", nil, &pixel)
+	err := c.doRequestContext(ctx, "GET", path, "", nil, &pixel)
 	if err != nil {
 		return nil, err
 	}
@@ -124,9 +221,17 @@ func (c *Client) GetPixel(id int) (*Pixel, error) {
 
 // UpdatePixel updates an existing pixel.
 func (c *Client) UpdatePixel(reqData PixelUpdateRequest) (*Pixel, error) {
+	return c.updatePixel(context.Background(), reqData)
+}
+
+// updatePixel is UpdatePixel with a context.
+func (c *Client) updatePixel(ctx context.Context, reqData PixelUpdateRequest) (*Pixel, error) {
+	if err := validatePixel(reqData.Name, reqData.PixelID, reqData.PixelType); err != nil {
+		return nil, err
+	}
 	path := fmt.Sprintf("/api/v1/link/pixel/%d", reqData.ID)
 	var pixel Pixel
-	err := c.doRequest("PUT", path, "", reqData, &pixel)
+	err := c.doRequestContext(ctx, "PUT", path, "", reqData, &pixel)
 	if err != nil {
 		return nil, err
 	}
@@ -135,8 +240,19 @@ func (c *Client) UpdatePixel(reqData PixelUpdateRequest) (*Pixel, error) {
 
 // DeletePixel deletes a pixel by its ID.
 func (c *Client) DeletePixel(id int) error {
+	return c.deletePixel(context.Background(), id)
+}
+
+// deletePixel is DeletePixel with a context.
+func (c *Client) deletePixel(ctx context.Context, id int) error {
 	path := fmt.Sprintf("/api/v1/link/pixel/%d", id)
-	return c.doRequest("DELETE", path, "", nil, nil)
+	if err := c.doRequestContext(ctx, "DELETE", path, "", nil, nil); err != nil {
+		return err
+	}
+	if c.pixels != nil {
+		c.pixels.Remove(id)
+	}
+	return nil
 }
 
 // =====================
@@ -153,9 +269,11 @@ type ShortLink struct {
 	ExpireAtViews    interface{} `json:"expire_at_views"`
 	ExpireAtDatetime interface{} `json:"expire_at_datetime"`
 	PublicStats      bool        `json:"public_stats"`
-	CreatedAt        string      `json:"created_at"`
-	UpdatedAt        string      `json:"updated_at"`
+	CreatedAt        Timestamp   `json:"created_at"`
+	UpdatedAt        Timestamp   `json:"updated_at"`
 	Meta             interface{} `json:"meta"`
+	Tags             []Tag       `json:"tags,omitempty"`
+	Pixels           []Pixel     `json:"pixels,omitempty"`
 }
 
 // ShortLinkCreateRequest is used to create a short link.
@@ -190,8 +308,16 @@ type ShortLinkUpdateRequest struct {
 
 // CreateShortLink creates a new short link.
 func (c *Client) CreateShortLink(reqData ShortLinkCreateRequest) (*ShortLink, error) {
+	return c.createShortLink(context.Background(), reqData)
+}
+
+// createShortLink is CreateShortLink with a context.
+func (c *Client) createShortLink(ctx context.Context, reqData ShortLinkCreateRequest) (*ShortLink, error) {
+	if reqData.Domain == "" {
+		reqData.Domain = c.domain
+	}
 	var link ShortLink
-	err := c.doRequest("POST", "/api/v1/link/shorten", "", reqData, &link)
+	err := c.doRequestContext(ctx, "POST", "/api/v1/link/shorten", "", reqData, &link)
 	if err != nil {
 		return nil, err
 	}
@@ -202,7 +328,7 @@ func (c *Client) CreateShortLink(reqData ShortLinkCreateRequest) (*ShortLink, er
 func (c *Client) GetShortLink(shortURL string) (*ShortLink, error) {
 	query := "short_url=" + shortURL
 	var link ShortLink
-	err := c.doRequest("GET", "/api/v1/link", query, nil, &link)
+	err := c.cachedGet(context.Background(), shortURL, "/api/v1/link", query, &link)
 	if err != nil {
 		return nil, err
 	}
@@ -211,20 +337,40 @@ func (c *Client) GetShortLink(shortURL string) (*ShortLink, error) {
 
 // UpdateShortLink updates an existing short link.
 func (c *Client) UpdateShortLink(reqData ShortLinkUpdateRequest) (*ShortLink, error) {
+	return c.updateShortLink(context.Background(), reqData)
+}
+
+// updateShortLink is UpdateShortLink with a context.
+func (c *Client) updateShortLink(ctx context.Context, reqData ShortLinkUpdateRequest) (*ShortLink, error) {
+	body, err := updateBody(reqData)
+	if err != nil {
+		return nil, err
+	}
 	var link ShortLink
-	err := c.doRequest("PUT", "/api/v1/link", "", reqData, &link)
+	err = c.doRequestContext(ctx, "PUT", "/api/v1/link", "", body, &link)
 	if err != nil {
 		return nil, err
 	}
+	c.Invalidate(reqData.ShortURL)
+	c.Invalidate(link.ShortURL)
 	return &link, nil
 }
 
 // DeleteShortLink deletes a short link.
 func (c *Client) DeleteShortLink(shortURL string) error {
+	return c.deleteShortLink(context.Background(), shortURL)
+}
+
+// deleteShortLink is DeleteShortLink with a context.
+func (c *Client) deleteShortLink(ctx context.Context, shortURL string) error {
 	reqBody := map[string]string{
 		"short_url": shortURL,
 	}
-	return c.doRequest("DELETE", "/api/v1/link", "", reqBody, nil)
+	if err := c.doRequestContext(ctx, "DELETE", "/api/v1/link", "", reqBody, nil); err != nil {
+		return err
+	}
+	c.Invalidate(shortURL)
+	return nil
 }
 
 // ExpandRequest is used to expand a short link.
@@ -244,6 +390,9 @@ func (c *Client) ExpandShortLink(reqData ExpandRequest) (*ExpandResponse, error)
 	var resp ExpandResponse
 	err := c.doRequest("POST", "/api/v1/link/expand", "", reqData, &resp)
 	if err != nil {
+		if reqData.Password != nil {
+			err = redactPassword(err, Password(*reqData.Password))
+		}
 		return nil, err
 	}
 	return &resp, nil
@@ -270,6 +419,94 @@ func (c *Client) ListShortLinks(queryParams map[string]string) (string, error) {
 	return result, nil
 }
 
+// ShortLinkListOptions filters the short link list endpoint.
+// Zero values are omitted from the query.
+type ShortLinkListOptions struct {
+	Search   string
+	TagIDs   []int
+	PixelIDs []int
+	// CampaignID limits the list to the links of one campaign.
+	CampaignID int
+	StartDate  time.Time
+	EndDate    time.Time
+	Page       int
+}
+
+// values encodes the options as query parameters.
+func (o ShortLinkListOptions) values() url.Values {
+	v := url.Values{}
+	if o.Search != "" {
+		v.Set("search", o.Search)
+	}
+	for _, id := range o.TagIDs {
+		v.Add("tag_ids[]", strconv.Itoa(id))
+	}
+	for _, id := range o.PixelIDs {
+		v.Add("pixel_ids[]", strconv.Itoa(id))
+	}
+	if o.CampaignID != 0 {
+		v.Set("campaign_id", strconv.Itoa(o.CampaignID))
+	}
+	if !o.StartDate.IsZero() {
+		v.Set("start_date", o.StartDate.Format(dateLayout))
+	}
+	if !o.EndDate.IsZero() {
+		v.Set("end_date", o.EndDate.Format(dateLayout))
+	}
+	if o.Page > 0 {
+		v.Set("page", strconv.Itoa(o.Page))
+	}
+	return v
+}
+
+// ShortLinkPage is a single page of the short link list.
+type ShortLinkPage struct {
+	CurrentPage int         `json:"current_page"`
+	LastPage    int         `json:"last_page"`
+	PerPage     int         `json:"per_page"`
+	Total       int         `json:"total"`
+	Data        []ShortLink `json:"data"`
+}
+
+// count returns the number of links matching the page's filter: the
+// reported total, or the page length when the API leaves it out.
+func (p *ShortLinkPage) count() int {
+	if p.Total > 0 {
+		return p.Total
+	}
+	return len(p.Data)
+}
+
+// ListShortLinksPage retrieves one page of short links as typed values.
+func (c *Client) ListShortLinksPage(ctx context.Context, opts ShortLinkListOptions) (*ShortLinkPage, error) {
+	var page ShortLinkPage
+	err := c.doRequestContext(ctx, "GET", "/api/v1/link/list", opts.values().Encode(), nil, &page)
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// ListAllShortLinks follows pagination from opts.Page (or the first page)
+// until the last page and returns every matching short link.
+func (c *Client) ListAllShortLinks(ctx context.Context, opts ShortLinkListOptions) ([]ShortLink, error) {
+	if opts.Page < 1 {
+		opts.Page = 1
+	}
+	var links []ShortLink
+	for {
+		page, err := c.ListShortLinksPage(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, page.Data...)
+		if len(page.Data) == 0 || page.CurrentPage >= page.LastPage {
+			return links, nil
+		}
+		opts.Page = page.CurrentPage + 1
+	}
+}
+
 // BulkShortenRequest is used for bulk shortening of links.
 type BulkShortenRequest struct {
 	Domain string   `json:"domain"`
@@ -280,6 +517,9 @@ type BulkShortenRequest struct {
 
 // BulkShortenLinks sends a bulk shorten request.
 func (c *Client) BulkShortenLinks(reqData BulkShortenRequest) (string, error) {
+	if reqData.Domain == "" {
+		reqData.Domain = c.domain
+	}
 	var result string
 	err := c.doRequest("POST", "/api/v1/link/bulk", "", reqData, &result)
 	if err != nil {
@@ -298,33 +538,138 @@ type Stats struct {
 	UniqueClicks int                    `json:"unique_clicks"`
 	Browsers     []interface{}          `json:"browsers"`
 	Countries    []interface{}          `json:"countries"`
+	Regions      []interface{}          `json:"regions,omitempty"`
 	Referrers    []interface{}          `json:"referrers"`
 	Platforms    []interface{}          `json:"platforms"`
 	DailyClicks  []interface{}          `json:"daily_clicks"`
+	HourlyClicks []interface{}          `json:"hourly_clicks,omitempty"`
+	Conversions  []interface{}          `json:"pixel_conversions,omitempty"`
 	Data         map[string]interface{} `json:"data"`
+
+	// BotClicks and HumanClicks split Clicks when the payload's Data map
+	// reports bot traffic, which HasBotData indicates.
+	BotClicks   int  `json:"-"`
+	HumanClicks int  `json:"-"`
+	HasBotData  bool `json:"-"`
+}
+
+// dateLayout is the date format used by the API's date filters.
+const dateLayout = "2006-01-02"
+
+// StatsOptions narrows a stats request. Zero values are omitted.
+type StatsOptions struct {
+	StartDate time.Time
+	EndDate   time.Time
+	// Granularity requests a finer series than the default daily one.
+	// Only GranularityHour is sent to the API; weekly and monthly series
+	// are derived client-side with Stats.Buckets.
+	Granularity Granularity
+	// ExcludeBots reduces Clicks to HumanClicks when the payload reports
+	// bot traffic. Breakdowns and series are not split by the API and are
+	// left unchanged.
+	ExcludeBots bool
+	// NotFoundRetry keeps retrying a 404 from the stats endpoint for up to
+	// this long. Freshly created links can 404 for a few seconds.
+	NotFoundRetry time.Duration
+	// NotFoundAsEmpty returns empty Stats instead of an error when the
+	// endpoint still answers 404 after any retries.
+	NotFoundAsEmpty bool
+	// Password unlocks the stats of a password-protected link. The stats
+	// are then requested with a POST carrying the password and the other
+	// parameters in its JSON body rather than the URL, which servers and
+	// proxies log. The password is scrubbed from returned errors, and such
+	// responses are not cached.
+	Password Password
+}
+
+// values encodes the options as query parameters for shortURL.
+func (o StatsOptions) values(shortURL string) url.Values {
+	v := url.Values{}
+	v.Set("short_url", shortURL)
+	if !o.StartDate.IsZero() {
+		v.Set("start_date", o.StartDate.Format(dateLayout))
+	}
+	if !o.EndDate.IsZero() {
+		v.Set("end_date", o.EndDate.Format(dateLayout))
+	}
+	if o.Granularity == GranularityHour {
+		v.Set("granularity", string(o.Granularity))
+	}
+	return v
+}
+
+// passwordBody encodes the options and the password as the JSON body of a
+// stats POST for shortURL.
+func (o StatsOptions) passwordBody(shortURL string) map[string]string {
+	body := map[string]string{"password": string(o.Password)}
+	for k, v := range o.values(shortURL) {
+		body[k] = v[0]
+	}
+	return body
 }
 
 // GetStats retrieves statistics for a given short link.
 func (c *Client) GetStats(shortURL string) (*Stats, error) {
 	query := "short_url=" + shortURL
 	var stats Stats
-	err := c.doRequest("GET", "/api/v1/link/stats", query, nil, &stats)
+	err := c.cachedGet(context.Background(), shortURL, "/api/v1/link/stats", query, &stats)
 	if err != nil {
 		return nil, err
 	}
 	return &stats, nil
 }
 
+// GetStatsWithOptions retrieves statistics for a short link within the
+// period described by opts.
+func (c *Client) GetStatsWithOptions(ctx context.Context, shortURL string, opts StatsOptions) (*Stats, error) {
+	var stats Stats
+	query := opts.values(shortURL).Encode()
+	deadline := time.Now().Add(opts.NotFoundRetry)
+	wait := 500 * time.Millisecond
+	for {
+		var err error
+		if opts.Password != "" {
+			err = c.doRequestContext(ctx, "POST", "/api/v1/link/stats", "", opts.passwordBody(shortURL), &stats)
+		} else {
+			err = c.cachedGet(ctx, shortURL, "/api/v1/link/stats", query, &stats)
+		}
+		if err == nil {
+			break
+		}
+		if !IsNotFound(err) {
+			return nil, redactPassword(err, opts.Password)
+		}
+		if time.Now().Add(wait).After(deadline) {
+			if opts.NotFoundAsEmpty {
+				return &Stats{}, nil
+			}
+			return nil, redactPassword(err, opts.Password)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		if wait < 2*time.Second {
+			wait *= 2
+		}
+	}
+	if opts.ExcludeBots && stats.HasBotData {
+		stats.Clicks = stats.HumanClicks
+	}
+	return &stats, nil
+}
+
 // =====================
 // Tag Management
 // =====================
 
 // Tag represents a tag.
 type Tag struct {
-	ID        int    `json:"id"`
-	Tag       string `json:"tag"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID        int       `json:"id"`
+	Tag       string    `json:"tag"`
+	CreatedAt Timestamp `json:"created_at"`
+	UpdatedAt Timestamp `json:"updated_at"`
 }
 
 // ListTags retrieves all tags.
@@ -339,6 +684,9 @@ func (c *Client) ListTags() ([]Tag, error) {
 
 // CreateTag creates a new tag.
 func (c *Client) CreateTag(tagValue string) (*Tag, error) {
+	if err := ValidateTagName(tagValue); err != nil {
+		return nil, err
+	}
 	reqBody := map[string]string{
 		"tag": tagValue,
 	}
@@ -363,20 +711,45 @@ func (c *Client) GetTag(id int) (*Tag, error) {
 
 // UpdateTag updates an existing tag.
 func (c *Client) UpdateTag(id int, tagValue string) (*Tag, error) {
+	return c.updateTag(context.Background(), id, tagValue)
+}
+
+// updateTag is UpdateTag with a context.
+func (c *Client) updateTag(ctx context.Context, id int, tagValue string) (*Tag, error) {
+	if err := ValidateTagName(tagValue); err != nil {
+		return nil, err
+	}
 	path := fmt.Sprintf("/api/v1/link/tag/%d", id)
 	reqBody := map[string]string{
 		"tag": tagValue,
 	}
 	var tag Tag
-	err := c.doRequest("PUT", path, "", reqBody, &tag)
+	err := c.doRequestContext(ctx, "PUT", path, "", reqBody, &tag)
 	if err != nil {
 		return nil, err
 	}
+	if c.tags != nil {
+		c.tags.Add(tag)
+	}
 	return &tag, nil
 }
 
 // DeleteTag deletes a tag by its ID.
 func (c *Client) DeleteTag(id int) error {
+	return c.deleteTag(context.Background(), id)
+}
+
+// deleteTag is DeleteTag with a context.
+func (c *Client) deleteTag(ctx context.Context, id int) error {
 	path := fmt.Sprintf("/api/v1/link/tag/%d", id)
-	return c.doRequest("DELETE", path, "", nil, nil)
+	if err := c.doRequestContext(ctx, "DELETE", path, "", nil, nil); err != nil {
+		return err
+	}
+	if c.tags != nil {
+		c.tags.Remove(id)
+	}
+	if c.tagMetadata != nil {
+		return c.tagMetadata.Delete(ctx, id)
+	}
+	return nil
 }
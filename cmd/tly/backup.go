@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+func init() {
+	register(&command{name: "export", args: "--all", summary: "Back up every link, tag and pixel as NDJSON.", run: runExport})
+	register(&command{name: "restore", args: "<backup.ndjson>", summary: "Recreate the links, tags and pixels missing from a backup.", run: runRestore})
+}
+
+func runExport(e *env, args []string) error {
+	fs := e.flagSet()
+	all := fs.Bool("all", false, "export links, tags and pixels (required)")
+	output := fs.String("o", "", `backup file, or "-" for stdout (default stdout)`)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return usagef("unexpected arguments")
+	}
+	if !*all {
+		return usagef("--all is required; partial exports are not supported")
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	if *output == "" || *output == "-" {
+		counts, err := c.ExportAccount(e.ctx, e.stdout)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stderr, "exported %d links, %d tags and %d pixels\n", counts.Links, counts.Tags, counts.Pixels)
+		return nil
+	}
+	counts, err := exportToFile(e, c, *output)
+	if err != nil {
+		return err
+	}
+	return e.output(counts, func(w io.Writer) error {
+		return fields(w,
+			"File", *output,
+			"Links", strconv.Itoa(counts.Links),
+			"Tags", strconv.Itoa(counts.Tags),
+			"Pixels", strconv.Itoa(counts.Pixels),
+		)
+	})
+}
+
+// exportToFile writes the backup to a temporary file next to name and
+// renames it into place once complete, so an interrupted run never leaves
+// a truncated backup behind. The file is readable only by its owner.
+func exportToFile(e *env, c *tly.Client, name string) (*tly.BackupCounts, error) {
+	f, err := ioutil.TempFile(filepath.Dir(name), "."+filepath.Base(name)+".*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	counts, err := c.ExportAccount(e.ctx, f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	return counts, os.Rename(f.Name(), name)
+}
+
+func runRestore(e *env, args []string) error {
+	fs := e.flagSet()
+	dryRun := fs.Bool("dry-run", false, "report what would be created without creating anything")
+	verbose := fs.Bool("v", false, "print every record as it is restored")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	input, err := oneArg(args, `backup file (or "-" for stdin)`)
+	if err != nil {
+		return err
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	in := e.stdin
+	if input != "-" {
+		f, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	opts := tly.RestoreOptions{DryRun: *dryRun}
+	if *verbose {
+		opts.Progress = func(kind, name string, outcome tly.RestoreOutcome) {
+			fmt.Fprintf(e.stderr, "%-8s %-5s %s\n", outcome, kind, name)
+		}
+	}
+	report, err := c.RestoreAccount(e.ctx, in, opts)
+	if err != nil {
+		return err
+	}
+	for _, f := range report.Failed {
+		fmt.Fprintf(e.stderr, "failed: %s %s: %v\n", f.Kind, f.Name, f.Err)
+	}
+	err = e.output(report, func(w io.Writer) error {
+		verb := "Created"
+		if report.DryRun {
+			verb = "Would create"
+		}
+		return fields(w,
+			verb, backupCounts(report.Created),
+			"Already present", backupCounts(report.Existing),
+			"Failed", strconv.Itoa(len(report.Failed)),
+		)
+	})
+	if err != nil {
+		return err
+	}
+	if len(report.Failed) > 0 {
+		return fmt.Errorf("%d records could not be restored", len(report.Failed))
+	}
+	return nil
+}
+
+// backupCounts describes counts as "3 links, 2 tags, 1 pixels".
+func backupCounts(c tly.BackupCounts) string {
+	return fmt.Sprintf("%d links, %d tags, %d pixels", c.Links, c.Tags, c.Pixels)
+}
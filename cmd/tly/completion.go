@@ -0,0 +1,187 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+func init() {
+	register(&command{name: "completion", args: "bash|zsh|fish", summary: "Print a shell completion script.", run: runCompletion})
+}
+
+// completionFlag is a flag offered by shell completion.
+type completionFlag struct {
+	name  string
+	usage string
+	bool  bool
+}
+
+// completionCommand is a command with its flags, for shell completion.
+type completionCommand struct {
+	name    string
+	summary string
+	flags   []completionFlag
+}
+
+// completionCommands collects every command and its flags by asking each
+// one for its help, which parses no further and touches nothing else.
+func completionCommands(e *env) []completionCommand {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var cmds []completionCommand
+	for _, name := range names {
+		cmd := commands[name]
+		probe := &env{ctx: e.ctx, stdin: strings.NewReader(""), stdout: ioutil.Discard, stderr: ioutil.Discard, getenv: e.getenv, cmd: cmd}
+		var fs *flag.FlagSet
+		probe.onFlagSet = func(f *flag.FlagSet) {
+			if fs == nil {
+				fs = f
+			}
+		}
+		cmd.run(probe, []string{"-help"})
+		cc := completionCommand{name: name, summary: cmd.summary}
+		if fs != nil {
+			fs.VisitAll(func(f *flag.Flag) {
+				b, ok := f.Value.(interface{ IsBoolFlag() bool })
+				cc.flags = append(cc.flags, completionFlag{name: f.Name, usage: f.Usage, bool: ok && b.IsBoolFlag()})
+			})
+		}
+		cmds = append(cmds, cc)
+	}
+	return cmds
+}
+
+func runCompletion(e *env, args []string) error {
+	fs := e.flagSet()
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	shell, err := oneArg(args, "shell")
+	if err != nil {
+		return err
+	}
+	cmds := completionCommands(e)
+	switch shell {
+	case "bash":
+		return bashCompletion(e.stdout, cmds)
+	case "zsh":
+		return zshCompletion(e.stdout, cmds)
+	case "fish":
+		return fishCompletion(e.stdout, cmds)
+	}
+	return usagef("unsupported shell %q: want bash, zsh or fish", shell)
+}
+
+func bashCompletion(w io.Writer, cmds []completionCommand) error {
+	names := make([]string, len(cmds))
+	for i, c := range cmds {
+		names[i] = c.name
+	}
+	fmt.Fprintf(w, `# bash completion for tly. Load with:
+#   source <(tly completion bash)
+_tly() {
+	local cur=${COMP_WORDS[COMP_CWORD]}
+	if [ "$COMP_CWORD" -eq 1 ]; then
+		COMPREPLY=($(compgen -W "help %s" -- "$cur"))
+		return
+	fi
+	if [ "${COMP_WORDS[1]}" = help ]; then
+		COMPREPLY=($(compgen -W "%s" -- "$cur"))
+		return
+	fi
+	[[ $cur == -* ]] || return
+	local flags
+	case ${COMP_WORDS[1]} in
+`, strings.Join(names, " "), strings.Join(names, " "))
+	for _, c := range cmds {
+		flags := make([]string, len(c.flags))
+		for i, f := range c.flags {
+			flags[i] = "--" + f.name
+		}
+		fmt.Fprintf(w, "\t%s) flags=%q ;;\n", c.name, strings.Join(flags, " "))
+	}
+	_, err := fmt.Fprint(w, `	esac
+	COMPREPLY=($(compgen -W "$flags" -- "$cur"))
+}
+complete -o default -F _tly tly
+`)
+	return err
+}
+
+// zshQuote escapes s for a single-quoted zsh word inside _arguments or
+// _describe specs.
+func zshQuote(s string) string {
+	s = strings.NewReplacer("'", `'\''`, "[", `\[`, "]", `\]`, ":", `\:`).Replace(s)
+	return s
+}
+
+func zshCompletion(w io.Writer, cmds []completionCommand) error {
+	fmt.Fprint(w, `#compdef tly
+# zsh completion for tly. Load with:
+#   source <(tly completion zsh)
+_tly() {
+	local -a cmds
+	cmds=(
+`)
+	for _, c := range cmds {
+		fmt.Fprintf(w, "\t\t'%s:%s'\n", c.name, zshQuote(c.summary))
+	}
+	fmt.Fprint(w, `	)
+	if (( CURRENT == 2 )); then
+		_describe 'command' cmds
+		return
+	fi
+	case $words[2] in
+	help)
+		_describe 'command' cmds ;;
+`)
+	for _, c := range cmds {
+		fmt.Fprintf(w, "\t%s)\n\t\t_arguments \\\n", c.name)
+		for _, f := range c.flags {
+			spec := fmt.Sprintf("--%s[%s]", f.name, zshQuote(f.usage))
+			if !f.bool {
+				spec += ":value:"
+			}
+			fmt.Fprintf(w, "\t\t\t'%s' \\\n", spec)
+		}
+		fmt.Fprint(w, "\t\t\t'*:file:_files' ;;\n")
+	}
+	_, err := fmt.Fprint(w, `	esac
+}
+compdef _tly tly
+`)
+	return err
+}
+
+// fishQuote escapes s for a single-quoted fish string.
+func fishQuote(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s)
+}
+
+func fishCompletion(w io.Writer, cmds []completionCommand) error {
+	fmt.Fprint(w, `# fish completion for tly. Load with:
+#   tly completion fish | source
+complete -c tly -f -n __fish_use_subcommand -a help -d 'Show help for a command'
+`)
+	for _, c := range cmds {
+		fmt.Fprintf(w, "complete -c tly -f -n __fish_use_subcommand -a %s -d '%s'\n", c.name, fishQuote(c.summary))
+	}
+	for _, c := range cmds {
+		for _, f := range c.flags {
+			req := " -r"
+			if f.bool {
+				req = ""
+			}
+			fmt.Fprintf(w, "complete -c tly -n '__fish_seen_subcommand_from %s' -l %s%s -d '%s'\n", c.name, f.name, req, fishQuote(f.usage))
+		}
+	}
+	return nil
+}
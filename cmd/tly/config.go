@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+func init() {
+	register(&command{name: "profiles", summary: "List the profiles in the config file.", run: runProfiles})
+}
+
+// profile is a named set of defaults from the config file.
+type profile struct {
+	Name    string   `json:"name"`
+	APIKey  string   `json:"-"`
+	Domain  string   `json:"domain,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	BaseURL string   `json:"base_url,omitempty"`
+}
+
+// config is the parsed config file:
+//
+//	default_profile: work
+//	profiles:
+//	  work:
+//	    api_key: ...
+//	    domain: go.acme.com
+//	    tags: [q3, email]
+//	  personal:
+//	    api_key: ...
+type config struct {
+	DefaultProfile string
+	Profiles       map[string]profile
+}
+
+// configPath returns the config file location: $TLY_CONFIG, or
+// tly/config.yaml under $XDG_CONFIG_HOME or ~/.config.
+func configPath(getenv func(string) string) string {
+	if p := getenv("TLY_CONFIG"); p != "" {
+		return p
+	}
+	dir := getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "tly", "config.yaml")
+}
+
+// loadConfig reads the config file at path. A missing file is an empty
+// config.
+func loadConfig(path string) (*config, error) {
+	cfg := &config{Profiles: map[string]profile{}}
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parseYAML(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if v, ok := doc["default_profile"].(string); ok {
+		cfg.DefaultProfile = v
+	}
+	profiles, ok := doc["profiles"].(map[string]interface{})
+	if !ok && doc["profiles"] != nil && doc["profiles"] != "" {
+		return nil, fmt.Errorf("%s: profiles must be a mapping", path)
+	}
+	for name, v := range profiles {
+		fields, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: profile %q must be a mapping", path, name)
+		}
+		p := profile{Name: name}
+		p.APIKey, _ = fields["api_key"].(string)
+		p.Domain, _ = fields["domain"].(string)
+		p.BaseURL, _ = fields["base_url"].(string)
+		switch tags := fields["tags"].(type) {
+		case []interface{}:
+			for _, t := range tags {
+				p.Tags = append(p.Tags, fmt.Sprint(t))
+			}
+		case string:
+			p.Tags = splitList(tags)
+		}
+		cfg.Profiles[name] = p
+	}
+	return cfg, nil
+}
+
+// names returns the profile names in order.
+func (c *config) names() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// selectProfile picks the profile named by --profile or $TLY_PROFILE,
+// falling back to default_profile and then to a profile named "default".
+// ok is false when no profile applies.
+func (c *config) selectProfile(name string) (p profile, ok bool, err error) {
+	if name != "" {
+		p, ok := c.Profiles[name]
+		if !ok {
+			return profile{}, false, fmt.Errorf("no profile %q in the config file (have: %s)", name, strings.Join(c.names(), ", "))
+		}
+		return p, true, nil
+	}
+	if c.DefaultProfile != "" {
+		p, ok := c.Profiles[c.DefaultProfile]
+		if !ok {
+			return profile{}, false, fmt.Errorf("default_profile %q is not defined", c.DefaultProfile)
+		}
+		return p, true, nil
+	}
+	p, ok = c.Profiles["default"]
+	return p, ok, nil
+}
+
+func runProfiles(e *env, args []string) error {
+	fs := e.flagSet()
+	e.defineFormat(fs)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return usagef("unexpected arguments")
+	}
+	path := configPath(e.getenv)
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+	ok, err := e.loadProfile()
+	if err != nil {
+		return err
+	}
+	profiles := make([]profile, 0, len(cfg.Profiles))
+	for _, name := range cfg.names() {
+		profiles = append(profiles, cfg.Profiles[name])
+	}
+	return e.output(profiles, func(w io.Writer) error {
+		if len(profiles) == 0 {
+			_, err := fmt.Fprintf(w, "No profiles in %s\n", path)
+			return err
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "\tPROFILE\tDOMAIN\tTAGS")
+		for _, p := range profiles {
+			mark := ""
+			if ok && p.Name == e.profile.Name {
+				mark = "*"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", mark, p.Name, p.Domain, strings.Join(p.Tags, ","))
+		}
+		return tw.Flush()
+	})
+}
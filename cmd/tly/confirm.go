@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// safety holds the --dry-run and --yes flags of commands that delete or
+// change resources.
+type safety struct {
+	dryRun bool
+	yes    bool
+}
+
+func (s *safety) define(fs *flag.FlagSet) {
+	fs.BoolVar(&s.dryRun, "dry-run", false, "print what would be affected without changing anything")
+	fs.BoolVar(&s.yes, "yes", false, "do not ask for confirmation")
+}
+
+// errAborted is returned when the user declines a confirmation.
+var errAborted = errors.New("aborted")
+
+// dryRunPlan is the JSON output of a dry run.
+type dryRunPlan struct {
+	DryRun bool     `json:"dry_run"`
+	Action string   `json:"action"`
+	Items  []string `json:"items"`
+}
+
+// confirm lists the items an action affects, such as "delete 3 links",
+// and reports whether to go ahead. A dry run prints the list as the
+// command's output and returns false. Otherwise the list is printed to
+// stderr and, unless --yes was given, the user is asked to confirm on the
+// terminal; declining returns errAborted. Without a terminal to ask on,
+// --yes is required.
+func (e *env) confirm(s safety, action string, items []string) (bool, error) {
+	if s.dryRun {
+		return false, e.output(dryRunPlan{DryRun: true, Action: action, Items: items}, func(w io.Writer) error {
+			fmt.Fprintf(w, "Would %s:\n", action)
+			for _, item := range items {
+				fmt.Fprintf(w, "  %s\n", item)
+			}
+			return nil
+		})
+	}
+	fmt.Fprintf(e.stderr, "About to %s:\n", action)
+	for _, item := range items {
+		fmt.Fprintf(e.stderr, "  %s\n", item)
+	}
+	if s.yes {
+		return true, nil
+	}
+	if e.stdin != os.Stdin || !stdinIsTerminal() {
+		return false, fmt.Errorf("refusing to %s without confirmation; pass --yes, or --dry-run to preview", action)
+	}
+	p := &prompter{in: bufio.NewScanner(e.stdin), out: e.stderr}
+	answer, _ := p.ask("Proceed? [y/N] ")
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, errAborted
+}
+
+// plural returns "n noun", adding an s to noun unless n is 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,434 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+func init() {
+	register(&command{name: "dashboard", summary: "Browse links with live click counts in a terminal UI.", run: runDashboard})
+}
+
+// dashRow is one link on the dashboard.
+type dashRow struct {
+	link   tly.ShortLink
+	clicks int
+	// gained is the clicks received since the dashboard started.
+	gained int
+	loaded bool
+	err    error
+}
+
+// dashPrompt is a line being typed at the bottom of the dashboard.
+type dashPrompt struct {
+	label string
+	text  string
+	// live is called on every change, done when Enter is pressed.
+	live func(text string)
+	done func(text string)
+}
+
+type dashboard struct {
+	e        *env
+	c        *tly.Client
+	opts     tly.ShortLinkListOptions
+	tag      string
+	domain   string
+	limit    int
+	interval time.Duration
+
+	rows    []*dashRow
+	byURL   map[string]*dashRow
+	view    []*dashRow
+	cursor  int
+	offset  int
+	filter  string
+	prompt  *dashPrompt
+	status  string
+	updated time.Time
+
+	updates   <-chan tly.StatsUpdate
+	stopWatch context.CancelFunc
+
+	rowsOnScreen, cols int
+	sized              time.Time
+}
+
+func runDashboard(e *env, args []string) error {
+	fs := e.flagSet()
+	tag := fs.String("tag", "", "only show links with this tag")
+	domain := fs.String("domain", "", "only show links on this domain")
+	limit := fs.Int("limit", 50, "maximum number of links to watch")
+	interval := fs.Duration("interval", 30*time.Second, "time between click count refreshes")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return usagef("unexpected arguments")
+	}
+	if !isTerminal(e.stdout) {
+		return errors.New("the dashboard needs a terminal")
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	d := &dashboard{e: e, c: c, tag: *tag, domain: *domain, limit: *limit, interval: *interval}
+	if err := d.load(); err != nil {
+		return err
+	}
+	defer d.stopWatch()
+
+	restore, err := rawTerminal()
+	if err != nil {
+		return err
+	}
+	defer restore()
+	fmt.Fprint(e.stdout, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(e.stdout, "\x1b[?25h\x1b[?1049l")
+
+	keys := make(chan string)
+	go readKeys(e.stdin, keys)
+	for {
+		d.draw()
+		select {
+		case <-e.ctx.Done():
+			return nil
+		case key, ok := <-keys:
+			if !ok || d.handleKey(key) {
+				return nil
+			}
+		case u, ok := <-d.updates:
+			if !ok {
+				d.updates = nil
+				continue
+			}
+			d.apply(u)
+		}
+	}
+}
+
+// load lists the links and (re)starts watching their stats.
+func (d *dashboard) load() error {
+	if d.stopWatch != nil {
+		d.stopWatch()
+	}
+	var links []tly.ShortLink
+	var err error
+	if d.tag != "" {
+		links, err = d.c.ListShortLinksByTag(d.e.ctx, tly.TagByName(d.tag), d.opts)
+	} else {
+		links, err = d.c.ListAllShortLinks(d.e.ctx, d.opts)
+	}
+	if err != nil {
+		return err
+	}
+	d.rows = d.rows[:0]
+	d.byURL = map[string]*dashRow{}
+	var urls []string
+	for _, l := range links {
+		if d.domain != "" && !onDomain(l, d.domain) {
+			continue
+		}
+		if len(d.rows) == d.limit {
+			break
+		}
+		row := &dashRow{link: l}
+		d.rows = append(d.rows, row)
+		d.byURL[l.ShortURL] = row
+		urls = append(urls, l.ShortURL)
+	}
+	ctx, cancel := context.WithCancel(d.e.ctx)
+	d.stopWatch = cancel
+	d.updates = d.c.WatchStats(ctx, urls, tly.WatchOptions{Interval: d.interval})
+	d.refilter()
+	return nil
+}
+
+// onDomain reports whether the link is on domain.
+func onDomain(l tly.ShortLink, domain string) bool {
+	host := l.Domain
+	if u, err := url.Parse(l.ShortURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	return strings.EqualFold(strings.TrimSuffix(host, "/"), domain)
+}
+
+// apply records a stats update.
+func (d *dashboard) apply(u tly.StatsUpdate) {
+	row, ok := d.byURL[u.ShortURL]
+	if !ok {
+		return
+	}
+	row.err = u.Err
+	if u.Err != nil {
+		return
+	}
+	row.clicks = u.Stats.Clicks
+	if !u.Delta.Initial {
+		row.gained += u.Delta.Clicks
+	}
+	row.loaded = true
+	d.updated = u.At
+}
+
+// refilter recomputes the visible rows from the filter text.
+func (d *dashboard) refilter() {
+	d.view = d.view[:0]
+	if d.filter == "" {
+		d.view = append(d.view, d.rows...)
+	} else {
+		links := make([]tly.ShortLink, len(d.rows))
+		for i, r := range d.rows {
+			links[i] = r.link
+		}
+		for _, m := range searchLinks(links, d.filter) {
+			d.view = append(d.view, d.byURL[m.link.ShortURL])
+		}
+	}
+	if d.cursor >= len(d.view) {
+		d.cursor = len(d.view) - 1
+	}
+	if d.cursor < 0 {
+		d.cursor = 0
+	}
+}
+
+// selected returns the row under the cursor, or nil.
+func (d *dashboard) selected() *dashRow {
+	if d.cursor < len(d.view) {
+		return d.view[d.cursor]
+	}
+	return nil
+}
+
+// handleKey acts on a key press and reports whether to quit.
+func (d *dashboard) handleKey(key string) bool {
+	if p := d.prompt; p != nil {
+		switch key {
+		case "esc":
+			d.prompt = nil
+			if p.live != nil {
+				p.live("")
+			}
+		case "enter":
+			d.prompt = nil
+			if p.done != nil {
+				p.done(p.text)
+			}
+		case "backspace":
+			if p.text != "" {
+				r := []rune(p.text)
+				p.text = string(r[:len(r)-1])
+			}
+		default:
+			if len([]rune(key)) == 1 {
+				p.text += key
+			}
+		}
+		if d.prompt != nil && p.live != nil {
+			p.live(p.text)
+		}
+		return false
+	}
+	d.status = ""
+	row := d.selected()
+	switch key {
+	case "q", "ctrl-c":
+		return true
+	case "up", "k":
+		if d.cursor > 0 {
+			d.cursor--
+		}
+	case "down", "j":
+		if d.cursor < len(d.view)-1 {
+			d.cursor++
+		}
+	case "/":
+		d.prompt = &dashPrompt{label: "Filter: ", text: d.filter, live: func(text string) {
+			d.filter = text
+			d.refilter()
+		}}
+	case "r":
+		if err := d.load(); err != nil {
+			d.status = "Refresh failed: " + err.Error()
+		} else {
+			d.status = "Reloaded the link list."
+		}
+	case "o":
+		if row != nil {
+			if err := openBrowser(row.link.ShortURL); err != nil {
+				d.status = "Open failed: " + err.Error()
+			}
+		}
+	case "c":
+		if row != nil {
+			// OSC 52 asks the terminal to set the clipboard.
+			fmt.Fprintf(d.e.stdout, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(row.link.ShortURL)))
+			d.status = "Copied " + row.link.ShortURL
+		}
+	case "e":
+		if row != nil {
+			d.prompt = &dashPrompt{label: "Expire at (YYYY-MM-DD HH:MM:SS, empty to clear): ", text: display(row.link.ExpireAtDatetime), done: func(text string) {
+				d.setExpiry(row, strings.TrimSpace(text))
+			}}
+		}
+	}
+	return false
+}
+
+// setExpiry saves a new expiry date for the link of row.
+func (d *dashboard) setExpiry(row *dashRow, expireAt string) {
+	req := tly.ShortLinkUpdateRequest{ShortURL: row.link.ShortURL, LongURL: row.link.LongURL, ExpireAtDatetime: &expireAt}
+	link, err := d.c.UpdateShortLink(req)
+	if err != nil {
+		d.status = "Update failed: " + err.Error()
+		return
+	}
+	row.link.ExpireAtDatetime = link.ExpireAtDatetime
+	if expireAt == "" {
+		d.status = "Cleared the expiry of " + row.link.ShortURL
+	} else {
+		d.status = fmt.Sprintf("%s now expires at %s", row.link.ShortURL, expireAt)
+	}
+}
+
+// draw redraws the whole screen.
+func (d *dashboard) draw() {
+	// Asking for the size runs stty, so do it at most once a second.
+	if time.Since(d.sized) > time.Second {
+		d.rowsOnScreen, d.cols = terminalSize()
+		d.sized = time.Now()
+	}
+	rows, cols := d.rowsOnScreen, d.cols
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	line := func(s string) {
+		if r := []rune(s); len(r) > cols {
+			s = string(r[:cols])
+		}
+		b.WriteString(s + "\r\n")
+	}
+	title := fmt.Sprintf("T.LY dashboard: %d links", len(d.rows))
+	if d.filter != "" {
+		title += fmt.Sprintf(", %d matching %q", len(d.view), d.filter)
+	}
+	if !d.updated.IsZero() {
+		title += ", updated " + d.updated.Format("15:04:05")
+	}
+	line(title)
+	line(fmt.Sprintf("  %-28s %8s %6s  %-19s  %s", "SHORT URL", "CLICKS", "NEW", "EXPIRES", "DESTINATION"))
+	height := rows - 5
+	if height < 1 {
+		height = 1
+	}
+	if d.cursor < d.offset {
+		d.offset = d.cursor
+	}
+	if d.cursor >= d.offset+height {
+		d.offset = d.cursor - height + 1
+	}
+	for i := d.offset; i < len(d.view) && i < d.offset+height; i++ {
+		r := d.view[i]
+		clicks, gained := "…", ""
+		switch {
+		case r.err != nil:
+			clicks = "error"
+		case r.loaded:
+			clicks = fmt.Sprint(r.clicks)
+			if r.gained > 0 {
+				gained = fmt.Sprintf("+%d", r.gained)
+			}
+		}
+		text := fmt.Sprintf("  %-28s %8s %6s  %-19s  %s", r.link.ShortURL, clicks, gained, display(r.link.ExpireAtDatetime), r.link.LongURL)
+		if i == d.cursor {
+			if rr := []rune(text); len(rr) > cols {
+				text = string(rr[:cols])
+			}
+			b.WriteString("\x1b[7m" + text + "\x1b[0m\r\n")
+			continue
+		}
+		line(text)
+	}
+	for i := len(d.view) - d.offset; i < height; i++ {
+		line("")
+	}
+	switch {
+	case d.prompt != nil:
+		line(d.prompt.label + d.prompt.text + "_")
+	default:
+		line(d.status)
+	}
+	b.WriteString("↑/↓ move  / filter  o open  e expiry  c copy  r reload  q quit")
+	io.WriteString(d.e.stdout, b.String())
+}
+
+// readKeys sends key presses read from r until it fails. Arrow keys,
+// Enter, Escape, Backspace and Ctrl-C get names; other keys are sent as
+// typed.
+func readKeys(r io.Reader, keys chan<- string) {
+	defer close(keys)
+	buf := make([]byte, 64)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			return
+		}
+		in := string(buf[:n])
+		for in != "" {
+			var key string
+			switch {
+			case strings.HasPrefix(in, "\x1b[A"):
+				key = "up"
+				in = in[3:]
+			case strings.HasPrefix(in, "\x1b[B"):
+				key = "down"
+				in = in[3:]
+			case strings.HasPrefix(in, "\x1b["):
+				in = in[3:]
+				continue
+			default:
+				r := []rune(in)[0]
+				key = string(r)
+				in = in[len(key):]
+				switch r {
+				case '\x1b':
+					key = "esc"
+				case '\r', '\n':
+					key = "enter"
+				case '\x7f', '\b':
+					key = "backspace"
+				case '\x03':
+					key = "ctrl-c"
+				}
+			}
+			keys <- key
+		}
+	}
+}
+
+// openBrowser opens rawURL in the default browser.
+func openBrowser(rawURL string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", rawURL)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", rawURL)
+	default:
+		cmd = exec.Command("xdg-open", rawURL)
+	}
+	return cmd.Start()
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+func init() {
+	register(&command{name: "doctor", summary: "Check the API key, latency, quota, rate limit and default domain.", run: runDoctor})
+}
+
+// Outcomes of a doctor check.
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+	checkSkip = "skip"
+)
+
+// check is the outcome of one doctor check.
+type check struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// doctor runs the checks in order, later ones reusing what earlier ones
+// learned.
+type doctor struct {
+	e       *env
+	c       *tly.Client
+	samples int
+	checks  []check
+
+	// probe is the cheapest request the key is allowed to make, set once
+	// the key has been accepted.
+	probe func() error
+}
+
+func (d *doctor) add(name, status, format string, args ...interface{}) {
+	detail := strings.Join(strings.Fields(fmt.Sprintf(format, args...)), " ")
+	d.checks = append(d.checks, check{Name: name, Status: status, Detail: detail})
+}
+
+func runDoctor(e *env, args []string) error {
+	fs := e.flagSet()
+	samples := fs.Int("samples", 3, "requests to time for the latency check")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return usagef("unexpected arguments")
+	}
+	if *samples < 1 {
+		return usagef("--samples must be at least 1")
+	}
+	d := &doctor{e: e, samples: *samples}
+	if d.config() {
+		d.account()
+		d.latency()
+		d.usage()
+		d.rateLimit()
+		d.domain()
+	}
+	failed := 0
+	for _, c := range d.checks {
+		if c.Status == checkFail {
+			failed++
+		}
+	}
+	err = e.output(d.checks, func(w io.Writer) error {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, c := range d.checks {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", strings.ToUpper(c.Status), c.Name, c.Detail)
+		}
+		return tw.Flush()
+	})
+	if err == nil && failed > 0 {
+		err = fmt.Errorf("%s failed", plural(failed, "check"))
+	}
+	return err
+}
+
+// config reports the config file, profile and API key, and creates the
+// client. It returns false when there is no usable key.
+func (d *doctor) config() bool {
+	path := configPath(d.e.getenv)
+	found, err := d.e.loadProfile()
+	switch {
+	case err != nil:
+		d.add("config", checkFail, "%s: %v", path, err)
+		return false
+	case found:
+		d.add("config", checkOK, "%s, profile %q", path, d.e.profile.Name)
+	default:
+		if _, statErr := os.Stat(path); statErr == nil {
+			d.add("config", checkOK, "%s, no profile selected", path)
+		} else {
+			d.add("config", checkOK, "no config file at %s", path)
+		}
+	}
+	key, source := d.e.resolveAPIKey()
+	if key == "" {
+		d.add("api key", checkFail, "no API key: set TLY_API_KEY, pass --api-key or add a profile")
+		return false
+	}
+	if d.c, err = d.e.Client(); err != nil {
+		d.add("api key", checkFail, "%v", err)
+		return false
+	}
+	d.add("api key", checkOK, "%s from %s, API at %s", maskKey(key), source, d.c.BaseURL)
+	return true
+}
+
+// maskKey shows only the last four characters of an API key.
+func maskKey(key string) string {
+	if len(key) <= 4 {
+		return strings.Repeat("*", len(key))
+	}
+	return strings.Repeat("*", 4) + key[len(key)-4:]
+}
+
+// account validates the key by fetching the account profile, or by
+// listing tags where the account endpoint is not available.
+func (d *doctor) account() {
+	d.probe = func() error {
+		_, err := d.c.GetAccount(d.e.ctx)
+		return err
+	}
+	account, err := d.c.GetAccount(d.e.ctx)
+	if tly.IsNotFound(err) {
+		d.probe = func() error {
+			_, err := d.c.ListTagsPage(d.e.ctx, tly.ListOptions{PerPage: 1})
+			return err
+		}
+		account, err = nil, d.probe()
+	}
+	var apiErr *tly.APIError
+	switch {
+	case errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden):
+		d.add("account", checkFail, "the API key was rejected (%d)", apiErr.StatusCode)
+		d.probe = nil
+		return
+	case err != nil:
+		d.add("account", checkFail, "%v", err)
+		d.probe = nil
+		return
+	case account == nil:
+		d.add("account", checkOK, "the API key was accepted; account details are not available")
+		return
+	}
+	who := account.Name
+	if account.Email != "" {
+		who += " <" + account.Email + ">"
+	}
+	if account.Plan != "" {
+		who += ", plan " + account.Plan
+	}
+	d.add("account", checkOK, "%s", strings.TrimSpace(who))
+}
+
+// slowLatency is the median round trip above which latency is flagged.
+const slowLatency = time.Second
+
+// latency times a few requests.
+func (d *doctor) latency() {
+	if d.probe == nil {
+		d.add("latency", checkSkip, "needs a valid API key")
+		return
+	}
+	var times []time.Duration
+	for i := 0; i < d.samples; i++ {
+		start := time.Now()
+		if err := d.probe(); err != nil {
+			d.add("latency", checkFail, "request %d of %d: %v", i+1, d.samples, err)
+			return
+		}
+		times = append(times, time.Since(start))
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	median := times[len(times)/2]
+	status := checkOK
+	if median > slowLatency {
+		status = checkWarn
+	}
+	d.add("latency", status, "median %s, min %s, max %s over %s",
+		roundDuration(median), roundDuration(times[0]), roundDuration(times[len(times)-1]), plural(len(times), "request"))
+}
+
+// roundDuration rounds d for display.
+func roundDuration(d time.Duration) time.Duration {
+	if d < 10*time.Millisecond {
+		return d.Round(100 * time.Microsecond)
+	}
+	return d.Round(time.Millisecond)
+}
+
+// quotaWarning is the share of a quota above which usage is flagged.
+const quotaWarning = 0.9
+
+// usage reports the plan's quotas.
+func (d *doctor) usage() {
+	if d.probe == nil {
+		d.add("quota", checkSkip, "needs a valid API key")
+		return
+	}
+	usage, err := d.c.GetUsage(d.e.ctx)
+	if tly.IsNotFound(err) {
+		d.add("quota", checkSkip, "usage is not available on this plan")
+		return
+	}
+	if err != nil {
+		d.add("quota", checkFail, "%v", err)
+		return
+	}
+	status := checkOK
+	var parts []string
+	for _, q := range []struct {
+		name string
+		u    tly.UsageCounter
+	}{{"links", usage.Links}, {"API calls", usage.APICalls}, {"domains", usage.Domains}} {
+		if q.u.Limit == 0 {
+			parts = append(parts, fmt.Sprintf("%s %d (unlimited)", q.name, q.u.Used))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s %d/%d", q.name, q.u.Used, q.u.Limit))
+		if q.u.Fraction() >= quotaWarning {
+			status = checkWarn
+		}
+	}
+	detail := strings.Join(parts, ", ")
+	if !usage.PeriodEnd.IsZero() {
+		detail += ", period ends " + usage.PeriodEnd.Format("2006-01-02")
+	}
+	d.add("quota", status, "%s", detail)
+}
+
+// rateLimit reports the headroom left in the rate limit window, from the
+// headers of the requests already made.
+func (d *doctor) rateLimit() {
+	if d.probe == nil {
+		d.add("rate limit", checkSkip, "needs a valid API key")
+		return
+	}
+	r, ok := d.c.LastRateLimit()
+	if !ok {
+		d.add("rate limit", checkSkip, "the API did not report a rate limit")
+		return
+	}
+	status := checkOK
+	if float64(r.Remaining) < float64(r.Limit)*(1-quotaWarning) {
+		status = checkWarn
+	}
+	detail := fmt.Sprintf("%d of %d requests left", r.Remaining, r.Limit)
+	if !r.Reset.IsZero() {
+		detail += fmt.Sprintf(", window resets in %s", time.Until(r.Reset).Round(time.Second))
+	}
+	d.add("rate limit", status, "%s", detail)
+}
+
+// builtinDomains are the T.LY domains every account can use.
+var builtinDomains = map[string]bool{"t.ly": true}
+
+// domain checks that the profile's default domain is usable.
+func (d *doctor) domain() {
+	configured := d.e.profile.Domain
+	if configured == "" {
+		d.add("domain", checkOK, "no default domain configured; links are created on t.ly")
+		return
+	}
+	host := hostname(configured)
+	if builtinDomains[host] {
+		d.add("domain", checkOK, "%s is a T.LY domain", host)
+		return
+	}
+	if d.probe == nil {
+		d.add("domain", checkSkip, "needs a valid API key")
+		return
+	}
+	domains, err := d.c.ListDomains(d.e.ctx)
+	if tly.IsNotFound(err) {
+		d.add("domain", checkWarn, "%s cannot be checked: branded domains are not available on this plan", host)
+		return
+	}
+	if err != nil {
+		d.add("domain", checkFail, "%v", err)
+		return
+	}
+	for _, dom := range domains {
+		if !strings.EqualFold(dom.Hostname, host) {
+			continue
+		}
+		switch dom.Status {
+		case tly.DomainActive, "":
+			d.add("domain", checkOK, "%s is active", host)
+		case tly.DomainPending:
+			d.add("domain", checkWarn, "%s is still pending DNS verification", host)
+		default:
+			d.add("domain", checkFail, "%s is %s", host, dom.Status)
+		}
+		return
+	}
+	d.add("domain", checkFail, "%s is not a domain of this account", host)
+}
+
+// hostname returns the host of a domain given as a host name or URL.
+func hostname(domain string) string {
+	domain = strings.TrimPrefix(strings.TrimPrefix(domain, "https://"), "http://")
+	if i := strings.IndexAny(domain, "/:"); i >= 0 {
+		domain = domain[:i]
+	}
+	return strings.ToLower(domain)
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// defineFormat adds --format and --jq to a command that prints records.
+// Both work on the command's JSON output, so they use the keys --json
+// prints.
+func (e *env) defineFormat(fs *flag.FlagSet) {
+	fs.Func("format", "print with a Go `template`, once per item of a list, e.g. '{{.short_url}}'", func(s string) error {
+		t, err := template.New("format").Funcs(templateFuncs).Parse(s)
+		if err != nil {
+			return err
+		}
+		e.format = t
+		return nil
+	})
+	fs.Func("jq", "print the values at a jq-style `path`, e.g. '.[].short_url'", func(s string) error {
+		p, err := parsePath(s)
+		if err != nil {
+			return err
+		}
+		e.jq = p
+		return nil
+	})
+}
+
+// templateFuncs are the functions available to --format templates.
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// formatted reports whether --format or --jq replaces the human output.
+func (e *env) formatted() bool {
+	return e.format != nil || e.jq != nil
+}
+
+// writeFormatted prints v with --format or --jq.
+func (e *env) writeFormatted(v interface{}) error {
+	if e.json || e.format != nil && e.jq != nil {
+		return usagef("--json, --format and --jq are mutually exclusive")
+	}
+	doc, err := generic(v)
+	if err != nil {
+		return err
+	}
+	if e.jq != nil {
+		values, err := e.jq.eval(doc)
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			if _, err := fmt.Fprintln(e.stdout, plain(v)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	items, ok := doc.([]interface{})
+	if !ok {
+		items = []interface{}{doc}
+	}
+	var buf bytes.Buffer
+	for _, item := range items {
+		buf.Reset()
+		if err := e.format.Execute(&buf, item); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		if _, err := e.stdout.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generic converts v to the maps, slices and json.Numbers of its JSON
+// encoding.
+func generic(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	err = dec.Decode(&doc)
+	return doc, err
+}
+
+// plain formats a JSON value for printing: strings raw, null as "null"
+// and everything else as compact JSON, like jq -r.
+func plain(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// jqPath is a path into a JSON document in the subset of jq syntax that
+// selects values: .key, ."key", .[n], .["key"] and .[] to iterate.
+type jqPath []jqStep
+
+// jqStep is one step of a jqPath. Exactly one of key, index or each is
+// used.
+type jqStep struct {
+	key   string
+	index int
+	isKey bool
+	each  bool
+}
+
+// parsePath parses a jqPath such as ".[].tags[0].tag".
+func parsePath(s string) (jqPath, error) {
+	bad := func(why string) (jqPath, error) {
+		return nil, fmt.Errorf("invalid path %q: %s", s, why)
+	}
+	if !strings.HasPrefix(s, ".") {
+		return bad(`it must start with "."`)
+	}
+	path := jqPath{}
+	rest := s
+	for rest != "" && rest != "." {
+		switch {
+		case strings.HasPrefix(rest, ".["), strings.HasPrefix(rest, "["):
+			rest = strings.TrimPrefix(rest, ".")
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return bad(`missing "]"`)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			switch {
+			case inner == "":
+				path = append(path, jqStep{each: true})
+			case strings.HasPrefix(inner, `"`):
+				key, err := strconv.Unquote(inner)
+				if err != nil {
+					return bad("malformed key " + inner)
+				}
+				path = append(path, jqStep{key: key, isKey: true})
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return bad("malformed index " + inner)
+				}
+				path = append(path, jqStep{index: n})
+			}
+		case strings.HasPrefix(rest, `."`):
+			end := 2
+			for end < len(rest) && (rest[end] != '"' || rest[end-1] == '\\') {
+				end++
+			}
+			if end == len(rest) {
+				return bad("unterminated key")
+			}
+			key, err := strconv.Unquote(rest[1 : end+1])
+			if err != nil {
+				return bad("malformed key " + rest[1:end+1])
+			}
+			path = append(path, jqStep{key: key, isKey: true})
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "."):
+			end := 1
+			for end < len(rest) && isIdentByte(rest[end], end == 1) {
+				end++
+			}
+			if end == 1 {
+				return bad("expected a key after " + strconv.Quote(s[:len(s)-len(rest)+1]))
+			}
+			path = append(path, jqStep{key: rest[1:end], isKey: true})
+			rest = rest[end:]
+		default:
+			return bad("unexpected " + strconv.Quote(rest[:1]))
+		}
+	}
+	return path, nil
+}
+
+func isIdentByte(c byte, first bool) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || !first && '0' <= c && c <= '9'
+}
+
+// eval returns the values the path selects in doc. Like jq, a key of null
+// is null, and iterating an object yields its values, here in key order.
+func (p jqPath) eval(doc interface{}) ([]interface{}, error) {
+	values := []interface{}{doc}
+	for _, step := range p {
+		var next []interface{}
+		for _, v := range values {
+			switch {
+			case v == nil && !step.each:
+				next = append(next, nil)
+			case step.isKey:
+				obj, ok := v.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("cannot index %s with %q", kind(v), step.key)
+				}
+				next = append(next, obj[step.key])
+			case step.each:
+				switch v := v.(type) {
+				case []interface{}:
+					next = append(next, v...)
+				case map[string]interface{}:
+					keys := make([]string, 0, len(v))
+					for k := range v {
+						keys = append(keys, k)
+					}
+					sort.Strings(keys)
+					for _, k := range keys {
+						next = append(next, v[k])
+					}
+				default:
+					return nil, fmt.Errorf("cannot iterate over %s", kind(v))
+				}
+			default:
+				arr, ok := v.([]interface{})
+				if !ok {
+					return nil, fmt.Errorf("cannot index %s with a number", kind(v))
+				}
+				i := step.index
+				if i < 0 {
+					i += len(arr)
+				}
+				if i < 0 || i >= len(arr) {
+					next = append(next, nil)
+				} else {
+					next = append(next, arr[i])
+				}
+			}
+		}
+		values = next
+	}
+	return values, nil
+}
+
+// kind names the JSON type of v for errors.
+func kind(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "a string"
+	case json.Number:
+		return "a number"
+	case bool:
+		return "a boolean"
+	case []interface{}:
+		return "an array"
+	}
+	return "an object"
+}
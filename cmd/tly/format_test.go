@@ -0,0 +1,174 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+	"github.com/timleland/t.ly-go-url-shortener-api/tlytest"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want jqPath
+	}{
+		{".", jqPath{}},
+		{".short_url", jqPath{{key: "short_url", isKey: true}}},
+		{".[]", jqPath{{each: true}}},
+		{".[].tags[0].tag", jqPath{{each: true}, {key: "tags", isKey: true}, {index: 0}, {key: "tag", isKey: true}}},
+		{".[-1]", jqPath{{index: -1}}},
+		{`."odd key"`, jqPath{{key: "odd key", isKey: true}}},
+		{`.["a\"b"]`, jqPath{{key: `a"b`, isKey: true}}},
+		{".a[ 2 ][]", jqPath{{key: "a", isKey: true}, {index: 2}, {each: true}}},
+		{"._x1", jqPath{{key: "_x1", isKey: true}}},
+	}
+	for _, tt := range tests {
+		got, err := parsePath(tt.path)
+		if err != nil {
+			t.Errorf("parsePath(%q): %v", tt.path, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePath(%q) = %+v, want %+v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParsePathErrors(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"short_url", `it must start with "."`},
+		{".[0", `missing "]"`},
+		{".[x]", "malformed index x"},
+		{`.["x]`, `malformed key "x`},
+		{`."x`, "unterminated key"},
+		{".1a", `expected a key after "."`},
+		{".a..b", `expected a key after ".a."`},
+		{".a b", `unexpected " "`},
+	}
+	for _, tt := range tests {
+		_, err := parsePath(tt.path)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parsePath(%q) error = %v, want %q", tt.path, err, tt.want)
+		}
+	}
+}
+
+func TestEval(t *testing.T) {
+	doc, err := generic([]map[string]interface{}{
+		{"short_url": "https://t.ly/a", "clicks": 3, "tags": []map[string]string{{"tag": "x"}, {"tag": "y"}}, "meta": nil},
+		{"short_url": "https://t.ly/b", "clicks": 0, "tags": []map[string]string{}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{".[].short_url", []string{"https://t.ly/a", "https://t.ly/b"}},
+		{".[0].clicks", []string{"3"}},
+		{".[-1].short_url", []string{"https://t.ly/b"}},
+		{".[5]", []string{"null"}},
+		{".[].tags[].tag", []string{"x", "y"}},
+		{".[].tags[0].tag", []string{"x", "null"}},
+		{".[0].meta.anything", []string{"null"}},
+		{".[0].missing", []string{"null"}},
+		{".[1].tags", []string{"[]"}},
+		{".[0].tags[1]", []string{`{"tag":"y"}`}},
+		{".[1][]", []string{"0", "https://t.ly/b", "[]"}},
+	}
+	for _, tt := range tests {
+		p, err := parsePath(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		values, err := p.eval(doc)
+		if err != nil {
+			t.Errorf("eval(%q): %v", tt.path, err)
+			continue
+		}
+		got := make([]string, len(values))
+		for i, v := range values {
+			got[i] = plain(v)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("eval(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	doc, err := generic(map[string]interface{}{"name": "a", "n": 1, "ok": true, "list": []int{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path string
+		want string
+	}{
+		{".[0]", "cannot index an object with a number"},
+		{".name.x", `cannot index a string with "x"`},
+		{".n[]", "cannot iterate over a number"},
+		{".ok[0]", "cannot index a boolean with a number"},
+		{".list.x", `cannot index an array with "x"`},
+	}
+	for _, tt := range tests {
+		p, err := parsePath(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := p.eval(doc); err == nil || err.Error() != tt.want {
+			t.Errorf("eval(%q) error = %v, want %q", tt.path, err, tt.want)
+		}
+	}
+}
+
+func TestFormatFlags(t *testing.T) {
+	srv := tlytest.NewServer(tlytest.Options{})
+	defer srv.Close()
+	c := srv.Client()
+	for _, name := range []string{"news", "blog"} {
+		if _, err := c.CreateTag(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link, err := c.CreateShortLink(tly.ShortLinkCreateRequest{LongURL: "https://example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{"jq", []string{"tag", "list", "--jq", ".[].tag"}, 0, "news\nblog\n", ""},
+		{"jq number", []string{"tag", "list", "--jq", ".[0].id"}, 0, "1\n", ""},
+		{"template per item", []string{"tag", "list", "--format", "{{.id}}={{.tag}}"}, 0, "1=news\n2=blog\n", ""},
+		{"template json", []string{"tag", "list", "--format", "{{json .tag}}"}, 0, "\"news\"\n\"blog\"\n", ""},
+		{"template on one record", []string{"get", "--format", "{{.long_url}}", link.ShortURL}, 0, "https://example.com\n", ""},
+		{"bad path", []string{"tag", "list", "--jq", "tag"}, 1, "", `it must start with "."`},
+		{"bad template", []string{"tag", "list", "--format", "{{.id"}, 1, "", "unclosed action"},
+		{"exclusive", []string{"tag", "list", "--jq", ".", "--json"}, 2, "", "mutually exclusive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := tlyCommand(t, srv, strings.NewReader(""), tt.args...)
+			if code != tt.code {
+				t.Fatalf("exit code %d, want %d; stderr:\n%s", code, tt.code, stderr)
+			}
+			if stdout != tt.stdout {
+				t.Errorf("stdout = %q, want %q", stdout, tt.stdout)
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want %q", stderr, tt.stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+func init() {
+	register(&command{name: "import", args: "<links.csv>", summary: "Shorten every URL in a CSV file.", run: runImport})
+}
+
+// importBatch is how many rows are shortened between progress updates and
+// writes to the results file.
+const importBatch = 50
+
+// importRow is one data row of the input CSV. Row numbers start at 1 after
+// the header.
+type importRow struct {
+	row  int
+	req  tly.ShortLinkCreateRequest
+	tags []string
+}
+
+// importColumns maps input columns to link settings. A column is -1 when
+// the input does not have it.
+type importColumns struct {
+	longURL, alias, description, tags int
+}
+
+// headerColumns reads the columns of a header row, or returns false if
+// record looks like data rather than a header.
+func headerColumns(record []string) (importColumns, bool) {
+	cols := importColumns{-1, -1, -1, -1}
+	if len(record) == 0 || strings.Contains(record[0], "://") {
+		return importColumns{0, -1, -1, -1}, false
+	}
+	for i, name := range record {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "long_url", "url":
+			cols.longURL = i
+		case "alias", "short_id":
+			cols.alias = i
+		case "description":
+			cols.description = i
+		case "tags":
+			cols.tags = i
+		}
+	}
+	return cols, true
+}
+
+// field returns column i of record, or "" when it is missing.
+func field(record []string, i int) string {
+	if i < 0 || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func runImport(e *env, args []string) error {
+	fs := e.flagSet()
+	domain := fs.String("domain", "", "domain to create the links on (default: the profile's domain)")
+	tags := fs.String("tags", "", "comma-separated tag names added to every link, created if missing (default: the profile's tags)")
+	output := fs.String("o", "", `results CSV (default "<input>.results.csv")`)
+	resume := fs.Bool("resume", false, "skip rows the results file already records as shortened")
+	concurrency := fs.Int("concurrency", 4, "parallel requests")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	input, err := oneArg(args, "CSV file")
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		*output = strings.TrimSuffix(input, ".csv") + ".results.csv"
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+
+	done := map[int]bool{}
+	if *resume {
+		if done, err = shortenedRows(*output); err != nil {
+			return err
+		}
+	}
+	total, err := countRows(input)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if *resume {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	out, err := os.OpenFile(*output, flags, 0o644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	results := csv.NewWriter(out)
+	if info, err := out.Stat(); err == nil && info.Size() == 0 {
+		results.Write([]string{"row", "long_url", "short_url", "error"})
+	}
+
+	tagIDs := newTagCache(e, c)
+	common, err := tagIDs.resolve(e.tagNames(fs, *tags))
+	if err != nil {
+		return err
+	}
+	r := csv.NewReader(in)
+	r.FieldsPerRecord = -1
+	var (
+		cols            = importColumns{0, -1, -1, -1}
+		batch           []importRow
+		row             int
+		shortened, fail int
+		skipped         = len(done)
+	)
+	showProgress := isTerminal(e.stderr) && !e.json
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		waitForQuota(e, c, len(batch))
+		record := func(item importRow, shortURL string, err error) {
+			msg := ""
+			if err != nil {
+				msg = err.Error()
+				fail++
+			} else {
+				shortened++
+			}
+			results.Write([]string{strconv.Itoa(item.row), item.req.LongURL, shortURL, msg})
+		}
+		var items []importRow
+		var reqs []tly.ShortLinkCreateRequest
+		for _, item := range batch {
+			ids, err := tagIDs.resolve(item.tags)
+			if err != nil {
+				record(item, "", err)
+				continue
+			}
+			item.req.Tags = append(append([]int(nil), common...), ids...)
+			items = append(items, item)
+			reqs = append(reqs, item.req)
+		}
+		created, err := c.CreateShortLinks(e.ctx, reqs, tly.CreateShortLinksOptions{Concurrency: *concurrency})
+		for i, res := range created {
+			switch {
+			case res.Err != nil:
+				record(items[i], "", res.Err)
+			case res.Link != nil:
+				record(items[i], res.Link.ShortURL, nil)
+			}
+		}
+		results.Flush()
+		if showProgress {
+			progress(e.stderr, skipped+shortened+fail, fail, total)
+		}
+		batch = batch[:0]
+		if err != nil {
+			return err
+		}
+		return results.Error()
+	}
+	for first := true; ; first = false {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if first {
+			if header, ok := headerColumns(record); ok {
+				cols = header
+				continue
+			}
+		}
+		row++
+		if done[row] {
+			continue
+		}
+		item := importRow{row: row, req: tly.ShortLinkCreateRequest{LongURL: field(record, cols.longURL), Domain: *domain}}
+		if alias := field(record, cols.alias); alias != "" {
+			item.req.ShortID = &alias
+		}
+		if description := field(record, cols.description); description != "" {
+			item.req.Description = &description
+		}
+		item.tags = strings.FieldsFunc(field(record, cols.tags), func(r rune) bool { return r == ';' || r == '|' })
+		batch = append(batch, item)
+		if len(batch) == importBatch {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	if showProgress {
+		fmt.Fprintln(e.stderr)
+	}
+	summary := map[string]interface{}{"shortened": shortened, "failed": fail, "skipped": skipped, "results": *output}
+	err = e.output(summary, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "Shortened %d, failed %d, skipped %d. Results in %s\n", shortened, fail, skipped, *output)
+		return err
+	})
+	if err == nil && fail > 0 {
+		err = fmt.Errorf("%d rows failed; rerun with --resume to retry them", fail)
+	}
+	return err
+}
+
+// shortenedRows returns the input rows the results file at path records as
+// shortened. A missing file records none.
+func shortenedRows(path string) (map[int]bool, error) {
+	done := map[int]bool{}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return done, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+		if n, err := strconv.Atoi(field(record, 0)); err == nil && field(record, 2) != "" {
+			done[n] = true
+		}
+	}
+}
+
+// countRows returns the number of data rows in the CSV file at path.
+func countRows(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	n := 0
+	for first := true; ; first = false {
+		record, err := r.Read()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if _, header := headerColumns(record); !first || !header {
+			n++
+		}
+	}
+}
+
+// tagCache resolves tag names to IDs, creating missing tags, and remembers
+// the answers for the rest of the run.
+type tagCache struct {
+	e   *env
+	c   *tly.Client
+	ids map[string]int
+}
+
+func newTagCache(e *env, c *tly.Client) *tagCache {
+	return &tagCache{e: e, c: c, ids: map[string]int{}}
+}
+
+func (t *tagCache) resolve(names []string) ([]int, error) {
+	ids := make([]int, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		id, ok := t.ids[name]
+		if !ok {
+			tag, err := t.c.GetOrCreateTag(t.e.ctx, name)
+			if err != nil {
+				return nil, fmt.Errorf("tag %q: %w", name, err)
+			}
+			id = tag.ID
+			t.ids[name] = id
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// waitForQuota sleeps until the rate limit window resets when fewer than n
+// requests remain in it.
+func waitForQuota(e *env, c *tly.Client, n int) {
+	r, ok := c.LastRateLimit()
+	if !ok || r.Remaining >= n || r.Reset.IsZero() {
+		return
+	}
+	select {
+	case <-time.After(time.Until(r.Reset)):
+	case <-e.ctx.Done():
+	}
+}
+
+// progress redraws a progress bar on the current terminal line.
+func progress(w io.Writer, done, failed, total int) {
+	const width = 30
+	filled := 0
+	if total > 0 {
+		filled = done * width / total
+	}
+	if filled > width {
+		filled = width
+	}
+	fmt.Fprintf(w, "\r[%s%s] %d/%d", strings.Repeat("#", filled), strings.Repeat("-", width-filled), done, total)
+	if failed > 0 {
+		fmt.Fprintf(w, " (%d failed)", failed)
+	}
+}
+
+// isTerminal reports whether w is a character device such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+func init() {
+	register(&command{name: "interactive", summary: "Search links interactively and pick one to edit.", run: runInteractive})
+}
+
+// interactiveLimit is how many matches a search shows.
+const interactiveLimit = 20
+
+// fuzzyScore reports whether the letters of pattern appear in order in
+// text, ignoring case, and scores the match: runs of consecutive letters
+// and letters starting a word score higher.
+func fuzzyScore(pattern, text string) (int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(strings.ToLower(text))
+	if len(p) == 0 {
+		return 0, true
+	}
+	score, run, j := 0, 0, 0
+	for i := 0; i < len(t) && j < len(p); i++ {
+		if t[i] != p[j] {
+			run = 0
+			continue
+		}
+		run++
+		score += run
+		if i == 0 || !unicode.IsLetter(t[i-1]) && !unicode.IsDigit(t[i-1]) {
+			score += 2
+		}
+		j++
+	}
+	return score, j == len(p)
+}
+
+// linkMatch is a link and its search score.
+type linkMatch struct {
+	link  tly.ShortLink
+	score int
+}
+
+// searchLinks returns the links matching query on their short URL, long
+// URL, description or tags, best first.
+func searchLinks(links []tly.ShortLink, query string) []linkMatch {
+	var matches []linkMatch
+	for _, l := range links {
+		best, found := 0, false
+		fields := []string{l.ShortURL, l.LongURL, l.Description}
+		for _, t := range l.Tags {
+			fields = append(fields, t.Tag)
+		}
+		for _, f := range fields {
+			if s, ok := fuzzyScore(query, f); ok && (!found || s > best) {
+				best, found = s, true
+			}
+		}
+		if found {
+			matches = append(matches, linkMatch{link: l, score: best})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	return matches
+}
+
+// prompter reads answers line by line.
+type prompter struct {
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+// ask prints question and returns the trimmed answer. ok is false at the
+// end of input.
+func (p *prompter) ask(question string) (answer string, ok bool) {
+	fmt.Fprint(p.out, question)
+	if !p.in.Scan() {
+		fmt.Fprintln(p.out)
+		return "", false
+	}
+	return strings.TrimSpace(p.in.Text()), true
+}
+
+func runInteractive(e *env, args []string) error {
+	fs := e.flagSet()
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return usagef("unexpected arguments")
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	links, err := c.ListAllShortLinks(e.ctx, tly.ShortLinkListOptions{})
+	if err != nil {
+		return err
+	}
+	p := &prompter{in: bufio.NewScanner(e.stdin), out: e.stdout}
+	fmt.Fprintf(e.stdout, "Loaded %d links. Type to search, an empty line lists the newest, q quits.\n", len(links))
+	for {
+		query, ok := p.ask("search> ")
+		if !ok || query == "q" {
+			return nil
+		}
+		matches := searchLinks(links, query)
+		if len(matches) == 0 {
+			fmt.Fprintln(e.stdout, "No matches.")
+			continue
+		}
+		if len(matches) > interactiveLimit {
+			matches = matches[:interactiveLimit]
+		}
+		for i, m := range matches {
+			fmt.Fprintf(e.stdout, "%3d  %s -> %s", i+1, m.link.ShortURL, m.link.LongURL)
+			if m.link.Description != "" {
+				fmt.Fprintf(e.stdout, "  (%s)", m.link.Description)
+			}
+			fmt.Fprintln(e.stdout)
+		}
+		answer, ok := p.ask("edit #> ")
+		if !ok {
+			return nil
+		}
+		n, err := strconv.Atoi(answer)
+		if err != nil || n < 1 || n > len(matches) {
+			continue
+		}
+		updated, err := editLink(e, c, p, matches[n-1].link)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "error: %v\n", err)
+			continue
+		}
+		if updated != nil {
+			for i := range links {
+				if links[i].ShortURL == matches[n-1].link.ShortURL {
+					links[i] = *updated
+				}
+			}
+		}
+	}
+}
+
+// editLink asks for new values of the link's settings and saves them after
+// confirmation. It returns nil when nothing was changed.
+func editLink(e *env, c *tly.Client, p *prompter, link tly.ShortLink) (*tly.ShortLink, error) {
+	printLink(e.stdout, &link)
+	fmt.Fprintln(e.stdout, `Enter a new value, an empty line to keep the current one, or "-" to clear it.`)
+	req := tly.ShortLinkUpdateRequest{ShortURL: link.ShortURL, LongURL: link.LongURL}
+	changed := false
+	if v, ok := p.ask(fmt.Sprintf("Long URL [%s]: ", link.LongURL)); ok && v != "" && v != "-" {
+		req.LongURL, changed = v, true
+	}
+	if v, ok := p.ask(fmt.Sprintf("Description [%s]: ", link.Description)); ok && v != "" {
+		if v == "-" {
+			v = ""
+		}
+		req.Description, changed = &v, true
+	}
+	if v, ok := p.ask(fmt.Sprintf("Expires at [%s]: ", display(link.ExpireAtDatetime))); ok && v != "" {
+		if v == "-" {
+			v = ""
+		}
+		req.ExpireAtDatetime, changed = &v, true
+	}
+	if !changed {
+		fmt.Fprintln(e.stdout, "Nothing changed.")
+		return nil, nil
+	}
+	if v, _ := p.ask("Save? [y/N] "); !strings.EqualFold(v, "y") && !strings.EqualFold(v, "yes") {
+		fmt.Fprintln(e.stdout, "Discarded.")
+		return nil, nil
+	}
+	updated, err := c.UpdateShortLink(req)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(e.stdout, "Saved %s\n", updated.ShortURL)
+	return updated, nil
+}
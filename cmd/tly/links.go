@@ -0,0 +1,428 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+func init() {
+	register(&command{name: "shorten", args: "<long-url> | -", summary: "Create a short link, or one for every URL on stdin.", run: runShorten})
+	register(&command{name: "expand", args: "<short-url>", summary: "Print the long URL of a short link.", run: runExpand})
+	register(&command{name: "get", args: "<short-url>", summary: "Show the details of a short link.", run: runGet})
+	register(&command{name: "update", args: "<short-url>", summary: "Change the settings of a short link.", run: runUpdate})
+	register(&command{name: "delete", args: "<short-url>... | --tag <name>", summary: "Delete short links.", run: runDelete})
+}
+
+// linkFlags are the short link settings shared by shorten and update.
+type linkFlags struct {
+	alias       string
+	description string
+	expireAt    string
+	expireViews int
+	password    string
+	publicStats bool
+}
+
+func (f *linkFlags) define(fs *flag.FlagSet) {
+	fs.StringVar(&f.alias, "alias", "", "custom back-half of the short URL")
+	fs.StringVar(&f.description, "description", "", "description of the link")
+	fs.StringVar(&f.expireAt, "expire-at", "", `expiry date, e.g. "2026-12-31 23:59:59"`)
+	fs.IntVar(&f.expireViews, "expire-views", 0, "expire the link after this many clicks")
+	fs.StringVar(&f.password, "password", "", "password visitors must enter")
+	fs.BoolVar(&f.publicStats, "public-stats", false, "make the link's stats public")
+}
+
+// oneArg checks that exactly one positional argument was given.
+func oneArg(args []string, what string) (string, error) {
+	if len(args) != 1 {
+		return "", usagef("expected one %s, got %d arguments", what, len(args))
+	}
+	return args[0], nil
+}
+
+func runShorten(e *env, args []string) error {
+	fs := e.flagSet()
+	var lf linkFlags
+	lf.define(fs)
+	domain := fs.String("domain", "", "domain to create the link on (default: the profile's domain)")
+	tags := fs.String("tags", "", "comma-separated tag names, created if missing (default: the profile's tags)")
+	concurrency := fs.Int("concurrency", 4, `parallel requests when reading URLs from stdin with "-"`)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	longURL, err := oneArg(args, `long URL (or "-" for stdin)`)
+	if err != nil {
+		return err
+	}
+	given := e.commandFlags(fs)
+	if longURL == "-" && given["alias"] {
+		return usagef("--alias cannot be used with stdin")
+	}
+	if *concurrency < 1 {
+		return usagef("--concurrency must be at least 1")
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	req := tly.ShortLinkCreateRequest{LongURL: longURL, Domain: *domain}
+	if given["alias"] {
+		req.ShortID = &lf.alias
+	}
+	if given["description"] {
+		req.Description = &lf.description
+	}
+	if given["expire-at"] {
+		req.ExpireAtDatetime = &lf.expireAt
+	}
+	if given["expire-views"] {
+		req.ExpireAtViews = &lf.expireViews
+	}
+	if given["password"] {
+		req.Password = &lf.password
+	}
+	if given["public-stats"] {
+		req.PublicStats = &lf.publicStats
+	}
+	if longURL == "-" {
+		ids, err := newTagCache(e, c).resolve(e.tagNames(fs, *tags))
+		if err != nil {
+			return err
+		}
+		req.Tags = ids
+		return shortenLines(e, c, req, *concurrency)
+	}
+	link, err := c.CreateShortLinkWithTagNames(e.ctx, req, e.tagNames(fs, *tags))
+	if err != nil {
+		return err
+	}
+	return e.output(link, func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, link.ShortURL)
+		return err
+	})
+}
+
+// shortenedLine is the outcome of one URL read from stdin.
+type shortenedLine struct {
+	LongURL  string `json:"long_url"`
+	ShortURL string `json:"short_url,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// shortenLines shortens every URL on stdin, one per line, with req as the
+// template, and writes "long<TAB>short" lines, or NDJSON with --json, in
+// input order. Up to concurrency links are created at once, and each line
+// is written as soon as it and the lines before it are done, so the
+// command works at the end of a pipeline that never closes. Failures are
+// reported on stderr and make the command fail once stdin is exhausted.
+func shortenLines(e *env, c *tly.Client, req tly.ShortLinkCreateRequest, concurrency int) error {
+	// order holds the results of the lines in flight in input order; its
+	// capacity and sem bound the lines read ahead of the output.
+	order := make(chan chan shortenedLine, concurrency)
+	sem := make(chan struct{}, concurrency)
+	// ctx stops reading and shortening when the output fails.
+	ctx, cancel := context.WithCancel(e.ctx)
+	defer cancel()
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(order)
+		sc := bufio.NewScanner(e.stdin)
+		for sc.Scan() {
+			longURL := strings.TrimSpace(sc.Text())
+			if longURL == "" {
+				continue
+			}
+			done := make(chan shortenedLine, 1)
+			select {
+			case order <- done:
+			case <-ctx.Done():
+				readErr <- ctx.Err()
+				return
+			}
+			sem <- struct{}{}
+			go func(r tly.ShortLinkCreateRequest) {
+				defer func() { <-sem }()
+				line := shortenedLine{LongURL: r.LongURL}
+				var link *tly.ShortLink
+				err := e.retry(func() (err error) {
+					link, err = c.CreateShortLinkWithTagNames(ctx, r, nil)
+					return err
+				})
+				if err != nil {
+					line.Error = errorMessage(err)
+				} else {
+					line.ShortURL = link.ShortURL
+				}
+				done <- line
+			}(withLongURL(req, longURL))
+		}
+		readErr <- sc.Err()
+	}()
+	enc := json.NewEncoder(e.stdout)
+	total, failed := 0, 0
+	var writeErr error
+	for done := range order {
+		line := <-done
+		total++
+		if line.Error != "" {
+			failed++
+			if ctx.Err() == nil && !e.json {
+				fmt.Fprintf(e.stderr, "%s: %s\n", line.LongURL, line.Error)
+			}
+		}
+		if writeErr != nil {
+			continue
+		}
+		switch {
+		case e.json:
+			writeErr = enc.Encode(line)
+		case line.Error == "":
+			_, writeErr = fmt.Fprintf(e.stdout, "%s\t%s\n", line.LongURL, line.ShortURL)
+		}
+		if writeErr != nil {
+			cancel()
+		}
+	}
+	if writeErr != nil {
+		return writeErr
+	}
+	if err := <-readErr; err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %s could not be shortened", failed, plural(total, "URL"))
+	}
+	return nil
+}
+
+// withLongURL returns a copy of req for longURL.
+func withLongURL(req tly.ShortLinkCreateRequest, longURL string) tly.ShortLinkCreateRequest {
+	req.LongURL = longURL
+	return req
+}
+
+func runExpand(e *env, args []string) error {
+	fs := e.flagSet()
+	e.defineFormat(fs)
+	password := fs.String("password", "", "password of a protected link")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	shortURL, err := oneArg(args, "short URL")
+	if err != nil {
+		return err
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	var resp *tly.ExpandResponse
+	if *password != "" {
+		resp, err = c.ExpandWithPassword(e.ctx, shortURL, tly.Password(*password))
+	} else {
+		resp, err = c.ExpandShortLink(tly.ExpandRequest{ShortURL: shortURL})
+	}
+	if err != nil {
+		return err
+	}
+	return e.output(resp, func(w io.Writer) error {
+		if resp.Expired {
+			fmt.Fprintln(e.stderr, "warning: the link has expired")
+		}
+		_, err := fmt.Fprintln(w, resp.LongURL)
+		return err
+	})
+}
+
+func runGet(e *env, args []string) error {
+	fs := e.flagSet()
+	e.defineFormat(fs)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	shortURL, err := oneArg(args, "short URL")
+	if err != nil {
+		return err
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	link, err := c.GetShortLink(shortURL)
+	if err != nil {
+		return err
+	}
+	return e.output(link, func(w io.Writer) error {
+		return printLink(w, link)
+	})
+}
+
+func runUpdate(e *env, args []string) error {
+	fs := e.flagSet()
+	var lf linkFlags
+	lf.define(fs)
+	longURL := fs.String("long-url", "", "new destination URL")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	shortURL, err := oneArg(args, "short URL")
+	if err != nil {
+		return err
+	}
+	given := e.commandFlags(fs)
+	if len(given) == 0 {
+		return usagef("nothing to update")
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	req := tly.ShortLinkUpdateRequest{ShortURL: shortURL, LongURL: *longURL}
+	if req.LongURL == "" {
+		// The API requires the destination on every update.
+		current, err := c.GetShortLink(shortURL)
+		if err != nil {
+			return err
+		}
+		req.LongURL = current.LongURL
+	}
+	if given["alias"] {
+		req.ShortID = &lf.alias
+	}
+	if given["description"] {
+		req.Description = &lf.description
+	}
+	if given["expire-at"] {
+		req.ExpireAtDatetime = &lf.expireAt
+	}
+	if given["expire-views"] {
+		req.ExpireAtViews = &lf.expireViews
+	}
+	if given["password"] {
+		req.Password = &lf.password
+	}
+	if given["public-stats"] {
+		req.PublicStats = &lf.publicStats
+	}
+	link, err := c.UpdateShortLink(req)
+	if err != nil {
+		return err
+	}
+	return e.output(link, func(w io.Writer) error {
+		return printLink(w, link)
+	})
+}
+
+// linkResult is the JSON output of a command for one link it changed.
+type linkResult struct {
+	ShortURL string `json:"short_url"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+func runDelete(e *env, args []string) error {
+	fs := e.flagSet()
+	tag := fs.String("tag", "", "delete every link with this tag")
+	var s safety
+	s.define(fs)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 && *tag == "" {
+		return usagef("expected short URLs or --tag")
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	links, err := lookupLinks(e, c, args, *tag)
+	if err != nil {
+		return err
+	}
+	if len(links) == 0 {
+		fmt.Fprintln(e.stderr, "no links to delete")
+		return nil
+	}
+	items := make([]string, len(links))
+	for i, l := range links {
+		items[i] = l.ShortURL + " -> " + l.LongURL
+	}
+	if ok, err := e.confirm(s, "delete "+plural(len(links), "link"), items); !ok || err != nil {
+		return err
+	}
+	results := make([]linkResult, 0, len(links))
+	failed := 0
+	for _, l := range links {
+		err := e.retry(func() error { return c.DeleteShortLink(l.ShortURL) })
+		r := linkResult{ShortURL: l.ShortURL, Status: "deleted"}
+		if err != nil {
+			r.Status, r.Error = "failed", err.Error()
+			failed++
+		}
+		results = append(results, r)
+	}
+	err = e.output(results, func(w io.Writer) error {
+		for _, r := range results {
+			if r.Error != "" {
+				fmt.Fprintf(w, "Failed to delete %s: %s\n", r.ShortURL, r.Error)
+				continue
+			}
+			fmt.Fprintf(w, "Deleted %s\n", r.ShortURL)
+		}
+		return nil
+	})
+	if err == nil && failed > 0 {
+		err = fmt.Errorf("%s could not be deleted", plural(failed, "link"))
+	}
+	return err
+}
+
+// lookupLinks looks up the links named by shortURLs and those carrying
+// tag, without duplicates. A named link that does not exist is an error, so
+// nothing is changed when one of the arguments is mistyped.
+func lookupLinks(e *env, c *tly.Client, shortURLs []string, tag string) ([]tly.ShortLink, error) {
+	seen := map[string]bool{}
+	var links []tly.ShortLink
+	for _, shortURL := range shortURLs {
+		var link *tly.ShortLink
+		err := e.retry(func() (err error) {
+			link, err = c.GetShortLink(shortURL)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", shortURL, err)
+		}
+		if link.ShortURL == "" {
+			link.ShortURL = shortURL
+		}
+		if !seen[link.ShortURL] {
+			seen[link.ShortURL] = true
+			links = append(links, *link)
+		}
+	}
+	if tag != "" {
+		tagged, err := c.ListShortLinksByTag(e.ctx, tly.TagByName(tag), tly.ShortLinkListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range tagged {
+			if !seen[l.ShortURL] {
+				seen[l.ShortURL] = true
+				links = append(links, l)
+			}
+		}
+	}
+	return links, nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timleland/t.ly-go-url-shortener-api/tlytest"
+)
+
+// tlyCommand runs the CLI against srv with stdin and returns its exit code,
+// stdout and stderr.
+func tlyCommand(t *testing.T, srv *tlytest.Server, stdin io.Reader, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, stdin, &stdout, &stderr, testEnv(t, srv))
+	return code, stdout.String(), stderr.String()
+}
+
+// testEnv returns a getenv pointing the CLI at srv and at an empty config.
+func testEnv(t *testing.T, srv *tlytest.Server) func(string) string {
+	vars := map[string]string{
+		"TLY_API_KEY":  "test-key",
+		"TLY_BASE_URL": srv.URL,
+		"TLY_CONFIG":   filepath.Join(t.TempDir(), "config.yaml"),
+	}
+	return func(k string) string { return vars[k] }
+}
+
+func TestShortenLinesOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       []string
+		concurrency string
+		json        bool
+		code        int
+		stdout      []string
+		stderr      string
+	}{
+		{
+			name:        "slow request",
+			input:       []string{"https://a.example", "https://b.example", "https://c.example", "https://d.example"},
+			concurrency: "4",
+			stdout:      []string{"https://a.example\thttps://t.ly/", "https://b.example\t", "https://c.example\t", "https://d.example\t"},
+		},
+		{
+			name:        "sequential",
+			input:       []string{"https://a.example", "", "  https://b.example  "},
+			concurrency: "1",
+			stdout:      []string{"https://a.example\t", "https://b.example\t"},
+		},
+		{
+			name:        "failures are reported and skipped",
+			input:       []string{"https://a.example", "not a url", "https://c.example"},
+			concurrency: "3",
+			code:        1,
+			stdout:      []string{"https://a.example\t", "https://c.example\t"},
+			stderr:      "not a url: The long url must be a valid URL.\ntly shorten: 1 of 3 URLs could not be shortened",
+		},
+		{
+			name:        "JSON keeps failures in line",
+			input:       []string{"https://a.example", "not a url", "https://c.example"},
+			concurrency: "3",
+			json:        true,
+			code:        1,
+			stdout: []string{
+				`{"long_url":"https://a.example","short_url":"https://t.ly/`,
+				`{"long_url":"not a url","error":"The long url must be a valid URL."}`,
+				`{"long_url":"https://c.example","short_url":"https://t.ly/`,
+			},
+			stderr: "1 of 3 URLs could not be shortened",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := tlytest.NewServer(tlytest.Options{})
+			defer srv.Close()
+			// The first request to arrive is answered last.
+			srv.Inject("POST /api/v1/link/shorten", tlytest.Fault{Latency: 50 * time.Millisecond})
+			args := []string{"shorten", "--concurrency", tt.concurrency}
+			if tt.json {
+				args = append(args, "--json")
+			}
+			code, stdout, stderr := tlyCommand(t, srv, strings.NewReader(strings.Join(tt.input, "\n")), append(args, "-")...)
+			if code != tt.code {
+				t.Fatalf("exit code %d, want %d; stderr:\n%s", code, tt.code, stderr)
+			}
+			lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+			if len(lines) != len(tt.stdout) {
+				t.Fatalf("stdout:\n%s\nwant %d lines", stdout, len(tt.stdout))
+			}
+			for i, want := range tt.stdout {
+				if !strings.HasPrefix(lines[i], want) {
+					t.Errorf("line %d = %q, want prefix %q", i+1, lines[i], want)
+				}
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want %q", stderr, tt.stderr)
+			}
+		})
+	}
+}
+
+func TestShortenLinesStreams(t *testing.T) {
+	srv := tlytest.NewServer(tlytest.Options{})
+	defer srv.Close()
+	stdin, input := io.Pipe()
+	output, stdout := io.Pipe()
+	done := make(chan int, 1)
+	go func() {
+		var stderr bytes.Buffer
+		code := run(context.Background(), []string{"shorten", "-"}, stdin, stdout, &stderr, testEnv(t, srv))
+		stdout.Close()
+		done <- code
+	}()
+	lines := bufio.NewReader(output)
+	for i := 1; i <= 2; i++ {
+		fmt.Fprintf(input, "https://example.com/%d\n", i)
+		got := make(chan string, 1)
+		go func() {
+			line, _ := lines.ReadString('\n')
+			got <- line
+		}()
+		select {
+		case line := <-got:
+			if want := fmt.Sprintf("https://example.com/%d\t", i); !strings.HasPrefix(line, want) {
+				t.Fatalf("line %d = %q, want prefix %q", i, line, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("line %d was not written while stdin stayed open", i)
+		}
+	}
+	input.Close()
+	if code := <-done; code != 0 {
+		t.Errorf("exit code %d, want 0", code)
+	}
+}
@@ -0,0 +1,345 @@
+// Command tly is a command-line client for the T.LY URL shortener.
+//
+// Usage:
+//
+//	tly <command> [flags] [arguments]
+//
+// The API key is read from the --api-key flag, the TLY_API_KEY environment
+// variable or a profile in ~/.config/tly/config.yaml selected with
+// --profile. TLY_BASE_URL points the client at another API server, such as
+// a test fake. Every command prints human-readable output by default and
+// JSON with --json; commands that list or show things also pick fields with
+// --format and --jq. Run "tly help" for the list of commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+// command is a tly subcommand.
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(e *env, args []string) error
+}
+
+// commands lists the subcommands by name. Each file registers its own in
+// an init function.
+var commands = map[string]*command{}
+
+func register(cmd *command) {
+	commands[cmd.name] = cmd
+}
+
+// group returns the run function of a command made of subcommands, such as
+// "tly tag list". The subcommand runs as a command named after both, so
+// its usage and errors read "tly tag list".
+func group(name string, subs ...*command) func(e *env, args []string) error {
+	return func(e *env, args []string) error {
+		if len(args) == 0 {
+			return usagef("missing subcommand")
+		}
+		switch args[0] {
+		case "help", "-h", "-help", "--help":
+			printSubcommands(e.stderr, name, subs)
+			return flag.ErrHelp
+		}
+		for _, sub := range subs {
+			if sub.name == args[0] {
+				e.cmd = &command{name: name + " " + sub.name, args: sub.args, summary: sub.summary, run: sub.run}
+				return sub.run(e, args[1:])
+			}
+		}
+		return usagef("unknown subcommand %q", args[0])
+	}
+}
+
+func printSubcommands(w io.Writer, name string, subs []*command) {
+	fmt.Fprintf(w, "Usage: tly %s <subcommand> [flags] [arguments]\n\nSubcommands:\n", name)
+	for _, sub := range subs {
+		fmt.Fprintf(w, "  %-10s %s\n", sub.name, sub.summary)
+	}
+	fmt.Fprintf(w, "\nRun \"tly %s <subcommand> -help\" for the flags of a subcommand.\n", name)
+}
+
+// usageError is returned by a command whose arguments are wrong. It makes
+// run print the command's usage and exit with status 2.
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
+func usagef(format string, args ...interface{}) error {
+	return usageError(fmt.Sprintf(format, args...))
+}
+
+// exitStatus is returned by a command that finished without error but
+// must exit with a status other than 0. run exits with it silently.
+type exitStatus int
+
+func (e exitStatus) Error() string { return fmt.Sprintf("exit status %d", int(e)) }
+
+// env is what a command runs with: its I/O, environment and the flags
+// shared by every command.
+type env struct {
+	ctx    context.Context
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+
+	apiKey      string
+	profileName string
+	json        bool
+	format      *template.Template
+	jq          jqPath
+
+	cmd     *command
+	client  *tly.Client
+	profile profile
+	shared  map[string]bool
+
+	// onFlagSet, when set, is called with every flag set a command
+	// creates, so completion can list the command's flags.
+	onFlagSet func(*flag.FlagSet)
+}
+
+// flagSet returns a flag set for the running command with the shared flags
+// already defined.
+func (e *env) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("tly "+e.cmd.name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.StringVar(&e.apiKey, "api-key", "", "T.LY API key (default $TLY_API_KEY)")
+	fs.StringVar(&e.profileName, "profile", "", "config file profile to use (default $TLY_PROFILE)")
+	fs.BoolVar(&e.json, "json", false, "print JSON")
+	e.shared = map[string]bool{}
+	fs.VisitAll(func(f *flag.Flag) { e.shared[f.Name] = true })
+	if e.onFlagSet != nil {
+		e.onFlagSet(fs)
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: tly %s [flags] %s\n\n%s\n\nFlags:\n", e.cmd.name, e.cmd.args, e.cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses args with fs, allowing flags after positional arguments,
+// and returns the positional arguments. Everything after "--" is
+// positional.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var rest []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		remaining := fs.Args()
+		if len(remaining) == 0 {
+			return rest, nil
+		}
+		if consumed := len(args) - len(remaining); consumed > 0 && args[consumed-1] == "--" {
+			return append(rest, remaining...), nil
+		}
+		rest = append(rest, remaining[0])
+		args = remaining[1:]
+	}
+}
+
+// loadProfile reads the config file and selects the profile for this run.
+func (e *env) loadProfile() (ok bool, err error) {
+	cfg, err := loadConfig(configPath(e.getenv))
+	if err != nil {
+		return false, err
+	}
+	name := e.profileName
+	if name == "" {
+		name = e.getenv("TLY_PROFILE")
+	}
+	e.profile, ok, err = cfg.selectProfile(name)
+	return ok, err
+}
+
+// Client returns the API client, creating it on first use. The API key is
+// taken from --api-key, then from a profile chosen with --profile or
+// $TLY_PROFILE, then from $TLY_API_KEY and finally from the default
+// profile.
+func (e *env) Client() (*tly.Client, error) {
+	if e.client != nil {
+		return e.client, nil
+	}
+	if _, err := e.loadProfile(); err != nil {
+		return nil, err
+	}
+	key, _ := e.resolveAPIKey()
+	if key == "" {
+		return nil, errors.New("no API key: set TLY_API_KEY, pass --api-key or add a profile to " + configPath(e.getenv))
+	}
+	var opts []tly.ClientOption
+	if e.profile.Domain != "" {
+		opts = append(opts, tly.WithDefaultDomain(e.profile.Domain))
+	}
+	e.client = tly.NewClient(key, opts...)
+	if e.profile.BaseURL != "" {
+		e.client.BaseURL = e.profile.BaseURL
+	}
+	if base := e.getenv("TLY_BASE_URL"); base != "" {
+		e.client.BaseURL = base
+	}
+	return e.client, nil
+}
+
+// resolveAPIKey returns the API key to use and where it came from: the
+// --api-key flag, then the profile when one was chosen explicitly, then
+// $TLY_API_KEY, then the default profile. Call loadProfile first.
+func (e *env) resolveAPIKey() (key, source string) {
+	profileSource := fmt.Sprintf("profile %q", e.profile.Name)
+	switch {
+	case e.apiKey != "":
+		return e.apiKey, "--api-key"
+	case (e.profileName != "" || e.getenv("TLY_PROFILE") != "") && e.profile.APIKey != "":
+		return e.profile.APIKey, profileSource
+	case e.getenv("TLY_API_KEY") != "":
+		return e.getenv("TLY_API_KEY"), "$TLY_API_KEY"
+	case e.profile.APIKey != "":
+		return e.profile.APIKey, profileSource
+	}
+	return "", ""
+}
+
+// commandFlags returns the names of the command's own flags given on the
+// command line, leaving out the shared ones.
+func (e *env) commandFlags(fs *flag.FlagSet) map[string]bool {
+	given := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		if !e.shared[f.Name] {
+			given[f.Name] = true
+		}
+	})
+	return given
+}
+
+// tagNames returns the tags given with a --tags flag, or the profile's
+// default tags when the flag was not given. Call it after Client.
+func (e *env) tagNames(fs *flag.FlagSet, value string) []string {
+	if !e.commandFlags(fs)["tags"] {
+		return e.profile.Tags
+	}
+	return splitList(value)
+}
+
+// maxRateLimitRetries is how often retry waits out a 429 before giving up.
+const maxRateLimitRetries = 3
+
+// retry calls fn, and when the API answers 429 waits as long as the rate
+// limit headers suggest and calls it again.
+func (e *env) retry(fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		var apiErr *tly.APIError
+		if attempt == maxRateLimitRetries || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+			return err
+		}
+		wait := time.Minute
+		if r, ok := e.client.LastRateLimit(); ok {
+			if d := r.Delay(time.Now()); d > 0 {
+				wait = d
+			}
+		}
+		fmt.Fprintf(e.stderr, "rate limited, retrying in %s\n", wait.Round(time.Second))
+		select {
+		case <-time.After(wait):
+		case <-e.ctx.Done():
+			return e.ctx.Err()
+		}
+	}
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr, os.Getenv)
+	stop()
+	os.Exit(code)
+}
+
+// run executes the command line args and returns the exit status.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		if len(args) > 1 {
+			if cmd, ok := commands[args[1]]; ok {
+				e := &env{ctx: ctx, stdin: stdin, stdout: stdout, stderr: stderr, getenv: getenv, cmd: cmd}
+				cmd.run(e, []string{"-help"})
+				return 0
+			}
+		}
+		printUsage(stderr)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "tly: unknown command %q\n", args[0])
+		printUsage(stderr)
+		return 2
+	}
+	e := &env{ctx: ctx, stdin: stdin, stdout: stdout, stderr: stderr, getenv: getenv, cmd: cmd}
+	err := cmd.run(e, args[1:])
+	var usage usageError
+	var status exitStatus
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &status):
+		return int(status)
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, &usage):
+		fmt.Fprintf(stderr, "tly %s: %v\n", e.cmd.name, err)
+		fmt.Fprintf(stderr, "Usage: tly %s [flags] %s\n", e.cmd.name, e.cmd.args)
+		return 2
+	default:
+		fmt.Fprintf(stderr, "tly %s: %v\n", e.cmd.name, err)
+		return 1
+	}
+}
+
+func printUsage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(w, "Usage: tly <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "tly help <command>" for the flags of a command.`)
+}
+
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+// output prints v as indented JSON with --json, through --format or --jq
+// when the command defines them, and calls human otherwise.
+func (e *env) output(v interface{}, human func(w io.Writer) error) error {
+	if e.formatted() {
+		return e.writeFormatted(v)
+	}
+	if e.json {
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	return human(e.stdout)
+}
+
+// fields prints aligned "name: value" lines, skipping empty values.
+func fields(w io.Writer, kv ...string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	for i := 0; i+1 < len(kv); i += 2 {
+		if kv[i+1] == "" {
+			continue
+		}
+		fmt.Fprintf(tw, "%s:\t%s\n", kv[i], kv[i+1])
+	}
+	return tw.Flush()
+}
+
+// printLink prints the details of a short link.
+func printLink(w io.Writer, link *tly.ShortLink) error {
+	tags := make([]string, len(link.Tags))
+	for i, t := range link.Tags {
+		tags[i] = t.Tag
+	}
+	pixels := make([]string, len(link.Pixels))
+	for i, p := range link.Pixels {
+		pixels[i] = p.Name
+	}
+	return fields(w,
+		"Short URL", link.ShortURL,
+		"Long URL", link.LongURL,
+		"Description", link.Description,
+		"Tags", strings.Join(tags, ", "),
+		"Pixels", strings.Join(pixels, ", "),
+		"Expires at", display(link.ExpireAtDatetime),
+		"Expires after", display(link.ExpireAtViews),
+		"Public stats", fmt.Sprint(link.PublicStats),
+		"Created", displayTime(link.CreatedAt),
+		"Updated", displayTime(link.UpdatedAt),
+	)
+}
+
+// displayTime formats an API timestamp, showing nothing when it is unset.
+func displayTime(t tly.Timestamp) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// display formats a loosely typed API value, showing nothing for null.
+func display(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return fmt.Sprintf("%g", v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+func init() {
+	register(&command{name: "pixel", args: "list|create|attach|detach|usage", summary: "List, create, attach and detach retargeting pixels.", run: group("pixel",
+		&command{name: "list", summary: "List the pixels.", run: runPixelList},
+		&command{name: "create", args: "--name <name> --type <type> --pixel-id <id>", summary: "Create a pixel.", run: runPixelCreate},
+		&command{name: "attach", args: "<pixel> <short-url>... | <pixel> --tag <name>", summary: "Add a pixel to links.", run: runPixelAttach},
+		&command{name: "detach", args: "<pixel> <short-url>... | <pixel> --all", summary: "Remove a pixel from links.", run: runPixelDetach},
+		&command{name: "usage", args: "[<pixel>]", summary: "Show how many links carry each pixel, or the links of one.", run: runPixelUsage},
+	)})
+}
+
+// lookupPixel finds the pixel an argument names: an ID, or a name. An
+// exact name match is preferred over a case-insensitive one.
+func lookupPixel(e *env, c *tly.Client, arg string) (*tly.Pixel, error) {
+	if id, err := strconv.Atoi(arg); err == nil {
+		return c.GetPixel(id)
+	}
+	pixels, err := c.ListAllPixels(e.ctx, tly.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var folded []tly.Pixel
+	for _, p := range pixels {
+		if p.Name == arg {
+			return &p, nil
+		}
+		if strings.EqualFold(p.Name, arg) {
+			folded = append(folded, p)
+		}
+	}
+	switch len(folded) {
+	case 0:
+		return nil, fmt.Errorf("no pixel named %q", arg)
+	case 1:
+		return &folded[0], nil
+	}
+	return nil, fmt.Errorf("%d pixels are named like %q; give the pixel ID instead", len(folded), arg)
+}
+
+// pixelLabel describes a pixel as `"name" (facebook 123, ID n)`.
+func pixelLabel(p tly.Pixel) string {
+	return fmt.Sprintf("%q (%s %s, ID %d)", p.Name, p.PixelType, p.PixelID, p.ID)
+}
+
+// hasPixel reports whether link carries the pixel id.
+func hasPixel(link tly.ShortLink, id int) bool {
+	for _, p := range link.Pixels {
+		if p.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func runPixelList(e *env, args []string) error {
+	fs := e.flagSet()
+	e.defineFormat(fs)
+	pixelType := fs.String("type", "", "only list pixels of this type")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return usagef("unexpected arguments")
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	var pixels []tly.Pixel
+	if *pixelType != "" {
+		pixels, err = c.ListPixelsByType(e.ctx, tly.PixelType(*pixelType))
+	} else {
+		pixels, err = c.ListAllPixels(e.ctx, tly.ListOptions{})
+	}
+	if err != nil {
+		return err
+	}
+	return e.output(pixels, func(w io.Writer) error {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tNAME\tTYPE\tPIXEL ID")
+		for _, p := range pixels {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", p.ID, p.Name, p.PixelType, p.PixelID)
+		}
+		return tw.Flush()
+	})
+}
+
+func runPixelCreate(e *env, args []string) error {
+	fs := e.flagSet()
+	name := fs.String("name", "", "display name of the pixel")
+	pixelType := fs.String("type", "", "platform of the pixel: "+pixelTypeList())
+	pixelID := fs.String("pixel-id", "", "ID of the pixel on its platform")
+	ifMissing := fs.Bool("if-missing", false, "return the existing pixel with this type and pixel ID instead of failing")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return usagef("unexpected arguments")
+	}
+	if *name == "" || *pixelType == "" || *pixelID == "" {
+		return usagef("--name, --type and --pixel-id are required")
+	}
+	if !tly.PixelType(*pixelType).Valid() {
+		return usagef("unknown pixel type %q: want one of %s", *pixelType, pixelTypeList())
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	req := tly.PixelCreateRequest{Name: *name, PixelType: tly.PixelType(*pixelType), PixelID: *pixelID}
+	var pixel *tly.Pixel
+	if *ifMissing {
+		pixel, err = c.GetOrCreatePixel(e.ctx, req)
+	} else {
+		pixel, err = c.CreatePixel(req)
+	}
+	if err != nil {
+		return err
+	}
+	return e.output(pixel, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "Pixel %s\n", pixelLabel(*pixel))
+		return err
+	})
+}
+
+// pixelTypeList lists the supported pixel types, comma-separated.
+func pixelTypeList() string {
+	types := tly.PixelTypes()
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = string(t)
+	}
+	return strings.Join(names, ", ")
+}
+
+func runPixelAttach(e *env, args []string) error {
+	fs := e.flagSet()
+	tag := fs.String("tag", "", "attach to every link with this tag")
+	var s safety
+	s.define(fs)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 || len(args) == 1 && *tag == "" {
+		return usagef("expected a pixel and short URLs or --tag")
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	pixel, err := lookupPixel(e, c, args[0])
+	if err != nil {
+		return err
+	}
+	links, err := lookupLinks(e, c, args[1:], *tag)
+	if err != nil {
+		return err
+	}
+	var pending []tly.ShortLink
+	for _, l := range links {
+		if !hasPixel(l, pixel.ID) {
+			pending = append(pending, l)
+		}
+	}
+	if skipped := len(links) - len(pending); skipped > 0 {
+		fmt.Fprintf(e.stderr, "skipped %s already carrying the pixel\n", plural(skipped, "link"))
+	}
+	return changePixel(e, s, "attach", "to", pixel, pending, func(shortURL string) error {
+		_, err := c.AttachPixelToLink(e.ctx, shortURL, pixel.ID)
+		return err
+	})
+}
+
+func runPixelDetach(e *env, args []string) error {
+	fs := e.flagSet()
+	all := fs.Bool("all", false, "detach from every link carrying the pixel")
+	var s safety
+	s.define(fs)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 || len(args) == 1 && !*all || len(args) > 1 && *all {
+		return usagef("expected a pixel and either short URLs or --all")
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	pixel, err := lookupPixel(e, c, args[0])
+	if err != nil {
+		return err
+	}
+	var links []tly.ShortLink
+	if *all {
+		links, err = c.PixelUsage(e.ctx, pixel.ID)
+	} else {
+		links, err = lookupLinks(e, c, args[1:], "")
+	}
+	if err != nil {
+		return err
+	}
+	var pending []tly.ShortLink
+	for _, l := range links {
+		if *all || hasPixel(l, pixel.ID) {
+			pending = append(pending, l)
+		}
+	}
+	if skipped := len(links) - len(pending); skipped > 0 {
+		fmt.Fprintf(e.stderr, "skipped %s not carrying the pixel\n", plural(skipped, "link"))
+	}
+	return changePixel(e, s, "detach", "from", pixel, pending, func(shortURL string) error {
+		_, err := c.DetachPixelFromLink(e.ctx, shortURL, pixel.ID)
+		return err
+	})
+}
+
+// changePixel confirms and applies change, which attaches or detaches
+// pixel as verb says, to every link and reports each link's outcome.
+func changePixel(e *env, s safety, verb, prep string, pixel *tly.Pixel, links []tly.ShortLink, change func(shortURL string) error) error {
+	if len(links) == 0 {
+		fmt.Fprintf(e.stderr, "no links to %s the pixel %s\n", verb, prep)
+		return nil
+	}
+	items := make([]string, len(links))
+	for i, l := range links {
+		items[i] = l.ShortURL + " -> " + l.LongURL
+	}
+	action := fmt.Sprintf("%s %s %s %s", verb, pixelLabel(*pixel), prep, plural(len(links), "link"))
+	if ok, err := e.confirm(s, action, items); !ok || err != nil {
+		return err
+	}
+	results := make([]linkResult, 0, len(links))
+	failed := 0
+	for _, l := range links {
+		err := e.retry(func() error { return change(l.ShortURL) })
+		r := linkResult{ShortURL: l.ShortURL, Status: verb + "ed"}
+		if err != nil {
+			r.Status, r.Error = "failed", err.Error()
+			failed++
+		}
+		results = append(results, r)
+	}
+	err := e.output(results, func(w io.Writer) error {
+		for _, r := range results {
+			if r.Error != "" {
+				fmt.Fprintf(w, "Failed to %s %s: %s\n", verb, r.ShortURL, r.Error)
+				continue
+			}
+			fmt.Fprintf(w, "%s%s %s\n", strings.ToUpper(r.Status[:1]), r.Status[1:], r.ShortURL)
+		}
+		return nil
+	})
+	if err == nil && failed > 0 {
+		err = fmt.Errorf("%s could not be changed", plural(failed, "link"))
+	}
+	return err
+}
+
+func runPixelUsage(e *env, args []string) error {
+	fs := e.flagSet()
+	e.defineFormat(fs)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 1 {
+		return usagef("expected at most one pixel")
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		pixel, err := lookupPixel(e, c, args[0])
+		if err != nil {
+			return err
+		}
+		links, err := c.PixelUsage(e.ctx, pixel.ID)
+		if err != nil {
+			return err
+		}
+		return e.output(links, func(w io.Writer) error {
+			fmt.Fprintf(w, "%s is on %s\n", pixelLabel(*pixel), plural(len(links), "link"))
+			for _, l := range links {
+				fmt.Fprintf(w, "  %s -> %s\n", l.ShortURL, l.LongURL)
+			}
+			return nil
+		})
+	}
+	var usage []tly.PixelLinkCount
+	err = e.retry(func() (err error) {
+		usage, err = c.PixelUsageCounts(e.ctx, tly.UsageOptions{})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return e.output(usage, func(w io.Writer) error {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "LINKS\tID\tNAME\tTYPE\tPIXEL ID")
+		for _, u := range usage {
+			p := u.Pixel
+			fmt.Fprintf(tw, "%d\t%d\t%s\t%s\t%s\n", u.Links, p.ID, p.Name, p.PixelType, p.PixelID)
+		}
+		return tw.Flush()
+	})
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+func init() {
+	register(&command{name: "qr", args: "<short-url>", summary: "Render a QR code for a link as PNG or SVG.", run: runQR})
+}
+
+// qrResult is the JSON output of the qr command when writing a file.
+type qrResult struct {
+	ShortURL string       `json:"short_url"`
+	File     string       `json:"file"`
+	Format   tly.QRFormat `json:"format"`
+	Size     int          `json:"size"`
+	Bytes    int          `json:"bytes"`
+	Source   tly.QRSource `json:"source"`
+}
+
+func runQR(e *env, args []string) error {
+	fs := e.flagSet()
+	out := fs.String("o", "", `file to write, or "-" for stdout (default stdout)`)
+	size := fs.Int("size", 512, "width and height in pixels")
+	format := fs.String("format", "", "png or svg (default from the -o extension, else png)")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	shortURL, err := oneArg(args, "short URL")
+	if err != nil {
+		return err
+	}
+	if *size <= 0 {
+		return usagef("--size must be positive")
+	}
+	f := tly.QRFormat(strings.ToLower(*format))
+	if f == "" {
+		f = tly.QRCodePNG
+		if strings.EqualFold(filepath.Ext(*out), ".svg") {
+			f = tly.QRCodeSVG
+		}
+	}
+	if f != tly.QRCodePNG && f != tly.QRCodeSVG {
+		return usagef("--format must be png or svg")
+	}
+	toStdout := *out == "" || *out == "-"
+	if toStdout && f == tly.QRCodePNG && isTerminal(e.stdout) {
+		return usagef("refusing to write a PNG to the terminal; use -o or --format svg")
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	var code *tly.QRCode
+	err = e.retry(func() (err error) {
+		code, err = c.GetQRCode(e.ctx, shortURL, tly.QRCodeOptions{Size: *size, Format: f})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if toStdout {
+		_, err := e.stdout.Write(code.Data)
+		return err
+	}
+	if err := ioutil.WriteFile(*out, code.Data, 0o644); err != nil {
+		return err
+	}
+	res := qrResult{ShortURL: shortURL, File: *out, Format: code.Format, Size: *size, Bytes: len(code.Data), Source: code.Source}
+	return e.output(res, func(w io.Writer) error {
+		return fields(w,
+			"File", res.File,
+			"Format", string(res.Format),
+			"Size", strconv.Itoa(res.Size)+"px",
+			"Bytes", strconv.Itoa(res.Bytes),
+			"Source", string(res.Source),
+		)
+	})
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+func init() {
+	register(&command{name: "serve", summary: "Serve a local HTTP API for shortening, expanding and stats.", run: runServe})
+}
+
+func runServe(e *env, args []string) error {
+	fs := e.flagSet()
+	addr := fs.String("addr", "127.0.0.1:8080", "address to listen on")
+	cacheTTL := fs.Duration("cache-ttl", 5*time.Minute, "how long to reuse responses; 0 disables caching")
+	rate := fs.Float64("rate", 5, "requests per second forwarded to T.LY")
+	burst := fs.Int("burst", 10, "requests forwarded at once before --rate applies")
+	quiet := fs.Bool("quiet", false, "do not log requests")
+	token := fs.String("token", "", "bearer token callers must send (default $TLY_SERVE_TOKEN)")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return usagef("unexpected arguments")
+	}
+	if *cacheTTL < 0 {
+		return usagef("--cache-ttl must not be negative")
+	}
+	if *rate <= 0 || *burst < 1 {
+		return usagef("--rate must be positive and --burst at least 1")
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	if *token == "" {
+		*token = e.getenv("TLY_SERVE_TOKEN")
+	}
+	s := &server{e: e, c: c, limit: newLimiter(*rate, *burst), quiet: *quiet, token: *token}
+	if *cacheTTL > 0 {
+		s.cache = tly.NewMemoryCache(*cacheTTL)
+	}
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: s.routes()}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-e.ctx.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	}()
+	fmt.Fprintf(e.stderr, "serving the T.LY API of %s on http://%s\n", c.BaseURL, ln.Addr())
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	<-done
+	return nil
+}
+
+// server is the local HTTP API of tly serve. It answers from its cache
+// where it can and forwards everything else to T.LY through one client,
+// no faster than its limiter allows. When token is set, callers must send
+// it as a bearer token.
+type server struct {
+	e     *env
+	c     *tly.Client
+	cache *tly.MemoryCache
+	limit *limiter
+	quiet bool
+	token string
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/shorten", s.handle(http.MethodPost, s.shorten))
+	mux.HandleFunc("/expand", s.handle(http.MethodGet, s.expand))
+	mux.HandleFunc("/stats", s.handle(http.MethodGet, s.stats))
+	return mux
+}
+
+// cachedAnswer is an answer served from the cache, already encoded.
+type cachedAnswer []byte
+
+// serveError is a failure reported to the caller with its own status.
+// retryAfter, when set, tells the caller when to try again.
+type serveError struct {
+	status     int
+	msg        string
+	retryAfter time.Duration
+}
+
+func (e *serveError) Error() string { return e.msg }
+
+func badRequest(format string, args ...interface{}) error {
+	return &serveError{status: http.StatusBadRequest, msg: fmt.Sprintf(format, args...)}
+}
+
+// handler answers a request with a value to encode as JSON, or with a
+// cachedAnswer. It returns the cache key to store a fresh answer under, or
+// "" not to cache it.
+type handler func(r *http.Request) (v interface{}, key string, err error)
+
+// handle wraps h with the token and method checks, the JSON encoding of
+// answers and errors, the caching of fresh answers and the request log.
+// The log has the path only: the query may hold a link's password.
+func (s *server) handle(method string, h handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		status, source := http.StatusOK, "-"
+		defer func() {
+			if !s.quiet {
+				fmt.Fprintf(s.e.stderr, "%s %s %d %s %s\n", r.Method, r.URL.Path, status, source, time.Since(start).Round(time.Millisecond))
+			}
+		}()
+		if !s.authorized(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			status = http.StatusUnauthorized
+			writeServeError(w, status, "missing or invalid bearer token")
+			return
+		}
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			status = http.StatusMethodNotAllowed
+			writeServeError(w, status, "use "+method)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+		v, key, err := h(r)
+		if data, ok := v.(cachedAnswer); ok {
+			source = "cache"
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(data)
+			return
+		}
+		var apiErr *tly.APIError
+		if err == nil || errors.As(err, &apiErr) {
+			source = "api"
+		}
+		if err != nil {
+			status = s.errorStatus(w, err)
+			writeServeError(w, status, errorMessage(err))
+			return
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			status = http.StatusInternalServerError
+			writeServeError(w, status, err.Error())
+			return
+		}
+		data = append(data, '\n')
+		if key != "" && s.cache != nil {
+			s.cache.Set(key, data)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}
+}
+
+// authorized reports whether r carries the server's token, if it has one.
+func (s *server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+	got := []byte(r.Header.Get("Authorization"))
+	return subtle.ConstantTimeCompare(got, []byte("Bearer "+s.token)) == 1
+}
+
+// maxBodySize is the largest request body accepted.
+const maxBodySize = 1 << 20
+
+// cached returns the answer stored under key, if any.
+func (s *server) cached(key string) (cachedAnswer, bool) {
+	if s.cache == nil {
+		return nil, false
+	}
+	data, ok := s.cache.Get(key)
+	return data, ok
+}
+
+// forward calls fn, which makes one request to T.LY, if the limiter has a
+// token for it. Otherwise it fails with 429 without calling T.LY.
+func (s *server) forward(fn func() error) error {
+	if wait, ok := s.limit.take(time.Now()); !ok {
+		return &serveError{status: http.StatusTooManyRequests, msg: "rate limited", retryAfter: wait}
+	}
+	return fn()
+}
+
+// errorStatus picks the status for err and sets Retry-After when the
+// caller should come back later.
+func (s *server) errorStatus(w http.ResponseWriter, err error) int {
+	var se *serveError
+	var apiErr *tly.APIError
+	switch {
+	case errors.As(err, &se):
+		if se.retryAfter > 0 {
+			setRetryAfter(w, se.retryAfter)
+		}
+		return se.status
+	case errors.As(err, &apiErr):
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests:
+			wait := time.Minute
+			if r, ok := s.c.LastRateLimit(); ok {
+				if d := r.Delay(time.Now()); d > 0 {
+					wait = d
+				}
+			}
+			setRetryAfter(w, wait)
+			return http.StatusTooManyRequests
+		case http.StatusNotFound, http.StatusUnprocessableEntity:
+			return apiErr.StatusCode
+		case http.StatusUnauthorized, http.StatusForbidden:
+			// The caller is not the one whose credentials failed.
+			return http.StatusBadGateway
+		}
+		if apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 {
+			return http.StatusBadRequest
+		}
+		return http.StatusBadGateway
+	}
+	return http.StatusBadGateway
+}
+
+// errorMessage returns the message of err, taken from the body of an API
+// error when it has one.
+func errorMessage(err error) string {
+	var apiErr *tly.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Message()
+	}
+	return err.Error()
+}
+
+// setRetryAfter sets Retry-After to d, rounded up to whole seconds.
+func setRetryAfter(w http.ResponseWriter, d time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+}
+
+func writeServeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{msg})
+}
+
+// shortenRequest is the body of POST /shorten.
+type shortenRequest struct {
+	LongURL     string   `json:"long_url"`
+	Domain      string   `json:"domain,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// shorten creates a short link. Shortening the same URL with the same
+// settings again within the cache TTL returns the same link instead of
+// creating another.
+func (s *server) shorten(r *http.Request) (interface{}, string, error) {
+	var req shortenRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return nil, "", badRequest("invalid JSON body: %v", err)
+	}
+	if req.LongURL == "" {
+		return nil, "", badRequest("long_url is required")
+	}
+	if req.Tags == nil {
+		req.Tags = s.e.profile.Tags
+	}
+	keyData, _ := json.Marshal(req)
+	key := "shorten " + string(keyData)
+	if data, ok := s.cached(key); ok {
+		return data, "", nil
+	}
+	create := tly.ShortLinkCreateRequest{LongURL: req.LongURL, Domain: req.Domain}
+	if req.Description != "" {
+		create.Description = &req.Description
+	}
+	var link *tly.ShortLink
+	err := s.forward(func() (err error) {
+		link, err = s.c.CreateShortLinkWithTagNames(r.Context(), create, req.Tags)
+		return err
+	})
+	return link, key, err
+}
+
+// expand resolves ?short_url=. Links with a password are expanded with
+// ?password= and never cached.
+func (s *server) expand(r *http.Request) (interface{}, string, error) {
+	q := r.URL.Query()
+	shortURL := q.Get("short_url")
+	if shortURL == "" {
+		return nil, "", badRequest("short_url is required")
+	}
+	password := q.Get("password")
+	key := ""
+	if password == "" {
+		key = "expand " + shortURL
+		if data, ok := s.cached(key); ok {
+			return data, "", nil
+		}
+	}
+	var resp *tly.ExpandResponse
+	err := s.forward(func() (err error) {
+		if password != "" {
+			resp, err = s.c.ExpandWithPassword(r.Context(), shortURL, tly.Password(password))
+		} else {
+			resp, err = s.c.ExpandShortLink(tly.ExpandRequest{ShortURL: shortURL})
+		}
+		return err
+	})
+	return resp, key, err
+}
+
+// stats returns the stats of ?short_url=, optionally from ?since=, which
+// takes the same values as tly stats --since.
+func (s *server) stats(r *http.Request) (interface{}, string, error) {
+	q := r.URL.Query()
+	shortURL := q.Get("short_url")
+	if shortURL == "" {
+		return nil, "", badRequest("short_url is required")
+	}
+	var opts tly.StatsOptions
+	since := strings.TrimSpace(q.Get("since"))
+	if since != "" {
+		t, err := parseSince(since, time.Now())
+		if err != nil {
+			return nil, "", badRequest("invalid since %q: want e.g. 7d, 12h, 2w or 2026-01-31", since)
+		}
+		opts.StartDate = t
+	}
+	key := "stats " + shortURL + " " + since
+	if data, ok := s.cached(key); ok {
+		return data, "", nil
+	}
+	var stats *tly.Stats
+	err := s.forward(func() (err error) {
+		stats, err = s.c.GetStatsWithOptions(r.Context(), shortURL, opts)
+		return err
+	})
+	return stats, key, err
+}
+
+// limiter is a token bucket: it holds up to burst tokens and gains rate
+// tokens per second.
+type limiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(rate float64, burst int) *limiter {
+	return &limiter{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// take spends a token if one is available. Otherwise it reports how long
+// until the next one.
+func (l *limiter) take(now time.Time) (wait time.Duration, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0, true
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second)), false
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+	"github.com/timleland/t.ly-go-url-shortener-api/tlytest"
+)
+
+func TestServeToken(t *testing.T) {
+	srv := tlytest.NewServer(tlytest.Options{})
+	defer srv.Close()
+	link, err := srv.Client().CreateShortLink(tly.ShortLinkCreateRequest{LongURL: "https://example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := "/expand?short_url=" + link.ShortURL
+	tests := []struct {
+		name  string
+		token string
+		auth  string
+		want  int
+	}{
+		{"no token configured", "", "", http.StatusOK},
+		{"token", "s3cret", "Bearer s3cret", http.StatusOK},
+		{"missing", "s3cret", "", http.StatusUnauthorized},
+		{"wrong", "s3cret", "Bearer other", http.StatusUnauthorized},
+		{"not bearer", "s3cret", "s3cret", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var log bytes.Buffer
+			s := &server{e: &env{stderr: &log}, c: srv.Client(), limit: newLimiter(100, 100), token: tt.token}
+			r := httptest.NewRequest("GET", target, nil)
+			if tt.auth != "" {
+				r.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			s.routes().ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Fatalf("status %d, want %d; body %s", w.Code, tt.want, w.Body)
+			}
+			if tt.want == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Errorf("WWW-Authenticate = %q, want Bearer", w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestServeLogOmitsQuery(t *testing.T) {
+	srv := tlytest.NewServer(tlytest.Options{})
+	defer srv.Close()
+	var log bytes.Buffer
+	s := &server{e: &env{stderr: &log}, c: srv.Client(), limit: newLimiter(100, 100)}
+	r := httptest.NewRequest("GET", "/expand?short_url=https://t.ly/none&password=hunter2", nil)
+	s.routes().ServeHTTP(httptest.NewRecorder(), r)
+	if got := log.String(); !strings.HasPrefix(got, "GET /expand 404 ") || strings.Contains(got, "hunter2") {
+		t.Errorf("log = %q, want the path without the query", got)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+func init() {
+	register(&command{name: "stats", args: "<short-url>", summary: "Show clicks, top countries and the daily series of a link.", run: runStats})
+}
+
+// linkStats is the JSON output of the stats command.
+type linkStats struct {
+	ShortURL     string              `json:"short_url"`
+	Clicks       int                 `json:"clicks"`
+	UniqueClicks int                 `json:"unique_clicks"`
+	Countries    []tly.CountryClicks `json:"countries"`
+	Daily        []tly.DailyClicks   `json:"daily"`
+}
+
+func runStats(e *env, args []string) error {
+	fs := e.flagSet()
+	e.defineFormat(fs)
+	since := fs.String("since", "", `start of the period: a duration such as "7d", "12h" or "2w", or a date such as 2026-01-31`)
+	table := fs.Bool("table", false, "print tables (the default)")
+	asCSV := fs.Bool("csv", false, "print a CSV report")
+	top := fs.Int("top", 10, "number of countries to show")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	shortURL, err := oneArg(args, "short URL")
+	if err != nil {
+		return err
+	}
+	modes := 0
+	for _, set := range []bool{*table, *asCSV, e.json, e.format != nil, e.jq != nil} {
+		if set {
+			modes++
+		}
+	}
+	if modes > 1 {
+		return usagef("--json, --table, --csv, --format and --jq are mutually exclusive")
+	}
+	var opts tly.StatsOptions
+	if *since != "" {
+		if opts.StartDate, err = parseSince(*since, time.Now()); err != nil {
+			return usagef("%v", err)
+		}
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	var stats *tly.Stats
+	err = e.retry(func() (err error) {
+		stats, err = c.GetStatsWithOptions(e.ctx, shortURL, opts)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if *asCSV {
+		return tly.WriteStatsReport(e.stdout, shortURL, stats, tly.StatsReportOptions{Stats: opts, TopN: *top})
+	}
+	countries := stats.GeoStats().Countries
+	if len(countries) > *top {
+		countries = countries[:*top]
+	}
+	out := linkStats{
+		ShortURL:     shortURL,
+		Clicks:       stats.Clicks,
+		UniqueClicks: stats.UniqueClicks,
+		Countries:    countries,
+		Daily:        stats.DailySeries(),
+	}
+	return e.output(out, func(w io.Writer) error {
+		return printStats(w, out)
+	})
+}
+
+// printStats prints the summary, top countries and the daily series with a
+// bar per day.
+func printStats(w io.Writer, s linkStats) error {
+	fmt.Fprintf(w, "%s\n\nClicks: %d (%d unique)\n", s.ShortURL, s.Clicks, s.UniqueClicks)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if len(s.Countries) > 0 {
+		fmt.Fprintln(tw, "\nCOUNTRY\tCODE\tCLICKS")
+		for _, c := range s.Countries {
+			fmt.Fprintf(tw, "%s\t%s\t%d\n", c.Name, c.Code, c.Total)
+		}
+	}
+	if len(s.Daily) > 0 {
+		peak := 0
+		for _, d := range s.Daily {
+			if d.Total > peak {
+				peak = d.Total
+			}
+		}
+		fmt.Fprintln(tw, "\nDATE\tCLICKS\t")
+		for _, d := range s.Daily {
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", d.Date, d.Total, bar(d.Total, peak, 40))
+		}
+	}
+	return tw.Flush()
+}
+
+// bar draws v as a bar of up to width characters relative to max.
+func bar(v, max, width int) string {
+	if max <= 0 || v <= 0 {
+		return ""
+	}
+	n := v * width / max
+	if n == 0 {
+		n = 1
+	}
+	return strings.Repeat("#", n)
+}
+
+// parseSince parses a --since value: a number of hours, days or weeks
+// before now, or a date.
+func parseSince(v string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", v, time.Local); err == nil {
+		return t, nil
+	}
+	unit := map[byte]time.Duration{'h': time.Hour, 'd': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	if len(v) > 1 {
+		if d, ok := unit[v[len(v)-1]]; ok {
+			if n, err := strconv.Atoi(v[:len(v)-1]); err == nil && n >= 0 {
+				return now.Add(-time.Duration(n) * d), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: want e.g. 7d, 12h, 2w or 2026-01-31", v)
+}
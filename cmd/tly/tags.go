@@ -0,0 +1,396 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+func init() {
+	register(&command{name: "tag", args: "list|create|rename|merge|rm|usage", summary: "List, create, rename, merge and delete tags.", run: group("tag",
+		&command{name: "list", summary: "List the tags.", run: runTagList},
+		&command{name: "create", args: "<name>...", summary: "Create tags.", run: runTagCreate},
+		&command{name: "rename", args: "<tag> <new-name>", summary: "Rename a tag.", run: runTagRename},
+		&command{name: "merge", args: "<tag>... --into <tag>", summary: "Move the links of tags onto another and delete them.", run: runTagMerge},
+		&command{name: "rm", args: "<tag>...", summary: "Delete tags.", run: runTagRemove},
+		&command{name: "usage", summary: "Show how many links carry each tag.", run: runTagUsage},
+	)})
+}
+
+// lookupTag finds the tag an argument names: an ID, or a name compared
+// case-insensitively.
+func lookupTag(e *env, c *tly.Client, arg string) (*tly.Tag, error) {
+	if id, err := strconv.Atoi(arg); err == nil {
+		return c.GetTag(id)
+	}
+	tag, err := c.FindTagByName(e.ctx, arg, tly.MatchCaseInsensitive)
+	if errors.Is(err, tly.ErrTagNotFound) {
+		return nil, fmt.Errorf("no tag named %q", arg)
+	}
+	return tag, err
+}
+
+// lookupTags finds the tags args name, without duplicates.
+func lookupTags(e *env, c *tly.Client, args []string) ([]tly.Tag, error) {
+	seen := map[int]bool{}
+	var tags []tly.Tag
+	for _, arg := range args {
+		tag, err := lookupTag(e, c, arg)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag.ID] {
+			seen[tag.ID] = true
+			tags = append(tags, *tag)
+		}
+	}
+	return tags, nil
+}
+
+// tagLabel describes a tag as `"name" (ID n)`.
+func tagLabel(t tly.Tag) string {
+	return fmt.Sprintf("%q (ID %d)", t.Tag, t.ID)
+}
+
+// linkCount returns how many links match opts, from the total of the first
+// page.
+func linkCount(e *env, c *tly.Client, opts tly.ShortLinkListOptions) (int, error) {
+	page, err := c.ListShortLinksPage(e.ctx, opts)
+	if err != nil {
+		return 0, err
+	}
+	if page.Total > 0 {
+		return page.Total, nil
+	}
+	return len(page.Data), nil
+}
+
+func runTagList(e *env, args []string) error {
+	fs := e.flagSet()
+	e.defineFormat(fs)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return usagef("unexpected arguments")
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	tags, err := c.ListAllTags(e.ctx, tly.ListOptions{})
+	if err != nil {
+		return err
+	}
+	return e.output(tags, func(w io.Writer) error {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tNAME\tCREATED")
+		for _, t := range tags {
+			created := ""
+			if !t.CreatedAt.IsZero() {
+				created = t.CreatedAt.Format("2006-01-02")
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\n", t.ID, t.Tag, created)
+		}
+		return tw.Flush()
+	})
+}
+
+func runTagCreate(e *env, args []string) error {
+	fs := e.flagSet()
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return usagef("expected tag names")
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	for _, name := range args {
+		if err := c.ValidateNewTag(e.ctx, name); err != nil {
+			return err
+		}
+	}
+	var created []tly.Tag
+	for _, name := range args {
+		tag, err := c.CreateTag(name)
+		if err != nil {
+			return fmt.Errorf("creating %q: %w", name, err)
+		}
+		created = append(created, *tag)
+	}
+	return e.output(created, func(w io.Writer) error {
+		for _, t := range created {
+			fmt.Fprintf(w, "Created tag %s\n", tagLabel(t))
+		}
+		return nil
+	})
+}
+
+func runTagRename(e *env, args []string) error {
+	fs := e.flagSet()
+	merge := fs.Bool("merge", false, "if another tag has the new name, merge into it")
+	var s safety
+	s.define(fs)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 {
+		return usagef("expected a tag and its new name, got %d arguments", len(args))
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	tag, err := lookupTag(e, c, args[0])
+	if err != nil {
+		return err
+	}
+	newName := args[1]
+	existing, err := c.FindTagByName(e.ctx, newName, tly.MatchExact)
+	if err != nil && !errors.Is(err, tly.ErrTagNotFound) {
+		return err
+	}
+	if existing != nil && existing.ID != tag.ID {
+		if !*merge {
+			return fmt.Errorf("tag %s already exists; pass --merge to merge %s into it", tagLabel(*existing), tagLabel(*tag))
+		}
+		return mergeTags(e, c, s, []tly.Tag{*tag}, *existing)
+	}
+	if s.dryRun {
+		_, err := e.confirm(s, "rename a tag", []string{fmt.Sprintf("%s -> %q", tagLabel(*tag), newName)})
+		return err
+	}
+	renamed, err := c.RenameTag(e.ctx, tag.ID, newName, tly.RenameTagOptions{})
+	if err != nil {
+		return err
+	}
+	return e.output(renamed, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "Renamed %q to %q\n", tag.Tag, renamed.Tag)
+		return err
+	})
+}
+
+func runTagMerge(e *env, args []string) error {
+	fs := e.flagSet()
+	into := fs.String("into", "", "tag to move the links onto (required)")
+	var s safety
+	s.define(fs)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return usagef("expected the tags to merge")
+	}
+	if *into == "" {
+		return usagef("--into is required")
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	target, err := lookupTag(e, c, *into)
+	if err != nil {
+		return err
+	}
+	sources, err := lookupTags(e, c, args)
+	if err != nil {
+		return err
+	}
+	return mergeTags(e, c, s, sources, *target)
+}
+
+// mergeTags confirms and runs a merge of sources into target, listing the
+// links that change and the tags that are deleted.
+func mergeTags(e *env, c *tly.Client, s safety, sources []tly.Tag, target tly.Tag) error {
+	var ids []int
+	names := map[int]string{target.ID: target.Tag}
+	for _, t := range sources {
+		if t.ID != target.ID {
+			ids = append(ids, t.ID)
+			names[t.ID] = t.Tag
+		}
+	}
+	if len(ids) == 0 {
+		return usagef("nothing to merge: the tags are the target")
+	}
+	plan, err := c.MergeTags(e.ctx, ids, target.ID, tly.MergeTagsOptions{DryRun: true})
+	if err != nil {
+		return err
+	}
+	var items []string
+	for _, ch := range plan.Links {
+		items = append(items, fmt.Sprintf("retag %s: %s -> %s", ch.ShortURL, tagNames(ch.OldTags, names), tagNames(ch.NewTags, names)))
+	}
+	for _, id := range plan.DeletedTags {
+		items = append(items, fmt.Sprintf("delete tag %q (ID %d)", names[id], id))
+	}
+	action := fmt.Sprintf("merge %s into %q", plural(len(ids), "tag"), target.Tag)
+	if ok, err := e.confirm(s, action, items); !ok || err != nil {
+		return err
+	}
+	report, mergeErr := c.MergeTags(e.ctx, ids, target.ID, tly.MergeTagsOptions{})
+	if report == nil {
+		return mergeErr
+	}
+	failed := 0
+	for _, ch := range report.Links {
+		if ch.Err != nil {
+			fmt.Fprintf(e.stderr, "failed to retag %s: %v\n", ch.ShortURL, ch.Err)
+			failed++
+		}
+	}
+	err = e.output(report, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "Retagged %s and deleted %s\n",
+			plural(len(report.Links)-failed, "link"), plural(len(report.DeletedTags), "tag"))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return mergeErr
+}
+
+// tagNames lists the tags ids by name where known, else by ID.
+func tagNames(ids []int, names map[int]string) string {
+	if len(ids) == 0 {
+		return "(none)"
+	}
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		if name, ok := names[id]; ok {
+			parts[i] = name
+		} else {
+			parts[i] = "#" + strconv.Itoa(id)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// tagDeleteResult is the JSON output of tag rm for one tag.
+type tagDeleteResult struct {
+	Tag    string `json:"tag"`
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func runTagRemove(e *env, args []string) error {
+	fs := e.flagSet()
+	force := fs.Bool("force", false, "delete tags even if links carry them, removing them from the links")
+	reassign := fs.String("reassign", "", "move the links of the tags onto this tag first")
+	var s safety
+	s.define(fs)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return usagef("expected the tags to delete")
+	}
+	if *force && *reassign != "" {
+		return usagef("--force and --reassign are mutually exclusive")
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	tags, err := lookupTags(e, c, args)
+	if err != nil {
+		return err
+	}
+	if *reassign != "" {
+		target, err := lookupTag(e, c, *reassign)
+		if err != nil {
+			return err
+		}
+		return mergeTags(e, c, s, tags, *target)
+	}
+	items := make([]string, len(tags))
+	for i, t := range tags {
+		n, err := linkCount(e, c, tly.ShortLinkListOptions{TagIDs: []int{t.ID}})
+		if err != nil {
+			return err
+		}
+		if n > 0 && !*force {
+			return fmt.Errorf("tag %s is on %s; pass --reassign <tag> to move them or --force to remove it from them", tagLabel(t), plural(n, "link"))
+		}
+		items[i] = fmt.Sprintf("%s, on %s", tagLabel(t), plural(n, "link"))
+	}
+	if ok, err := e.confirm(s, "delete "+plural(len(tags), "tag"), items); !ok || err != nil {
+		return err
+	}
+	results := make([]tagDeleteResult, 0, len(tags))
+	failed := 0
+	for _, t := range tags {
+		var err error
+		if *force {
+			err = c.DeleteTag(t.ID)
+		} else {
+			err = c.DeleteTagSafely(e.ctx, t.ID, tly.DeleteTagOptions{})
+		}
+		r := tagDeleteResult{Tag: t.Tag, ID: t.ID, Status: "deleted"}
+		if err != nil {
+			r.Status, r.Error = "failed", err.Error()
+			failed++
+		}
+		results = append(results, r)
+	}
+	err = e.output(results, func(w io.Writer) error {
+		for _, r := range results {
+			if r.Error != "" {
+				fmt.Fprintf(w, "Failed to delete tag %q: %s\n", r.Tag, r.Error)
+				continue
+			}
+			fmt.Fprintf(w, "Deleted tag %q\n", r.Tag)
+		}
+		return nil
+	})
+	if err == nil && failed > 0 {
+		err = fmt.Errorf("%s could not be deleted", plural(failed, "tag"))
+	}
+	return err
+}
+
+func runTagUsage(e *env, args []string) error {
+	fs := e.flagSet()
+	e.defineFormat(fs)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return usagef("unexpected arguments")
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	var usage []tly.TagLinkCount
+	err = e.retry(func() (err error) {
+		usage, err = c.TagUsage(e.ctx, tly.UsageOptions{})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return e.output(usage, func(w io.Writer) error {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "LINKS\tTAG")
+		for _, u := range usage {
+			fmt.Fprintf(tw, "%d\t%s\n", u.Links, u.Tag.Tag)
+		}
+		return tw.Flush()
+	})
+}
@@ -0,0 +1,57 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// stty runs stty on the terminal with args and returns its output.
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// rawTerminal switches the terminal to unbuffered, unechoed input so single
+// key presses can be read, and returns a function restoring the previous
+// mode.
+func rawTerminal() (restore func(), err error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("-icanon", "-echo", "min", "1"); err != nil {
+		return nil, err
+	}
+	return func() { stty(saved) }, nil
+}
+
+// terminalSize returns the rows and columns of the terminal, or 24x80 when
+// they cannot be read.
+func terminalSize() (rows, cols int) {
+	out, err := stty("size")
+	if err == nil {
+		if f := strings.Fields(out); len(f) == 2 {
+			rows, _ = strconv.Atoi(f[0])
+			cols, _ = strconv.Atoi(f[1])
+		}
+	}
+	if rows <= 0 || cols <= 0 {
+		return 24, 80
+	}
+	return rows, cols
+}
+
+// stdinIsTerminal reports whether standard input is a terminal. Unlike
+// isTerminal it is not fooled by other character devices such as
+// /dev/null.
+func stdinIsTerminal() bool {
+	_, err := stty("-g")
+	return err == nil
+}
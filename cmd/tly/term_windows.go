@@ -0,0 +1,23 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// rawTerminal is not implemented on Windows; the dashboard needs a Unix
+// terminal.
+func rawTerminal() (restore func(), err error) {
+	return nil, errors.New("the dashboard needs a Unix terminal")
+}
+
+func terminalSize() (rows, cols int) {
+	return 24, 80
+}
+
+func stdinIsTerminal() bool {
+	return isTerminal(os.Stdin)
+}
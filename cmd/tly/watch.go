@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+func init() {
+	register(&command{name: "watch", args: "<short-url>", summary: "Print click changes of a link as they happen.", run: runWatch})
+}
+
+// thresholdReached is the exit status of watch when --threshold is reached.
+const thresholdReached exitStatus = 3
+
+// watchLine is one JSON line printed by the watch command per poll.
+type watchLine struct {
+	At              time.Time           `json:"at"`
+	ShortURL        string              `json:"short_url"`
+	Clicks          int                 `json:"clicks"`
+	UniqueClicks    int                 `json:"unique_clicks"`
+	NewClicks       int                 `json:"new_clicks"`
+	NewUniqueClicks int                 `json:"new_unique_clicks"`
+	Countries       []tly.BreakdownItem `json:"countries,omitempty"`
+}
+
+func runWatch(e *env, args []string) error {
+	fs := e.flagSet()
+	interval := fs.Duration("interval", 30*time.Second, "time between polls")
+	threshold := fs.Int("threshold", 0, "exit with status 3 once the link has this many clicks")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	shortURL, err := oneArg(args, "short URL")
+	if err != nil {
+		return err
+	}
+	if *interval < time.Second {
+		return usagef("--interval must be at least 1s")
+	}
+	if *threshold < 0 {
+		return usagef("--threshold must not be negative")
+	}
+	c, err := e.Client()
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(e.stdout)
+	polls := 0
+	for u := range c.WatchStats(e.ctx, []string{shortURL}, tly.WatchOptions{Interval: *interval}) {
+		polls++
+		if u.Err != nil {
+			if polls == 1 {
+				return u.Err
+			}
+			fmt.Fprintf(e.stderr, "warning: %v\n", u.Err)
+			continue
+		}
+		line := watchLine{
+			At:           u.At,
+			ShortURL:     u.ShortURL,
+			Clicks:       u.Stats.Clicks,
+			UniqueClicks: u.Stats.UniqueClicks,
+		}
+		if !u.Delta.Initial {
+			line.NewClicks = u.Delta.Clicks
+			line.NewUniqueClicks = u.Delta.UniqueClicks
+			line.Countries = u.Delta.Countries
+		}
+		if e.json {
+			err = enc.Encode(line)
+		} else {
+			err = printWatchLine(e, line, u.Delta.Initial)
+		}
+		if err != nil {
+			return err
+		}
+		if *threshold > 0 && line.Clicks >= *threshold {
+			fmt.Fprintf(e.stderr, "threshold of %d clicks reached\n", *threshold)
+			return thresholdReached
+		}
+	}
+	return nil
+}
+
+// printWatchLine prints the totals of a poll and, after the first, the
+// clicks gained and the countries they came from.
+func printWatchLine(e *env, l watchLine, initial bool) error {
+	at := l.At.Format("15:04:05")
+	if initial {
+		_, err := fmt.Fprintf(e.stdout, "%s  %d clicks  %d unique\n", at, l.Clicks, l.UniqueClicks)
+		return err
+	}
+	var from []string
+	for _, c := range l.Countries {
+		if c.Total > 0 {
+			from = append(from, fmt.Sprintf("%s +%d", c.Name, c.Total))
+		}
+	}
+	suffix := ""
+	if len(from) > 0 {
+		suffix = "  " + strings.Join(from, ", ")
+	}
+	_, err := fmt.Fprintf(e.stdout, "%s  %d clicks (+%d)  %d unique (+%d)%s\n",
+		at, l.Clicks, l.NewClicks, l.UniqueClicks, l.NewUniqueClicks, suffix)
+	return err
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseYAML parses the subset of YAML the config file needs: nested
+// mappings by indentation, block ("- item") and flow ("[a, b]") sequences
+// of scalars, quoted or plain scalars and # comments. Scalars are returned
+// as strings, mappings as map[string]interface{} and sequences as
+// []interface{}. Anchors, multi-line strings and flow mappings are not
+// supported.
+func parseYAML(data string) (map[string]interface{}, error) {
+	var lines []yamlLine
+	for i, raw := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if lead := raw[:len(raw)-len(strings.TrimLeft(raw, " \t"))]; strings.Contains(lead, "\t") {
+			return nil, fmt.Errorf("line %d: indent with spaces, not tabs", i+1)
+		}
+		text := strings.TrimRight(stripComment(raw), " ")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		lines = append(lines, yamlLine{n: i + 1, indent: len(text) - len(trimmed), text: trimmed})
+	}
+	if len(lines) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	p := &yamlParser{lines: lines}
+	v, err := p.block(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.i < len(lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", lines[p.i].n)
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("line %d: top level must be a mapping", lines[0].n)
+	}
+	return m, nil
+}
+
+type yamlLine struct {
+	n      int
+	indent int
+	text   string
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	i     int
+}
+
+// block parses the mapping or sequence starting at the current line, which
+// is indented by indent.
+func (p *yamlParser) block(indent int) (interface{}, error) {
+	if strings.HasPrefix(p.lines[p.i].text, "- ") || p.lines[p.i].text == "-" {
+		return p.sequence(indent)
+	}
+	return p.mapping(indent)
+}
+
+func (p *yamlParser) sequence(indent int) (interface{}, error) {
+	var items []interface{}
+	for p.i < len(p.lines) && p.lines[p.i].indent == indent {
+		line := p.lines[p.i]
+		if !strings.HasPrefix(line.text, "-") {
+			return nil, fmt.Errorf("line %d: expected a list item", line.n)
+		}
+		item := strings.TrimSpace(strings.TrimPrefix(line.text, "-"))
+		if strings.Contains(item, ": ") || strings.HasSuffix(item, ":") {
+			return nil, fmt.Errorf("line %d: lists of mappings are not supported", line.n)
+		}
+		v, err := scalar(item, line.n)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+		p.i++
+	}
+	return items, nil
+}
+
+func (p *yamlParser) mapping(indent int) (interface{}, error) {
+	m := map[string]interface{}{}
+	for p.i < len(p.lines) && p.lines[p.i].indent == indent {
+		line := p.lines[p.i]
+		colon := strings.Index(line.text, ":")
+		if colon <= 0 || (colon+1 < len(line.text) && line.text[colon+1] != ' ') {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", line.n)
+		}
+		key := unquote(strings.TrimSpace(line.text[:colon]))
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", line.n, key)
+		}
+		rest := strings.TrimSpace(line.text[colon+1:])
+		p.i++
+		if rest != "" {
+			v, err := scalar(rest, line.n)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+			continue
+		}
+		if next := p.i; next < len(p.lines) && (p.lines[next].indent > indent ||
+			p.lines[next].indent == indent && strings.HasPrefix(p.lines[next].text, "-")) {
+			v, err := p.block(p.lines[next].indent)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+			continue
+		}
+		m[key] = ""
+	}
+	return m, nil
+}
+
+// scalar parses a plain, quoted or flow sequence value.
+func scalar(s string, n int) (interface{}, error) {
+	if strings.HasPrefix(s, "[") {
+		if !strings.HasSuffix(s, "]") {
+			return nil, fmt.Errorf("line %d: unterminated list", n)
+		}
+		items := []interface{}{}
+		for _, item := range strings.Split(s[1:len(s)-1], ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, unquote(item))
+			}
+		}
+		return items, nil
+	}
+	if strings.HasPrefix(s, "{") {
+		return nil, fmt.Errorf("line %d: flow mappings are not supported", n)
+	}
+	return unquote(s), nil
+}
+
+// unquote removes single or double quotes around s.
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if u, err := strconv.Unquote(s); err == nil {
+			return u
+		}
+	}
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	return s
+}
+
+// stripComment removes a # comment that is outside quotes and starts the
+// line or follows a space. Quotes only count at the start of a value, so
+// apostrophes inside plain scalars are kept.
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch ch := line[i]; {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case (ch == '"' || ch == '\'') && (i == 0 || strings.IndexByte(" :[,", line[i-1]) >= 0):
+			quote = ch
+		case ch == '#' && (i == 0 || line[i-1] == ' '):
+			return line[:i]
+		}
+	}
+	return line
+}
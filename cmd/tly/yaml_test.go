@@ -0,0 +1,103 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want map[string]interface{}
+	}{
+		{"empty", "", map[string]interface{}{}},
+		{"comments only", "# config\n---\n", map[string]interface{}{}},
+		{
+			name: "scalars",
+			in:   "api_key: abc123\ndomain: \"https://t.ly/\"\nname: 'it''s'\nempty:\n",
+			want: map[string]interface{}{"api_key": "abc123", "domain": "https://t.ly/", "name": "it's", "empty": ""},
+		},
+		{
+			name: "nested mappings",
+			in:   "profiles:\n  work:\n    api_key: w\n  home:\n    api_key: h\ndefault: work\n",
+			want: map[string]interface{}{
+				"profiles": map[string]interface{}{
+					"work": map[string]interface{}{"api_key": "w"},
+					"home": map[string]interface{}{"api_key": "h"},
+				},
+				"default": "work",
+			},
+		},
+		{
+			name: "block sequences",
+			in:   "tags:\n  - a\n  - \"b c\"\npixels:\n- 1\n- 2\n",
+			want: map[string]interface{}{
+				"tags":   []interface{}{"a", "b c"},
+				"pixels": []interface{}{"1", "2"},
+			},
+		},
+		{
+			name: "flow sequences",
+			in:   "tags: [a, 'b', \"c\"]\nnone: []\n",
+			want: map[string]interface{}{
+				"tags": []interface{}{"a", "b", "c"},
+				"none": []interface{}{},
+			},
+		},
+		{
+			name: "trailing comments",
+			in:   "url: https://example.com/#top # the landing page\nnote: \"# not a comment\"\nsay: don't # plain apostrophe\n",
+			want: map[string]interface{}{"url": "https://example.com/#top", "note": "# not a comment", "say": "don't"},
+		},
+		{
+			name: "windows line endings",
+			in:   "a: 1\r\nb: 2\r\n",
+			want: map[string]interface{}{"a": "1", "b": "2"},
+		},
+		{
+			name: "quoted keys",
+			in:   "\"odd key\": v\n",
+			want: map[string]interface{}{"odd key": "v"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYAML(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseYAML(%q) = %#v, want %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"tab indent", "a:\n\tb: 1\n", "line 2: indent with spaces"},
+		{"missing colon", "a: 1\njust text\n", `line 2: expected "key: value"`},
+		{"no space after colon", "a:1\n", `line 1: expected "key: value"`},
+		{"duplicate key", "a: 1\na: 2\n", `line 2: duplicate key "a"`},
+		{"bad indentation", "a:\n    b: 1\n  c: 2\n", "line 3: unexpected indentation"},
+		{"top level sequence", "- a\n- b\n", "line 1: top level must be a mapping"},
+		{"list of mappings", "a:\n  - b: 1\n", "line 2: lists of mappings are not supported"},
+		{"flow mapping", "a: {b: 1}\n", "line 1: flow mappings are not supported"},
+		{"unterminated list", "a: [b, c\n", "line 1: unterminated list"},
+		{"mixed block", "a:\n  - b\n  c: 1\n", "line 3: expected a list item"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseYAML(tt.in)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseYAML(%q) error = %v, want %q", tt.in, err, tt.want)
+			}
+		})
+	}
+}
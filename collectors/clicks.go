@@ -0,0 +1,194 @@
+// Package collectors exposes T.LY link metrics to monitoring systems.
+//
+// The collectors render the Prometheus text exposition format directly so
+// the SDK stays free of third-party dependencies; mount a collector on any
+// http.ServeMux path and point a Prometheus scrape job at it.
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+// ClickCollectorOptions configures a ClickCollector.
+type ClickCollectorOptions struct {
+	// Filter selects the links to export. The zero value exports every link.
+	Filter tly.ShortLinkListOptions
+	// Namespace prefixes every metric name. Defaults to "tly".
+	Namespace string
+	// RefreshInterval controls how often Run refreshes the metrics. When it
+	// is zero the metrics are refreshed on every scrape instead.
+	RefreshInterval time.Duration
+}
+
+// clickSample is the exported state of one link.
+type clickSample struct {
+	shortID      string
+	domain       string
+	tags         []string
+	clicks       int
+	uniqueClicks int
+}
+
+// ClickCollector exports per-link click counts as Prometheus gauges labeled
+// by short_id, domain and tag. A link carrying several tags is exported once
+// per tag, so sum by short_id rather than across tags to avoid double counting.
+type ClickCollector struct {
+	client *tly.Client
+	opts   ClickCollectorOptions
+
+	mu        sync.Mutex
+	samples   []clickSample
+	refreshed time.Time
+	failures  int
+}
+
+// NewClickCollector creates a collector for the links selected by opts.
+func NewClickCollector(client *tly.Client, opts ClickCollectorOptions) *ClickCollector {
+	if opts.Namespace == "" {
+		opts.Namespace = "tly"
+	}
+	return &ClickCollector{client: client, opts: opts}
+}
+
+// Refresh fetches the current link list and stats and replaces the exported
+// samples. The stats are fetched with GetStatsMulti, several links at a
+// time. On error the previous samples are kept.
+func (c *ClickCollector) Refresh(ctx context.Context) error {
+	links, err := c.client.ListAllShortLinks(ctx, c.opts.Filter)
+	if err != nil {
+		c.recordFailure()
+		return err
+	}
+	urls := make([]string, len(links))
+	for i, link := range links {
+		urls[i] = link.ShortURL
+	}
+	all, err := c.client.GetStatsMulti(ctx, urls, tly.StatsOptions{})
+	if err != nil {
+		c.recordFailure()
+		return err
+	}
+	samples := make([]clickSample, 0, len(links))
+	for _, link := range links {
+		stats := all[link.ShortURL]
+		s := clickSample{
+			shortID:      link.ShortID,
+			domain:       link.Domain,
+			clicks:       stats.Clicks,
+			uniqueClicks: stats.UniqueClicks,
+		}
+		for _, tag := range link.Tags {
+			s.tags = append(s.tags, tag.Tag)
+		}
+		samples = append(samples, s)
+	}
+	c.mu.Lock()
+	c.samples = samples
+	c.refreshed = time.Now()
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *ClickCollector) recordFailure() {
+	c.mu.Lock()
+	c.failures++
+	c.mu.Unlock()
+}
+
+// Run refreshes the metrics every RefreshInterval until ctx is done. Refresh
+// errors are counted in the failures metric and otherwise ignored.
+func (c *ClickCollector) Run(ctx context.Context) {
+	if c.opts.RefreshInterval <= 0 {
+		return
+	}
+	c.Refresh(ctx)
+	ticker := time.NewTicker(c.opts.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Refresh(ctx)
+		}
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format. When no
+// RefreshInterval is configured the metrics are refreshed first.
+func (c *ClickCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if c.opts.RefreshInterval <= 0 {
+		// A failed refresh still serves the last good samples; the
+		// failures counter tells the scraper something went wrong.
+		c.Refresh(r.Context())
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
+
+// WriteTo writes the current metrics to w in the Prometheus text format.
+func (c *ClickCollector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	samples := c.samples
+	refreshed := c.refreshed
+	failures := c.failures
+	c.mu.Unlock()
+
+	ns := c.opts.Namespace
+	var b strings.Builder
+	writeGauge(&b, ns+"_link_clicks", "Total clicks per short link.", samples,
+		func(s clickSample) int { return s.clicks })
+	writeGauge(&b, ns+"_link_unique_clicks", "Unique clicks per short link.", samples,
+		func(s clickSample) int { return s.uniqueClicks })
+
+	fmt.Fprintf(&b, "# HELP %s_collector_last_refresh_timestamp_seconds Unix time of the last successful refresh.\n", ns)
+	fmt.Fprintf(&b, "# TYPE %s_collector_last_refresh_timestamp_seconds gauge\n", ns)
+	var ts int64
+	if !refreshed.IsZero() {
+		ts = refreshed.Unix()
+	}
+	fmt.Fprintf(&b, "%s_collector_last_refresh_timestamp_seconds %d\n", ns, ts)
+	fmt.Fprintf(&b, "# HELP %s_collector_refresh_failures_total Number of failed refreshes.\n", ns)
+	fmt.Fprintf(&b, "# TYPE %s_collector_refresh_failures_total counter\n", ns)
+	fmt.Fprintf(&b, "%s_collector_refresh_failures_total %d\n", ns, failures)
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// writeGauge renders one gauge family, one series per (link, tag) pair.
+func writeGauge(b *strings.Builder, name, help string, samples []clickSample, value func(clickSample) int) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s gauge\n", name)
+	lines := make([]string, 0, len(samples))
+	for _, s := range samples {
+		tags := s.tags
+		if len(tags) == 0 {
+			tags = []string{""}
+		}
+		for _, tag := range tags {
+			lines = append(lines, fmt.Sprintf("%s{short_id=\"%s\",domain=\"%s\",tag=\"%s\"} %d\n",
+				name, escapeLabel(s.shortID), escapeLabel(s.domain), escapeLabel(tag), value(s)))
+		}
+	}
+	sort.Strings(lines)
+	for _, l := range lines {
+		b.WriteString(l)
+	}
+}
+
+// escapeLabel escapes a label value per the text exposition format.
+func escapeLabel(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, "\n", `\n`)
+	return strings.ReplaceAll(v, `"`, `\"`)
+}
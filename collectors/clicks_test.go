@@ -0,0 +1,108 @@
+package collectors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+	"github.com/timleland/t.ly-go-url-shortener-api/tlytest"
+)
+
+func TestClickCollector(t *testing.T) {
+	srv := tlytest.NewServer(tlytest.Options{})
+	defer srv.Close()
+	c := srv.Client()
+	for _, name := range []string{"news", `a "b" \c`} {
+		if _, err := c.CreateTag(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a, b := "a", "b"
+	links := []tly.ShortLinkCreateRequest{
+		{LongURL: "https://example.com/a", ShortID: &a, Tags: []int{1, 2}},
+		{LongURL: "https://example.com/b", ShortID: &b},
+	}
+	for _, req := range links {
+		if _, err := c.CreateShortLink(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, v := range []tlytest.Visit{{Visitor: "1.1.1.1"}, {Visitor: "1.1.1.1"}, {Visitor: "2.2.2.2"}} {
+		if err := srv.Click("https://t.ly/a", v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	collector := NewClickCollector(c, ClickCollectorOptions{})
+	w := httptest.NewRecorder()
+	collector.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	got := regexp.MustCompile(`(?m)^(tly_collector_last_refresh_timestamp_seconds) [1-9]\d*$`).ReplaceAllString(w.Body.String(), "$1 <now>")
+	want := `# HELP tly_link_clicks Total clicks per short link.
+# TYPE tly_link_clicks gauge
+tly_link_clicks{short_id="a",domain="https://t.ly/",tag="a \"b\" \\c"} 3
+tly_link_clicks{short_id="a",domain="https://t.ly/",tag="news"} 3
+tly_link_clicks{short_id="b",domain="https://t.ly/",tag=""} 0
+# HELP tly_link_unique_clicks Unique clicks per short link.
+# TYPE tly_link_unique_clicks gauge
+tly_link_unique_clicks{short_id="a",domain="https://t.ly/",tag="a \"b\" \\c"} 2
+tly_link_unique_clicks{short_id="a",domain="https://t.ly/",tag="news"} 2
+tly_link_unique_clicks{short_id="b",domain="https://t.ly/",tag=""} 0
+# HELP tly_collector_last_refresh_timestamp_seconds Unix time of the last successful refresh.
+# TYPE tly_collector_last_refresh_timestamp_seconds gauge
+tly_collector_last_refresh_timestamp_seconds <now>
+# HELP tly_collector_refresh_failures_total Number of failed refreshes.
+# TYPE tly_collector_refresh_failures_total counter
+tly_collector_refresh_failures_total 0
+`
+	if got != want {
+		t.Errorf("exposition:\n%s\nwant:\n%s", got, want)
+	}
+
+	// A failed refresh keeps the last samples and counts the failure.
+	srv.Inject("GET /api/v1/link/stats", tlytest.Fault{Status: http.StatusInternalServerError})
+	if err := collector.Refresh(context.Background()); err == nil {
+		t.Fatal("Refresh succeeded despite a failing stats request")
+	}
+	var out strings.Builder
+	collector.WriteTo(&out)
+	for _, line := range []string{
+		`tly_link_clicks{short_id="a",domain="https://t.ly/",tag="news"} 3`,
+		"tly_collector_refresh_failures_total 1",
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("after a failed refresh the output lacks %q:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestClickCollectorConcurrentStats(t *testing.T) {
+	srv := tlytest.NewServer(tlytest.Options{})
+	defer srv.Close()
+	c := srv.Client()
+	for i := 0; i < 8; i++ {
+		if _, err := c.CreateShortLink(tly.ShortLinkCreateRequest{LongURL: "https://example.com"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Each stats request takes 100ms; one at a time the refresh would take
+	// 800ms.
+	srv.Inject("GET /api/v1/link/stats", tlytest.Fault{Latency: 100 * time.Millisecond, Times: 8})
+	start := time.Now()
+	if err := NewClickCollector(c, ClickCollectorOptions{}).Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 600*time.Millisecond {
+		t.Errorf("refresh took %v, want the stats fetched concurrently", elapsed)
+	}
+	if got := srv.Requests("GET /api/v1/link/stats"); got != 8 {
+		t.Errorf("%d stats requests, want 8", got)
+	}
+}
@@ -0,0 +1,381 @@
+//go:build contract
+// +build contract
+
+package tly_test
+
+// The contract tests run the SDK against a real T.LY account to detect
+// changes to the API before users do. They only build with the contract
+// tag:
+//
+//	TLY_API_KEY=... go test -tags contract -run Contract -v .
+//
+// Without TLY_API_KEY they run against the tlytest fake server instead.
+// Every resource they create is named with a unique prefix and deleted
+// again when the test ends, including after failures. Besides checking each
+// call, they compare the fields of every JSON response with the SDK's
+// types: a field the SDK expects but the API no longer sends fails the
+// test, and a field the SDK does not know yet is logged as a warning.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+	"github.com/timleland/t.ly-go-url-shortener-api/tlytest"
+)
+
+// recorder keeps the body of the last response of each route.
+type recorder struct {
+	next   http.RoundTripper
+	mu     sync.Mutex
+	bodies map[string][]byte
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	r.mu.Lock()
+	r.bodies[req.Method+" "+req.URL.Path] = body
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// last returns the body of the last response of route.
+func (r *recorder) last(route string) []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.bodies[route]
+}
+
+// contractClient returns a client for the account of TLY_API_KEY, or for a
+// tlytest server when it is unset, that records every response.
+func contractClient(t *testing.T) (*tly.Client, *recorder) {
+	var c *tly.Client
+	if key := os.Getenv("TLY_API_KEY"); key != "" {
+		c = tly.NewClient(key)
+		if base := os.Getenv("TLY_BASE_URL"); base != "" {
+			c.BaseURL = base
+		}
+	} else {
+		t.Log("TLY_API_KEY is not set; running against tlytest")
+		srv := tlytest.NewServer(tlytest.Options{})
+		t.Cleanup(srv.Close)
+		c = srv.Client()
+	}
+	rec := &recorder{next: http.DefaultTransport, bodies: map[string][]byte{}}
+	c.Client = &http.Client{Transport: rec, Timeout: 30 * time.Second}
+	return c, rec
+}
+
+// checkShape compares the fields of the last response of route with the
+// JSON fields of v's type. The response may be an object, an array of
+// objects or a paginated list whose items are under "data".
+func checkShape(t *testing.T, rec *recorder, route string, v interface{}) {
+	t.Helper()
+	var raw interface{}
+	if err := json.Unmarshal(rec.last(route), &raw); err != nil {
+		t.Errorf("%s: response is not JSON: %v", route, err)
+		return
+	}
+	if m, ok := raw.(map[string]interface{}); ok {
+		if data, ok := m["data"].([]interface{}); ok {
+			if _, own := jsonFields(reflect.TypeOf(v))["data"]; !own {
+				raw = data
+			}
+		}
+	}
+	objects := []map[string]interface{}{}
+	switch x := raw.(type) {
+	case map[string]interface{}:
+		objects = append(objects, x)
+	case []interface{}:
+		for _, item := range x {
+			if m, ok := item.(map[string]interface{}); ok {
+				objects = append(objects, m)
+			}
+		}
+	}
+	want := jsonFields(reflect.TypeOf(v))
+	missing := map[string]bool{}
+	for _, obj := range objects {
+		for f, optional := range want {
+			if _, ok := obj[f]; !ok && !optional {
+				missing[f] = true
+			}
+		}
+		for f := range obj {
+			if _, known := want[f]; !known {
+				t.Logf("WARN %s: unknown field %q", route, f)
+				want[f] = true
+			}
+		}
+	}
+	if len(missing) > 0 {
+		t.Errorf("%s: missing fields %s", route, strings.Join(sortedKeys(missing), ", "))
+	}
+}
+
+// jsonFields returns the JSON field names of struct type t, mapped to
+// whether they are optional (omitempty). Embedded structs are flattened.
+func jsonFields(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	fields := map[string]bool{}
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if f.Anonymous && tag == "" {
+			for name, optional := range jsonFields(f.Type) {
+				fields[name] = optional
+			}
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "-" || f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = strings.Contains(tag, ",omitempty")
+	}
+	return fields
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// deleteAtEnd registers del to run when t ends, whatever the outcome. A 404
+// counts as already deleted.
+func deleteAtEnd(t *testing.T, name string, del func() error) {
+	t.Cleanup(func() {
+		if err := del(); err != nil && !tly.IsNotFound(err) {
+			t.Errorf("cleanup: %s: %v", name, err)
+		}
+	})
+}
+
+// TestContract exercises tags, pixels and links. Subtests depending on a
+// failed one are skipped.
+func TestContract(t *testing.T) {
+	// Cleanups are registered on the test itself, not on the subtest that
+	// created the resource, so later subtests can still use it.
+	top := t
+	c, rec := contractClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	prefix := fmt.Sprintf("contract-%d-", time.Now().Unix())
+	t.Logf("prefix %s", prefix)
+
+	var tag *tly.Tag
+	t.Run("create tag", func(t *testing.T) {
+		created, err := c.CreateTag(prefix + "tag")
+		if err != nil {
+			t.Fatal(err)
+		}
+		id := created.ID
+		deleteAtEnd(top, "delete tag", func() error { return c.DeleteTag(id) })
+		tag = created
+		checkShape(t, rec, "POST /api/v1/link/tag", tly.Tag{})
+	})
+	if tag != nil {
+		t.Run("duplicate tag is rejected", func(t *testing.T) {
+			_, err := c.CreateTag(prefix + "tag")
+			var apiErr *tly.APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity && apiErr.StatusCode != http.StatusConflict {
+				t.Errorf("want a 422 or 409, got %v", err)
+			}
+		})
+		t.Run("rename tag", func(t *testing.T) {
+			updated, err := c.UpdateTag(tag.ID, prefix+"tag-renamed")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if updated.Tag != prefix+"tag-renamed" {
+				t.Errorf("tag is %q after renaming", updated.Tag)
+			}
+		})
+		t.Run("list tags", func(t *testing.T) {
+			tags, err := c.ListAllTags(ctx, tly.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, got := range tags {
+				if got.ID == tag.ID {
+					checkShape(t, rec, "GET /api/v1/link/tag", tly.Tag{})
+					return
+				}
+			}
+			t.Errorf("tag %d is not listed", tag.ID)
+		})
+	}
+
+	var pixel *tly.Pixel
+	t.Run("create pixel", func(t *testing.T) {
+		created, err := c.CreatePixel(tly.PixelCreateRequest{Name: prefix + "pixel", PixelID: strings.Trim(prefix, "-"), PixelType: tly.PixelTypeFacebook})
+		if err != nil {
+			t.Fatal(err)
+		}
+		id := created.ID
+		deleteAtEnd(top, "delete pixel", func() error { return c.DeletePixel(id) })
+		pixel = created
+		checkShape(t, rec, "POST /api/v1/link/pixel", tly.Pixel{})
+	})
+	if pixel != nil {
+		t.Run("get pixel", func(t *testing.T) {
+			got, err := c.GetPixel(pixel.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.PixelType != tly.PixelTypeFacebook {
+				t.Errorf("pixel type is %q", got.PixelType)
+			}
+			checkShape(t, rec, fmt.Sprintf("GET /api/v1/link/pixel/%d", pixel.ID), tly.Pixel{})
+		})
+		t.Run("list pixels", func(t *testing.T) {
+			if _, err := c.ListAllPixels(ctx, tly.ListOptions{}); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	t.Run("invalid long URL is rejected", func(t *testing.T) {
+		if _, err := c.CreateShortLink(tly.ShortLinkCreateRequest{LongURL: "not a url"}); err == nil {
+			t.Error("an invalid long URL was accepted")
+		}
+	})
+
+	var link *tly.ShortLink
+	t.Run("create link", func(t *testing.T) {
+		alias := prefix + "link"
+		desc := "tly contract run"
+		req := tly.ShortLinkCreateRequest{LongURL: "https://example.com/" + prefix, ShortID: &alias, Description: &desc}
+		if tag != nil {
+			req.Tags = []int{tag.ID}
+		}
+		if pixel != nil {
+			req.Pixels = []int{pixel.ID}
+		}
+		created, err := c.CreateShortLink(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shortURL := created.ShortURL
+		deleteAtEnd(top, "delete link", func() error { return c.DeleteShortLink(shortURL) })
+		link = created
+		if link.ShortID != alias {
+			t.Errorf("short ID is %q, want %q", link.ShortID, alias)
+		}
+		checkShape(t, rec, "POST /api/v1/link/shorten", tly.ShortLink{})
+	})
+	if link == nil {
+		return
+	}
+	t.Run("taken alias is rejected", func(t *testing.T) {
+		alias := link.ShortID
+		if _, err := c.CreateShortLink(tly.ShortLinkCreateRequest{LongURL: "https://example.com", ShortID: &alias}); err == nil {
+			t.Error("a taken alias was accepted")
+		}
+	})
+	t.Run("get link", func(t *testing.T) {
+		got, err := c.GetShortLink(link.ShortURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.LongURL != link.LongURL {
+			t.Errorf("long URL is %q, want %q", got.LongURL, link.LongURL)
+		}
+		checkShape(t, rec, "GET /api/v1/link", tly.ShortLink{})
+	})
+	t.Run("update link", func(t *testing.T) {
+		desc := "tly contract run, updated"
+		got, err := c.UpdateShortLink(tly.ShortLinkUpdateRequest{ShortURL: link.ShortURL, LongURL: link.LongURL + "/updated", Description: &desc})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Description != desc {
+			t.Errorf("description is %q after the update", got.Description)
+		}
+		link = got
+		checkShape(t, rec, "PUT /api/v1/link", tly.ShortLink{})
+	})
+	t.Run("list links", func(t *testing.T) {
+		page, err := c.ListShortLinksPage(ctx, tly.ShortLinkListOptions{Search: prefix})
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkShape(t, rec, "GET /api/v1/link/list", tly.ShortLinkPage{})
+		for _, l := range page.Data {
+			if l.ShortURL == link.ShortURL {
+				return
+			}
+		}
+		t.Errorf("%s is not listed when searching for it", link.ShortURL)
+	})
+	if tag != nil {
+		t.Run("list links by tag", func(t *testing.T) {
+			links, err := c.ListShortLinksByTag(ctx, tly.TagByID(tag.ID), tly.ShortLinkListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(links) != 1 || links[0].ShortURL != link.ShortURL {
+				t.Errorf("got %d links for the tag, want only %s", len(links), link.ShortURL)
+			}
+		})
+	}
+	t.Run("expand link", func(t *testing.T) {
+		resp, err := c.ExpandShortLink(tly.ExpandRequest{ShortURL: link.ShortURL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.LongURL != link.LongURL || resp.Expired {
+			t.Errorf("expanded to %q (expired %v), want %q", resp.LongURL, resp.Expired, link.LongURL)
+		}
+		checkShape(t, rec, "POST /api/v1/link/expand", tly.ExpandResponse{})
+	})
+	t.Run("link stats", func(t *testing.T) {
+		if _, err := c.GetStatsWithOptions(ctx, link.ShortURL, tly.StatsOptions{NotFoundRetry: 10 * time.Second}); err != nil {
+			t.Fatal(err)
+		}
+		checkShape(t, rec, "GET /api/v1/link/stats", tly.Stats{})
+	})
+	t.Run("delete link", func(t *testing.T) {
+		if err := c.DeleteShortLink(link.ShortURL); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.GetShortLink(link.ShortURL); !tly.IsNotFound(err) {
+			t.Errorf("getting the deleted link returned %v, want a 404", err)
+		}
+	})
+}
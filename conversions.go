@@ -0,0 +1,110 @@
+package tly
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrConversionStatsUnavailable is returned when the stats payload carries
+// no pixel conversion data, e.g. because the link has no pixels or the
+// account plan does not report conversions.
+var ErrConversionStatsUnavailable = errors.New("tly: pixel conversion stats not available")
+
+// PixelConversions is the fire and conversion counts of one pixel.
+type PixelConversions struct {
+	PixelID     int    `json:"pixel_id"`
+	Name        string `json:"name"`
+	Fires       int    `json:"fires"`
+	Conversions int    `json:"conversions"`
+}
+
+// ConversionStats is the pixel activity recorded for a link or a pixel.
+type ConversionStats struct {
+	Clicks      int                `json:"clicks"`
+	Fires       int                `json:"fires"`
+	Conversions int                `json:"conversions"`
+	Pixels      []PixelConversions `json:"pixels"`
+}
+
+// ConversionRate returns conversions divided by clicks, or 0 without clicks.
+func (c *ConversionStats) ConversionRate() float64 {
+	if c.Clicks == 0 {
+		return 0
+	}
+	return float64(c.Conversions) / float64(c.Clicks)
+}
+
+// ConversionStats returns the typed pixel conversion data of the payload.
+// It reports false when the payload has none.
+func (s *Stats) ConversionStats() (*ConversionStats, bool) {
+	if s.Conversions == nil {
+		return nil, false
+	}
+	cs := &ConversionStats{Clicks: s.Clicks}
+	for _, r := range s.Conversions {
+		row, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := row["name"].(string)
+		p := PixelConversions{
+			PixelID:     toInt(row["pixel_id"]),
+			Name:        name,
+			Fires:       toInt(row["fires"]),
+			Conversions: toInt(row["conversions"]),
+		}
+		cs.Fires += p.Fires
+		cs.Conversions += p.Conversions
+		cs.Pixels = append(cs.Pixels, p)
+	}
+	return cs, true
+}
+
+// GetConversionStats retrieves the pixel conversion stats of a short link.
+func (c *Client) GetConversionStats(ctx context.Context, shortURL string, opts StatsOptions) (*ConversionStats, error) {
+	stats, err := c.GetStatsWithOptions(ctx, shortURL, opts)
+	if err != nil {
+		return nil, err
+	}
+	cs, ok := stats.ConversionStats()
+	if !ok {
+		return nil, ErrConversionStatsUnavailable
+	}
+	return cs, nil
+}
+
+// GetPixelConversionStats sums the conversion stats of pixelID across every
+// link it is attached to. Clicks covers all of those links. Links whose
+// payload has no conversion data contribute only their clicks.
+func (c *Client) GetPixelConversionStats(ctx context.Context, pixelID int, opts StatsOptions) (*ConversionStats, error) {
+	links, err := c.ListAllShortLinks(ctx, ShortLinkListOptions{PixelIDs: []int{pixelID}})
+	if err != nil {
+		return nil, err
+	}
+	stats, err := c.fetchStats(ctx, links, opts, defaultConcurrency)
+	if err != nil {
+		return nil, err
+	}
+	total := &ConversionStats{}
+	pixel := PixelConversions{PixelID: pixelID}
+	for _, s := range stats {
+		total.Clicks += s.Clicks
+		cs, ok := s.ConversionStats()
+		if !ok {
+			continue
+		}
+		for _, p := range cs.Pixels {
+			if p.PixelID != pixelID {
+				continue
+			}
+			if pixel.Name == "" {
+				pixel.Name = p.Name
+			}
+			pixel.Fires += p.Fires
+			pixel.Conversions += p.Conversions
+		}
+	}
+	total.Fires, total.Conversions = pixel.Fires, pixel.Conversions
+	total.Pixels = []PixelConversions{pixel}
+	return total, nil
+}
@@ -0,0 +1,264 @@
+package tly
+
+// country is an ISO 3166-1 country with an approximate centroid.
+type country struct {
+	code string
+	name string
+	lat  float64
+	lng  float64
+}
+
+// countries lists ISO 3166-1 alpha-2 codes, common English names and
+// approximate centroids used by the geo helpers.
+var countries = []country{
+	{"AD", "Andorra", 42.546245, 1.601554},
+	{"AE", "United Arab Emirates", 23.424076, 53.847818},
+	{"AF", "Afghanistan", 33.93911, 67.709953},
+	{"AG", "Antigua and Barbuda", 17.060816, -61.796428},
+	{"AI", "Anguilla", 18.220554, -63.068615},
+	{"AL", "Albania", 41.153332, 20.168331},
+	{"AM", "Armenia", 40.069099, 45.038189},
+	{"AO", "Angola", -11.202692, 17.873887},
+	{"AR", "Argentina", -38.416097, -63.616672},
+	{"AS", "American Samoa", -14.270972, -170.132217},
+	{"AT", "Austria", 47.516231, 14.550072},
+	{"AU", "Australia", -25.274398, 133.775136},
+	{"AW", "Aruba", 12.52111, -69.968338},
+	{"AZ", "Azerbaijan", 40.143105, 47.576927},
+	{"BA", "Bosnia and Herzegovina", 43.915886, 17.679076},
+	{"BB", "Barbados", 13.193887, -59.543198},
+	{"BD", "Bangladesh", 23.684994, 90.356331},
+	{"BE", "Belgium", 50.503887, 4.469936},
+	{"BF", "Burkina Faso", 12.238333, -1.561593},
+	{"BG", "Bulgaria", 42.733883, 25.48583},
+	{"BH", "Bahrain", 25.930414, 50.637772},
+	{"BI", "Burundi", -3.373056, 29.918886},
+	{"BJ", "Benin", 9.30769, 2.315834},
+	{"BM", "Bermuda", 32.321384, -64.75737},
+	{"BN", "Brunei", 4.535277, 114.727669},
+	{"BO", "Bolivia", -16.290154, -63.588653},
+	{"BR", "Brazil", -14.235004, -51.92528},
+	{"BS", "Bahamas", 25.03428, -77.39628},
+	{"BT", "Bhutan", 27.514162, 90.433601},
+	{"BW", "Botswana", -22.328474, 24.684866},
+	{"BY", "Belarus", 53.709807, 27.953389},
+	{"BZ", "Belize", 17.189877, -88.49765},
+	{"CA", "Canada", 56.130366, -106.346771},
+	{"CD", "Democratic Republic of the Congo", -4.038333, 21.758664},
+	{"CF", "Central African Republic", 6.611111, 20.939444},
+	{"CG", "Republic of the Congo", -0.228021, 15.827659},
+	{"CH", "Switzerland", 46.818188, 8.227512},
+	{"CI", "Ivory Coast", 7.539989, -5.54708},
+	{"CL", "Chile", -35.675147, -71.542969},
+	{"CM", "Cameroon", 7.369722, 12.354722},
+	{"CN", "China", 35.86166, 104.195397},
+	{"CO", "Colombia", 4.570868, -74.297333},
+	{"CR", "Costa Rica", 9.748917, -83.753428},
+	{"CU", "Cuba", 21.521757, -77.781167},
+	{"CV", "Cape Verde", 16.002082, -24.013197},
+	{"CY", "Cyprus", 35.126413, 33.429859},
+	{"CZ", "Czechia", 49.817492, 15.472962},
+	{"DE", "Germany", 51.165691, 10.451526},
+	{"DJ", "Djibouti", 11.825138, 42.590275},
+	{"DK", "Denmark", 56.26392, 9.501785},
+	{"DM", "Dominica", 15.414999, -61.370976},
+	{"DO", "Dominican Republic", 18.735693, -70.162651},
+	{"DZ", "Algeria", 28.033886, 1.659626},
+	{"EC", "Ecuador", -1.831239, -78.183406},
+	{"EE", "Estonia", 58.595272, 25.013607},
+	{"EG", "Egypt", 26.820553, 30.802498},
+	{"ER", "Eritrea", 15.179384, 39.782334},
+	{"ES", "Spain", 40.463667, -3.74922},
+	{"ET", "Ethiopia", 9.145, 40.489673},
+	{"FI", "Finland", 61.92411, 25.748151},
+	{"FJ", "Fiji", -16.578193, 179.414413},
+	{"FM", "Micronesia", 7.425554, 150.550812},
+	{"FO", "Faroe Islands", 61.892635, -6.911806},
+	{"FR", "France", 46.227638, 2.213749},
+	{"GA", "Gabon", -0.803689, 11.609444},
+	{"GB", "United Kingdom", 55.378051, -3.435973},
+	{"GD", "Grenada", 12.262776, -61.604171},
+	{"GE", "Georgia", 42.315407, 43.356892},
+	{"GH", "Ghana", 7.946527, -1.023194},
+	{"GI", "Gibraltar", 36.137741, -5.345374},
+	{"GL", "Greenland", 71.706936, -42.604303},
+	{"GM", "Gambia", 13.443182, -15.310139},
+	{"GN", "Guinea", 9.945587, -9.696645},
+	{"GQ", "Equatorial Guinea", 1.650801, 10.267895},
+	{"GR", "Greece", 39.074208, 21.824312},
+	{"GT", "Guatemala", 15.783471, -90.230759},
+	{"GU", "Guam", 13.444304, 144.793731},
+	{"GW", "Guinea-Bissau", 11.803749, -15.180413},
+	{"GY", "Guyana", 4.860416, -58.93018},
+	{"HK", "Hong Kong", 22.396428, 114.109497},
+	{"HN", "Honduras", 15.199999, -86.241905},
+	{"HR", "Croatia", 45.1, 15.2},
+	{"HT", "Haiti", 18.971187, -72.285215},
+	{"HU", "Hungary", 47.162494, 19.503304},
+	{"ID", "Indonesia", -0.789275, 113.921327},
+	{"IE", "Ireland", 53.41291, -8.24389},
+	{"IL", "Israel", 31.046051, 34.851612},
+	{"IN", "India", 20.593684, 78.96288},
+	{"IQ", "Iraq", 33.223191, 43.679291},
+	{"IR", "Iran", 32.427908, 53.688046},
+	{"IS", "Iceland", 64.963051, -19.020835},
+	{"IT", "Italy", 41.87194, 12.56738},
+	{"JM", "Jamaica", 18.109581, -77.297508},
+	{"JO", "Jordan", 30.585164, 36.238414},
+	{"JP", "Japan", 36.204824, 138.252924},
+	{"KE", "Kenya", -0.023559, 37.906193},
+	{"KG", "Kyrgyzstan", 41.20438, 74.766098},
+	{"KH", "Cambodia", 12.565679, 104.990963},
+	{"KI", "Kiribati", -3.370417, -168.734039},
+	{"KM", "Comoros", -11.875001, 43.872219},
+	{"KN", "Saint Kitts and Nevis", 17.357822, -62.782998},
+	{"KP", "North Korea", 40.339852, 127.510093},
+	{"KR", "South Korea", 35.907757, 127.766922},
+	{"KW", "Kuwait", 29.31166, 47.481766},
+	{"KY", "Cayman Islands", 19.513469, -80.566956},
+	{"KZ", "Kazakhstan", 48.019573, 66.923684},
+	{"LA", "Laos", 19.85627, 102.495496},
+	{"LB", "Lebanon", 33.854721, 35.862285},
+	{"LC", "Saint Lucia", 13.909444, -60.978893},
+	{"LI", "Liechtenstein", 47.166, 9.555373},
+	{"LK", "Sri Lanka", 7.873054, 80.771797},
+	{"LR", "Liberia", 6.428055, -9.429499},
+	{"LS", "Lesotho", -29.609988, 28.233608},
+	{"LT", "Lithuania", 55.169438, 23.881275},
+	{"LU", "Luxembourg", 49.815273, 6.129583},
+	{"LV", "Latvia", 56.879635, 24.603189},
+	{"LY", "Libya", 26.3351, 17.228331},
+	{"MA", "Morocco", 31.791702, -7.09262},
+	{"MC", "Monaco", 43.750298, 7.412841},
+	{"MD", "Moldova", 47.411631, 28.369885},
+	{"ME", "Montenegro", 42.708678, 19.37439},
+	{"MG", "Madagascar", -18.766947, 46.869107},
+	{"MH", "Marshall Islands", 7.131474, 171.184478},
+	{"MK", "North Macedonia", 41.608635, 21.745275},
+	{"ML", "Mali", 17.570692, -3.996166},
+	{"MM", "Myanmar", 21.913965, 95.956223},
+	{"MN", "Mongolia", 46.862496, 103.846656},
+	{"MO", "Macao", 22.198745, 113.543873},
+	{"MR", "Mauritania", 21.00789, -10.940835},
+	{"MT", "Malta", 35.937496, 14.375416},
+	{"MU", "Mauritius", -20.348404, 57.552152},
+	{"MV", "Maldives", 3.202778, 73.22068},
+	{"MW", "Malawi", -13.254308, 34.301525},
+	{"MX", "Mexico", 23.634501, -102.552784},
+	{"MY", "Malaysia", 4.210484, 101.975766},
+	{"MZ", "Mozambique", -18.665695, 35.529562},
+	{"NA", "Namibia", -22.95764, 18.49041},
+	{"NC", "New Caledonia", -20.904305, 165.618042},
+	{"NE", "Niger", 17.607789, 8.081666},
+	{"NG", "Nigeria", 9.081999, 8.675277},
+	{"NI", "Nicaragua", 12.865416, -85.207229},
+	{"NL", "Netherlands", 52.132633, 5.291266},
+	{"NO", "Norway", 60.472024, 8.468946},
+	{"NP", "Nepal", 28.394857, 84.124008},
+	{"NZ", "New Zealand", -40.900557, 174.885971},
+	{"OM", "Oman", 21.512583, 55.923255},
+	{"PA", "Panama", 8.537981, -80.782127},
+	{"PE", "Peru", -9.189967, -75.015152},
+	{"PF", "French Polynesia", -17.679742, -149.406843},
+	{"PG", "Papua New Guinea", -6.314993, 143.95555},
+	{"PH", "Philippines", 12.879721, 121.774017},
+	{"PK", "Pakistan", 30.375321, 69.345116},
+	{"PL", "Poland", 51.919438, 19.145136},
+	{"PR", "Puerto Rico", 18.220833, -66.590149},
+	{"PS", "Palestine", 31.952162, 35.233154},
+	{"PT", "Portugal", 39.399872, -8.224454},
+	{"PW", "Palau", 7.51498, 134.58252},
+	{"PY", "Paraguay", -23.442503, -58.443832},
+	{"QA", "Qatar", 25.354826, 51.183884},
+	{"RE", "Reunion", -21.115141, 55.536384},
+	{"RO", "Romania", 45.943161, 24.96676},
+	{"RS", "Serbia", 44.016521, 21.005859},
+	{"RU", "Russia", 61.52401, 105.318756},
+	{"RW", "Rwanda", -1.940278, 29.873888},
+	{"SA", "Saudi Arabia", 23.885942, 45.079162},
+	{"SB", "Solomon Islands", -9.64571, 160.156194},
+	{"SC", "Seychelles", -4.679574, 55.491977},
+	{"SD", "Sudan", 12.862807, 30.217636},
+	{"SE", "Sweden", 60.128161, 18.643501},
+	{"SG", "Singapore", 1.352083, 103.819836},
+	{"SI", "Slovenia", 46.151241, 14.995463},
+	{"SK", "Slovakia", 48.669026, 19.699024},
+	{"SL", "Sierra Leone", 8.460555, -11.779889},
+	{"SM", "San Marino", 43.94236, 12.457777},
+	{"SN", "Senegal", 14.497401, -14.452362},
+	{"SO", "Somalia", 5.152149, 46.199616},
+	{"SR", "Suriname", 3.919305, -56.027783},
+	{"SS", "South Sudan", 6.876992, 31.306978},
+	{"ST", "Sao Tome and Principe", 0.18636, 6.613081},
+	{"SV", "El Salvador", 13.794185, -88.89653},
+	{"SY", "Syria", 34.802075, 38.996815},
+	{"SZ", "Eswatini", -26.522503, 31.465866},
+	{"TD", "Chad", 15.454166, 18.732207},
+	{"TG", "Togo", 8.619543, 0.824782},
+	{"TH", "Thailand", 15.870032, 100.992541},
+	{"TJ", "Tajikistan", 38.861034, 71.276093},
+	{"TL", "Timor-Leste", -8.874217, 125.727539},
+	{"TM", "Turkmenistan", 38.969719, 59.556278},
+	{"TN", "Tunisia", 33.886917, 9.537499},
+	{"TO", "Tonga", -21.178986, -175.198242},
+	{"TR", "Turkey", 38.963745, 35.243322},
+	{"TT", "Trinidad and Tobago", 10.691803, -61.222503},
+	{"TW", "Taiwan", 23.69781, 120.960515},
+	{"TZ", "Tanzania", -6.369028, 34.888822},
+	{"UA", "Ukraine", 48.379433, 31.16558},
+	{"UG", "Uganda", 1.373333, 32.290275},
+	{"US", "United States", 37.09024, -95.712891},
+	{"UY", "Uruguay", -32.522779, -55.765835},
+	{"UZ", "Uzbekistan", 41.377491, 64.585262},
+	{"VA", "Vatican City", 41.902916, 12.453389},
+	{"VC", "Saint Vincent and the Grenadines", 12.984305, -61.287228},
+	{"VE", "Venezuela", 6.42375, -66.58973},
+	{"VG", "British Virgin Islands", 18.420695, -64.639968},
+	{"VI", "U.S. Virgin Islands", 18.335765, -64.896335},
+	{"VN", "Vietnam", 14.058324, 108.277199},
+	{"VU", "Vanuatu", -15.376706, 166.959158},
+	{"WS", "Samoa", -13.759029, -172.104629},
+	{"XK", "Kosovo", 42.602636, 20.902977},
+	{"YE", "Yemen", 15.552727, 48.516388},
+	{"ZA", "South Africa", -30.559482, 22.937506},
+	{"ZM", "Zambia", -13.133897, 27.849332},
+	{"ZW", "Zimbabwe", -19.015438, 29.154857},
+}
+
+// countryAliases maps alternate country names seen in stats to ISO codes.
+var countryAliases = map[string]string{
+	"united states of america":               "US",
+	"usa":                                    "US",
+	"uk":                                     "GB",
+	"great britain":                          "GB",
+	"russian federation":                     "RU",
+	"korea, republic of":                     "KR",
+	"republic of korea":                      "KR",
+	"korea, democratic people's republic of": "KP",
+	"viet nam":                               "VN",
+	"czech republic":                         "CZ",
+	"côte d'ivoire":                          "CI",
+	"cote d'ivoire":                          "CI",
+	"macedonia":                              "MK",
+	"swaziland":                              "SZ",
+	"burma":                                  "MM",
+	"iran, islamic republic of":              "IR",
+	"türkiye":                                "TR",
+	"turkiye":                                "TR",
+	"the netherlands":                        "NL",
+	"syrian arab republic":                   "SY",
+	"lao people's democratic republic":       "LA",
+	"tanzania, united republic of":           "TZ",
+	"moldova, republic of":                   "MD",
+	"bolivia, plurinational state of":        "BO",
+	"venezuela, bolivarian republic of":      "VE",
+	"congo":                                  "CG",
+	"congo, the democratic republic of the":  "CD",
+	"palestine, state of":                    "PS",
+	"hong kong sar":                          "HK",
+	"macau":                                  "MO",
+	"brunei darussalam":                      "BN",
+	"cabo verde":                             "CV",
+	"east timor":                             "TL",
+	"holy see":                               "VA",
+}
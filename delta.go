@@ -0,0 +1,175 @@
+package tly
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// CheckpointStore persists the last stats seen for a key so StatsDeltaTracker
+// can compute changes across runs.
+type CheckpointStore interface {
+	// Load returns the saved stats for key, or nil when there is none.
+	Load(ctx context.Context, key string) (*Stats, error)
+	Save(ctx context.Context, key string, stats *Stats) error
+}
+
+// MemoryCheckpointStore keeps checkpoints in memory.
+type MemoryCheckpointStore struct {
+	mu sync.Mutex
+	m  map[string]*Stats
+}
+
+// NewMemoryCheckpointStore creates an empty in-memory checkpoint store.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{m: map[string]*Stats{}}
+}
+
+// Load implements CheckpointStore.
+func (s *MemoryCheckpointStore) Load(ctx context.Context, key string) (*Stats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.m[key], nil
+}
+
+// Save implements CheckpointStore.
+func (s *MemoryCheckpointStore) Save(ctx context.Context, key string, stats *Stats) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[key] = stats
+	return nil
+}
+
+// FileCheckpointStore keeps one JSON checkpoint file per key in Dir, so
+// checkpoints survive between runs of an ETL job.
+type FileCheckpointStore struct {
+	Dir string
+}
+
+func (s FileCheckpointStore) path(key string) string {
+	return filepath.Join(s.Dir, url.PathEscape(key)+".json")
+}
+
+// Load implements CheckpointStore.
+func (s FileCheckpointStore) Load(ctx context.Context, key string) (*Stats, error) {
+	data, err := os.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stats Stats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// Save implements CheckpointStore. The file is replaced atomically.
+func (s FileCheckpointStore) Save(ctx context.Context, key string, stats *Stats) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	tmp := s.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(key))
+}
+
+// StatsDelta is the change in a link's stats since the previous checkpoint.
+// Breakdowns list only rows whose totals changed; a negative total means the
+// API revised a count downward.
+type StatsDelta struct {
+	ShortURL     string          `json:"short_url"`
+	Initial      bool            `json:"initial"`
+	Clicks       int             `json:"clicks"`
+	UniqueClicks int             `json:"unique_clicks"`
+	Browsers     []BreakdownItem `json:"browsers,omitempty"`
+	Countries    []BreakdownItem `json:"countries,omitempty"`
+	Referrers    []BreakdownItem `json:"referrers,omitempty"`
+	Platforms    []BreakdownItem `json:"platforms,omitempty"`
+	Daily        []DailyClicks   `json:"daily,omitempty"`
+}
+
+// StatsDeltaTracker returns only what changed in a link's stats since the
+// last call, checkpointing the latest stats in a CheckpointStore.
+type StatsDeltaTracker struct {
+	client *Client
+	store  CheckpointStore
+	// Stats narrows the fetched stats. It should stay the same between
+	// runs, otherwise deltas compare different periods.
+	Stats StatsOptions
+}
+
+// NewStatsDeltaTracker creates a tracker backed by store.
+func NewStatsDeltaTracker(client *Client, store CheckpointStore) *StatsDeltaTracker {
+	return &StatsDeltaTracker{client: client, store: store}
+}
+
+// Next fetches the current stats for shortURL, returns the delta against the
+// stored checkpoint and saves the new checkpoint. With no checkpoint the
+// whole stats payload is returned and Initial is set.
+func (t *StatsDeltaTracker) Next(ctx context.Context, shortURL string) (*StatsDelta, error) {
+	prev, err := t.store.Load(ctx, shortURL)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := t.client.GetStatsWithOptions(ctx, shortURL, t.Stats)
+	if err != nil {
+		return nil, err
+	}
+	delta := diffStats(prev, cur)
+	delta.ShortURL = shortURL
+	if err := t.store.Save(ctx, shortURL, cur); err != nil {
+		return nil, err
+	}
+	return delta, nil
+}
+
+// diffStats computes cur minus prev; a nil prev counts as empty.
+func diffStats(prev, cur *Stats) *StatsDelta {
+	delta := &StatsDelta{Initial: prev == nil}
+	if prev == nil {
+		prev = &Stats{}
+	}
+	delta.Clicks = cur.Clicks - prev.Clicks
+	delta.UniqueClicks = cur.UniqueClicks - prev.UniqueClicks
+	delta.Browsers = diffRows(prev.BrowserBreakdown(), cur.BrowserBreakdown())
+	delta.Countries = diffRows(prev.CountryBreakdown(), cur.CountryBreakdown())
+	delta.Referrers = diffRows(prev.ReferrerBreakdown(), cur.ReferrerBreakdown())
+	delta.Platforms = diffRows(prev.PlatformBreakdown(), cur.PlatformBreakdown())
+	for _, row := range diffRows(breakdown(prev.DailyClicks, dateKey), breakdown(cur.DailyClicks, dateKey)) {
+		delta.Daily = append(delta.Daily, DailyClicks{Date: row.Name, Total: row.Total})
+	}
+	return delta
+}
+
+// diffRows returns the non-zero per-name changes from prev to cur, sorted
+// by name.
+func diffRows(prev, cur []BreakdownItem) []BreakdownItem {
+	totals := map[string]int{}
+	for _, row := range cur {
+		totals[row.Name] += row.Total
+	}
+	for _, row := range prev {
+		totals[row.Name] -= row.Total
+	}
+	var changed []BreakdownItem
+	for name, total := range totals {
+		if total != 0 {
+			changed = append(changed, BreakdownItem{Name: name, Total: total})
+		}
+	}
+	sort.Slice(changed, func(i, j int) bool { return changed[i].Name < changed[j].Name })
+	return changed
+}
@@ -0,0 +1,88 @@
+package tly
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestDiffStats(t *testing.T) {
+	prev := `{"clicks": 5, "unique_clicks": 4,
+		"browsers": [{"browser": "Chrome", "total": 3}, {"browser": "Safari", "total": 2}],
+		"countries": [{"countryName": "France", "total": 5}],
+		"daily_clicks": [{"date": "2024-01-01", "total": 2}, {"date": "2024-01-02", "total": 3}]}`
+	tests := []struct {
+		name string
+		prev string // "" for no checkpoint
+		cur  string
+		want StatsDelta
+	}{
+		{"empty first run", "", `{}`, StatsDelta{Initial: true}},
+		{"first run", "", prev, StatsDelta{
+			Initial:      true,
+			Clicks:       5,
+			UniqueClicks: 4,
+			Browsers:     []BreakdownItem{{"Chrome", 3}, {"Safari", 2}},
+			Countries:    []BreakdownItem{{"France", 5}},
+			Daily:        []DailyClicks{{"2024-01-01", 2}, {"2024-01-02", 3}},
+		}},
+		{"unchanged", prev, prev, StatsDelta{}},
+		{"new clicks on overlapping days", prev, `{"clicks": 9, "unique_clicks": 7,
+			"browsers": [{"browser": "Chrome", "total": 5}, {"browser": "Safari", "total": 2}, {"browser": "Edge", "total": 2}],
+			"countries": [{"countryName": "France", "total": 9}],
+			"daily_clicks": [{"date": "2024-01-02", "total": 5}, {"date": "2024-01-01", "total": 2}, {"date": "2024-01-03", "total": 2}]}`,
+			StatsDelta{
+				Clicks:       4,
+				UniqueClicks: 3,
+				Browsers:     []BreakdownItem{{"Chrome", 2}, {"Edge", 2}},
+				Countries:    []BreakdownItem{{"France", 4}},
+				Daily:        []DailyClicks{{"2024-01-02", 2}, {"2024-01-03", 2}},
+			}},
+		{"revised down and rows gone", prev, `{"clicks": 4, "unique_clicks": 4,
+			"browsers": [{"browser": "Chrome", "total": 2}],
+			"countries": [{"countryName": "France", "total": 4}],
+			"daily_clicks": [{"date": "2024-01-02", "total": 3}]}`,
+			StatsDelta{
+				Clicks:    -1,
+				Browsers:  []BreakdownItem{{"Chrome", -1}, {"Safari", -2}},
+				Countries: []BreakdownItem{{"France", -1}},
+				Daily:     []DailyClicks{{"2024-01-01", -2}},
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p *Stats
+			if tt.prev != "" {
+				p = decodeStats(t, tt.prev)
+			}
+			got := diffStats(p, decodeStats(t, tt.cur))
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("diffStats() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	ctx := context.Background()
+	store := FileCheckpointStore{Dir: t.TempDir() + "/checkpoints"}
+	key := "https://t.ly/a?b"
+	got, err := store.Load(ctx, key)
+	if err != nil || got != nil {
+		t.Fatalf("Load() before Save = %v, %v, want nil, nil", got, err)
+	}
+	saved := &Stats{Clicks: 3, DailyClicks: []interface{}{map[string]interface{}{"date": "2024-01-01", "total": float64(3)}}}
+	if err := store.Save(ctx, key, saved); err != nil {
+		t.Fatal(err)
+	}
+	got, err = store.Load(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Clicks != 3 || !reflect.DeepEqual(got.DailySeries(), saved.DailySeries()) {
+		t.Errorf("Load() = %+v, want %+v", got, saved)
+	}
+	if other, err := store.Load(ctx, "https://t.ly/a"); err != nil || other != nil {
+		t.Errorf("Load() of another key = %v, %v, want nil, nil", other, err)
+	}
+}
@@ -0,0 +1,7 @@
+// Package tly is a client for the T.LY URL shortener API.
+//
+// Create a Client with NewClient and an API key. Each part of the API also
+// has a service interface, such as ShortLinkService or TagService, that
+// *Client implements; depend on the interfaces to substitute a fake, such as
+// the ones in the mocks package, in tests.
+package tly
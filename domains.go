@@ -0,0 +1,216 @@
+package tly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// DomainService is the branded domain part of the API.
+type DomainService interface {
+	ListDomains(ctx context.Context) ([]Domain, error)
+	AddDomain(ctx context.Context, reqData DomainCreateRequest) (*Domain, error)
+	DeleteDomain(ctx context.Context, id int) error
+	CheckDomainStatus(ctx context.Context, hostname string) (*DomainStatusReport, error)
+	GetDomainSettings(ctx context.Context, id int) (*DomainSettings, error)
+	UpdateDomainSettings(ctx context.Context, id int, reqData DomainSettingsUpdateRequest) (*DomainSettings, error)
+}
+
+var _ DomainService = (*Client)(nil)
+
+// DomainStatus is the provisioning state of a branded domain.
+type DomainStatus string
+
+// Domain states reported by the API.
+const (
+	DomainPending DomainStatus = "pending"
+	DomainActive  DomainStatus = "active"
+	DomainFailed  DomainStatus = "failed"
+)
+
+// Domain is a branded domain, such as go.acme.com, that short links can be
+// created on.
+type Domain struct {
+	ID       int          `json:"id"`
+	Hostname string       `json:"domain"`
+	Status   DomainStatus `json:"status"`
+	// DefaultRedirect is where the bare domain redirects to.
+	DefaultRedirect string    `json:"default_redirect"`
+	CreatedAt       Timestamp `json:"created_at"`
+	UpdatedAt       Timestamp `json:"updated_at"`
+}
+
+// DomainCreateRequest is used to add a branded domain.
+type DomainCreateRequest struct {
+	Hostname        string `json:"domain"`
+	DefaultRedirect string `json:"default_redirect,omitempty"`
+}
+
+// ListDomains retrieves every branded domain in the account, following
+// pagination.
+func (c *Client) ListDomains(ctx context.Context) ([]Domain, error) {
+	opts := ListOptions{Page: 1}
+	var domains []Domain
+	for {
+		var data []Domain
+		info, err := c.getPage(ctx, "/api/v1/domain", opts, &data)
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, data...)
+		if len(data) == 0 || !info.HasNext() {
+			return domains, nil
+		}
+		opts.Page = info.CurrentPage + 1
+	}
+}
+
+// AddDomain adds a branded domain. The domain stays pending until its DNS
+// records point at T.LY.
+func (c *Client) AddDomain(ctx context.Context, reqData DomainCreateRequest) (*Domain, error) {
+	if reqData.Hostname == "" {
+		return nil, fmt.Errorf("tly: domain hostname is required")
+	}
+	var domain Domain
+	err := c.doRequestContext(ctx, "POST", "/api/v1/domain", "", reqData, &domain)
+	if err != nil {
+		return nil, err
+	}
+	return &domain, nil
+}
+
+// DeleteDomain removes a branded domain by its ID.
+func (c *Client) DeleteDomain(ctx context.Context, id int) error {
+	path := fmt.Sprintf("/api/v1/domain/%d", id)
+	return c.doRequestContext(ctx, "DELETE", path, "", nil, nil)
+}
+
+// SSLStatus is the certificate provisioning state of a branded domain.
+type SSLStatus string
+
+// SSL provisioning states reported by the API.
+const (
+	SSLPending SSLStatus = "pending"
+	SSLActive  SSLStatus = "active"
+	SSLFailed  SSLStatus = "failed"
+)
+
+// DNSRecord is a DNS record a branded domain needs. Found reports whether
+// the record was seen with the expected value.
+type DNSRecord struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Found bool   `json:"found"`
+}
+
+// DomainStatusReport is the verification state of a branded domain.
+type DomainStatusReport struct {
+	Hostname    string       `json:"domain"`
+	Status      DomainStatus `json:"status"`
+	DNSVerified bool         `json:"dns_verified"`
+	// Records are the CNAME or A records the domain must publish.
+	Records   []DNSRecord `json:"records"`
+	SSLStatus SSLStatus   `json:"ssl_status"`
+}
+
+// Live reports whether the domain is verified and serving over HTTPS.
+func (r *DomainStatusReport) Live() bool {
+	return r.DNSVerified && r.SSLStatus == SSLActive
+}
+
+// Missing returns the required DNS records that were not found.
+func (r *DomainStatusReport) Missing() []DNSRecord {
+	var missing []DNSRecord
+	for _, rec := range r.Records {
+		if !rec.Found {
+			missing = append(missing, rec)
+		}
+	}
+	return missing
+}
+
+// CheckDomainStatus reports the DNS verification and SSL provisioning state
+// of a branded domain.
+func (c *Client) CheckDomainStatus(ctx context.Context, hostname string) (*DomainStatusReport, error) {
+	query := url.Values{"domain": {hostname}}.Encode()
+	var report DomainStatusReport
+	err := c.doRequestContext(ctx, "GET", "/api/v1/domain/status", query, nil, &report)
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ErrDomainFailed is returned by WaitForDomain when verification or SSL
+// provisioning failed.
+var ErrDomainFailed = errors.New("tly: domain verification failed")
+
+// WaitForDomain polls CheckDomainStatus every interval (30 seconds when
+// zero) until the domain is live, provisioning fails or ctx is done. It
+// returns the last report in every case where one was fetched.
+func (c *Client) WaitForDomain(ctx context.Context, hostname string, interval time.Duration) (*DomainStatusReport, error) {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	for {
+		report, err := c.CheckDomainStatus(ctx, hostname)
+		if err != nil {
+			return nil, err
+		}
+		if report.Live() {
+			return report, nil
+		}
+		if report.Status == DomainFailed || report.SSLStatus == SSLFailed {
+			return report, ErrDomainFailed
+		}
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return report, ctx.Err()
+		}
+	}
+}
+
+// DomainSettings are the redirect targets of a branded domain for requests
+// that do not hit a short link. Empty targets use the T.LY defaults.
+type DomainSettings struct {
+	// RootRedirect is where the bare domain, e.g. go.acme.com/, points.
+	// It is the same setting as Domain.DefaultRedirect.
+	RootRedirect string `json:"root_redirect"`
+	// NotFoundRedirect is where unknown or expired short links point.
+	NotFoundRedirect string `json:"not_found_redirect"`
+}
+
+// DomainSettingsUpdateRequest changes the redirect targets of a domain.
+// Nil fields are left unchanged; an empty string resets a target to the
+// T.LY default.
+type DomainSettingsUpdateRequest struct {
+	RootRedirect     *string `json:"root_redirect,omitempty"`
+	NotFoundRedirect *string `json:"not_found_redirect,omitempty"`
+}
+
+// GetDomainSettings retrieves the redirect settings of a branded domain.
+func (c *Client) GetDomainSettings(ctx context.Context, id int) (*DomainSettings, error) {
+	path := fmt.Sprintf("/api/v1/domain/%d/settings", id)
+	var settings DomainSettings
+	err := c.doRequestContext(ctx, "GET", path, "", nil, &settings)
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// UpdateDomainSettings updates the redirect settings of a branded domain
+// and returns the resulting settings.
+func (c *Client) UpdateDomainSettings(ctx context.Context, id int, reqData DomainSettingsUpdateRequest) (*DomainSettings, error) {
+	path := fmt.Sprintf("/api/v1/domain/%d/settings", id)
+	var settings DomainSettings
+	err := c.doRequestContext(ctx, "PUT", path, "", reqData, &settings)
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
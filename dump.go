@@ -0,0 +1,108 @@
+package tly
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Stats record dimensions written by DumpStats.
+const (
+	DimensionDaily    = "daily"
+	DimensionBrowser  = "browser"
+	DimensionCountry  = "country"
+	DimensionReferrer = "referrer"
+	DimensionPlatform = "platform"
+)
+
+// StatsDumpFilter selects the links and period written by DumpStats.
+type StatsDumpFilter struct {
+	Links ShortLinkListOptions
+	Stats StatsOptions
+	// PerDay fetches stats once per day between Stats.StartDate and
+	// Stats.EndDate so breakdown records carry a date. It costs one
+	// request per link per day.
+	PerDay bool
+}
+
+// StatsRecord is one line of a stats dump. Breakdown records have an empty
+// Date unless the dump was made with PerDay.
+type StatsRecord struct {
+	ShortURL  string `json:"short_url"`
+	ShortID   string `json:"short_id"`
+	Domain    string `json:"domain"`
+	Date      string `json:"date,omitempty"`
+	Dimension string `json:"dimension"`
+	Value     string `json:"value,omitempty"`
+	Clicks    int    `json:"clicks"`
+}
+
+// DumpStats streams the stats of every link matching filter to w as
+// newline-delimited JSON, one StatsRecord per (link, day, dimension).
+func (c *Client) DumpStats(ctx context.Context, filter StatsDumpFilter, w io.Writer) error {
+	if filter.PerDay && (filter.Stats.StartDate.IsZero() || filter.Stats.EndDate.IsZero()) {
+		return errors.New("tly: PerDay requires Stats.StartDate and Stats.EndDate")
+	}
+	links, err := c.ListAllShortLinks(ctx, filter.Links)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	for _, link := range links {
+		if !filter.PerDay {
+			stats, err := c.GetStatsWithOptions(ctx, link.ShortURL, filter.Stats)
+			if err != nil {
+				return err
+			}
+			if err := writeStatsRecords(enc, link, "", stats); err != nil {
+				return err
+			}
+			continue
+		}
+		start, end := dayStart(filter.Stats.StartDate), dayStart(filter.Stats.EndDate)
+		for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			opts := filter.Stats
+			opts.StartDate, opts.EndDate = day, day
+			stats, err := c.GetStatsWithOptions(ctx, link.ShortURL, opts)
+			if err != nil {
+				return err
+			}
+			if err := writeStatsRecords(enc, link, day.Format(dateLayout), stats); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeStatsRecords encodes the daily series and breakdowns of stats.
+func writeStatsRecords(enc *json.Encoder, link ShortLink, date string, stats *Stats) error {
+	base := StatsRecord{ShortURL: link.ShortURL, ShortID: link.ShortID, Domain: link.Domain}
+	for _, d := range stats.DailySeries() {
+		r := base
+		r.Date, r.Dimension, r.Clicks = d.Date, DimensionDaily, d.Total
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	dims := []struct {
+		name string
+		rows []BreakdownItem
+	}{
+		{DimensionBrowser, stats.BrowserBreakdown()},
+		{DimensionCountry, stats.CountryBreakdown()},
+		{DimensionReferrer, stats.ReferrerBreakdown()},
+		{DimensionPlatform, stats.PlatformBreakdown()},
+	}
+	for _, dim := range dims {
+		for _, row := range dim.rows {
+			r := base
+			r.Date, r.Dimension, r.Value, r.Clicks = date, dim.name, row.Name, row.Total
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package tly_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+func TestDumpStats(t *testing.T) {
+	links := `{"current_page":1,"last_page":1,"data":[{"short_url":"https://t.ly/a","short_id":"a","domain":"https://t.ly/"}]}`
+	// stats holds the payload for each start_date; "" is the whole period.
+	stats := map[string]string{
+		"": `{"clicks":3,"browsers":[{"browser":"Chrome","total":3}],"countries":[{"countryName":"France","total":3}],` +
+			`"referrers":[{"referrer":"","total":1}],"platforms":[],"daily_clicks":[{"date":"2024-01-01","total":1},{"date":"2024-01-02","total":2}]}`,
+		"2024-01-01": `{"clicks":1,"browsers":[{"browser":"Chrome","total":1}],"daily_clicks":[{"date":"2024-01-01","total":1}]}`,
+		"2024-01-02": `{"clicks":0,"browsers":[],"daily_clicks":[]}`,
+	}
+	day := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	const record = `{"short_url":"https://t.ly/a","short_id":"a","domain":"https://t.ly/",%s}`
+	tests := []struct {
+		name     string
+		links    string
+		filter   tly.StatsDumpFilter
+		want     []string // the fields of each record after those of the link
+		requests []string // start dates of the stats requests
+		err      bool
+	}{
+		{
+			name:  "whole period",
+			links: links,
+			want: []string{
+				`"date":"2024-01-01","dimension":"daily","clicks":1`,
+				`"date":"2024-01-02","dimension":"daily","clicks":2`,
+				`"dimension":"browser","value":"Chrome","clicks":3`,
+				`"dimension":"country","value":"France","clicks":3`,
+				`"dimension":"referrer","clicks":1`,
+			},
+			requests: []string{""},
+		},
+		{
+			name:   "per day",
+			links:  links,
+			filter: tly.StatsDumpFilter{PerDay: true, Stats: tly.StatsOptions{StartDate: day("2024-01-01"), EndDate: day("2024-01-02")}},
+			want: []string{
+				`"date":"2024-01-01","dimension":"daily","clicks":1`,
+				`"date":"2024-01-01","dimension":"browser","value":"Chrome","clicks":1`,
+			},
+			requests: []string{"2024-01-01", "2024-01-02"},
+		},
+		{
+			name:   "per day without dates",
+			links:  links,
+			filter: tly.StatsDumpFilter{PerDay: true, Stats: tly.StatsOptions{StartDate: day("2024-01-01")}},
+			err:    true,
+		},
+		{
+			name:  "no links",
+			links: `{"current_page":1,"last_page":1,"data":[]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/api/v1/link/list":
+					fmt.Fprint(w, tt.links)
+				case "/api/v1/link/stats":
+					start := r.URL.Query().Get("start_date")
+					requests = append(requests, start)
+					fmt.Fprint(w, stats[start])
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer srv.Close()
+			c := tly.NewClient("test-key", func(c *tly.Client) { c.BaseURL = srv.URL })
+
+			var out bytes.Buffer
+			err := c.DumpStats(context.Background(), tt.filter, &out)
+			if (err != nil) != tt.err {
+				t.Fatalf("DumpStats() error = %v, want error %v", err, tt.err)
+			}
+			var want []string
+			for _, fields := range tt.want {
+				want = append(want, fmt.Sprintf(record, fields))
+			}
+			if got, want := strings.TrimSpace(out.String()), strings.Join(want, "\n"); got != want {
+				t.Errorf("DumpStats() wrote\n%s\nwant\n%s", got, want)
+			}
+			if fmt.Sprint(requests) != fmt.Sprint(tt.requests) {
+				t.Errorf("stats requests for start dates %q, want %q", requests, tt.requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package tly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// DataExportStatus is the state of an account data export.
+type DataExportStatus string
+
+// Data export states.
+const (
+	ExportPending    DataExportStatus = "pending"
+	ExportProcessing DataExportStatus = "processing"
+	ExportReady      DataExportStatus = "ready"
+	ExportFailed     DataExportStatus = "failed"
+)
+
+// DataExport is an archive of all account data, as required for GDPR
+// data access requests. It can be downloaded once Status is ExportReady and
+// until ExpiresAt.
+type DataExport struct {
+	ID        int              `json:"id"`
+	Status    DataExportStatus `json:"status"`
+	Size      int64            `json:"size"`
+	CreatedAt Timestamp        `json:"created_at"`
+	ExpiresAt Timestamp        `json:"expires_at"`
+}
+
+// ErrExportFailed is returned by WaitForDataExport when the export failed.
+var ErrExportFailed = errors.New("tly: data export failed")
+
+// RequestDataExport starts an export of all account data. Exports are
+// built in the background; poll GetExportStatus or use WaitForDataExport.
+func (c *Client) RequestDataExport(ctx context.Context) (*DataExport, error) {
+	var export DataExport
+	err := c.doRequestContext(ctx, "POST", "/api/v1/account/export", "", nil, &export)
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// GetExportStatus retrieves the state of a data export.
+func (c *Client) GetExportStatus(ctx context.Context, id int) (*DataExport, error) {
+	path := fmt.Sprintf("/api/v1/account/export/%d", id)
+	var export DataExport
+	err := c.doRequestContext(ctx, "GET", path, "", nil, &export)
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// WaitForDataExport polls GetExportStatus every interval (10 seconds when
+// zero) until the export is ready, fails or ctx is done.
+func (c *Client) WaitForDataExport(ctx context.Context, id int, interval time.Duration) (*DataExport, error) {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	for {
+		export, err := c.GetExportStatus(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		switch export.Status {
+		case ExportReady:
+			return export, nil
+		case ExportFailed:
+			return export, ErrExportFailed
+		}
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return export, ctx.Err()
+		}
+	}
+}
+
+// DownloadDataExport streams the archive of a ready export to w and
+// returns the number of bytes written. The archive is not buffered in
+// memory.
+func (c *Client) DownloadDataExport(ctx context.Context, id int, w io.Writer) (int64, error) {
+	path := fmt.Sprintf("/api/v1/account/export/%d/download", id)
+	resp, err := c.send(ctx, "GET", path, "", nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return io.Copy(w, resp.Body)
+}
@@ -0,0 +1,166 @@
+package tly
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// countryIndex maps upper-case codes and lower-case names to countries.
+var countryIndex = func() map[string]country {
+	idx := make(map[string]country, 2*len(countries)+len(countryAliases))
+	for _, c := range countries {
+		idx[c.code] = c
+		idx[strings.ToLower(c.name)] = c
+	}
+	for alias, code := range countryAliases {
+		idx[alias] = idx[code]
+	}
+	return idx
+}()
+
+// lookupCountry resolves an ISO code or country name.
+func lookupCountry(v string) (country, bool) {
+	v = strings.TrimSpace(v)
+	if c, ok := countryIndex[strings.ToUpper(v)]; ok && len(v) == 2 {
+		return c, true
+	}
+	c, ok := countryIndex[strings.ToLower(v)]
+	return c, ok
+}
+
+// CountryCode returns the ISO 3166-1 alpha-2 code for a country name or
+// code as reported in stats, or "" when it is not recognized.
+func CountryCode(nameOrCode string) string {
+	c, _ := lookupCountry(nameOrCode)
+	return c.code
+}
+
+// CountryClicks is the clicks from one country. Lat and Lng are the
+// approximate centroid of the country.
+type CountryClicks struct {
+	Code  string  `json:"code"`
+	Name  string  `json:"name"`
+	Total int     `json:"total"`
+	Lat   float64 `json:"lat"`
+	Lng   float64 `json:"lng"`
+}
+
+// RegionClicks is the clicks from one region within a country.
+type RegionClicks struct {
+	CountryCode string `json:"country_code"`
+	Region      string `json:"region"`
+	Total       int    `json:"total"`
+}
+
+// GeoStats is the geographic distribution of clicks keyed by ISO code.
+type GeoStats struct {
+	Countries []CountryClicks `json:"countries"`
+	// Regions is only populated when the stats payload includes regions.
+	Regions []RegionClicks `json:"regions,omitempty"`
+	// Unknown counts clicks whose country could not be resolved.
+	Unknown int `json:"unknown"`
+}
+
+// countryCodeKeys are row keys that may carry an ISO code directly.
+var countryCodeKeys = []string{"countryCode", "country_code", "code"}
+
+// rowCode returns the ISO code of a raw row, preferring an explicit code
+// over the name under nameKey.
+func rowCode(row map[string]interface{}, nameKey string) string {
+	for _, k := range countryCodeKeys {
+		if v, ok := row[k].(string); ok {
+			if c, ok := lookupCountry(v); ok {
+				return c.code
+			}
+		}
+	}
+	name, _ := row[nameKey].(string)
+	return CountryCode(name)
+}
+
+// GeoStats returns the country (and, when available, region) breakdown
+// keyed by ISO 3166-1 alpha-2 code, ordered by descending clicks.
+func (s *Stats) GeoStats() *GeoStats {
+	geo := &GeoStats{}
+	totals := map[string]int{}
+	for _, r := range s.Countries {
+		row, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		code := rowCode(row, countryKey)
+		if code == "" {
+			geo.Unknown += toInt(row[totalKey])
+			continue
+		}
+		totals[code] += toInt(row[totalKey])
+	}
+	for code, total := range totals {
+		c := countryIndex[code]
+		geo.Countries = append(geo.Countries, CountryClicks{Code: code, Name: c.name, Total: total, Lat: c.lat, Lng: c.lng})
+	}
+	sort.Slice(geo.Countries, func(i, j int) bool {
+		if geo.Countries[i].Total == geo.Countries[j].Total {
+			return geo.Countries[i].Code < geo.Countries[j].Code
+		}
+		return geo.Countries[i].Total > geo.Countries[j].Total
+	})
+	for _, r := range s.Regions {
+		row, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		region, _ := row["region"].(string)
+		if region == "" {
+			region, _ = row["regionName"].(string)
+		}
+		geo.Regions = append(geo.Regions, RegionClicks{
+			CountryCode: rowCode(row, countryKey),
+			Region:      region,
+			Total:       toInt(row[totalKey]),
+		})
+	}
+	return geo
+}
+
+// ByCode returns clicks keyed by ISO code, the shape most choropleth
+// libraries accept directly.
+func (g *GeoStats) ByCode() map[string]int {
+	m := make(map[string]int, len(g.Countries))
+	for _, c := range g.Countries {
+		m[c.Code] = c.Total
+	}
+	return m
+}
+
+// GeoJSON encodes the country clicks as a GeoJSON FeatureCollection of
+// centroid points with "code", "name" and "clicks" properties, suitable for
+// heatmaps or for joining to country shapes by code.
+func (g *GeoStats) GeoJSON() ([]byte, error) {
+	type geometry struct {
+		Type        string     `json:"type"`
+		Coordinates [2]float64 `json:"coordinates"`
+	}
+	type feature struct {
+		Type       string                 `json:"type"`
+		Geometry   geometry               `json:"geometry"`
+		Properties map[string]interface{} `json:"properties"`
+	}
+	collection := struct {
+		Type     string    `json:"type"`
+		Features []feature `json:"features"`
+	}{Type: "FeatureCollection", Features: []feature{}}
+	for _, c := range g.Countries {
+		collection.Features = append(collection.Features, feature{
+			Type:     "Feature",
+			Geometry: geometry{Type: "Point", Coordinates: [2]float64{c.Lng, c.Lat}},
+			Properties: map[string]interface{}{
+				"code":   c.Code,
+				"name":   c.Name,
+				"clicks": c.Total,
+			},
+		})
+	}
+	return json.Marshal(collection)
+}
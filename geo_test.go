@@ -0,0 +1,87 @@
+package tly
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCountryCode(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"France", "FR"},
+		{"fr", "FR"},
+		{" United States ", "US"},
+		{"USA", "US"},
+		{"UK", "GB"},
+		{"Atlantis", ""},
+		{"ZZ", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := CountryCode(tt.in); got != tt.want {
+			t.Errorf("CountryCode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGeoStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    GeoStats
+		geoJSON string
+	}{
+		{"no countries", `{"countries": []}`, GeoStats{}, `{"type":"FeatureCollection","features":[]}`},
+		{
+			name: "codes, names and unknown countries",
+			payload: `{"countries": [
+				{"countryName": "France", "total": 3},
+				{"countryName": "United States of America", "total": 4},
+				{"countryCode": "fr", "countryName": "Frankreich", "total": 1},
+				{"countryName": "Unknown", "total": 4},
+				{"countryName": "", "total": 1},
+				"not a row"
+			], "regions": [
+				{"countryCode": "US", "region": "Texas", "total": 2},
+				{"countryName": "France", "regionName": "Brittany", "total": 1}
+			]}`,
+			want: GeoStats{
+				Countries: []CountryClicks{
+					{Code: "FR", Name: "France", Total: 4, Lat: 46.227638, Lng: 2.213749},
+					{Code: "US", Name: "United States", Total: 4, Lat: 37.09024, Lng: -95.712891},
+				},
+				Regions: []RegionClicks{
+					{CountryCode: "US", Region: "Texas", Total: 2},
+					{CountryCode: "FR", Region: "Brittany", Total: 1},
+				},
+				Unknown: 5,
+			},
+			geoJSON: `{"type":"FeatureCollection","features":[` +
+				`{"type":"Feature","geometry":{"type":"Point","coordinates":[2.213749,46.227638]},"properties":{"clicks":4,"code":"FR","name":"France"}},` +
+				`{"type":"Feature","geometry":{"type":"Point","coordinates":[-95.712891,37.09024]},"properties":{"clicks":4,"code":"US","name":"United States"}}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			geo := decodeStats(t, tt.payload).GeoStats()
+			if !reflect.DeepEqual(*geo, tt.want) {
+				t.Errorf("GeoStats() = %+v, want %+v", *geo, tt.want)
+			}
+			byCode := map[string]int{}
+			for _, c := range tt.want.Countries {
+				byCode[c.Code] = c.Total
+			}
+			if got := geo.ByCode(); !reflect.DeepEqual(got, byCode) {
+				t.Errorf("ByCode() = %v, want %v", got, byCode)
+			}
+			data, err := geo.GeoJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.geoJSON {
+				t.Errorf("GeoJSON() = %s, want %s", data, tt.geoJSON)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package grpc
+
+// The messages of tly.proto. Each field comment gives its field number.
+
+// ShortenRequest asks for a short link.
+type ShortenRequest struct {
+	LongURL     string   // 1
+	Domain      string   // 2
+	Tags        []string // 3
+	Description string   // 4
+	ShortID     string   // 5
+}
+
+func (m *ShortenRequest) marshal(e *encoder) {
+	e.string(1, m.LongURL)
+	e.string(2, m.Domain)
+	e.strings(3, m.Tags)
+	e.string(4, m.Description)
+	e.string(5, m.ShortID)
+}
+
+func (m *ShortenRequest) unmarshal(b []byte) error {
+	return decode(b, func(d *decoder, num, wire int) error {
+		switch num {
+		case 1:
+			return d.string(wire, &m.LongURL)
+		case 2:
+			return d.string(wire, &m.Domain)
+		case 3:
+			return d.appendString(wire, &m.Tags)
+		case 4:
+			return d.string(wire, &m.Description)
+		case 5:
+			return d.string(wire, &m.ShortID)
+		}
+		return d.skip(wire)
+	})
+}
+
+// ShortLink is a short link.
+type ShortLink struct {
+	ShortURL    string   // 1
+	LongURL     string   // 2
+	Domain      string   // 3
+	ShortID     string   // 4
+	Description string   // 5
+	Tags        []string // 6
+	CreatedAt   string   // 7
+}
+
+func (m *ShortLink) marshal(e *encoder) {
+	e.string(1, m.ShortURL)
+	e.string(2, m.LongURL)
+	e.string(3, m.Domain)
+	e.string(4, m.ShortID)
+	e.string(5, m.Description)
+	e.strings(6, m.Tags)
+	e.string(7, m.CreatedAt)
+}
+
+func (m *ShortLink) unmarshal(b []byte) error {
+	return decode(b, func(d *decoder, num, wire int) error {
+		switch num {
+		case 1:
+			return d.string(wire, &m.ShortURL)
+		case 2:
+			return d.string(wire, &m.LongURL)
+		case 3:
+			return d.string(wire, &m.Domain)
+		case 4:
+			return d.string(wire, &m.ShortID)
+		case 5:
+			return d.string(wire, &m.Description)
+		case 6:
+			return d.appendString(wire, &m.Tags)
+		case 7:
+			return d.string(wire, &m.CreatedAt)
+		}
+		return d.skip(wire)
+	})
+}
+
+// ExpandRequest asks for the long URL of a short link.
+type ExpandRequest struct {
+	ShortURL string // 1
+	Password string // 2
+}
+
+func (m *ExpandRequest) marshal(e *encoder) {
+	e.string(1, m.ShortURL)
+	e.string(2, m.Password)
+}
+
+func (m *ExpandRequest) unmarshal(b []byte) error {
+	return decode(b, func(d *decoder, num, wire int) error {
+		switch num {
+		case 1:
+			return d.string(wire, &m.ShortURL)
+		case 2:
+			return d.string(wire, &m.Password)
+		}
+		return d.skip(wire)
+	})
+}
+
+// ExpandResponse is the long URL of a short link.
+type ExpandResponse struct {
+	LongURL string // 1
+	Expired bool   // 2
+}
+
+func (m *ExpandResponse) marshal(e *encoder) {
+	e.string(1, m.LongURL)
+	e.bool(2, m.Expired)
+}
+
+func (m *ExpandResponse) unmarshal(b []byte) error {
+	return decode(b, func(d *decoder, num, wire int) error {
+		switch num {
+		case 1:
+			return d.string(wire, &m.LongURL)
+		case 2:
+			return d.bool(wire, &m.Expired)
+		}
+		return d.skip(wire)
+	})
+}
+
+// StatsRequest asks for the clicks of a short link.
+type StatsRequest struct {
+	ShortURL    string // 1
+	StartDate   string // 2
+	EndDate     string // 3
+	ExcludeBots bool   // 4
+}
+
+func (m *StatsRequest) marshal(e *encoder) {
+	e.string(1, m.ShortURL)
+	e.string(2, m.StartDate)
+	e.string(3, m.EndDate)
+	e.bool(4, m.ExcludeBots)
+}
+
+func (m *StatsRequest) unmarshal(b []byte) error {
+	return decode(b, func(d *decoder, num, wire int) error {
+		switch num {
+		case 1:
+			return d.string(wire, &m.ShortURL)
+		case 2:
+			return d.string(wire, &m.StartDate)
+		case 3:
+			return d.string(wire, &m.EndDate)
+		case 4:
+			return d.bool(wire, &m.ExcludeBots)
+		}
+		return d.skip(wire)
+	})
+}
+
+// StatsResponse is the clicks of a short link.
+type StatsResponse struct {
+	Clicks       int64           // 1
+	UniqueClicks int64           // 2
+	Countries    []CountryClicks // 3
+	Daily        []DailyClicks   // 4
+}
+
+func (m *StatsResponse) marshal(e *encoder) {
+	e.int(1, m.Clicks)
+	e.int(2, m.UniqueClicks)
+	for i := range m.Countries {
+		e.message(3, &m.Countries[i])
+	}
+	for i := range m.Daily {
+		e.message(4, &m.Daily[i])
+	}
+}
+
+func (m *StatsResponse) unmarshal(b []byte) error {
+	return decode(b, func(d *decoder, num, wire int) error {
+		switch num {
+		case 1:
+			return d.int64(wire, &m.Clicks)
+		case 2:
+			return d.int64(wire, &m.UniqueClicks)
+		case 3:
+			var c CountryClicks
+			if err := d.message(wire, &c); err != nil {
+				return err
+			}
+			m.Countries = append(m.Countries, c)
+			return nil
+		case 4:
+			var c DailyClicks
+			if err := d.message(wire, &c); err != nil {
+				return err
+			}
+			m.Daily = append(m.Daily, c)
+			return nil
+		}
+		return d.skip(wire)
+	})
+}
+
+// CountryClicks is the clicks from one country.
+type CountryClicks struct {
+	Code   string // 1
+	Name   string // 2
+	Clicks int64  // 3
+}
+
+func (m *CountryClicks) marshal(e *encoder) {
+	e.string(1, m.Code)
+	e.string(2, m.Name)
+	e.int(3, m.Clicks)
+}
+
+func (m *CountryClicks) unmarshal(b []byte) error {
+	return decode(b, func(d *decoder, num, wire int) error {
+		switch num {
+		case 1:
+			return d.string(wire, &m.Code)
+		case 2:
+			return d.string(wire, &m.Name)
+		case 3:
+			return d.int64(wire, &m.Clicks)
+		}
+		return d.skip(wire)
+	})
+}
+
+// DailyClicks is the clicks on one day.
+type DailyClicks struct {
+	Date   string // 1
+	Clicks int64  // 2
+}
+
+func (m *DailyClicks) marshal(e *encoder) {
+	e.string(1, m.Date)
+	e.int(2, m.Clicks)
+}
+
+func (m *DailyClicks) unmarshal(b []byte) error {
+	return decode(b, func(d *decoder, num, wire int) error {
+		switch num {
+		case 1:
+			return d.string(wire, &m.Date)
+		case 2:
+			return d.int64(wire, &m.Clicks)
+		}
+		return d.skip(wire)
+	})
+}
+
+// ListRequest asks for a page of short links.
+type ListRequest struct {
+	Search string  // 1
+	TagIDs []int64 // 2
+	Page   int32   // 3
+}
+
+func (m *ListRequest) marshal(e *encoder) {
+	e.string(1, m.Search)
+	e.ints(2, m.TagIDs)
+	e.int(3, int64(m.Page))
+}
+
+func (m *ListRequest) unmarshal(b []byte) error {
+	return decode(b, func(d *decoder, num, wire int) error {
+		switch num {
+		case 1:
+			return d.string(wire, &m.Search)
+		case 2:
+			return d.appendInts(wire, &m.TagIDs)
+		case 3:
+			return d.int32(wire, &m.Page)
+		}
+		return d.skip(wire)
+	})
+}
+
+// ListResponse is a page of short links.
+type ListResponse struct {
+	Links    []ShortLink // 1
+	Page     int32       // 2
+	LastPage int32       // 3
+	Total    int64       // 4
+}
+
+func (m *ListResponse) marshal(e *encoder) {
+	for i := range m.Links {
+		e.message(1, &m.Links[i])
+	}
+	e.int(2, int64(m.Page))
+	e.int(3, int64(m.LastPage))
+	e.int(4, m.Total)
+}
+
+func (m *ListResponse) unmarshal(b []byte) error {
+	return decode(b, func(d *decoder, num, wire int) error {
+		switch num {
+		case 1:
+			var l ShortLink
+			if err := d.message(wire, &l); err != nil {
+				return err
+			}
+			m.Links = append(m.Links, l)
+			return nil
+		case 2:
+			return d.int32(wire, &m.Page)
+		case 3:
+			return d.int32(wire, &m.LastPage)
+		case 4:
+			return d.int64(wire, &m.Total)
+		}
+		return d.skip(wire)
+	})
+}
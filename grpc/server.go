@@ -0,0 +1,440 @@
+// Package grpc serves the T.LY client as the gRPC service defined in
+// tly.proto, so services that standardize on gRPC can shorten, expand and
+// read stats through one gateway holding the API key.
+//
+// Like package collectors, it keeps the SDK free of third-party
+// dependencies: Server speaks the gRPC wire protocol over net/http's
+// HTTP/2 support and encodes the messages of tly.proto itself. Clients in
+// any language are generated from tly.proto as usual.
+//
+// gRPC needs HTTP/2. Serve the handler over TLS, which negotiates HTTP/2
+// on its own:
+//
+//	srv := grpc.NewServer(client, grpc.Options{Token: os.Getenv("GATEWAY_TOKEN")})
+//	mux := http.NewServeMux()
+//	mux.Handle(grpc.Path, srv)
+//	log.Fatal(http.ListenAndServeTLS(":8443", "cert.pem", "key.pem", mux))
+//
+// For plaintext HTTP/2 inside a cluster, wrap the handler with
+// golang.org/x/net/http2/h2c or, from Go 1.24, enable unencrypted HTTP/2
+// in http.Server.Protocols.
+//
+// Only unary calls and uncompressed messages are supported.
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+// ServiceName is the full name of the ShortLinks service.
+const ServiceName = "tly.v1.ShortLinks"
+
+// Path is the URL path prefix of the service's methods, for mounting a
+// Server on a mux.
+const Path = "/" + ServiceName + "/"
+
+// Code is a gRPC status code.
+type Code uint32
+
+// The status codes the server returns.
+const (
+	OK                 Code = 0
+	Canceled           Code = 1
+	InvalidArgument    Code = 3
+	DeadlineExceeded   Code = 4
+	NotFound           Code = 5
+	ResourceExhausted  Code = 8
+	FailedPrecondition Code = 9
+	Unimplemented      Code = 12
+	Internal           Code = 13
+	Unavailable        Code = 14
+	Unauthenticated    Code = 16
+)
+
+// Status is an error with a gRPC status code.
+type Status struct {
+	Code    Code
+	Message string
+}
+
+func (s *Status) Error() string {
+	return fmt.Sprintf("grpc: code %d: %s", s.Code, s.Message)
+}
+
+func statusf(code Code, format string, args ...interface{}) *Status {
+	return &Status{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Options configures a Server.
+type Options struct {
+	// Token, when set, is the bearer token callers must send in the
+	// authorization metadata. Calls without it fail with Unauthenticated.
+	Token string
+	// Timeout bounds every call, including calls whose grpc-timeout is
+	// longer. Defaults to 30 seconds.
+	Timeout time.Duration
+	// MaxMessageSize bounds request messages in bytes. Defaults to 4 MiB,
+	// gRPC's default.
+	MaxMessageSize int
+}
+
+// Server implements the ShortLinks service with a T.LY client. It is an
+// http.Handler for the paths under Path.
+type Server struct {
+	client *tly.Client
+	opts   Options
+}
+
+// NewServer creates a Server calling T.LY with client.
+func NewServer(client *tly.Client, opts Options) *Server {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	if opts.MaxMessageSize <= 0 {
+		opts.MaxMessageSize = 4 << 20
+	}
+	return &Server{client: client, opts: opts}
+}
+
+// Shorten implements ShortLinks.Shorten.
+func (s *Server) Shorten(ctx context.Context, req *ShortenRequest) (*ShortLink, error) {
+	if req.LongURL == "" {
+		return nil, statusf(InvalidArgument, "long_url is required")
+	}
+	create := tly.ShortLinkCreateRequest{LongURL: req.LongURL, Domain: req.Domain}
+	if req.Description != "" {
+		create.Description = &req.Description
+	}
+	if req.ShortID != "" {
+		create.ShortID = &req.ShortID
+	}
+	link, err := s.client.CreateShortLinkWithTagNames(ctx, create, req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	return toShortLink(*link), nil
+}
+
+// Expand implements ShortLinks.Expand.
+func (s *Server) Expand(ctx context.Context, req *ExpandRequest) (*ExpandResponse, error) {
+	if req.ShortURL == "" {
+		return nil, statusf(InvalidArgument, "short_url is required")
+	}
+	var resp *tly.ExpandResponse
+	var err error
+	if req.Password != "" {
+		resp, err = s.client.ExpandWithPassword(ctx, req.ShortURL, tly.Password(req.Password))
+	} else {
+		resp, err = s.client.ExpandShortLink(tly.ExpandRequest{ShortURL: req.ShortURL})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ExpandResponse{LongURL: resp.LongURL, Expired: resp.Expired}, nil
+}
+
+// Stats implements ShortLinks.Stats.
+func (s *Server) Stats(ctx context.Context, req *StatsRequest) (*StatsResponse, error) {
+	if req.ShortURL == "" {
+		return nil, statusf(InvalidArgument, "short_url is required")
+	}
+	opts := tly.StatsOptions{ExcludeBots: req.ExcludeBots}
+	var err error
+	if opts.StartDate, err = parseDate("start_date", req.StartDate); err != nil {
+		return nil, err
+	}
+	if opts.EndDate, err = parseDate("end_date", req.EndDate); err != nil {
+		return nil, err
+	}
+	stats, err := s.client.GetStatsWithOptions(ctx, req.ShortURL, opts)
+	if err != nil {
+		return nil, err
+	}
+	resp := &StatsResponse{Clicks: int64(stats.Clicks), UniqueClicks: int64(stats.UniqueClicks)}
+	for _, c := range stats.GeoStats().Countries {
+		resp.Countries = append(resp.Countries, CountryClicks{Code: c.Code, Name: c.Name, Clicks: int64(c.Total)})
+	}
+	for _, d := range stats.DailySeries() {
+		resp.Daily = append(resp.Daily, DailyClicks{Date: d.Date, Clicks: int64(d.Total)})
+	}
+	return resp, nil
+}
+
+// parseDate parses an optional YYYY-MM-DD date.
+func parseDate(field, v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return time.Time{}, statusf(InvalidArgument, "%s %q is not a YYYY-MM-DD date", field, v)
+	}
+	return t, nil
+}
+
+// List implements ShortLinks.List.
+func (s *Server) List(ctx context.Context, req *ListRequest) (*ListResponse, error) {
+	if req.Page < 0 {
+		return nil, statusf(InvalidArgument, "page must not be negative")
+	}
+	opts := tly.ShortLinkListOptions{Search: req.Search, Page: int(req.Page)}
+	for _, id := range req.TagIDs {
+		opts.TagIDs = append(opts.TagIDs, int(id))
+	}
+	page, err := s.client.ListShortLinksPage(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	resp := &ListResponse{Page: int32(page.CurrentPage), LastPage: int32(page.LastPage), Total: int64(page.Total)}
+	for _, l := range page.Data {
+		resp.Links = append(resp.Links, *toShortLink(l))
+	}
+	return resp, nil
+}
+
+func toShortLink(l tly.ShortLink) *ShortLink {
+	out := &ShortLink{
+		ShortURL:    l.ShortURL,
+		LongURL:     l.LongURL,
+		Domain:      l.Domain,
+		ShortID:     l.ShortID,
+		Description: l.Description,
+	}
+	if !l.CreatedAt.IsZero() {
+		out.CreatedAt = l.CreatedAt.Format("2006-01-02 15:04:05")
+	}
+	for _, t := range l.Tags {
+		out.Tags = append(out.Tags, t.Tag)
+	}
+	return out
+}
+
+// methods are the service's methods by name: each decodes its request
+// into a new message and calls the Server.
+var methods = map[string]struct {
+	request func() message
+	call    func(s *Server, ctx context.Context, req message) (message, error)
+}{
+	"Shorten": {
+		func() message { return &ShortenRequest{} },
+		func(s *Server, ctx context.Context, req message) (message, error) {
+			return s.Shorten(ctx, req.(*ShortenRequest))
+		},
+	},
+	"Expand": {
+		func() message { return &ExpandRequest{} },
+		func(s *Server, ctx context.Context, req message) (message, error) {
+			return s.Expand(ctx, req.(*ExpandRequest))
+		},
+	},
+	"Stats": {
+		func() message { return &StatsRequest{} },
+		func(s *Server, ctx context.Context, req message) (message, error) {
+			return s.Stats(ctx, req.(*StatsRequest))
+		},
+	},
+	"List": {
+		func() message { return &ListRequest{} },
+		func(s *Server, ctx context.Context, req message) (message, error) {
+			return s.List(ctx, req.(*ListRequest))
+		},
+	},
+}
+
+// ServeHTTP implements http.Handler. It answers a unary gRPC call: one
+// length-prefixed request message in, one response message and the
+// grpc-status trailer out.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.ProtoMajor != 2 {
+		http.Error(w, "gRPC requires HTTP/2", http.StatusHTTPVersionNotSupported)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "gRPC requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+	ct := r.Header.Get("Content-Type")
+	if ct != "application/grpc" && ct != "application/grpc+proto" {
+		http.Error(w, "unsupported content type "+ct, http.StatusUnsupportedMediaType)
+		return
+	}
+	h := w.Header()
+	h.Set("Content-Type", "application/grpc")
+	resp, err := s.call(r)
+	if err != nil {
+		// A failed call is answered with headers only.
+		setStatus(h, statusOf(err))
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	h.Set("Trailer", "Grpc-Status, Grpc-Message")
+	frame := make([]byte, 5, 5+len(resp))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(resp)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(append(frame, resp...))
+	setStatus(h, &Status{Code: OK})
+}
+
+func setStatus(h http.Header, st *Status) {
+	h.Set("Grpc-Status", strconv.Itoa(int(st.Code)))
+	if st.Message != "" {
+		h.Set("Grpc-Message", encodeMessage(st.Message))
+	}
+}
+
+// call authenticates, decodes and runs the call of r and returns the
+// encoded response message.
+func (s *Server) call(r *http.Request) (resp []byte, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = statusf(Internal, "internal error")
+		}
+	}()
+	if s.opts.Token != "" {
+		got := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(got, []byte("Bearer "+s.opts.Token)) != 1 {
+			return nil, statusf(Unauthenticated, "missing or invalid bearer token")
+		}
+	}
+	if !strings.HasPrefix(r.URL.Path, Path) {
+		return nil, statusf(Unimplemented, "unknown service %s", r.URL.Path)
+	}
+	m, ok := methods[strings.TrimPrefix(r.URL.Path, Path)]
+	if !ok {
+		return nil, statusf(Unimplemented, "unknown method %s", r.URL.Path)
+	}
+	if enc := r.Header.Get("Grpc-Encoding"); enc != "" && enc != "identity" {
+		return nil, statusf(Unimplemented, "compression %q is not supported", enc)
+	}
+	timeout := s.opts.Timeout
+	if v := r.Header.Get("Grpc-Timeout"); v != "" {
+		d, err := parseTimeout(v)
+		if err != nil {
+			return nil, statusf(InvalidArgument, "%v", err)
+		}
+		if d < timeout {
+			timeout = d
+		}
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	data, err := s.readMessage(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	req := m.request()
+	if err := req.unmarshal(data); err != nil {
+		return nil, statusf(InvalidArgument, "invalid request message: %v", err)
+	}
+	out, err := m.call(s, ctx, req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	return marshal(out), nil
+}
+
+// readMessage reads the one length-prefixed message of a unary call.
+func (s *Server) readMessage(body io.Reader) ([]byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(body, prefix[:]); err != nil {
+		return nil, statusf(InvalidArgument, "reading request message: %v", err)
+	}
+	if prefix[0] != 0 {
+		return nil, statusf(Unimplemented, "compressed messages are not supported")
+	}
+	n := binary.BigEndian.Uint32(prefix[1:])
+	if uint64(n) > uint64(s.opts.MaxMessageSize) {
+		return nil, statusf(ResourceExhausted, "request message of %d bytes exceeds the limit of %d", n, s.opts.MaxMessageSize)
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(body, data); err != nil {
+		return nil, statusf(InvalidArgument, "reading request message: %v", err)
+	}
+	return data, nil
+}
+
+// parseTimeout parses a grpc-timeout header: up to 8 digits and a unit.
+// Timeouts too long for a time.Duration, such as "99999999H", are as good
+// as no client deadline and come back as the longest duration.
+func parseTimeout(v string) (time.Duration, error) {
+	units := map[byte]time.Duration{
+		'H': time.Hour, 'M': time.Minute, 'S': time.Second,
+		'm': time.Millisecond, 'u': time.Microsecond, 'n': time.Nanosecond,
+	}
+	if len(v) < 2 || len(v) > 9 {
+		return 0, fmt.Errorf("invalid grpc-timeout %q", v)
+	}
+	unit, ok := units[v[len(v)-1]]
+	n, err := strconv.ParseInt(v[:len(v)-1], 10, 64)
+	if !ok || err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid grpc-timeout %q", v)
+	}
+	if n > math.MaxInt64/int64(unit) {
+		return math.MaxInt64, nil
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// statusOf maps the error of a call to its status. API errors keep their
+// meaning: a missing link is NotFound and a rejected request
+// InvalidArgument. Failures of T.LY itself, and of the gateway's API key,
+// are Unavailable and Internal.
+func statusOf(err error) *Status {
+	var st *Status
+	var apiErr *tly.APIError
+	switch {
+	case errors.As(err, &st):
+		return st
+	case errors.Is(err, context.DeadlineExceeded):
+		return statusf(DeadlineExceeded, "deadline exceeded")
+	case errors.Is(err, context.Canceled):
+		return statusf(Canceled, "canceled")
+	case errors.As(err, &apiErr):
+		msg := apiErr.Message()
+		switch code := apiErr.StatusCode; {
+		case code == http.StatusNotFound:
+			return statusf(NotFound, "%s", msg)
+		case code == http.StatusBadRequest || code == http.StatusUnprocessableEntity:
+			return statusf(InvalidArgument, "%s", msg)
+		case code == http.StatusTooManyRequests:
+			return statusf(ResourceExhausted, "%s", msg)
+		case code == http.StatusUnauthorized || code == http.StatusForbidden:
+			return statusf(Internal, "the gateway's API key was rejected")
+		case code >= 500:
+			return statusf(Unavailable, "%s", msg)
+		}
+		return statusf(FailedPrecondition, "%s", msg)
+	}
+	return statusf(Unavailable, "%v", err)
+}
+
+// encodeMessage percent-encodes a grpc-message value as the protocol
+// requires.
+func encodeMessage(msg string) string {
+	var b strings.Builder
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if c < ' ' || c > '~' || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
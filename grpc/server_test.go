@@ -0,0 +1,124 @@
+package grpc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+	"github.com/timleland/t.ly-go-url-shortener-api/tlytest"
+)
+
+func TestParseTimeout(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"1S", time.Second, true},
+		{"250m", 250 * time.Millisecond, true},
+		{"5M", 5 * time.Minute, true},
+		{"2H", 2 * time.Hour, true},
+		{"7u", 7 * time.Microsecond, true},
+		{"0n", 0, true},
+		{"99999999M", 99999999 * time.Minute, true},
+		{"2562047H", 2562047 * time.Hour, true},
+		{"2562048H", math.MaxInt64, true},
+		{"99999999H", math.MaxInt64, true},
+		{"", 0, false},
+		{"S", 0, false},
+		{"10", 0, false},
+		{"10s", 0, false},
+		{"-1S", 0, false},
+		{"1.5S", 0, false},
+		{"100000000S", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := parseTimeout(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseTimeout(%q) = %v, %v; want %v, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+// unary calls method on s with req and the given headers, and returns the
+// status code and the response message.
+func unary(t *testing.T, s *Server, method string, req message, header map[string]string) (Code, []byte) {
+	t.Helper()
+	body := marshal(req)
+	frame := make([]byte, 5, 5+len(body))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(body)))
+	r := httptest.NewRequest("POST", Path+method, bytes.NewReader(append(frame, body...)))
+	r.ProtoMajor = 2
+	r.Header.Set("Content-Type", "application/grpc")
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	resp := w.Result()
+	status := resp.Header.Get("Grpc-Status")
+	if status == "" {
+		status = resp.Trailer.Get("Grpc-Status")
+	}
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		t.Fatalf("%s: no grpc-status in the response", method)
+	}
+	data := w.Body.Bytes()
+	if len(data) >= 5 {
+		data = data[5:]
+	}
+	return Code(code), data
+}
+
+func TestServeHTTP(t *testing.T) {
+	srv := tlytest.NewServer(tlytest.Options{})
+	defer srv.Close()
+	link, err := srv.Client().CreateShortLink(tly.ShortLinkCreateRequest{LongURL: "https://example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(srv.Client(), Options{Token: "caller"})
+	auth := "Bearer caller"
+	tests := []struct {
+		name   string
+		method string
+		req    message
+		header map[string]string
+		want   Code
+	}{
+		{"expand", "Expand", &ExpandRequest{ShortURL: link.ShortURL}, map[string]string{"Authorization": auth}, OK},
+		{"no token", "Expand", &ExpandRequest{ShortURL: link.ShortURL}, nil, Unauthenticated},
+		{"wrong token", "Expand", &ExpandRequest{ShortURL: link.ShortURL}, map[string]string{"Authorization": "Bearer other"}, Unauthenticated},
+		{"missing link", "Expand", &ExpandRequest{ShortURL: "https://t.ly/none"}, map[string]string{"Authorization": auth}, NotFound},
+		{"missing field", "Expand", &ExpandRequest{}, map[string]string{"Authorization": auth}, InvalidArgument},
+		{"unknown method", "Delete", &ExpandRequest{}, map[string]string{"Authorization": auth}, Unimplemented},
+		{"timeout", "Expand", &ExpandRequest{ShortURL: link.ShortURL}, map[string]string{"Authorization": auth, "Grpc-Timeout": "5S"}, OK},
+		{"huge timeout", "Expand", &ExpandRequest{ShortURL: link.ShortURL}, map[string]string{"Authorization": auth, "Grpc-Timeout": "99999999H"}, OK},
+		{"bad timeout", "Expand", &ExpandRequest{ShortURL: link.ShortURL}, map[string]string{"Authorization": auth, "Grpc-Timeout": "soon"}, InvalidArgument},
+		{"compressed", "Expand", &ExpandRequest{ShortURL: link.ShortURL}, map[string]string{"Authorization": auth, "Grpc-Encoding": "gzip"}, Unimplemented},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, data := unary(t, s, tt.method, tt.req, tt.header)
+			if code != tt.want {
+				t.Fatalf("status = %d, want %d", code, tt.want)
+			}
+			if code != OK {
+				return
+			}
+			var resp ExpandResponse
+			if err := resp.unmarshal(data); err != nil {
+				t.Fatal(err)
+			}
+			if resp.LongURL != "https://example.com" {
+				t.Errorf("LongURL = %q, want https://example.com", resp.LongURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The protobuf wire types used by tly.proto.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("truncated message")
+
+// encoder appends fields in the protobuf wire format. Fields holding their
+// proto3 default are left out, as the format prescribes.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) varint(v uint64) {
+	for v >= 0x80 {
+		e.buf = append(e.buf, byte(v)|0x80)
+		v >>= 7
+	}
+	e.buf = append(e.buf, byte(v))
+}
+
+func (e *encoder) key(field, wire int) {
+	e.varint(uint64(field)<<3 | uint64(wire))
+}
+
+func (e *encoder) string(field int, s string) {
+	if s == "" {
+		return
+	}
+	e.key(field, wireBytes)
+	e.varint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) strings(field int, ss []string) {
+	for _, s := range ss {
+		e.key(field, wireBytes)
+		e.varint(uint64(len(s)))
+		e.buf = append(e.buf, s...)
+	}
+}
+
+func (e *encoder) int(field int, v int64) {
+	if v == 0 {
+		return
+	}
+	e.key(field, wireVarint)
+	e.varint(uint64(v))
+}
+
+// ints encodes a repeated integer field packed, the proto3 default.
+func (e *encoder) ints(field int, vs []int64) {
+	if len(vs) == 0 {
+		return
+	}
+	var packed encoder
+	for _, v := range vs {
+		packed.varint(uint64(v))
+	}
+	e.key(field, wireBytes)
+	e.varint(uint64(len(packed.buf)))
+	e.buf = append(e.buf, packed.buf...)
+}
+
+func (e *encoder) bool(field int, v bool) {
+	if v {
+		e.key(field, wireVarint)
+		e.varint(1)
+	}
+}
+
+// message encodes m as an embedded message, even when it is empty, so
+// repeated fields keep their length.
+func (e *encoder) message(field int, m message) {
+	var sub encoder
+	m.marshal(&sub)
+	e.key(field, wireBytes)
+	e.varint(uint64(len(sub.buf)))
+	e.buf = append(e.buf, sub.buf...)
+}
+
+// message is a type of tly.proto.
+type message interface {
+	marshal(e *encoder)
+	unmarshal(b []byte) error
+}
+
+func marshal(m message) []byte {
+	var e encoder
+	m.marshal(&e)
+	return e.buf
+}
+
+// decoder reads fields in the protobuf wire format.
+type decoder struct {
+	buf []byte
+}
+
+// decode calls field for every field of b. field reads the value or
+// skips it.
+func decode(b []byte, field func(d *decoder, num, wire int) error) error {
+	d := &decoder{buf: b}
+	for len(d.buf) > 0 {
+		key, err := d.varint()
+		if err != nil {
+			return err
+		}
+		num, wire := int(key>>3), int(key&7)
+		if num == 0 {
+			return errors.New("invalid field number 0")
+		}
+		if err := field(d, num, wire); err != nil {
+			return fmt.Errorf("field %d: %w", num, err)
+		}
+	}
+	return nil
+}
+
+func (d *decoder) varint() (uint64, error) {
+	var v uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		if len(d.buf) == 0 {
+			return 0, errTruncated
+		}
+		b := d.buf[0]
+		d.buf = d.buf[1:]
+		v |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			return v, nil
+		}
+	}
+	return 0, errors.New("varint overflows 64 bits")
+}
+
+func (d *decoder) bytes() ([]byte, error) {
+	n, err := d.varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(d.buf)) {
+		return nil, errTruncated
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b, nil
+}
+
+// skip discards a field of an unknown number, so newer clients can send
+// fields this server does not know.
+func (d *decoder) skip(wire int) error {
+	switch wire {
+	case wireVarint:
+		_, err := d.varint()
+		return err
+	case wireBytes:
+		_, err := d.bytes()
+		return err
+	case wireFixed64, wireFixed32:
+		n := 8
+		if wire == wireFixed32 {
+			n = 4
+		}
+		if len(d.buf) < n {
+			return errTruncated
+		}
+		d.buf = d.buf[n:]
+		return nil
+	}
+	return fmt.Errorf("unsupported wire type %d", wire)
+}
+
+func wantWire(got, want int) error {
+	if got != want {
+		return fmt.Errorf("wire type %d, want %d", got, want)
+	}
+	return nil
+}
+
+func (d *decoder) string(wire int, s *string) error {
+	if err := wantWire(wire, wireBytes); err != nil {
+		return err
+	}
+	b, err := d.bytes()
+	*s = string(b)
+	return err
+}
+
+func (d *decoder) appendString(wire int, ss *[]string) error {
+	var s string
+	if err := d.string(wire, &s); err != nil {
+		return err
+	}
+	*ss = append(*ss, s)
+	return nil
+}
+
+func (d *decoder) int64(wire int, v *int64) error {
+	if err := wantWire(wire, wireVarint); err != nil {
+		return err
+	}
+	u, err := d.varint()
+	*v = int64(u)
+	return err
+}
+
+func (d *decoder) int32(wire int, v *int32) error {
+	var w int64
+	if err := d.int64(wire, &w); err != nil {
+		return err
+	}
+	if w < math.MinInt32 || w > math.MaxInt32 {
+		return errors.New("int32 out of range")
+	}
+	*v = int32(w)
+	return nil
+}
+
+func (d *decoder) bool(wire int, v *bool) error {
+	var w int64
+	err := d.int64(wire, &w)
+	*v = w != 0
+	return err
+}
+
+// appendInts reads a repeated integer field, packed or not.
+func (d *decoder) appendInts(wire int, vs *[]int64) error {
+	if wire == wireVarint {
+		var v int64
+		if err := d.int64(wire, &v); err != nil {
+			return err
+		}
+		*vs = append(*vs, v)
+		return nil
+	}
+	if err := wantWire(wire, wireBytes); err != nil {
+		return err
+	}
+	b, err := d.bytes()
+	if err != nil {
+		return err
+	}
+	packed := &decoder{buf: b}
+	for len(packed.buf) > 0 {
+		v, err := packed.varint()
+		if err != nil {
+			return err
+		}
+		*vs = append(*vs, int64(v))
+	}
+	return nil
+}
+
+func (d *decoder) message(wire int, m message) error {
+	if err := wantWire(wire, wireBytes); err != nil {
+		return err
+	}
+	b, err := d.bytes()
+	if err != nil {
+		return err
+	}
+	return m.unmarshal(b)
+}
@@ -0,0 +1,116 @@
+package grpc
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		m    message
+		want []byte
+	}{
+		{"defaults are left out", &ShortenRequest{}, nil},
+		{"strings", &ShortenRequest{LongURL: "a", Tags: []string{"x", ""}}, []byte{0x0a, 1, 'a', 0x1a, 1, 'x', 0x1a, 0}},
+		{"packed ints", &ListRequest{TagIDs: []int64{1, 300}, Page: 2}, []byte{0x12, 3, 1, 0xac, 0x02, 0x18, 2}},
+		{"bool", &ExpandResponse{Expired: true}, []byte{0x10, 1}},
+		{"empty embedded message", &StatsResponse{Daily: []DailyClicks{{}}}, []byte{0x22, 0}},
+		{"embedded message", &StatsResponse{Clicks: 3, Countries: []CountryClicks{{Code: "DE", Clicks: 2}}}, []byte{0x08, 3, 0x1a, 6, 0x0a, 2, 'D', 'E', 0x18, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := marshal(tt.m); !bytes.Equal(got, tt.want) {
+				t.Errorf("marshal = % x, want % x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		in, out message
+	}{
+		{&ShortenRequest{LongURL: "https://example.com", Domain: "https://t.ly/", Tags: []string{"a", "b"}, Description: "d", ShortID: "x"}, &ShortenRequest{}},
+		{&ShortLink{ShortURL: "https://t.ly/x", LongURL: "https://example.com", Tags: []string{"ü"}, CreatedAt: "2024-01-01 00:00:00"}, &ShortLink{}},
+		{&ExpandRequest{ShortURL: "https://t.ly/x", Password: "p"}, &ExpandRequest{}},
+		{&StatsResponse{Clicks: 1 << 40, UniqueClicks: 1, Countries: []CountryClicks{{"DE", "Germany", 2}}, Daily: []DailyClicks{{"2024-01-01", 2}, {}}}, &StatsResponse{}},
+		{&ListRequest{Search: "s", TagIDs: []int64{1, 2, 1 << 35}, Page: 7}, &ListRequest{}},
+		{&ListResponse{Links: []ShortLink{{ShortURL: "a"}, {}}, Page: 1, LastPage: 2, Total: -1}, &ListResponse{}},
+	}
+	for _, tt := range tests {
+		if err := tt.out.unmarshal(marshal(tt.in)); err != nil {
+			t.Errorf("%T: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(tt.in, tt.out) {
+			t.Errorf("round trip of %+v = %+v", tt.in, tt.out)
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	var unknown encoder
+	unknown.string(1, "https://t.ly/x")
+	unknown.key(9, wireVarint)
+	unknown.varint(1 << 50)
+	unknown.key(10, wireFixed64)
+	unknown.buf = append(unknown.buf, 1, 2, 3, 4, 5, 6, 7, 8)
+	unknown.key(11, wireFixed32)
+	unknown.buf = append(unknown.buf, 1, 2, 3, 4)
+	unknown.string(12, "later")
+	unknown.string(2, "p")
+	var req ExpandRequest
+	if err := req.unmarshal(unknown.buf); err != nil {
+		t.Fatal(err)
+	}
+	if req.ShortURL != "https://t.ly/x" || req.Password != "p" {
+		t.Errorf("with unknown fields = %+v", req)
+	}
+
+	var list ListRequest
+	if err := list.unmarshal([]byte{0x10, 1, 0x12, 2, 2, 3, 0x10, 4}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(list.TagIDs, []int64{1, 2, 3, 4}) {
+		t.Errorf("mixed packed and unpacked ints = %v, want [1 2 3 4]", list.TagIDs)
+	}
+
+	var link ShortLink
+	if err := link.unmarshal([]byte{0x0a, 1, 'a', 0x0a, 1, 'b'}); err != nil {
+		t.Fatal(err)
+	}
+	if link.ShortURL != "b" {
+		t.Errorf("repeated scalar = %q, want the last value", link.ShortURL)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		m    message
+		in   []byte
+		want string
+	}{
+		{"truncated key", &ExpandRequest{}, []byte{0x80}, "truncated"},
+		{"truncated length", &ExpandRequest{}, []byte{0x0a}, "truncated"},
+		{"truncated string", &ExpandRequest{}, []byte{0x0a, 5, 'a'}, "truncated"},
+		{"truncated fixed64", &ExpandRequest{}, []byte{0x51, 1, 2}, "truncated"},
+		{"field zero", &ExpandRequest{}, []byte{0x02, 0}, "field number 0"},
+		{"wrong wire type", &ExpandRequest{}, []byte{0x08, 1}, "field 1: wire type 0, want 2"},
+		{"group wire type", &ExpandRequest{}, []byte{0x4b}, "unsupported wire type 3"},
+		{"varint overflow", &StatsResponse{}, append([]byte{0x08}, bytes.Repeat([]byte{0xff}, 10)...), "overflows"},
+		{"int32 out of range", &ListRequest{}, []byte{0x18, 0x80, 0x80, 0x80, 0x80, 0x10}, "int32 out of range"},
+		{"bad embedded message", &StatsResponse{}, []byte{0x1a, 2, 0x0a, 5}, "field 3: field 1: truncated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.m.unmarshal(tt.in)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("unmarshal(% x) error = %v, want %q", tt.in, err, tt.want)
+			}
+		})
+	}
+}
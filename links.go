@@ -0,0 +1,377 @@
+package tly
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ShortLinkService is the short link part of the API.
+type ShortLinkService interface {
+	CreateShortLink(reqData ShortLinkCreateRequest) (*ShortLink, error)
+	GetShortLink(shortURL string) (*ShortLink, error)
+	UpdateShortLink(reqData ShortLinkUpdateRequest) (*ShortLink, error)
+	DeleteShortLink(shortURL string) error
+	ExpandShortLink(reqData ExpandRequest) (*ExpandResponse, error)
+	ListShortLinksPage(ctx context.Context, opts ShortLinkListOptions) (*ShortLinkPage, error)
+	ListAllShortLinks(ctx context.Context, opts ShortLinkListOptions) ([]ShortLink, error)
+	CreateShortLinks(ctx context.Context, reqs []ShortLinkCreateRequest, opts CreateShortLinksOptions) ([]ShortLinkCreateResult, error)
+}
+
+var _ ShortLinkService = (*Client)(nil)
+
+// updateBody returns the request body for reqData. The omitempty tags drop
+// empty tag and pixel lists, so a non-nil empty list is sent explicitly to
+// clear them.
+func updateBody(reqData ShortLinkUpdateRequest) (interface{}, error) {
+	clearTags := reqData.Tags != nil && len(reqData.Tags) == 0
+	clearPixels := reqData.Pixels != nil && len(reqData.Pixels) == 0
+	if !clearTags && !clearPixels {
+		return reqData, nil
+	}
+	data, err := json.Marshal(reqData)
+	if err != nil {
+		return nil, err
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, err
+	}
+	if clearTags {
+		body["tags"] = []int{}
+	}
+	if clearPixels {
+		body["pixels"] = []int{}
+	}
+	return body, nil
+}
+
+// fetchShortLink retrieves a short link bypassing the response cache, for
+// read-modify-write updates that must see the current state.
+func (c *Client) fetchShortLink(ctx context.Context, shortURL string) (*ShortLink, error) {
+	query := url.Values{"short_url": {shortURL}}.Encode()
+	var link ShortLink
+	if err := c.doRequestContext(ctx, "GET", "/api/v1/link", query, nil, &link); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// updateRequestFrom builds an update request that resends the current
+// settings of link, so a change to one field does not reset the others.
+func updateRequestFrom(link ShortLink) ShortLinkUpdateRequest {
+	req := ShortLinkUpdateRequest{
+		ShortURL: link.ShortURL,
+		LongURL:  link.LongURL,
+		Tags:     tagIDs(link.Tags),
+		Pixels:   pixelIDs(link.Pixels),
+		Meta:     link.Meta,
+	}
+	publicStats := link.PublicStats
+	req.PublicStats = &publicStats
+	if link.Description != "" {
+		description := link.Description
+		req.Description = &description
+	}
+	if v, ok := link.ExpireAtDatetime.(string); ok && v != "" {
+		req.ExpireAtDatetime = &v
+	}
+	if link.ExpireAtViews != nil {
+		if views := toInt(link.ExpireAtViews); views > 0 {
+			req.ExpireAtViews = &views
+		}
+	}
+	return req
+}
+
+// tagIDs returns the IDs of tags. It returns nil for nil tags, so a
+// link whose payload left the tags out is not updated with an empty list,
+// which would clear them.
+func tagIDs(tags []Tag) []int {
+	if tags == nil {
+		return nil
+	}
+	ids := make([]int, 0, len(tags))
+	for _, t := range tags {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
+
+// pixelIDs returns the IDs of pixels. It returns nil for nil pixels, so a
+// link whose payload left the pixels out is not updated with an empty list,
+// which would clear them.
+func pixelIDs(pixels []Pixel) []int {
+	if pixels == nil {
+		return nil
+	}
+	ids := make([]int, 0, len(pixels))
+	for _, p := range pixels {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+// replaceIDs returns ids with every member of from replaced by to, keeping
+// the order of first appearance and dropping duplicates.
+func replaceIDs(ids []int, from map[int]bool, to int) []int {
+	seen := map[int]bool{}
+	var out []int
+	for _, id := range ids {
+		if from[id] {
+			id = to
+		}
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// LinkTagChange records the tag change of one link during a tag merge.
+type LinkTagChange struct {
+	ShortURL string `json:"short_url"`
+	OldTags  []int  `json:"old_tags"`
+	NewTags  []int  `json:"new_tags"`
+	// Err is set when the update failed, and Error holds its message for
+	// JSON reports.
+	Err   error  `json:"-"`
+	Error string `json:"error,omitempty"`
+}
+
+// retagLinks moves every link tagged with one of from onto the tag to,
+// removing the from tags. With dryRun the changes are computed but not
+// applied. Per-link failures are recorded in the changes; the error is only
+// set when the links cannot be listed.
+func (c *Client) retagLinks(ctx context.Context, from []int, to int, dryRun bool) ([]LinkTagChange, error) {
+	links, err := c.ListAllShortLinks(ctx, ShortLinkListOptions{TagIDs: from})
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[int]bool, len(from))
+	for _, id := range from {
+		set[id] = true
+	}
+	changes := make([]LinkTagChange, 0, len(links))
+	for _, link := range links {
+		req := updateRequestFrom(link)
+		change := LinkTagChange{ShortURL: link.ShortURL, OldTags: req.Tags}
+		req.Tags = replaceIDs(req.Tags, set, to)
+		change.NewTags = req.Tags
+		if !dryRun {
+			if _, err := c.updateShortLink(ctx, req); err != nil {
+				change.Err = fmt.Errorf("retag %s: %w", link.ShortURL, err)
+				change.Error = change.Err.Error()
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// firstChangeErr returns the first failed change, if any.
+func firstChangeErr(changes []LinkTagChange) error {
+	for _, ch := range changes {
+		if ch.Err != nil {
+			return ch.Err
+		}
+	}
+	return nil
+}
+
+// AttachPixelToLink adds pixelID to the pixels of shortURL, keeping the
+// link's other settings. Attaching a pixel that is already present is a
+// no-op that returns the current link.
+func (c *Client) AttachPixelToLink(ctx context.Context, shortURL string, pixelID int) (*ShortLink, error) {
+	link, err := c.fetchShortLink(ctx, shortURL)
+	if err != nil {
+		return nil, err
+	}
+	ids := pixelIDs(link.Pixels)
+	for _, id := range ids {
+		if id == pixelID {
+			return link, nil
+		}
+	}
+	req := updateRequestFrom(*link)
+	req.Pixels = append(ids, pixelID)
+	return c.updateShortLink(ctx, req)
+}
+
+// DetachPixelFromLink removes pixelID from the pixels of shortURL, keeping
+// the link's other settings. Detaching a pixel that is not attached is a
+// no-op that returns the current link.
+func (c *Client) DetachPixelFromLink(ctx context.Context, shortURL string, pixelID int) (*ShortLink, error) {
+	link, err := c.fetchShortLink(ctx, shortURL)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(link.Pixels))
+	found := false
+	for _, id := range pixelIDs(link.Pixels) {
+		if id == pixelID {
+			found = true
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if !found {
+		return link, nil
+	}
+	req := updateRequestFrom(*link)
+	req.Pixels = ids
+	return c.updateShortLink(ctx, req)
+}
+
+// containsID reports whether ids contains id.
+func containsID(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// LinkFilter selects links for bulk operations. List is sent to the list
+// endpoint; Domain and Match narrow the results client-side.
+type LinkFilter struct {
+	List ShortLinkListOptions
+	// Domain keeps only links on this domain, e.g. "https://t.ly/" or
+	// "go.acme.com". Scheme and trailing slash are ignored.
+	Domain string
+	// Match, when set, keeps only links for which it returns true.
+	Match func(ShortLink) bool
+}
+
+// matches reports whether link passes the client-side parts of f.
+func (f LinkFilter) matches(link ShortLink) bool {
+	if f.Domain != "" && bareHost(link.Domain) != bareHost(f.Domain) {
+		return false
+	}
+	return f.Match == nil || f.Match(link)
+}
+
+// bareHost strips the scheme and trailing slash from a domain.
+func bareHost(domain string) string {
+	if i := strings.Index(domain, "://"); i >= 0 {
+		domain = domain[i+3:]
+	}
+	return strings.ToLower(strings.TrimRight(domain, "/"))
+}
+
+// filterLinks returns every link matching f, following pagination.
+func (c *Client) filterLinks(ctx context.Context, f LinkFilter) ([]ShortLink, error) {
+	links, err := c.ListAllShortLinks(ctx, f.List)
+	if err != nil {
+		return nil, err
+	}
+	matched := links[:0]
+	for _, link := range links {
+		if f.matches(link) {
+			matched = append(matched, link)
+		}
+	}
+	return matched, nil
+}
+
+// AttachPixelOptions configures AttachPixelToFilter.
+type AttachPixelOptions struct {
+	// DryRun reports the changes without updating links.
+	DryRun bool
+	// Interval is the minimum delay between link updates, to stay under
+	// the API rate limit. Zero sends updates back to back.
+	Interval time.Duration
+}
+
+// LinkPixelChange records the pixel change of one link.
+type LinkPixelChange struct {
+	ShortURL  string `json:"short_url"`
+	OldPixels []int  `json:"old_pixels"`
+	NewPixels []int  `json:"new_pixels"`
+	// Unchanged is set when the link already carried the pixel.
+	Unchanged bool `json:"unchanged"`
+	// Err is set when the update failed, and Error holds its message for
+	// JSON reports.
+	Err   error  `json:"-"`
+	Error string `json:"error,omitempty"`
+}
+
+// AttachPixelToFilter adds pixelID to every link matching filter, keeping
+// each link's other pixels and settings. Links that already carry the pixel
+// are reported as unchanged. Updates run one at a time, at most one per
+// opts.Interval. Per-link failures are recorded in the changes; the error is
+// set when the links cannot be listed or ctx is done.
+func (c *Client) AttachPixelToFilter(ctx context.Context, pixelID int, filter LinkFilter, opts AttachPixelOptions) ([]LinkPixelChange, error) {
+	links, err := c.filterLinks(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var tick <-chan time.Time
+	if opts.Interval > 0 && !opts.DryRun {
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	changes := make([]LinkPixelChange, 0, len(links))
+	updated := 0
+	for _, link := range links {
+		req := updateRequestFrom(link)
+		change := LinkPixelChange{ShortURL: link.ShortURL, OldPixels: req.Pixels, NewPixels: req.Pixels}
+		if containsID(req.Pixels, pixelID) {
+			change.Unchanged = true
+			changes = append(changes, change)
+			continue
+		}
+		change.NewPixels = append(append([]int(nil), req.Pixels...), pixelID)
+		if !opts.DryRun {
+			if tick != nil && updated > 0 {
+				select {
+				case <-tick:
+				case <-ctx.Done():
+					return changes, ctx.Err()
+				}
+			}
+			updated++
+			req.Pixels = change.NewPixels
+			if _, err := c.updateShortLink(ctx, req); err != nil {
+				change.Err = fmt.Errorf("attach pixel to %s: %w", link.ShortURL, err)
+				change.Error = change.Err.Error()
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// CreateShortLinksOptions configures CreateShortLinks.
+type CreateShortLinksOptions struct {
+	// Concurrency bounds the parallel requests. Defaults to 4.
+	Concurrency int
+}
+
+// ShortLinkCreateResult is the outcome of creating one short link.
+type ShortLinkCreateResult struct {
+	Link  *ShortLink `json:"link,omitempty"`
+	Err   error      `json:"-"`
+	Error string     `json:"error,omitempty"`
+}
+
+// CreateShortLinks creates several short links in parallel. Unlike
+// BulkShortenLinks it reports the short URL of every link it creates. A
+// failure on one link is recorded in its result and does not stop the
+// others. The results are index-aligned with reqs; the error is set only if
+// ctx is done.
+func (c *Client) CreateShortLinks(ctx context.Context, reqs []ShortLinkCreateRequest, opts CreateShortLinksOptions) ([]ShortLinkCreateResult, error) {
+	results := make([]ShortLinkCreateResult, len(reqs))
+	err := forEachLimit(ctx, len(reqs), opts.Concurrency, func(ctx context.Context, i int) error {
+		link, err := c.createShortLink(ctx, reqs[i])
+		results[i] = ShortLinkCreateResult{Link: link, Err: err, Error: errorText(err)}
+		return nil
+	})
+	return results, err
+}
@@ -0,0 +1,46 @@
+package tly
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUpdateRequestFromKeepsMissingLists(t *testing.T) {
+	tests := []struct {
+		name       string
+		link       string
+		wantTags   string // JSON of the tags in the update body, "" if absent
+		wantPixels string
+	}{
+		{"lists left out", `{"short_url":"https://t.ly/a","long_url":"https://example.com"}`, "", ""},
+		{"tags left out", `{"short_url":"https://t.ly/a","long_url":"https://example.com","pixels":[{"id":3}]}`, "", "[3]"},
+		{"pixels left out", `{"short_url":"https://t.ly/a","long_url":"https://example.com","tags":[{"id":1},{"id":2}]}`, "[1,2]", ""},
+		{"empty lists", `{"short_url":"https://t.ly/a","long_url":"https://example.com","tags":[],"pixels":[]}`, "[]", "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var link ShortLink
+			if err := json.Unmarshal([]byte(tt.link), &link); err != nil {
+				t.Fatal(err)
+			}
+			body, err := updateBody(updateRequestFrom(link))
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := json.Marshal(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var sent map[string]json.RawMessage
+			if err := json.Unmarshal(data, &sent); err != nil {
+				t.Fatal(err)
+			}
+			if got := string(sent["tags"]); got != tt.wantTags {
+				t.Errorf("tags = %q, want %q in %s", got, tt.wantTags, data)
+			}
+			if got := string(sent["pixels"]); got != tt.wantPixels {
+				t.Errorf("pixels = %q, want %q in %s", got, tt.wantPixels, data)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package tly_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+// sparseServer is a fake API whose links leave out the fields set to "" in
+// link, e.g. "tags" or "pixels", as some get and list responses do. It
+// records the bodies of link updates.
+type sparseServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	updates []map[string]json.RawMessage
+}
+
+func newSparseServer(t *testing.T, link string) *sparseServer {
+	s := &sparseServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/link":
+			w.Write([]byte(link))
+		case "GET /api/v1/link/list":
+			w.Write([]byte(`{"current_page":1,"last_page":1,"per_page":100,"total":1,"data":[` + link + `]}`))
+		case "GET /api/v1/link/tag":
+			w.Write([]byte(`[{"id":1,"tag":"old"},{"id":2,"tag":"new"}]`))
+		case "DELETE /api/v1/link/tag/1":
+		case "PUT /api/v1/link":
+			data, _ := ioutil.ReadAll(r.Body)
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal(data, &body); err != nil {
+				t.Errorf("update body %s: %v", data, err)
+			}
+			s.mu.Lock()
+			s.updates = append(s.updates, body)
+			s.mu.Unlock()
+			w.Write([]byte(link))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// client returns a client of the server.
+func (s *sparseServer) client() *tly.Client {
+	c := tly.NewClient("test-key")
+	c.BaseURL = s.URL
+	return c
+}
+
+// update returns the only update body sent.
+func (s *sparseServer) update(t *testing.T) map[string]json.RawMessage {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.updates) != 1 {
+		t.Fatalf("%d updates sent, want 1", len(s.updates))
+	}
+	return s.updates[0]
+}
+
+// checkField fails unless body has field with the JSON value want, or
+// lacks it when want is "".
+func checkField(t *testing.T, body map[string]json.RawMessage, field, want string) {
+	t.Helper()
+	if got := string(body[field]); got != want {
+		t.Errorf("%s = %q, want %q", field, got, want)
+	}
+}
+
+func TestAttachPixelToLinkKeepsTags(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name   string
+		link   string
+		tags   string
+		pixels string
+	}{
+		{"tags left out", `{"short_url":"https://t.ly/a","long_url":"https://example.com","pixels":[{"id":3}]}`, "", "[3,9]"},
+		{"both left out", `{"short_url":"https://t.ly/a","long_url":"https://example.com"}`, "", "[9]"},
+		{"tags reported", `{"short_url":"https://t.ly/a","long_url":"https://example.com","tags":[{"id":1}]}`, "[1]", "[9]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newSparseServer(t, tt.link)
+			if _, err := srv.client().AttachPixelToLink(ctx, "https://t.ly/a", 9); err != nil {
+				t.Fatal(err)
+			}
+			body := srv.update(t)
+			checkField(t, body, "tags", tt.tags)
+			checkField(t, body, "pixels", tt.pixels)
+		})
+	}
+}
+
+func TestDetachPixelFromLinkKeepsTags(t *testing.T) {
+	srv := newSparseServer(t, `{"short_url":"https://t.ly/a","long_url":"https://example.com","pixels":[{"id":9}]}`)
+	if _, err := srv.client().DetachPixelFromLink(context.Background(), "https://t.ly/a", 9); err != nil {
+		t.Fatal(err)
+	}
+	body := srv.update(t)
+	checkField(t, body, "tags", "")
+	checkField(t, body, "pixels", "[]")
+}
+
+func TestRenameTagMergeKeepsPixels(t *testing.T) {
+	srv := newSparseServer(t, `{"short_url":"https://t.ly/a","long_url":"https://example.com","tags":[{"id":1}]}`)
+	tag, err := srv.client().RenameTag(context.Background(), 1, "new", tly.RenameTagOptions{OnConflict: tly.RenameMerge})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.ID != 2 {
+		t.Errorf("merged into tag %d, want 2", tag.ID)
+	}
+	body := srv.update(t)
+	checkField(t, body, "tags", "[2]")
+	checkField(t, body, "pixels", "")
+}
+
+func TestAttachPixelToFilterKeepsTags(t *testing.T) {
+	srv := newSparseServer(t, `{"short_url":"https://t.ly/a","long_url":"https://example.com","pixels":[{"id":3}]}`)
+	changes, err := srv.client().AttachPixelToFilter(context.Background(), 9, tly.LinkFilter{}, tly.AttachPixelOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Err != nil {
+		t.Fatalf("changes = %+v", changes)
+	}
+	body := srv.update(t)
+	checkField(t, body, "tags", "")
+	checkField(t, body, "pixels", "[3,9]")
+}
+
+func TestMergeTagsKeepsPixels(t *testing.T) {
+	srv := newSparseServer(t, `{"short_url":"https://t.ly/a","long_url":"https://example.com","tags":[{"id":1}]}`)
+	if _, err := srv.client().MergeTags(context.Background(), []int{1}, 2, tly.MergeTagsOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	body := srv.update(t)
+	checkField(t, body, "tags", "[2]")
+	checkField(t, body, "pixels", "")
+}
@@ -0,0 +1,22 @@
+// Package mocks provides mocks of the tly service interfaces, so code that
+// depends on tly.ShortLinkService, tly.TagService and the others can be
+// tested without a server or hand-written fakes.
+//
+// Stub the methods a test needs through their Func fields and inspect the
+// recorded calls afterwards:
+//
+//	links := &mocks.ShortLinkService{
+//		CreateShortLinkFunc: func(req tly.ShortLinkCreateRequest) (*tly.ShortLink, error) {
+//			return &tly.ShortLink{ShortURL: "https://t.ly/abc", LongURL: req.LongURL}, nil
+//		},
+//	}
+//	err := app.Publish(links, "https://example.com")
+//	if calls := links.CreateShortLinkCalls(); len(calls) != 1 {
+//		t.Fatalf("got %d calls, want 1", len(calls))
+//	}
+//
+// The mocks are generated from the interfaces; run go generate in this
+// directory after changing one.
+package mocks
+
+//go:generate go run gen.go
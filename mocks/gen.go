@@ -0,0 +1,274 @@
+//go:build ignore
+// +build ignore
+
+// gen writes mocks.go: a mock of every *Service interface of package tly.
+// Run it with go generate from this directory.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// method is one interface method.
+type method struct {
+	name    string
+	params  []param
+	results []string
+}
+
+// param is a named method parameter.
+type param struct {
+	name, typ string
+	variadic  bool
+}
+
+// service is an interface to mock.
+type service struct {
+	name    string
+	methods []method
+}
+
+func main() {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, "..", func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pkg := pkgs["tly"]
+	if pkg == nil {
+		log.Fatal("package tly not found")
+	}
+	types := map[string]bool{}
+	imports := map[string]string{}
+	var specs []*ast.TypeSpec
+	for _, f := range pkg.Files {
+		for _, imp := range f.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			name := path[strings.LastIndex(path, "/")+1:]
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			imports[name] = path
+		}
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				types[ts.Name.Name] = true
+				if _, ok := ts.Type.(*ast.InterfaceType); ok && strings.HasSuffix(ts.Name.Name, "Service") {
+					specs = append(specs, ts)
+				}
+			}
+		}
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Name.Name < specs[j].Name.Name })
+
+	used := map[string]bool{"sync": true}
+	typeString := func(expr ast.Expr) string {
+		ast.Inspect(expr, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.SelectorExpr:
+				used[n.X.(*ast.Ident).Name] = true
+				return false
+			case *ast.Ident:
+				if types[n.Name] {
+					n.Name = "tly." + n.Name
+				}
+			}
+			return true
+		})
+		var buf bytes.Buffer
+		printer.Fprint(&buf, fset, expr)
+		return buf.String()
+	}
+	var services []service
+	for _, ts := range specs {
+		s := service{name: ts.Name.Name}
+		for _, field := range ts.Type.(*ast.InterfaceType).Methods.List {
+			fn, ok := field.Type.(*ast.FuncType)
+			if !ok {
+				log.Fatalf("%s: embedded interfaces are not supported", s.name)
+			}
+			m := method{name: field.Names[0].Name}
+			for _, p := range fn.Params.List {
+				variadic := false
+				typ := p.Type
+				if e, ok := typ.(*ast.Ellipsis); ok {
+					variadic, typ = true, e.Elt
+				}
+				t := typeString(typ)
+				if len(p.Names) == 0 {
+					m.params = append(m.params, param{fmt.Sprintf("p%d", len(m.params)), t, variadic})
+				}
+				for _, n := range p.Names {
+					m.params = append(m.params, param{n.Name, t, variadic})
+				}
+			}
+			if fn.Results != nil {
+				for _, r := range fn.Results.List {
+					n := len(r.Names)
+					if n == 0 {
+						n = 1
+					}
+					for i := 0; i < n; i++ {
+						m.results = append(m.results, typeString(r.Type))
+					}
+				}
+			}
+			s.methods = append(s.methods, m)
+		}
+		services = append(services, s)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "// Code generated by gen.go; DO NOT EDIT.")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "package mocks")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "import (")
+	var names []string
+	for name := range used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := imports[name]
+		if name == "sync" {
+			path = "sync"
+		}
+		fmt.Fprintf(&b, "%q\n", path)
+	}
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, `tly "github.com/timleland/t.ly-go-url-shortener-api"`)
+	fmt.Fprintln(&b, ")")
+	for _, s := range services {
+		writeService(&b, s)
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatalf("formatting the output: %v\n%s", err, b.Bytes())
+	}
+	if err := ioutil.WriteFile("mocks.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// initialisms are parameter names exported in upper case.
+var initialisms = map[string]string{"id": "ID", "url": "URL"}
+
+// exported returns name with its first letter in upper case.
+func exported(name string) string {
+	if s, ok := initialisms[name]; ok {
+		return s
+	}
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// writeService writes the mock of s and its call record types.
+func writeService(b *bytes.Buffer, s service) {
+	fmt.Fprintf(b, "\n// %s is a mock of tly.%s.\n", s.name, s.name)
+	fmt.Fprintf(b, "// Set the Func field of a method to stub it; calling a method whose Func\n")
+	fmt.Fprintf(b, "// is nil panics. Every call is recorded and returned by the method's\n")
+	fmt.Fprintf(b, "// Calls function.\n")
+	fmt.Fprintf(b, "type %s struct {\n", s.name)
+	for _, m := range s.methods {
+		fmt.Fprintf(b, "%sFunc func(%s) %s\n", m.name, signature(m.params), results(m.results))
+	}
+	fmt.Fprintln(b)
+	fmt.Fprintln(b, "mu sync.Mutex")
+	fmt.Fprintln(b, "calls struct {")
+	for _, m := range s.methods {
+		fmt.Fprintf(b, "%s []%s%sCall\n", m.name, s.name, m.name)
+	}
+	fmt.Fprintln(b, "}")
+	fmt.Fprintln(b, "}")
+	fmt.Fprintf(b, "\nvar _ tly.%s = (*%s)(nil)\n", s.name, s.name)
+	for _, m := range s.methods {
+		call := s.name + m.name + "Call"
+		fmt.Fprintf(b, "\n// %s records a call of %s.%s.\n", call, s.name, m.name)
+		fmt.Fprintf(b, "type %s struct {\n", call)
+		for _, p := range m.params {
+			typ := p.typ
+			if p.variadic {
+				typ = "[]" + typ
+			}
+			fmt.Fprintf(b, "%s %s\n", exported(p.name), typ)
+		}
+		fmt.Fprintln(b, "}")
+
+		var args, fields []string
+		for _, p := range m.params {
+			arg := p.name
+			if p.variadic {
+				arg += "..."
+			}
+			args = append(args, arg)
+			fields = append(fields, fmt.Sprintf("%s: %s", exported(p.name), p.name))
+		}
+		fmt.Fprintf(b, "\n// %s calls %sFunc.\n", m.name, m.name)
+		fmt.Fprintf(b, "func (m *%s) %s(%s) %s {\n", s.name, m.name, signature(m.params), results(m.results))
+		fmt.Fprintln(b, "m.mu.Lock()")
+		fmt.Fprintf(b, "m.calls.%s = append(m.calls.%s, %s{%s})\n", m.name, m.name, call, strings.Join(fields, ", "))
+		fmt.Fprintln(b, "m.mu.Unlock()")
+		fmt.Fprintf(b, "if m.%sFunc == nil {\n", m.name)
+		fmt.Fprintf(b, "panic(\"mocks: %s.%sFunc is not set\")\n", s.name, m.name)
+		fmt.Fprintln(b, "}")
+		ret := ""
+		if len(m.results) > 0 {
+			ret = "return "
+		}
+		fmt.Fprintf(b, "%sm.%sFunc(%s)\n", ret, m.name, strings.Join(args, ", "))
+		fmt.Fprintln(b, "}")
+
+		fmt.Fprintf(b, "\n// %sCalls returns the calls of %s so far.\n", m.name, m.name)
+		fmt.Fprintf(b, "func (m *%s) %sCalls() []%s {\n", s.name, m.name, call)
+		fmt.Fprintln(b, "m.mu.Lock()")
+		fmt.Fprintln(b, "defer m.mu.Unlock()")
+		fmt.Fprintf(b, "return append([]%s(nil), m.calls.%s...)\n", call, m.name)
+		fmt.Fprintln(b, "}")
+	}
+}
+
+// signature returns the parameter list of params.
+func signature(params []param) string {
+	var parts []string
+	for _, p := range params {
+		typ := p.typ
+		if p.variadic {
+			typ = "..." + typ
+		}
+		parts = append(parts, p.name+" "+typ)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// results returns the result list of a method.
+func results(rs []string) string {
+	switch len(rs) {
+	case 0:
+		return ""
+	case 1:
+		return rs[0]
+	}
+	return "(" + strings.Join(rs, ", ") + ")"
+}
@@ -0,0 +1,1486 @@
+// Code generated by gen.go; DO NOT EDIT.
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+// BioPageService is a mock of tly.BioPageService.
+// Set the Func field of a method to stub it; calling a method whose Func
+// is nil panics. Every call is recorded and returned by the method's
+// Calls function.
+type BioPageService struct {
+	ListBioPagesFunc     func(ctx context.Context) ([]tly.BioPage, error)
+	GetBioPageFunc       func(ctx context.Context, id int) (*tly.BioPage, error)
+	CreateBioPageFunc    func(ctx context.Context, reqData tly.BioPageRequest) (*tly.BioPage, error)
+	UpdateBioPageFunc    func(ctx context.Context, id int, reqData tly.BioPageRequest) (*tly.BioPage, error)
+	DeleteBioPageFunc    func(ctx context.Context, id int) error
+	AddBioBlockFunc      func(ctx context.Context, pageID int, reqData tly.BioBlockRequest) (*tly.BioBlock, error)
+	UpdateBioBlockFunc   func(ctx context.Context, pageID int, blockID int, reqData tly.BioBlockRequest) (*tly.BioBlock, error)
+	DeleteBioBlockFunc   func(ctx context.Context, pageID int, blockID int) error
+	ReorderBioBlocksFunc func(ctx context.Context, pageID int, blockIDs []int) (*tly.BioPage, error)
+
+	mu    sync.Mutex
+	calls struct {
+		ListBioPages     []BioPageServiceListBioPagesCall
+		GetBioPage       []BioPageServiceGetBioPageCall
+		CreateBioPage    []BioPageServiceCreateBioPageCall
+		UpdateBioPage    []BioPageServiceUpdateBioPageCall
+		DeleteBioPage    []BioPageServiceDeleteBioPageCall
+		AddBioBlock      []BioPageServiceAddBioBlockCall
+		UpdateBioBlock   []BioPageServiceUpdateBioBlockCall
+		DeleteBioBlock   []BioPageServiceDeleteBioBlockCall
+		ReorderBioBlocks []BioPageServiceReorderBioBlocksCall
+	}
+}
+
+var _ tly.BioPageService = (*BioPageService)(nil)
+
+// BioPageServiceListBioPagesCall records a call of BioPageService.ListBioPages.
+type BioPageServiceListBioPagesCall struct {
+	Ctx context.Context
+}
+
+// ListBioPages calls ListBioPagesFunc.
+func (m *BioPageService) ListBioPages(ctx context.Context) ([]tly.BioPage, error) {
+	m.mu.Lock()
+	m.calls.ListBioPages = append(m.calls.ListBioPages, BioPageServiceListBioPagesCall{Ctx: ctx})
+	m.mu.Unlock()
+	if m.ListBioPagesFunc == nil {
+		panic("mocks: BioPageService.ListBioPagesFunc is not set")
+	}
+	return m.ListBioPagesFunc(ctx)
+}
+
+// ListBioPagesCalls returns the calls of ListBioPages so far.
+func (m *BioPageService) ListBioPagesCalls() []BioPageServiceListBioPagesCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]BioPageServiceListBioPagesCall(nil), m.calls.ListBioPages...)
+}
+
+// BioPageServiceGetBioPageCall records a call of BioPageService.GetBioPage.
+type BioPageServiceGetBioPageCall struct {
+	Ctx context.Context
+	ID  int
+}
+
+// GetBioPage calls GetBioPageFunc.
+func (m *BioPageService) GetBioPage(ctx context.Context, id int) (*tly.BioPage, error) {
+	m.mu.Lock()
+	m.calls.GetBioPage = append(m.calls.GetBioPage, BioPageServiceGetBioPageCall{Ctx: ctx, ID: id})
+	m.mu.Unlock()
+	if m.GetBioPageFunc == nil {
+		panic("mocks: BioPageService.GetBioPageFunc is not set")
+	}
+	return m.GetBioPageFunc(ctx, id)
+}
+
+// GetBioPageCalls returns the calls of GetBioPage so far.
+func (m *BioPageService) GetBioPageCalls() []BioPageServiceGetBioPageCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]BioPageServiceGetBioPageCall(nil), m.calls.GetBioPage...)
+}
+
+// BioPageServiceCreateBioPageCall records a call of BioPageService.CreateBioPage.
+type BioPageServiceCreateBioPageCall struct {
+	Ctx     context.Context
+	ReqData tly.BioPageRequest
+}
+
+// CreateBioPage calls CreateBioPageFunc.
+func (m *BioPageService) CreateBioPage(ctx context.Context, reqData tly.BioPageRequest) (*tly.BioPage, error) {
+	m.mu.Lock()
+	m.calls.CreateBioPage = append(m.calls.CreateBioPage, BioPageServiceCreateBioPageCall{Ctx: ctx, ReqData: reqData})
+	m.mu.Unlock()
+	if m.CreateBioPageFunc == nil {
+		panic("mocks: BioPageService.CreateBioPageFunc is not set")
+	}
+	return m.CreateBioPageFunc(ctx, reqData)
+}
+
+// CreateBioPageCalls returns the calls of CreateBioPage so far.
+func (m *BioPageService) CreateBioPageCalls() []BioPageServiceCreateBioPageCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]BioPageServiceCreateBioPageCall(nil), m.calls.CreateBioPage...)
+}
+
+// BioPageServiceUpdateBioPageCall records a call of BioPageService.UpdateBioPage.
+type BioPageServiceUpdateBioPageCall struct {
+	Ctx     context.Context
+	ID      int
+	ReqData tly.BioPageRequest
+}
+
+// UpdateBioPage calls UpdateBioPageFunc.
+func (m *BioPageService) UpdateBioPage(ctx context.Context, id int, reqData tly.BioPageRequest) (*tly.BioPage, error) {
+	m.mu.Lock()
+	m.calls.UpdateBioPage = append(m.calls.UpdateBioPage, BioPageServiceUpdateBioPageCall{Ctx: ctx, ID: id, ReqData: reqData})
+	m.mu.Unlock()
+	if m.UpdateBioPageFunc == nil {
+		panic("mocks: BioPageService.UpdateBioPageFunc is not set")
+	}
+	return m.UpdateBioPageFunc(ctx, id, reqData)
+}
+
+// UpdateBioPageCalls returns the calls of UpdateBioPage so far.
+func (m *BioPageService) UpdateBioPageCalls() []BioPageServiceUpdateBioPageCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]BioPageServiceUpdateBioPageCall(nil), m.calls.UpdateBioPage...)
+}
+
+// BioPageServiceDeleteBioPageCall records a call of BioPageService.DeleteBioPage.
+type BioPageServiceDeleteBioPageCall struct {
+	Ctx context.Context
+	ID  int
+}
+
+// DeleteBioPage calls DeleteBioPageFunc.
+func (m *BioPageService) DeleteBioPage(ctx context.Context, id int) error {
+	m.mu.Lock()
+	m.calls.DeleteBioPage = append(m.calls.DeleteBioPage, BioPageServiceDeleteBioPageCall{Ctx: ctx, ID: id})
+	m.mu.Unlock()
+	if m.DeleteBioPageFunc == nil {
+		panic("mocks: BioPageService.DeleteBioPageFunc is not set")
+	}
+	return m.DeleteBioPageFunc(ctx, id)
+}
+
+// DeleteBioPageCalls returns the calls of DeleteBioPage so far.
+func (m *BioPageService) DeleteBioPageCalls() []BioPageServiceDeleteBioPageCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]BioPageServiceDeleteBioPageCall(nil), m.calls.DeleteBioPage...)
+}
+
+// BioPageServiceAddBioBlockCall records a call of BioPageService.AddBioBlock.
+type BioPageServiceAddBioBlockCall struct {
+	Ctx     context.Context
+	PageID  int
+	ReqData tly.BioBlockRequest
+}
+
+// AddBioBlock calls AddBioBlockFunc.
+func (m *BioPageService) AddBioBlock(ctx context.Context, pageID int, reqData tly.BioBlockRequest) (*tly.BioBlock, error) {
+	m.mu.Lock()
+	m.calls.AddBioBlock = append(m.calls.AddBioBlock, BioPageServiceAddBioBlockCall{Ctx: ctx, PageID: pageID, ReqData: reqData})
+	m.mu.Unlock()
+	if m.AddBioBlockFunc == nil {
+		panic("mocks: BioPageService.AddBioBlockFunc is not set")
+	}
+	return m.AddBioBlockFunc(ctx, pageID, reqData)
+}
+
+// AddBioBlockCalls returns the calls of AddBioBlock so far.
+func (m *BioPageService) AddBioBlockCalls() []BioPageServiceAddBioBlockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]BioPageServiceAddBioBlockCall(nil), m.calls.AddBioBlock...)
+}
+
+// BioPageServiceUpdateBioBlockCall records a call of BioPageService.UpdateBioBlock.
+type BioPageServiceUpdateBioBlockCall struct {
+	Ctx     context.Context
+	PageID  int
+	BlockID int
+	ReqData tly.BioBlockRequest
+}
+
+// UpdateBioBlock calls UpdateBioBlockFunc.
+func (m *BioPageService) UpdateBioBlock(ctx context.Context, pageID int, blockID int, reqData tly.BioBlockRequest) (*tly.BioBlock, error) {
+	m.mu.Lock()
+	m.calls.UpdateBioBlock = append(m.calls.UpdateBioBlock, BioPageServiceUpdateBioBlockCall{Ctx: ctx, PageID: pageID, BlockID: blockID, ReqData: reqData})
+	m.mu.Unlock()
+	if m.UpdateBioBlockFunc == nil {
+		panic("mocks: BioPageService.UpdateBioBlockFunc is not set")
+	}
+	return m.UpdateBioBlockFunc(ctx, pageID, blockID, reqData)
+}
+
+// UpdateBioBlockCalls returns the calls of UpdateBioBlock so far.
+func (m *BioPageService) UpdateBioBlockCalls() []BioPageServiceUpdateBioBlockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]BioPageServiceUpdateBioBlockCall(nil), m.calls.UpdateBioBlock...)
+}
+
+// BioPageServiceDeleteBioBlockCall records a call of BioPageService.DeleteBioBlock.
+type BioPageServiceDeleteBioBlockCall struct {
+	Ctx     context.Context
+	PageID  int
+	BlockID int
+}
+
+// DeleteBioBlock calls DeleteBioBlockFunc.
+func (m *BioPageService) DeleteBioBlock(ctx context.Context, pageID int, blockID int) error {
+	m.mu.Lock()
+	m.calls.DeleteBioBlock = append(m.calls.DeleteBioBlock, BioPageServiceDeleteBioBlockCall{Ctx: ctx, PageID: pageID, BlockID: blockID})
+	m.mu.Unlock()
+	if m.DeleteBioBlockFunc == nil {
+		panic("mocks: BioPageService.DeleteBioBlockFunc is not set")
+	}
+	return m.DeleteBioBlockFunc(ctx, pageID, blockID)
+}
+
+// DeleteBioBlockCalls returns the calls of DeleteBioBlock so far.
+func (m *BioPageService) DeleteBioBlockCalls() []BioPageServiceDeleteBioBlockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]BioPageServiceDeleteBioBlockCall(nil), m.calls.DeleteBioBlock...)
+}
+
+// BioPageServiceReorderBioBlocksCall records a call of BioPageService.ReorderBioBlocks.
+type BioPageServiceReorderBioBlocksCall struct {
+	Ctx      context.Context
+	PageID   int
+	BlockIDs []int
+}
+
+// ReorderBioBlocks calls ReorderBioBlocksFunc.
+func (m *BioPageService) ReorderBioBlocks(ctx context.Context, pageID int, blockIDs []int) (*tly.BioPage, error) {
+	m.mu.Lock()
+	m.calls.ReorderBioBlocks = append(m.calls.ReorderBioBlocks, BioPageServiceReorderBioBlocksCall{Ctx: ctx, PageID: pageID, BlockIDs: blockIDs})
+	m.mu.Unlock()
+	if m.ReorderBioBlocksFunc == nil {
+		panic("mocks: BioPageService.ReorderBioBlocksFunc is not set")
+	}
+	return m.ReorderBioBlocksFunc(ctx, pageID, blockIDs)
+}
+
+// ReorderBioBlocksCalls returns the calls of ReorderBioBlocks so far.
+func (m *BioPageService) ReorderBioBlocksCalls() []BioPageServiceReorderBioBlocksCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]BioPageServiceReorderBioBlocksCall(nil), m.calls.ReorderBioBlocks...)
+}
+
+// CampaignService is a mock of tly.CampaignService.
+// Set the Func field of a method to stub it; calling a method whose Func
+// is nil panics. Every call is recorded and returned by the method's
+// Calls function.
+type CampaignService struct {
+	ListCampaignsFunc     func(ctx context.Context) ([]tly.Campaign, error)
+	GetCampaignFunc       func(ctx context.Context, id int) (*tly.Campaign, error)
+	CreateCampaignFunc    func(ctx context.Context, reqData tly.CampaignRequest) (*tly.Campaign, error)
+	UpdateCampaignFunc    func(ctx context.Context, id int, reqData tly.CampaignRequest) (*tly.Campaign, error)
+	DeleteCampaignFunc    func(ctx context.Context, id int) error
+	AddLinkToCampaignFunc func(ctx context.Context, id int, shortURL string) error
+	ListCampaignLinksFunc func(ctx context.Context, id int) ([]tly.ShortLink, error)
+	GetCampaignStatsFunc  func(ctx context.Context, id int, opts tly.StatsOptions) (*tly.CampaignStats, error)
+
+	mu    sync.Mutex
+	calls struct {
+		ListCampaigns     []CampaignServiceListCampaignsCall
+		GetCampaign       []CampaignServiceGetCampaignCall
+		CreateCampaign    []CampaignServiceCreateCampaignCall
+		UpdateCampaign    []CampaignServiceUpdateCampaignCall
+		DeleteCampaign    []CampaignServiceDeleteCampaignCall
+		AddLinkToCampaign []CampaignServiceAddLinkToCampaignCall
+		ListCampaignLinks []CampaignServiceListCampaignLinksCall
+		GetCampaignStats  []CampaignServiceGetCampaignStatsCall
+	}
+}
+
+var _ tly.CampaignService = (*CampaignService)(nil)
+
+// CampaignServiceListCampaignsCall records a call of CampaignService.ListCampaigns.
+type CampaignServiceListCampaignsCall struct {
+	Ctx context.Context
+}
+
+// ListCampaigns calls ListCampaignsFunc.
+func (m *CampaignService) ListCampaigns(ctx context.Context) ([]tly.Campaign, error) {
+	m.mu.Lock()
+	m.calls.ListCampaigns = append(m.calls.ListCampaigns, CampaignServiceListCampaignsCall{Ctx: ctx})
+	m.mu.Unlock()
+	if m.ListCampaignsFunc == nil {
+		panic("mocks: CampaignService.ListCampaignsFunc is not set")
+	}
+	return m.ListCampaignsFunc(ctx)
+}
+
+// ListCampaignsCalls returns the calls of ListCampaigns so far.
+func (m *CampaignService) ListCampaignsCalls() []CampaignServiceListCampaignsCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]CampaignServiceListCampaignsCall(nil), m.calls.ListCampaigns...)
+}
+
+// CampaignServiceGetCampaignCall records a call of CampaignService.GetCampaign.
+type CampaignServiceGetCampaignCall struct {
+	Ctx context.Context
+	ID  int
+}
+
+// GetCampaign calls GetCampaignFunc.
+func (m *CampaignService) GetCampaign(ctx context.Context, id int) (*tly.Campaign, error) {
+	m.mu.Lock()
+	m.calls.GetCampaign = append(m.calls.GetCampaign, CampaignServiceGetCampaignCall{Ctx: ctx, ID: id})
+	m.mu.Unlock()
+	if m.GetCampaignFunc == nil {
+		panic("mocks: CampaignService.GetCampaignFunc is not set")
+	}
+	return m.GetCampaignFunc(ctx, id)
+}
+
+// GetCampaignCalls returns the calls of GetCampaign so far.
+func (m *CampaignService) GetCampaignCalls() []CampaignServiceGetCampaignCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]CampaignServiceGetCampaignCall(nil), m.calls.GetCampaign...)
+}
+
+// CampaignServiceCreateCampaignCall records a call of CampaignService.CreateCampaign.
+type CampaignServiceCreateCampaignCall struct {
+	Ctx     context.Context
+	ReqData tly.CampaignRequest
+}
+
+// CreateCampaign calls CreateCampaignFunc.
+func (m *CampaignService) CreateCampaign(ctx context.Context, reqData tly.CampaignRequest) (*tly.Campaign, error) {
+	m.mu.Lock()
+	m.calls.CreateCampaign = append(m.calls.CreateCampaign, CampaignServiceCreateCampaignCall{Ctx: ctx, ReqData: reqData})
+	m.mu.Unlock()
+	if m.CreateCampaignFunc == nil {
+		panic("mocks: CampaignService.CreateCampaignFunc is not set")
+	}
+	return m.CreateCampaignFunc(ctx, reqData)
+}
+
+// CreateCampaignCalls returns the calls of CreateCampaign so far.
+func (m *CampaignService) CreateCampaignCalls() []CampaignServiceCreateCampaignCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]CampaignServiceCreateCampaignCall(nil), m.calls.CreateCampaign...)
+}
+
+// CampaignServiceUpdateCampaignCall records a call of CampaignService.UpdateCampaign.
+type CampaignServiceUpdateCampaignCall struct {
+	Ctx     context.Context
+	ID      int
+	ReqData tly.CampaignRequest
+}
+
+// UpdateCampaign calls UpdateCampaignFunc.
+func (m *CampaignService) UpdateCampaign(ctx context.Context, id int, reqData tly.CampaignRequest) (*tly.Campaign, error) {
+	m.mu.Lock()
+	m.calls.UpdateCampaign = append(m.calls.UpdateCampaign, CampaignServiceUpdateCampaignCall{Ctx: ctx, ID: id, ReqData: reqData})
+	m.mu.Unlock()
+	if m.UpdateCampaignFunc == nil {
+		panic("mocks: CampaignService.UpdateCampaignFunc is not set")
+	}
+	return m.UpdateCampaignFunc(ctx, id, reqData)
+}
+
+// UpdateCampaignCalls returns the calls of UpdateCampaign so far.
+func (m *CampaignService) UpdateCampaignCalls() []CampaignServiceUpdateCampaignCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]CampaignServiceUpdateCampaignCall(nil), m.calls.UpdateCampaign...)
+}
+
+// CampaignServiceDeleteCampaignCall records a call of CampaignService.DeleteCampaign.
+type CampaignServiceDeleteCampaignCall struct {
+	Ctx context.Context
+	ID  int
+}
+
+// DeleteCampaign calls DeleteCampaignFunc.
+func (m *CampaignService) DeleteCampaign(ctx context.Context, id int) error {
+	m.mu.Lock()
+	m.calls.DeleteCampaign = append(m.calls.DeleteCampaign, CampaignServiceDeleteCampaignCall{Ctx: ctx, ID: id})
+	m.mu.Unlock()
+	if m.DeleteCampaignFunc == nil {
+		panic("mocks: CampaignService.DeleteCampaignFunc is not set")
+	}
+	return m.DeleteCampaignFunc(ctx, id)
+}
+
+// DeleteCampaignCalls returns the calls of DeleteCampaign so far.
+func (m *CampaignService) DeleteCampaignCalls() []CampaignServiceDeleteCampaignCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]CampaignServiceDeleteCampaignCall(nil), m.calls.DeleteCampaign...)
+}
+
+// CampaignServiceAddLinkToCampaignCall records a call of CampaignService.AddLinkToCampaign.
+type CampaignServiceAddLinkToCampaignCall struct {
+	Ctx      context.Context
+	ID       int
+	ShortURL string
+}
+
+// AddLinkToCampaign calls AddLinkToCampaignFunc.
+func (m *CampaignService) AddLinkToCampaign(ctx context.Context, id int, shortURL string) error {
+	m.mu.Lock()
+	m.calls.AddLinkToCampaign = append(m.calls.AddLinkToCampaign, CampaignServiceAddLinkToCampaignCall{Ctx: ctx, ID: id, ShortURL: shortURL})
+	m.mu.Unlock()
+	if m.AddLinkToCampaignFunc == nil {
+		panic("mocks: CampaignService.AddLinkToCampaignFunc is not set")
+	}
+	return m.AddLinkToCampaignFunc(ctx, id, shortURL)
+}
+
+// AddLinkToCampaignCalls returns the calls of AddLinkToCampaign so far.
+func (m *CampaignService) AddLinkToCampaignCalls() []CampaignServiceAddLinkToCampaignCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]CampaignServiceAddLinkToCampaignCall(nil), m.calls.AddLinkToCampaign...)
+}
+
+// CampaignServiceListCampaignLinksCall records a call of CampaignService.ListCampaignLinks.
+type CampaignServiceListCampaignLinksCall struct {
+	Ctx context.Context
+	ID  int
+}
+
+// ListCampaignLinks calls ListCampaignLinksFunc.
+func (m *CampaignService) ListCampaignLinks(ctx context.Context, id int) ([]tly.ShortLink, error) {
+	m.mu.Lock()
+	m.calls.ListCampaignLinks = append(m.calls.ListCampaignLinks, CampaignServiceListCampaignLinksCall{Ctx: ctx, ID: id})
+	m.mu.Unlock()
+	if m.ListCampaignLinksFunc == nil {
+		panic("mocks: CampaignService.ListCampaignLinksFunc is not set")
+	}
+	return m.ListCampaignLinksFunc(ctx, id)
+}
+
+// ListCampaignLinksCalls returns the calls of ListCampaignLinks so far.
+func (m *CampaignService) ListCampaignLinksCalls() []CampaignServiceListCampaignLinksCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]CampaignServiceListCampaignLinksCall(nil), m.calls.ListCampaignLinks...)
+}
+
+// CampaignServiceGetCampaignStatsCall records a call of CampaignService.GetCampaignStats.
+type CampaignServiceGetCampaignStatsCall struct {
+	Ctx  context.Context
+	ID   int
+	Opts tly.StatsOptions
+}
+
+// GetCampaignStats calls GetCampaignStatsFunc.
+func (m *CampaignService) GetCampaignStats(ctx context.Context, id int, opts tly.StatsOptions) (*tly.CampaignStats, error) {
+	m.mu.Lock()
+	m.calls.GetCampaignStats = append(m.calls.GetCampaignStats, CampaignServiceGetCampaignStatsCall{Ctx: ctx, ID: id, Opts: opts})
+	m.mu.Unlock()
+	if m.GetCampaignStatsFunc == nil {
+		panic("mocks: CampaignService.GetCampaignStatsFunc is not set")
+	}
+	return m.GetCampaignStatsFunc(ctx, id, opts)
+}
+
+// GetCampaignStatsCalls returns the calls of GetCampaignStats so far.
+func (m *CampaignService) GetCampaignStatsCalls() []CampaignServiceGetCampaignStatsCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]CampaignServiceGetCampaignStatsCall(nil), m.calls.GetCampaignStats...)
+}
+
+// DomainService is a mock of tly.DomainService.
+// Set the Func field of a method to stub it; calling a method whose Func
+// is nil panics. Every call is recorded and returned by the method's
+// Calls function.
+type DomainService struct {
+	ListDomainsFunc          func(ctx context.Context) ([]tly.Domain, error)
+	AddDomainFunc            func(ctx context.Context, reqData tly.DomainCreateRequest) (*tly.Domain, error)
+	DeleteDomainFunc         func(ctx context.Context, id int) error
+	CheckDomainStatusFunc    func(ctx context.Context, hostname string) (*tly.DomainStatusReport, error)
+	GetDomainSettingsFunc    func(ctx context.Context, id int) (*tly.DomainSettings, error)
+	UpdateDomainSettingsFunc func(ctx context.Context, id int, reqData tly.DomainSettingsUpdateRequest) (*tly.DomainSettings, error)
+
+	mu    sync.Mutex
+	calls struct {
+		ListDomains          []DomainServiceListDomainsCall
+		AddDomain            []DomainServiceAddDomainCall
+		DeleteDomain         []DomainServiceDeleteDomainCall
+		CheckDomainStatus    []DomainServiceCheckDomainStatusCall
+		GetDomainSettings    []DomainServiceGetDomainSettingsCall
+		UpdateDomainSettings []DomainServiceUpdateDomainSettingsCall
+	}
+}
+
+var _ tly.DomainService = (*DomainService)(nil)
+
+// DomainServiceListDomainsCall records a call of DomainService.ListDomains.
+type DomainServiceListDomainsCall struct {
+	Ctx context.Context
+}
+
+// ListDomains calls ListDomainsFunc.
+func (m *DomainService) ListDomains(ctx context.Context) ([]tly.Domain, error) {
+	m.mu.Lock()
+	m.calls.ListDomains = append(m.calls.ListDomains, DomainServiceListDomainsCall{Ctx: ctx})
+	m.mu.Unlock()
+	if m.ListDomainsFunc == nil {
+		panic("mocks: DomainService.ListDomainsFunc is not set")
+	}
+	return m.ListDomainsFunc(ctx)
+}
+
+// ListDomainsCalls returns the calls of ListDomains so far.
+func (m *DomainService) ListDomainsCalls() []DomainServiceListDomainsCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]DomainServiceListDomainsCall(nil), m.calls.ListDomains...)
+}
+
+// DomainServiceAddDomainCall records a call of DomainService.AddDomain.
+type DomainServiceAddDomainCall struct {
+	Ctx     context.Context
+	ReqData tly.DomainCreateRequest
+}
+
+// AddDomain calls AddDomainFunc.
+func (m *DomainService) AddDomain(ctx context.Context, reqData tly.DomainCreateRequest) (*tly.Domain, error) {
+	m.mu.Lock()
+	m.calls.AddDomain = append(m.calls.AddDomain, DomainServiceAddDomainCall{Ctx: ctx, ReqData: reqData})
+	m.mu.Unlock()
+	if m.AddDomainFunc == nil {
+		panic("mocks: DomainService.AddDomainFunc is not set")
+	}
+	return m.AddDomainFunc(ctx, reqData)
+}
+
+// AddDomainCalls returns the calls of AddDomain so far.
+func (m *DomainService) AddDomainCalls() []DomainServiceAddDomainCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]DomainServiceAddDomainCall(nil), m.calls.AddDomain...)
+}
+
+// DomainServiceDeleteDomainCall records a call of DomainService.DeleteDomain.
+type DomainServiceDeleteDomainCall struct {
+	Ctx context.Context
+	ID  int
+}
+
+// DeleteDomain calls DeleteDomainFunc.
+func (m *DomainService) DeleteDomain(ctx context.Context, id int) error {
+	m.mu.Lock()
+	m.calls.DeleteDomain = append(m.calls.DeleteDomain, DomainServiceDeleteDomainCall{Ctx: ctx, ID: id})
+	m.mu.Unlock()
+	if m.DeleteDomainFunc == nil {
+		panic("mocks: DomainService.DeleteDomainFunc is not set")
+	}
+	return m.DeleteDomainFunc(ctx, id)
+}
+
+// DeleteDomainCalls returns the calls of DeleteDomain so far.
+func (m *DomainService) DeleteDomainCalls() []DomainServiceDeleteDomainCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]DomainServiceDeleteDomainCall(nil), m.calls.DeleteDomain...)
+}
+
+// DomainServiceCheckDomainStatusCall records a call of DomainService.CheckDomainStatus.
+type DomainServiceCheckDomainStatusCall struct {
+	Ctx      context.Context
+	Hostname string
+}
+
+// CheckDomainStatus calls CheckDomainStatusFunc.
+func (m *DomainService) CheckDomainStatus(ctx context.Context, hostname string) (*tly.DomainStatusReport, error) {
+	m.mu.Lock()
+	m.calls.CheckDomainStatus = append(m.calls.CheckDomainStatus, DomainServiceCheckDomainStatusCall{Ctx: ctx, Hostname: hostname})
+	m.mu.Unlock()
+	if m.CheckDomainStatusFunc == nil {
+		panic("mocks: DomainService.CheckDomainStatusFunc is not set")
+	}
+	return m.CheckDomainStatusFunc(ctx, hostname)
+}
+
+// CheckDomainStatusCalls returns the calls of CheckDomainStatus so far.
+func (m *DomainService) CheckDomainStatusCalls() []DomainServiceCheckDomainStatusCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]DomainServiceCheckDomainStatusCall(nil), m.calls.CheckDomainStatus...)
+}
+
+// DomainServiceGetDomainSettingsCall records a call of DomainService.GetDomainSettings.
+type DomainServiceGetDomainSettingsCall struct {
+	Ctx context.Context
+	ID  int
+}
+
+// GetDomainSettings calls GetDomainSettingsFunc.
+func (m *DomainService) GetDomainSettings(ctx context.Context, id int) (*tly.DomainSettings, error) {
+	m.mu.Lock()
+	m.calls.GetDomainSettings = append(m.calls.GetDomainSettings, DomainServiceGetDomainSettingsCall{Ctx: ctx, ID: id})
+	m.mu.Unlock()
+	if m.GetDomainSettingsFunc == nil {
+		panic("mocks: DomainService.GetDomainSettingsFunc is not set")
+	}
+	return m.GetDomainSettingsFunc(ctx, id)
+}
+
+// GetDomainSettingsCalls returns the calls of GetDomainSettings so far.
+func (m *DomainService) GetDomainSettingsCalls() []DomainServiceGetDomainSettingsCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]DomainServiceGetDomainSettingsCall(nil), m.calls.GetDomainSettings...)
+}
+
+// DomainServiceUpdateDomainSettingsCall records a call of DomainService.UpdateDomainSettings.
+type DomainServiceUpdateDomainSettingsCall struct {
+	Ctx     context.Context
+	ID      int
+	ReqData tly.DomainSettingsUpdateRequest
+}
+
+// UpdateDomainSettings calls UpdateDomainSettingsFunc.
+func (m *DomainService) UpdateDomainSettings(ctx context.Context, id int, reqData tly.DomainSettingsUpdateRequest) (*tly.DomainSettings, error) {
+	m.mu.Lock()
+	m.calls.UpdateDomainSettings = append(m.calls.UpdateDomainSettings, DomainServiceUpdateDomainSettingsCall{Ctx: ctx, ID: id, ReqData: reqData})
+	m.mu.Unlock()
+	if m.UpdateDomainSettingsFunc == nil {
+		panic("mocks: DomainService.UpdateDomainSettingsFunc is not set")
+	}
+	return m.UpdateDomainSettingsFunc(ctx, id, reqData)
+}
+
+// UpdateDomainSettingsCalls returns the calls of UpdateDomainSettings so far.
+func (m *DomainService) UpdateDomainSettingsCalls() []DomainServiceUpdateDomainSettingsCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]DomainServiceUpdateDomainSettingsCall(nil), m.calls.UpdateDomainSettings...)
+}
+
+// PixelService is a mock of tly.PixelService.
+// Set the Func field of a method to stub it; calling a method whose Func
+// is nil panics. Every call is recorded and returned by the method's
+// Calls function.
+type PixelService struct {
+	ListPixelsFunc     func() ([]tly.Pixel, error)
+	ListPixelsPageFunc func(ctx context.Context, opts tly.ListOptions) (*tly.PixelPage, error)
+	ListAllPixelsFunc  func(ctx context.Context, opts tly.ListOptions) ([]tly.Pixel, error)
+	CreatePixelFunc    func(reqData tly.PixelCreateRequest) (*tly.Pixel, error)
+	GetPixelFunc       func(id int) (*tly.Pixel, error)
+	UpdatePixelFunc    func(reqData tly.PixelUpdateRequest) (*tly.Pixel, error)
+	DeletePixelFunc    func(id int) error
+
+	mu    sync.Mutex
+	calls struct {
+		ListPixels     []PixelServiceListPixelsCall
+		ListPixelsPage []PixelServiceListPixelsPageCall
+		ListAllPixels  []PixelServiceListAllPixelsCall
+		CreatePixel    []PixelServiceCreatePixelCall
+		GetPixel       []PixelServiceGetPixelCall
+		UpdatePixel    []PixelServiceUpdatePixelCall
+		DeletePixel    []PixelServiceDeletePixelCall
+	}
+}
+
+var _ tly.PixelService = (*PixelService)(nil)
+
+// PixelServiceListPixelsCall records a call of PixelService.ListPixels.
+type PixelServiceListPixelsCall struct {
+}
+
+// ListPixels calls ListPixelsFunc.
+func (m *PixelService) ListPixels() ([]tly.Pixel, error) {
+	m.mu.Lock()
+	m.calls.ListPixels = append(m.calls.ListPixels, PixelServiceListPixelsCall{})
+	m.mu.Unlock()
+	if m.ListPixelsFunc == nil {
+		panic("mocks: PixelService.ListPixelsFunc is not set")
+	}
+	return m.ListPixelsFunc()
+}
+
+// ListPixelsCalls returns the calls of ListPixels so far.
+func (m *PixelService) ListPixelsCalls() []PixelServiceListPixelsCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]PixelServiceListPixelsCall(nil), m.calls.ListPixels...)
+}
+
+// PixelServiceListPixelsPageCall records a call of PixelService.ListPixelsPage.
+type PixelServiceListPixelsPageCall struct {
+	Ctx  context.Context
+	Opts tly.ListOptions
+}
+
+// ListPixelsPage calls ListPixelsPageFunc.
+func (m *PixelService) ListPixelsPage(ctx context.Context, opts tly.ListOptions) (*tly.PixelPage, error) {
+	m.mu.Lock()
+	m.calls.ListPixelsPage = append(m.calls.ListPixelsPage, PixelServiceListPixelsPageCall{Ctx: ctx, Opts: opts})
+	m.mu.Unlock()
+	if m.ListPixelsPageFunc == nil {
+		panic("mocks: PixelService.ListPixelsPageFunc is not set")
+	}
+	return m.ListPixelsPageFunc(ctx, opts)
+}
+
+// ListPixelsPageCalls returns the calls of ListPixelsPage so far.
+func (m *PixelService) ListPixelsPageCalls() []PixelServiceListPixelsPageCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]PixelServiceListPixelsPageCall(nil), m.calls.ListPixelsPage...)
+}
+
+// PixelServiceListAllPixelsCall records a call of PixelService.ListAllPixels.
+type PixelServiceListAllPixelsCall struct {
+	Ctx  context.Context
+	Opts tly.ListOptions
+}
+
+// ListAllPixels calls ListAllPixelsFunc.
+func (m *PixelService) ListAllPixels(ctx context.Context, opts tly.ListOptions) ([]tly.Pixel, error) {
+	m.mu.Lock()
+	m.calls.ListAllPixels = append(m.calls.ListAllPixels, PixelServiceListAllPixelsCall{Ctx: ctx, Opts: opts})
+	m.mu.Unlock()
+	if m.ListAllPixelsFunc == nil {
+		panic("mocks: PixelService.ListAllPixelsFunc is not set")
+	}
+	return m.ListAllPixelsFunc(ctx, opts)
+}
+
+// ListAllPixelsCalls returns the calls of ListAllPixels so far.
+func (m *PixelService) ListAllPixelsCalls() []PixelServiceListAllPixelsCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]PixelServiceListAllPixelsCall(nil), m.calls.ListAllPixels...)
+}
+
+// PixelServiceCreatePixelCall records a call of PixelService.CreatePixel.
+type PixelServiceCreatePixelCall struct {
+	ReqData tly.PixelCreateRequest
+}
+
+// CreatePixel calls CreatePixelFunc.
+func (m *PixelService) CreatePixel(reqData tly.PixelCreateRequest) (*tly.Pixel, error) {
+	m.mu.Lock()
+	m.calls.CreatePixel = append(m.calls.CreatePixel, PixelServiceCreatePixelCall{ReqData: reqData})
+	m.mu.Unlock()
+	if m.CreatePixelFunc == nil {
+		panic("mocks: PixelService.CreatePixelFunc is not set")
+	}
+	return m.CreatePixelFunc(reqData)
+}
+
+// CreatePixelCalls returns the calls of CreatePixel so far.
+func (m *PixelService) CreatePixelCalls() []PixelServiceCreatePixelCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]PixelServiceCreatePixelCall(nil), m.calls.CreatePixel...)
+}
+
+// PixelServiceGetPixelCall records a call of PixelService.GetPixel.
+type PixelServiceGetPixelCall struct {
+	ID int
+}
+
+// GetPixel calls GetPixelFunc.
+func (m *PixelService) GetPixel(id int) (*tly.Pixel, error) {
+	m.mu.Lock()
+	m.calls.GetPixel = append(m.calls.GetPixel, PixelServiceGetPixelCall{ID: id})
+	m.mu.Unlock()
+	if m.GetPixelFunc == nil {
+		panic("mocks: PixelService.GetPixelFunc is not set")
+	}
+	return m.GetPixelFunc(id)
+}
+
+// GetPixelCalls returns the calls of GetPixel so far.
+func (m *PixelService) GetPixelCalls() []PixelServiceGetPixelCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]PixelServiceGetPixelCall(nil), m.calls.GetPixel...)
+}
+
+// PixelServiceUpdatePixelCall records a call of PixelService.UpdatePixel.
+type PixelServiceUpdatePixelCall struct {
+	ReqData tly.PixelUpdateRequest
+}
+
+// UpdatePixel calls UpdatePixelFunc.
+func (m *PixelService) UpdatePixel(reqData tly.PixelUpdateRequest) (*tly.Pixel, error) {
+	m.mu.Lock()
+	m.calls.UpdatePixel = append(m.calls.UpdatePixel, PixelServiceUpdatePixelCall{ReqData: reqData})
+	m.mu.Unlock()
+	if m.UpdatePixelFunc == nil {
+		panic("mocks: PixelService.UpdatePixelFunc is not set")
+	}
+	return m.UpdatePixelFunc(reqData)
+}
+
+// UpdatePixelCalls returns the calls of UpdatePixel so far.
+func (m *PixelService) UpdatePixelCalls() []PixelServiceUpdatePixelCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]PixelServiceUpdatePixelCall(nil), m.calls.UpdatePixel...)
+}
+
+// PixelServiceDeletePixelCall records a call of PixelService.DeletePixel.
+type PixelServiceDeletePixelCall struct {
+	ID int
+}
+
+// DeletePixel calls DeletePixelFunc.
+func (m *PixelService) DeletePixel(id int) error {
+	m.mu.Lock()
+	m.calls.DeletePixel = append(m.calls.DeletePixel, PixelServiceDeletePixelCall{ID: id})
+	m.mu.Unlock()
+	if m.DeletePixelFunc == nil {
+		panic("mocks: PixelService.DeletePixelFunc is not set")
+	}
+	return m.DeletePixelFunc(id)
+}
+
+// DeletePixelCalls returns the calls of DeletePixel so far.
+func (m *PixelService) DeletePixelCalls() []PixelServiceDeletePixelCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]PixelServiceDeletePixelCall(nil), m.calls.DeletePixel...)
+}
+
+// RulesService is a mock of tly.RulesService.
+// Set the Func field of a method to stub it; calling a method whose Func
+// is nil panics. Every call is recorded and returned by the method's
+// Calls function.
+type RulesService struct {
+	ListRulesFunc  func(ctx context.Context, shortURL string) ([]tly.Rule, error)
+	CreateRuleFunc func(ctx context.Context, reqData tly.RuleCreateRequest) (*tly.Rule, error)
+	UpdateRuleFunc func(ctx context.Context, reqData tly.RuleUpdateRequest) (*tly.Rule, error)
+	DeleteRuleFunc func(ctx context.Context, id int) error
+
+	mu    sync.Mutex
+	calls struct {
+		ListRules  []RulesServiceListRulesCall
+		CreateRule []RulesServiceCreateRuleCall
+		UpdateRule []RulesServiceUpdateRuleCall
+		DeleteRule []RulesServiceDeleteRuleCall
+	}
+}
+
+var _ tly.RulesService = (*RulesService)(nil)
+
+// RulesServiceListRulesCall records a call of RulesService.ListRules.
+type RulesServiceListRulesCall struct {
+	Ctx      context.Context
+	ShortURL string
+}
+
+// ListRules calls ListRulesFunc.
+func (m *RulesService) ListRules(ctx context.Context, shortURL string) ([]tly.Rule, error) {
+	m.mu.Lock()
+	m.calls.ListRules = append(m.calls.ListRules, RulesServiceListRulesCall{Ctx: ctx, ShortURL: shortURL})
+	m.mu.Unlock()
+	if m.ListRulesFunc == nil {
+		panic("mocks: RulesService.ListRulesFunc is not set")
+	}
+	return m.ListRulesFunc(ctx, shortURL)
+}
+
+// ListRulesCalls returns the calls of ListRules so far.
+func (m *RulesService) ListRulesCalls() []RulesServiceListRulesCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]RulesServiceListRulesCall(nil), m.calls.ListRules...)
+}
+
+// RulesServiceCreateRuleCall records a call of RulesService.CreateRule.
+type RulesServiceCreateRuleCall struct {
+	Ctx     context.Context
+	ReqData tly.RuleCreateRequest
+}
+
+// CreateRule calls CreateRuleFunc.
+func (m *RulesService) CreateRule(ctx context.Context, reqData tly.RuleCreateRequest) (*tly.Rule, error) {
+	m.mu.Lock()
+	m.calls.CreateRule = append(m.calls.CreateRule, RulesServiceCreateRuleCall{Ctx: ctx, ReqData: reqData})
+	m.mu.Unlock()
+	if m.CreateRuleFunc == nil {
+		panic("mocks: RulesService.CreateRuleFunc is not set")
+	}
+	return m.CreateRuleFunc(ctx, reqData)
+}
+
+// CreateRuleCalls returns the calls of CreateRule so far.
+func (m *RulesService) CreateRuleCalls() []RulesServiceCreateRuleCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]RulesServiceCreateRuleCall(nil), m.calls.CreateRule...)
+}
+
+// RulesServiceUpdateRuleCall records a call of RulesService.UpdateRule.
+type RulesServiceUpdateRuleCall struct {
+	Ctx     context.Context
+	ReqData tly.RuleUpdateRequest
+}
+
+// UpdateRule calls UpdateRuleFunc.
+func (m *RulesService) UpdateRule(ctx context.Context, reqData tly.RuleUpdateRequest) (*tly.Rule, error) {
+	m.mu.Lock()
+	m.calls.UpdateRule = append(m.calls.UpdateRule, RulesServiceUpdateRuleCall{Ctx: ctx, ReqData: reqData})
+	m.mu.Unlock()
+	if m.UpdateRuleFunc == nil {
+		panic("mocks: RulesService.UpdateRuleFunc is not set")
+	}
+	return m.UpdateRuleFunc(ctx, reqData)
+}
+
+// UpdateRuleCalls returns the calls of UpdateRule so far.
+func (m *RulesService) UpdateRuleCalls() []RulesServiceUpdateRuleCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]RulesServiceUpdateRuleCall(nil), m.calls.UpdateRule...)
+}
+
+// RulesServiceDeleteRuleCall records a call of RulesService.DeleteRule.
+type RulesServiceDeleteRuleCall struct {
+	Ctx context.Context
+	ID  int
+}
+
+// DeleteRule calls DeleteRuleFunc.
+func (m *RulesService) DeleteRule(ctx context.Context, id int) error {
+	m.mu.Lock()
+	m.calls.DeleteRule = append(m.calls.DeleteRule, RulesServiceDeleteRuleCall{Ctx: ctx, ID: id})
+	m.mu.Unlock()
+	if m.DeleteRuleFunc == nil {
+		panic("mocks: RulesService.DeleteRuleFunc is not set")
+	}
+	return m.DeleteRuleFunc(ctx, id)
+}
+
+// DeleteRuleCalls returns the calls of DeleteRule so far.
+func (m *RulesService) DeleteRuleCalls() []RulesServiceDeleteRuleCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]RulesServiceDeleteRuleCall(nil), m.calls.DeleteRule...)
+}
+
+// ShortLinkService is a mock of tly.ShortLinkService.
+// Set the Func field of a method to stub it; calling a method whose Func
+// is nil panics. Every call is recorded and returned by the method's
+// Calls function.
+type ShortLinkService struct {
+	CreateShortLinkFunc    func(reqData tly.ShortLinkCreateRequest) (*tly.ShortLink, error)
+	GetShortLinkFunc       func(shortURL string) (*tly.ShortLink, error)
+	UpdateShortLinkFunc    func(reqData tly.ShortLinkUpdateRequest) (*tly.ShortLink, error)
+	DeleteShortLinkFunc    func(shortURL string) error
+	ExpandShortLinkFunc    func(reqData tly.ExpandRequest) (*tly.ExpandResponse, error)
+	ListShortLinksPageFunc func(ctx context.Context, opts tly.ShortLinkListOptions) (*tly.ShortLinkPage, error)
+	ListAllShortLinksFunc  func(ctx context.Context, opts tly.ShortLinkListOptions) ([]tly.ShortLink, error)
+	CreateShortLinksFunc   func(ctx context.Context, reqs []tly.ShortLinkCreateRequest, opts tly.CreateShortLinksOptions) ([]tly.ShortLinkCreateResult, error)
+
+	mu    sync.Mutex
+	calls struct {
+		CreateShortLink    []ShortLinkServiceCreateShortLinkCall
+		GetShortLink       []ShortLinkServiceGetShortLinkCall
+		UpdateShortLink    []ShortLinkServiceUpdateShortLinkCall
+		DeleteShortLink    []ShortLinkServiceDeleteShortLinkCall
+		ExpandShortLink    []ShortLinkServiceExpandShortLinkCall
+		ListShortLinksPage []ShortLinkServiceListShortLinksPageCall
+		ListAllShortLinks  []ShortLinkServiceListAllShortLinksCall
+		CreateShortLinks   []ShortLinkServiceCreateShortLinksCall
+	}
+}
+
+var _ tly.ShortLinkService = (*ShortLinkService)(nil)
+
+// ShortLinkServiceCreateShortLinkCall records a call of ShortLinkService.CreateShortLink.
+type ShortLinkServiceCreateShortLinkCall struct {
+	ReqData tly.ShortLinkCreateRequest
+}
+
+// CreateShortLink calls CreateShortLinkFunc.
+func (m *ShortLinkService) CreateShortLink(reqData tly.ShortLinkCreateRequest) (*tly.ShortLink, error) {
+	m.mu.Lock()
+	m.calls.CreateShortLink = append(m.calls.CreateShortLink, ShortLinkServiceCreateShortLinkCall{ReqData: reqData})
+	m.mu.Unlock()
+	if m.CreateShortLinkFunc == nil {
+		panic("mocks: ShortLinkService.CreateShortLinkFunc is not set")
+	}
+	return m.CreateShortLinkFunc(reqData)
+}
+
+// CreateShortLinkCalls returns the calls of CreateShortLink so far.
+func (m *ShortLinkService) CreateShortLinkCalls() []ShortLinkServiceCreateShortLinkCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ShortLinkServiceCreateShortLinkCall(nil), m.calls.CreateShortLink...)
+}
+
+// ShortLinkServiceGetShortLinkCall records a call of ShortLinkService.GetShortLink.
+type ShortLinkServiceGetShortLinkCall struct {
+	ShortURL string
+}
+
+// GetShortLink calls GetShortLinkFunc.
+func (m *ShortLinkService) GetShortLink(shortURL string) (*tly.ShortLink, error) {
+	m.mu.Lock()
+	m.calls.GetShortLink = append(m.calls.GetShortLink, ShortLinkServiceGetShortLinkCall{ShortURL: shortURL})
+	m.mu.Unlock()
+	if m.GetShortLinkFunc == nil {
+		panic("mocks: ShortLinkService.GetShortLinkFunc is not set")
+	}
+	return m.GetShortLinkFunc(shortURL)
+}
+
+// GetShortLinkCalls returns the calls of GetShortLink so far.
+func (m *ShortLinkService) GetShortLinkCalls() []ShortLinkServiceGetShortLinkCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ShortLinkServiceGetShortLinkCall(nil), m.calls.GetShortLink...)
+}
+
+// ShortLinkServiceUpdateShortLinkCall records a call of ShortLinkService.UpdateShortLink.
+type ShortLinkServiceUpdateShortLinkCall struct {
+	ReqData tly.ShortLinkUpdateRequest
+}
+
+// UpdateShortLink calls UpdateShortLinkFunc.
+func (m *ShortLinkService) UpdateShortLink(reqData tly.ShortLinkUpdateRequest) (*tly.ShortLink, error) {
+	m.mu.Lock()
+	m.calls.UpdateShortLink = append(m.calls.UpdateShortLink, ShortLinkServiceUpdateShortLinkCall{ReqData: reqData})
+	m.mu.Unlock()
+	if m.UpdateShortLinkFunc == nil {
+		panic("mocks: ShortLinkService.UpdateShortLinkFunc is not set")
+	}
+	return m.UpdateShortLinkFunc(reqData)
+}
+
+// UpdateShortLinkCalls returns the calls of UpdateShortLink so far.
+func (m *ShortLinkService) UpdateShortLinkCalls() []ShortLinkServiceUpdateShortLinkCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ShortLinkServiceUpdateShortLinkCall(nil), m.calls.UpdateShortLink...)
+}
+
+// ShortLinkServiceDeleteShortLinkCall records a call of ShortLinkService.DeleteShortLink.
+type ShortLinkServiceDeleteShortLinkCall struct {
+	ShortURL string
+}
+
+// DeleteShortLink calls DeleteShortLinkFunc.
+func (m *ShortLinkService) DeleteShortLink(shortURL string) error {
+	m.mu.Lock()
+	m.calls.DeleteShortLink = append(m.calls.DeleteShortLink, ShortLinkServiceDeleteShortLinkCall{ShortURL: shortURL})
+	m.mu.Unlock()
+	if m.DeleteShortLinkFunc == nil {
+		panic("mocks: ShortLinkService.DeleteShortLinkFunc is not set")
+	}
+	return m.DeleteShortLinkFunc(shortURL)
+}
+
+// DeleteShortLinkCalls returns the calls of DeleteShortLink so far.
+func (m *ShortLinkService) DeleteShortLinkCalls() []ShortLinkServiceDeleteShortLinkCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ShortLinkServiceDeleteShortLinkCall(nil), m.calls.DeleteShortLink...)
+}
+
+// ShortLinkServiceExpandShortLinkCall records a call of ShortLinkService.ExpandShortLink.
+type ShortLinkServiceExpandShortLinkCall struct {
+	ReqData tly.ExpandRequest
+}
+
+// ExpandShortLink calls ExpandShortLinkFunc.
+func (m *ShortLinkService) ExpandShortLink(reqData tly.ExpandRequest) (*tly.ExpandResponse, error) {
+	m.mu.Lock()
+	m.calls.ExpandShortLink = append(m.calls.ExpandShortLink, ShortLinkServiceExpandShortLinkCall{ReqData: reqData})
+	m.mu.Unlock()
+	if m.ExpandShortLinkFunc == nil {
+		panic("mocks: ShortLinkService.ExpandShortLinkFunc is not set")
+	}
+	return m.ExpandShortLinkFunc(reqData)
+}
+
+// ExpandShortLinkCalls returns the calls of ExpandShortLink so far.
+func (m *ShortLinkService) ExpandShortLinkCalls() []ShortLinkServiceExpandShortLinkCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ShortLinkServiceExpandShortLinkCall(nil), m.calls.ExpandShortLink...)
+}
+
+// ShortLinkServiceListShortLinksPageCall records a call of ShortLinkService.ListShortLinksPage.
+type ShortLinkServiceListShortLinksPageCall struct {
+	Ctx  context.Context
+	Opts tly.ShortLinkListOptions
+}
+
+// ListShortLinksPage calls ListShortLinksPageFunc.
+func (m *ShortLinkService) ListShortLinksPage(ctx context.Context, opts tly.ShortLinkListOptions) (*tly.ShortLinkPage, error) {
+	m.mu.Lock()
+	m.calls.ListShortLinksPage = append(m.calls.ListShortLinksPage, ShortLinkServiceListShortLinksPageCall{Ctx: ctx, Opts: opts})
+	m.mu.Unlock()
+	if m.ListShortLinksPageFunc == nil {
+		panic("mocks: ShortLinkService.ListShortLinksPageFunc is not set")
+	}
+	return m.ListShortLinksPageFunc(ctx, opts)
+}
+
+// ListShortLinksPageCalls returns the calls of ListShortLinksPage so far.
+func (m *ShortLinkService) ListShortLinksPageCalls() []ShortLinkServiceListShortLinksPageCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ShortLinkServiceListShortLinksPageCall(nil), m.calls.ListShortLinksPage...)
+}
+
+// ShortLinkServiceListAllShortLinksCall records a call of ShortLinkService.ListAllShortLinks.
+type ShortLinkServiceListAllShortLinksCall struct {
+	Ctx  context.Context
+	Opts tly.ShortLinkListOptions
+}
+
+// ListAllShortLinks calls ListAllShortLinksFunc.
+func (m *ShortLinkService) ListAllShortLinks(ctx context.Context, opts tly.ShortLinkListOptions) ([]tly.ShortLink, error) {
+	m.mu.Lock()
+	m.calls.ListAllShortLinks = append(m.calls.ListAllShortLinks, ShortLinkServiceListAllShortLinksCall{Ctx: ctx, Opts: opts})
+	m.mu.Unlock()
+	if m.ListAllShortLinksFunc == nil {
+		panic("mocks: ShortLinkService.ListAllShortLinksFunc is not set")
+	}
+	return m.ListAllShortLinksFunc(ctx, opts)
+}
+
+// ListAllShortLinksCalls returns the calls of ListAllShortLinks so far.
+func (m *ShortLinkService) ListAllShortLinksCalls() []ShortLinkServiceListAllShortLinksCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ShortLinkServiceListAllShortLinksCall(nil), m.calls.ListAllShortLinks...)
+}
+
+// ShortLinkServiceCreateShortLinksCall records a call of ShortLinkService.CreateShortLinks.
+type ShortLinkServiceCreateShortLinksCall struct {
+	Ctx  context.Context
+	Reqs []tly.ShortLinkCreateRequest
+	Opts tly.CreateShortLinksOptions
+}
+
+// CreateShortLinks calls CreateShortLinksFunc.
+func (m *ShortLinkService) CreateShortLinks(ctx context.Context, reqs []tly.ShortLinkCreateRequest, opts tly.CreateShortLinksOptions) ([]tly.ShortLinkCreateResult, error) {
+	m.mu.Lock()
+	m.calls.CreateShortLinks = append(m.calls.CreateShortLinks, ShortLinkServiceCreateShortLinksCall{Ctx: ctx, Reqs: reqs, Opts: opts})
+	m.mu.Unlock()
+	if m.CreateShortLinksFunc == nil {
+		panic("mocks: ShortLinkService.CreateShortLinksFunc is not set")
+	}
+	return m.CreateShortLinksFunc(ctx, reqs, opts)
+}
+
+// CreateShortLinksCalls returns the calls of CreateShortLinks so far.
+func (m *ShortLinkService) CreateShortLinksCalls() []ShortLinkServiceCreateShortLinksCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ShortLinkServiceCreateShortLinksCall(nil), m.calls.CreateShortLinks...)
+}
+
+// StatsService is a mock of tly.StatsService.
+// Set the Func field of a method to stub it; calling a method whose Func
+// is nil panics. Every call is recorded and returned by the method's
+// Calls function.
+type StatsService struct {
+	GetStatsFunc            func(shortURL string) (*tly.Stats, error)
+	GetStatsWithOptionsFunc func(ctx context.Context, shortURL string, opts tly.StatsOptions) (*tly.Stats, error)
+	GetStatsMultiFunc       func(ctx context.Context, shortURLs []string, opts tly.StatsOptions) (map[string]*tly.Stats, error)
+	GetStatsByTagFunc       func(ctx context.Context, tagID int, opts tly.StatsOptions) (*tly.TagStats, error)
+
+	mu    sync.Mutex
+	calls struct {
+		GetStats            []StatsServiceGetStatsCall
+		GetStatsWithOptions []StatsServiceGetStatsWithOptionsCall
+		GetStatsMulti       []StatsServiceGetStatsMultiCall
+		GetStatsByTag       []StatsServiceGetStatsByTagCall
+	}
+}
+
+var _ tly.StatsService = (*StatsService)(nil)
+
+// StatsServiceGetStatsCall records a call of StatsService.GetStats.
+type StatsServiceGetStatsCall struct {
+	ShortURL string
+}
+
+// GetStats calls GetStatsFunc.
+func (m *StatsService) GetStats(shortURL string) (*tly.Stats, error) {
+	m.mu.Lock()
+	m.calls.GetStats = append(m.calls.GetStats, StatsServiceGetStatsCall{ShortURL: shortURL})
+	m.mu.Unlock()
+	if m.GetStatsFunc == nil {
+		panic("mocks: StatsService.GetStatsFunc is not set")
+	}
+	return m.GetStatsFunc(shortURL)
+}
+
+// GetStatsCalls returns the calls of GetStats so far.
+func (m *StatsService) GetStatsCalls() []StatsServiceGetStatsCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]StatsServiceGetStatsCall(nil), m.calls.GetStats...)
+}
+
+// StatsServiceGetStatsWithOptionsCall records a call of StatsService.GetStatsWithOptions.
+type StatsServiceGetStatsWithOptionsCall struct {
+	Ctx      context.Context
+	ShortURL string
+	Opts     tly.StatsOptions
+}
+
+// GetStatsWithOptions calls GetStatsWithOptionsFunc.
+func (m *StatsService) GetStatsWithOptions(ctx context.Context, shortURL string, opts tly.StatsOptions) (*tly.Stats, error) {
+	m.mu.Lock()
+	m.calls.GetStatsWithOptions = append(m.calls.GetStatsWithOptions, StatsServiceGetStatsWithOptionsCall{Ctx: ctx, ShortURL: shortURL, Opts: opts})
+	m.mu.Unlock()
+	if m.GetStatsWithOptionsFunc == nil {
+		panic("mocks: StatsService.GetStatsWithOptionsFunc is not set")
+	}
+	return m.GetStatsWithOptionsFunc(ctx, shortURL, opts)
+}
+
+// GetStatsWithOptionsCalls returns the calls of GetStatsWithOptions so far.
+func (m *StatsService) GetStatsWithOptionsCalls() []StatsServiceGetStatsWithOptionsCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]StatsServiceGetStatsWithOptionsCall(nil), m.calls.GetStatsWithOptions...)
+}
+
+// StatsServiceGetStatsMultiCall records a call of StatsService.GetStatsMulti.
+type StatsServiceGetStatsMultiCall struct {
+	Ctx       context.Context
+	ShortURLs []string
+	Opts      tly.StatsOptions
+}
+
+// GetStatsMulti calls GetStatsMultiFunc.
+func (m *StatsService) GetStatsMulti(ctx context.Context, shortURLs []string, opts tly.StatsOptions) (map[string]*tly.Stats, error) {
+	m.mu.Lock()
+	m.calls.GetStatsMulti = append(m.calls.GetStatsMulti, StatsServiceGetStatsMultiCall{Ctx: ctx, ShortURLs: shortURLs, Opts: opts})
+	m.mu.Unlock()
+	if m.GetStatsMultiFunc == nil {
+		panic("mocks: StatsService.GetStatsMultiFunc is not set")
+	}
+	return m.GetStatsMultiFunc(ctx, shortURLs, opts)
+}
+
+// GetStatsMultiCalls returns the calls of GetStatsMulti so far.
+func (m *StatsService) GetStatsMultiCalls() []StatsServiceGetStatsMultiCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]StatsServiceGetStatsMultiCall(nil), m.calls.GetStatsMulti...)
+}
+
+// StatsServiceGetStatsByTagCall records a call of StatsService.GetStatsByTag.
+type StatsServiceGetStatsByTagCall struct {
+	Ctx   context.Context
+	TagID int
+	Opts  tly.StatsOptions
+}
+
+// GetStatsByTag calls GetStatsByTagFunc.
+func (m *StatsService) GetStatsByTag(ctx context.Context, tagID int, opts tly.StatsOptions) (*tly.TagStats, error) {
+	m.mu.Lock()
+	m.calls.GetStatsByTag = append(m.calls.GetStatsByTag, StatsServiceGetStatsByTagCall{Ctx: ctx, TagID: tagID, Opts: opts})
+	m.mu.Unlock()
+	if m.GetStatsByTagFunc == nil {
+		panic("mocks: StatsService.GetStatsByTagFunc is not set")
+	}
+	return m.GetStatsByTagFunc(ctx, tagID, opts)
+}
+
+// GetStatsByTagCalls returns the calls of GetStatsByTag so far.
+func (m *StatsService) GetStatsByTagCalls() []StatsServiceGetStatsByTagCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]StatsServiceGetStatsByTagCall(nil), m.calls.GetStatsByTag...)
+}
+
+// TagService is a mock of tly.TagService.
+// Set the Func field of a method to stub it; calling a method whose Func
+// is nil panics. Every call is recorded and returned by the method's
+// Calls function.
+type TagService struct {
+	ListTagsFunc     func() ([]tly.Tag, error)
+	ListTagsPageFunc func(ctx context.Context, opts tly.ListOptions) (*tly.TagPage, error)
+	ListAllTagsFunc  func(ctx context.Context, opts tly.ListOptions) ([]tly.Tag, error)
+	CreateTagFunc    func(tagValue string) (*tly.Tag, error)
+	GetTagFunc       func(id int) (*tly.Tag, error)
+	UpdateTagFunc    func(id int, tagValue string) (*tly.Tag, error)
+	DeleteTagFunc    func(id int) error
+
+	mu    sync.Mutex
+	calls struct {
+		ListTags     []TagServiceListTagsCall
+		ListTagsPage []TagServiceListTagsPageCall
+		ListAllTags  []TagServiceListAllTagsCall
+		CreateTag    []TagServiceCreateTagCall
+		GetTag       []TagServiceGetTagCall
+		UpdateTag    []TagServiceUpdateTagCall
+		DeleteTag    []TagServiceDeleteTagCall
+	}
+}
+
+var _ tly.TagService = (*TagService)(nil)
+
+// TagServiceListTagsCall records a call of TagService.ListTags.
+type TagServiceListTagsCall struct {
+}
+
+// ListTags calls ListTagsFunc.
+func (m *TagService) ListTags() ([]tly.Tag, error) {
+	m.mu.Lock()
+	m.calls.ListTags = append(m.calls.ListTags, TagServiceListTagsCall{})
+	m.mu.Unlock()
+	if m.ListTagsFunc == nil {
+		panic("mocks: TagService.ListTagsFunc is not set")
+	}
+	return m.ListTagsFunc()
+}
+
+// ListTagsCalls returns the calls of ListTags so far.
+func (m *TagService) ListTagsCalls() []TagServiceListTagsCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TagServiceListTagsCall(nil), m.calls.ListTags...)
+}
+
+// TagServiceListTagsPageCall records a call of TagService.ListTagsPage.
+type TagServiceListTagsPageCall struct {
+	Ctx  context.Context
+	Opts tly.ListOptions
+}
+
+// ListTagsPage calls ListTagsPageFunc.
+func (m *TagService) ListTagsPage(ctx context.Context, opts tly.ListOptions) (*tly.TagPage, error) {
+	m.mu.Lock()
+	m.calls.ListTagsPage = append(m.calls.ListTagsPage, TagServiceListTagsPageCall{Ctx: ctx, Opts: opts})
+	m.mu.Unlock()
+	if m.ListTagsPageFunc == nil {
+		panic("mocks: TagService.ListTagsPageFunc is not set")
+	}
+	return m.ListTagsPageFunc(ctx, opts)
+}
+
+// ListTagsPageCalls returns the calls of ListTagsPage so far.
+func (m *TagService) ListTagsPageCalls() []TagServiceListTagsPageCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TagServiceListTagsPageCall(nil), m.calls.ListTagsPage...)
+}
+
+// TagServiceListAllTagsCall records a call of TagService.ListAllTags.
+type TagServiceListAllTagsCall struct {
+	Ctx  context.Context
+	Opts tly.ListOptions
+}
+
+// ListAllTags calls ListAllTagsFunc.
+func (m *TagService) ListAllTags(ctx context.Context, opts tly.ListOptions) ([]tly.Tag, error) {
+	m.mu.Lock()
+	m.calls.ListAllTags = append(m.calls.ListAllTags, TagServiceListAllTagsCall{Ctx: ctx, Opts: opts})
+	m.mu.Unlock()
+	if m.ListAllTagsFunc == nil {
+		panic("mocks: TagService.ListAllTagsFunc is not set")
+	}
+	return m.ListAllTagsFunc(ctx, opts)
+}
+
+// ListAllTagsCalls returns the calls of ListAllTags so far.
+func (m *TagService) ListAllTagsCalls() []TagServiceListAllTagsCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TagServiceListAllTagsCall(nil), m.calls.ListAllTags...)
+}
+
+// TagServiceCreateTagCall records a call of TagService.CreateTag.
+type TagServiceCreateTagCall struct {
+	TagValue string
+}
+
+// CreateTag calls CreateTagFunc.
+func (m *TagService) CreateTag(tagValue string) (*tly.Tag, error) {
+	m.mu.Lock()
+	m.calls.CreateTag = append(m.calls.CreateTag, TagServiceCreateTagCall{TagValue: tagValue})
+	m.mu.Unlock()
+	if m.CreateTagFunc == nil {
+		panic("mocks: TagService.CreateTagFunc is not set")
+	}
+	return m.CreateTagFunc(tagValue)
+}
+
+// CreateTagCalls returns the calls of CreateTag so far.
+func (m *TagService) CreateTagCalls() []TagServiceCreateTagCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TagServiceCreateTagCall(nil), m.calls.CreateTag...)
+}
+
+// TagServiceGetTagCall records a call of TagService.GetTag.
+type TagServiceGetTagCall struct {
+	ID int
+}
+
+// GetTag calls GetTagFunc.
+func (m *TagService) GetTag(id int) (*tly.Tag, error) {
+	m.mu.Lock()
+	m.calls.GetTag = append(m.calls.GetTag, TagServiceGetTagCall{ID: id})
+	m.mu.Unlock()
+	if m.GetTagFunc == nil {
+		panic("mocks: TagService.GetTagFunc is not set")
+	}
+	return m.GetTagFunc(id)
+}
+
+// GetTagCalls returns the calls of GetTag so far.
+func (m *TagService) GetTagCalls() []TagServiceGetTagCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TagServiceGetTagCall(nil), m.calls.GetTag...)
+}
+
+// TagServiceUpdateTagCall records a call of TagService.UpdateTag.
+type TagServiceUpdateTagCall struct {
+	ID       int
+	TagValue string
+}
+
+// UpdateTag calls UpdateTagFunc.
+func (m *TagService) UpdateTag(id int, tagValue string) (*tly.Tag, error) {
+	m.mu.Lock()
+	m.calls.UpdateTag = append(m.calls.UpdateTag, TagServiceUpdateTagCall{ID: id, TagValue: tagValue})
+	m.mu.Unlock()
+	if m.UpdateTagFunc == nil {
+		panic("mocks: TagService.UpdateTagFunc is not set")
+	}
+	return m.UpdateTagFunc(id, tagValue)
+}
+
+// UpdateTagCalls returns the calls of UpdateTag so far.
+func (m *TagService) UpdateTagCalls() []TagServiceUpdateTagCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TagServiceUpdateTagCall(nil), m.calls.UpdateTag...)
+}
+
+// TagServiceDeleteTagCall records a call of TagService.DeleteTag.
+type TagServiceDeleteTagCall struct {
+	ID int
+}
+
+// DeleteTag calls DeleteTagFunc.
+func (m *TagService) DeleteTag(id int) error {
+	m.mu.Lock()
+	m.calls.DeleteTag = append(m.calls.DeleteTag, TagServiceDeleteTagCall{ID: id})
+	m.mu.Unlock()
+	if m.DeleteTagFunc == nil {
+		panic("mocks: TagService.DeleteTagFunc is not set")
+	}
+	return m.DeleteTagFunc(id)
+}
+
+// DeleteTagCalls returns the calls of DeleteTag so far.
+func (m *TagService) DeleteTagCalls() []TagServiceDeleteTagCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TagServiceDeleteTagCall(nil), m.calls.DeleteTag...)
+}
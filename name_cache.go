@@ -0,0 +1,128 @@
+package tly
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// nameEntry is an item held by a nameCache, with the ID and lookup keys
+// it is indexed under.
+type nameEntry struct {
+	id    int
+	keys  []string
+	value interface{}
+}
+
+// nameCache is the cache behind TagResolver and PixelResolver. It holds a
+// list of items indexed by ID and by string keys, such as names, and
+// expires after a TTL. A lookup that misses refreshes it once before
+// failing, so items created elsewhere are picked up without waiting for
+// the TTL. When two items share a key, the first one stored wins.
+type nameCache struct {
+	ttl  time.Duration
+	load func(ctx context.Context) ([]nameEntry, error)
+
+	mu     sync.Mutex
+	byKey  map[string]nameEntry
+	byID   map[int]nameEntry
+	loaded time.Time
+}
+
+// refresh reloads the list.
+func (c *nameCache) refresh(ctx context.Context) error {
+	entries, err := c.load(ctx)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byKey = make(map[string]nameEntry, len(entries))
+	c.byID = make(map[int]nameEntry, len(entries))
+	for _, e := range entries {
+		c.store(e)
+	}
+	c.loaded = time.Now()
+	return nil
+}
+
+// add records an entry, replacing any entry with the same ID. It does
+// nothing until the cache has been loaded.
+func (c *nameCache) add(e nameEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.byID == nil {
+		return
+	}
+	c.remove(e.id)
+	c.store(e)
+}
+
+// evict drops the entry with the given ID and its keys.
+func (c *nameCache) evict(id int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(id)
+}
+
+// store indexes e. The caller holds c.mu.
+func (c *nameCache) store(e nameEntry) {
+	for _, key := range e.keys {
+		if _, dup := c.byKey[key]; !dup {
+			c.byKey[key] = e
+		}
+	}
+	c.byID[e.id] = e
+}
+
+// remove unindexes the entry with the given ID. The caller holds c.mu.
+func (c *nameCache) remove(id int) {
+	e, ok := c.byID[id]
+	if !ok {
+		return
+	}
+	for _, key := range e.keys {
+		if c.byKey[key].id == id {
+			delete(c.byKey, key)
+		}
+	}
+	delete(c.byID, id)
+}
+
+// key returns the item stored under key.
+func (c *nameCache) key(ctx context.Context, key string) (interface{}, bool, error) {
+	return c.lookup(ctx, func() (nameEntry, bool) {
+		e, ok := c.byKey[key]
+		return e, ok
+	})
+}
+
+// id returns the item with the given ID.
+func (c *nameCache) id(ctx context.Context, id int) (interface{}, bool, error) {
+	return c.lookup(ctx, func() (nameEntry, bool) {
+		e, ok := c.byID[id]
+		return e, ok
+	})
+}
+
+// lookup runs find against a fresh cache, refreshing once on a miss.
+func (c *nameCache) lookup(ctx context.Context, find func() (nameEntry, bool)) (interface{}, bool, error) {
+	c.mu.Lock()
+	fresh := c.byID != nil && (c.ttl <= 0 || time.Since(c.loaded) < c.ttl)
+	var e nameEntry
+	var ok bool
+	if fresh {
+		e, ok = find()
+	}
+	c.mu.Unlock()
+	if ok {
+		return e.value, true, nil
+	}
+	if err := c.refresh(ctx); err != nil {
+		return nil, false, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok = find()
+	return e.value, ok, nil
+}
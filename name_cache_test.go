@@ -0,0 +1,57 @@
+package tly
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNameCache(t *testing.T) {
+	ctx := context.Background()
+	list := []nameEntry{
+		{id: 1, keys: []string{"a"}, value: "one"},
+		{id: 2, keys: []string{"a", "b"}, value: "two"},
+	}
+	loads := 0
+	c := nameCache{load: func(context.Context) ([]nameEntry, error) {
+		loads++
+		return list, nil
+	}}
+
+	tests := []struct {
+		name   string
+		change func()
+		key    string
+		want   interface{} // nil when the key is missing
+		loads  int
+	}{
+		{"add before the first load is dropped", func() {
+			c.add(nameEntry{id: 3, keys: []string{"z"}, value: "three"})
+		}, "z", nil, 1},
+		{"first stored wins", nil, "a", "one", 1},
+		{"second key", nil, "b", "two", 1},
+		{"add replaces the entry with the same ID", func() {
+			list[0] = nameEntry{id: 1, keys: []string{"c"}, value: "renamed"}
+			c.add(list[0])
+		}, "c", "renamed", 1},
+		{"replaced key misses and refreshes", nil, "a", "two", 2},
+		{"evict", func() {
+			list = list[:1]
+			c.evict(2)
+		}, "b", nil, 3},
+	}
+	for _, tt := range tests {
+		if tt.change != nil {
+			tt.change()
+		}
+		got, ok, err := c.key(ctx, tt.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			got = nil
+		}
+		if got != tt.want || loads != tt.loads {
+			t.Errorf("%s: key(%q) = %v after %d loads, want %v after %d", tt.name, tt.key, got, loads, tt.want, tt.loads)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package tly
+
+import "context"
+
+// NotificationSettings are the email notifications of an account.
+type NotificationSettings struct {
+	// ExpirationWarnings emails before short links expire.
+	ExpirationWarnings bool `json:"expiration_warnings"`
+	// ExpirationWarningDays is how many days ahead of expiry the warning
+	// is sent.
+	ExpirationWarningDays int `json:"expiration_warning_days"`
+	// WeeklyDigest emails a weekly click summary.
+	WeeklyDigest bool `json:"weekly_digest"`
+	// UsageAlerts emails when the plan's quotas are nearly used up.
+	UsageAlerts bool `json:"usage_alerts"`
+	// Recipients receive the notifications in addition to the account
+	// email.
+	Recipients []string `json:"recipients"`
+}
+
+// NotificationSettingsUpdateRequest changes the notification settings of
+// an account. Nil fields are left unchanged; a non-nil empty Recipients
+// removes every extra recipient.
+type NotificationSettingsUpdateRequest struct {
+	ExpirationWarnings    *bool     `json:"expiration_warnings,omitempty"`
+	ExpirationWarningDays *int      `json:"expiration_warning_days,omitempty"`
+	WeeklyDigest          *bool     `json:"weekly_digest,omitempty"`
+	UsageAlerts           *bool     `json:"usage_alerts,omitempty"`
+	Recipients            *[]string `json:"recipients,omitempty"`
+}
+
+// NotificationUpdateFrom returns the request that sets every field to the
+// value in s, for applying the same settings to many accounts.
+func NotificationUpdateFrom(s NotificationSettings) NotificationSettingsUpdateRequest {
+	recipients := s.Recipients
+	if recipients == nil {
+		recipients = []string{}
+	}
+	return NotificationSettingsUpdateRequest{
+		ExpirationWarnings:    &s.ExpirationWarnings,
+		ExpirationWarningDays: &s.ExpirationWarningDays,
+		WeeklyDigest:          &s.WeeklyDigest,
+		UsageAlerts:           &s.UsageAlerts,
+		Recipients:            &recipients,
+	}
+}
+
+// GetNotificationSettings retrieves the notification settings of the
+// account.
+func (c *Client) GetNotificationSettings(ctx context.Context) (*NotificationSettings, error) {
+	var settings NotificationSettings
+	err := c.doRequestContext(ctx, "GET", "/api/v1/account/notifications", "", nil, &settings)
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// UpdateNotificationSettings updates the notification settings of the
+// account and returns the resulting settings.
+func (c *Client) UpdateNotificationSettings(ctx context.Context, reqData NotificationSettingsUpdateRequest) (*NotificationSettings, error) {
+	var settings NotificationSettings
+	err := c.doRequestContext(ctx, "PUT", "/api/v1/account/notifications", "", reqData, &settings)
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
@@ -0,0 +1,187 @@
+// Package oauth implements the T.LY OAuth 2.0 authorization-code flow, so
+// applications can act on behalf of their users instead of sharing a
+// static API key.
+//
+// Send the user to Config.AuthCodeURL, exchange the code T.LY redirects back
+// with for a Token, keep it in a TokenStore and build a client with the
+// access token:
+//
+//	tok, err := conf.Exchange(ctx, r.URL.Query().Get("code"))
+//	err = store.Save(ctx, userID, tok)
+//	client := tly.NewClient(tok.AccessToken)
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Default T.LY OAuth endpoints.
+const (
+	DefaultAuthURL  = "https://t.ly/oauth/authorize"
+	DefaultTokenURL = "https://t.ly/oauth/token"
+)
+
+// Config describes an OAuth client registered with T.LY.
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is where T.LY sends the user after authorization. It
+	// must match the URL registered for the client.
+	RedirectURL string
+	Scopes      []string
+	// AuthURL and TokenURL default to DefaultAuthURL and DefaultTokenURL.
+	AuthURL  string
+	TokenURL string
+	// HTTPClient makes token requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Token is an OAuth access token with its refresh token.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+}
+
+// expiryDelta is how long before its expiry a token is treated as expired,
+// so it does not lapse while a request is in flight.
+const expiryDelta = 30 * time.Second
+
+// Valid reports whether the token has an access token that is not about to
+// expire. Tokens without an expiry never expire.
+func (t *Token) Valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || time.Now().Add(expiryDelta).Before(t.Expiry)
+}
+
+// Error is an error response from the token endpoint.
+type Error struct {
+	StatusCode  int    `json:"-"`
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *Error) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("oauth: %s: %s", e.Code, e.Description)
+	}
+	return fmt.Sprintf("oauth: %s (status %d)", e.Code, e.StatusCode)
+}
+
+// AuthCodeURL returns the URL to send the user to for authorization. state
+// is echoed back on the redirect and must be checked to prevent CSRF.
+func (c *Config) AuthCodeURL(state string) string {
+	authURL := c.AuthURL
+	if authURL == "" {
+		authURL = DefaultAuthURL
+	}
+	v := url.Values{
+		"response_type": {"code"},
+		"client_id":     {c.ClientID},
+		"state":         {state},
+	}
+	if c.RedirectURL != "" {
+		v.Set("redirect_uri", c.RedirectURL)
+	}
+	if len(c.Scopes) > 0 {
+		v.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	sep := "?"
+	if strings.Contains(authURL, "?") {
+		sep = "&"
+	}
+	return authURL + sep + v.Encode()
+}
+
+// Exchange trades an authorization code for a token.
+func (c *Config) Exchange(ctx context.Context, code string) (*Token, error) {
+	v := url.Values{
+		"grant_type": {"authorization_code"},
+		"code":       {code},
+	}
+	if c.RedirectURL != "" {
+		v.Set("redirect_uri", c.RedirectURL)
+	}
+	return c.retrieveToken(ctx, v)
+}
+
+// Refresh trades a refresh token for a new token. If the response carries
+// no new refresh token, the old one is kept.
+func (c *Config) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	tok, err := c.retrieveToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tok.RefreshToken == "" {
+		tok.RefreshToken = refreshToken
+	}
+	return tok, nil
+}
+
+// retrieveToken posts v with the client credentials to the token endpoint.
+func (c *Config) retrieveToken(ctx context.Context, v url.Values) (*Token, error) {
+	tokenURL := c.TokenURL
+	if tokenURL == "" {
+		tokenURL = DefaultTokenURL
+	}
+	v.Set("client_id", c.ClientID)
+	if c.ClientSecret != "" {
+		v.Set("client_secret", c.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		e := &Error{StatusCode: resp.StatusCode}
+		if json.Unmarshal(data, e) != nil || e.Code == "" {
+			e.Code = strings.TrimSpace(string(data))
+		}
+		return nil, e
+	}
+	var raw struct {
+		AccessToken  string `json:"access_token"`
+		TokenType    string `json:"token_type"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if raw.AccessToken == "" {
+		return nil, fmt.Errorf("oauth: token response has no access_token")
+	}
+	tok := &Token{AccessToken: raw.AccessToken, TokenType: raw.TokenType, RefreshToken: raw.RefreshToken}
+	if raw.ExpiresIn > 0 {
+		tok.Expiry = time.Now().Add(time.Duration(raw.ExpiresIn) * time.Second)
+	}
+	return tok, nil
+}
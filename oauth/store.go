@@ -0,0 +1,135 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrNoToken is returned by a TokenStore that holds no token for a key.
+var ErrNoToken = errors.New("oauth: no token stored")
+
+// TokenStore persists tokens per key, typically the ID of the user who
+// authorized the application.
+type TokenStore interface {
+	Load(ctx context.Context, key string) (*Token, error)
+	Save(ctx context.Context, key string, tok *Token) error
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryStore is an in-process TokenStore. The zero value is ready to use.
+type MemoryStore struct {
+	mu     sync.Mutex
+	tokens map[string]Token
+}
+
+// Load implements TokenStore.
+func (s *MemoryStore) Load(ctx context.Context, key string) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tok, ok := s.tokens[key]
+	if !ok {
+		return nil, ErrNoToken
+	}
+	return &tok, nil
+}
+
+// Save implements TokenStore.
+func (s *MemoryStore) Save(ctx context.Context, key string, tok *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tokens == nil {
+		s.tokens = map[string]Token{}
+	}
+	s.tokens[key] = *tok
+	return nil
+}
+
+// Delete implements TokenStore.
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, key)
+	return nil
+}
+
+// FileStore is a TokenStore keeping every token in one JSON file keyed by
+// store key, rewritten atomically on every change. The file is created with
+// mode 0600 since it holds credentials.
+type FileStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func (s *FileStore) load() (map[string]Token, error) {
+	m := map[string]Token{}
+	data, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m, json.Unmarshal(data, &m)
+}
+
+func (s *FileStore) save(m map[string]Token) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o700); err != nil {
+		return err
+	}
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
+
+// Load implements TokenStore.
+func (s *FileStore) Load(ctx context.Context, key string) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	tok, ok := tokens[key]
+	if !ok {
+		return nil, ErrNoToken
+	}
+	return &tok, nil
+}
+
+// Save implements TokenStore.
+func (s *FileStore) Save(ctx context.Context, key string, tok *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+	tokens[key] = *tok
+	return s.save(tokens)
+}
+
+// Delete implements TokenStore.
+func (s *FileStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[key]; !ok {
+		return nil
+	}
+	delete(tokens, key)
+	return s.save(tokens)
+}
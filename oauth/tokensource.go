@@ -0,0 +1,105 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNoRefreshToken is returned when a token has expired and cannot be
+// refreshed.
+var ErrNoRefreshToken = errors.New("oauth: token expired and has no refresh token")
+
+// refreshTimeout bounds a refresh, so a token endpoint that never answers
+// fails the waiting callers and lets the next call try again.
+var refreshTimeout = time.Minute
+
+// TokenSource hands out a valid access token, refreshing it when it is
+// about to expire. Concurrent callers share a single refresh. It satisfies
+// tly.TokenSource, so it plugs into a client with tly.WithTokenSource.
+type TokenSource struct {
+	conf  *Config
+	store TokenStore
+	key   string
+
+	mu       sync.Mutex
+	tok      *Token
+	inflight *refreshCall
+}
+
+// refreshCall is a refresh in progress; done is closed when it finishes.
+type refreshCall struct {
+	done chan struct{}
+	tok  *Token
+	err  error
+}
+
+// TokenSource returns a TokenSource starting from tok. When store is not
+// nil, refreshed tokens are saved to it under key.
+func (c *Config) TokenSource(tok *Token, store TokenStore, key string) *TokenSource {
+	return &TokenSource{conf: c, store: store, key: key, tok: tok}
+}
+
+// Current returns the token the source holds, without refreshing it.
+func (s *TokenSource) Current() *Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tok
+}
+
+// Token returns a valid access token, refreshing the held token first if
+// it has expired. Only one refresh runs at a time; other callers wait for
+// its result or for their ctx to be done.
+func (s *TokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	if s.tok.Valid() {
+		access := s.tok.AccessToken
+		s.mu.Unlock()
+		return access, nil
+	}
+	call := s.inflight
+	if call == nil {
+		if s.tok == nil || s.tok.RefreshToken == "" {
+			s.mu.Unlock()
+			return "", ErrNoRefreshToken
+		}
+		call = &refreshCall{done: make(chan struct{})}
+		s.inflight = call
+		go s.refresh(call, s.tok.RefreshToken)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	if call.err != nil {
+		return "", call.err
+	}
+	return call.tok.AccessToken, nil
+}
+
+// refresh runs one refresh detached from any caller's context, so a caller
+// giving up does not fail the refresh for the others, and bounded by
+// refreshTimeout. A failure to save the refreshed token is reported to the
+// waiting callers.
+func (s *TokenSource) refresh(call *refreshCall, refreshToken string) {
+	ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+	defer cancel()
+	tok, err := s.conf.Refresh(ctx, refreshToken)
+	s.mu.Lock()
+	if err == nil {
+		// Keep the new token even if saving fails: the server may have
+		// rotated the refresh token, making the old one useless.
+		s.tok = tok
+	}
+	s.inflight = nil
+	s.mu.Unlock()
+	if err == nil && s.store != nil {
+		err = s.store.Save(ctx, s.key, tok)
+	}
+	call.tok, call.err = tok, err
+	close(call.done)
+}
@@ -0,0 +1,93 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func expired() *Token {
+	return &Token{AccessToken: "old", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Hour)}
+}
+
+func TestTokenSourceSharesRefresh(t *testing.T) {
+	var refreshes int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&refreshes, 1)
+		<-release
+		fmt.Fprintf(w, `{"access_token":"new-%d","refresh_token":"refresh","expires_in":3600}`, n)
+	}))
+	defer srv.Close()
+	store := &MemoryStore{}
+	ts := (&Config{TokenURL: srv.URL}).TokenSource(expired(), store, "user")
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 5)
+	errs := make([]error, 5)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], errs[i] = ts.Token(context.Background())
+		}(i)
+	}
+	for atomic.LoadInt32(&refreshes) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+	for i := range tokens {
+		if errs[i] != nil || tokens[i] != "new-1" {
+			t.Errorf("Token() = %q, %v; want new-1", tokens[i], errs[i])
+		}
+	}
+	if n := atomic.LoadInt32(&refreshes); n != 1 {
+		t.Errorf("%d refreshes, want 1", n)
+	}
+	if saved, err := store.Load(context.Background(), "user"); err != nil || saved.AccessToken != "new-1" {
+		t.Errorf("stored token = %+v, %v", saved, err)
+	}
+}
+
+func TestTokenSourceRefreshTimeout(t *testing.T) {
+	defer func(d time.Duration) { refreshTimeout = d }(refreshTimeout)
+	refreshTimeout = 50 * time.Millisecond
+
+	var refreshes int32
+	hang := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&refreshes, 1) == 1 {
+			<-hang
+			return
+		}
+		fmt.Fprint(w, `{"access_token":"new","expires_in":3600}`)
+	}))
+	defer srv.Close()
+	defer close(hang)
+	ts := (&Config{TokenURL: srv.URL}).TokenSource(expired(), nil, "")
+
+	if _, err := ts.Token(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Token() with a hanging endpoint = %v, want a deadline error", err)
+	}
+	tok, err := ts.Token(context.Background())
+	if err != nil || tok != "new" {
+		t.Fatalf("Token() after the timeout = %q, %v; want a new refresh", tok, err)
+	}
+	if n := atomic.LoadInt32(&refreshes); n != 2 {
+		t.Errorf("%d refreshes, want 2", n)
+	}
+}
+
+func TestTokenSourceNoRefreshToken(t *testing.T) {
+	ts := (&Config{}).TokenSource(&Token{AccessToken: "old", Expiry: time.Now().Add(-time.Hour)}, nil, "")
+	if _, err := ts.Token(context.Background()); err != ErrNoRefreshToken {
+		t.Errorf("Token() = %v, want ErrNoRefreshToken", err)
+	}
+}
@@ -0,0 +1,144 @@
+package tly
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+// ListOptions selects a page of a paginated list endpoint. Zero values use
+// the API defaults.
+type ListOptions struct {
+	Page    int
+	PerPage int
+}
+
+// values encodes the options as query parameters.
+func (o ListOptions) values() url.Values {
+	v := url.Values{}
+	if o.Page > 0 {
+		v.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PerPage > 0 {
+		v.Set("per_page", strconv.Itoa(o.PerPage))
+	}
+	return v
+}
+
+// PageInfo describes the position of a page within a paginated list.
+type PageInfo struct {
+	CurrentPage int `json:"current_page"`
+	LastPage    int `json:"last_page"`
+	PerPage     int `json:"per_page"`
+	Total       int `json:"total"`
+}
+
+// HasNext reports whether a page follows this one.
+func (p PageInfo) HasNext() bool {
+	return p.CurrentPage < p.LastPage
+}
+
+// getPage fetches path with opts and decodes the items into data. Endpoints
+// that ignore pagination and answer with a bare array are reported as a
+// single page.
+func (c *Client) getPage(ctx context.Context, path string, opts ListOptions, data interface{}) (PageInfo, error) {
+	var raw json.RawMessage
+	if err := c.doRequestContext(ctx, "GET", path, opts.values().Encode(), nil, &raw); err != nil {
+		return PageInfo{}, err
+	}
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, data); err != nil {
+			return PageInfo{}, err
+		}
+		return PageInfo{CurrentPage: 1, LastPage: 1}, nil
+	}
+	var page struct {
+		PageInfo
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &page); err != nil {
+		return PageInfo{}, err
+	}
+	if len(page.Data) > 0 {
+		if err := json.Unmarshal(page.Data, data); err != nil {
+			return PageInfo{}, err
+		}
+	}
+	return page.PageInfo, nil
+}
+
+// TagPage is a single page of the tag list.
+type TagPage struct {
+	PageInfo
+	Data []Tag `json:"data"`
+}
+
+// ListTagsPage retrieves one page of tags.
+func (c *Client) ListTagsPage(ctx context.Context, opts ListOptions) (*TagPage, error) {
+	page := &TagPage{}
+	info, err := c.getPage(ctx, "/api/v1/link/tag", opts, &page.Data)
+	if err != nil {
+		return nil, err
+	}
+	page.PageInfo = info
+	return page, nil
+}
+
+// ListAllTags follows pagination from opts.Page (or the first page) and
+// returns every tag.
+func (c *Client) ListAllTags(ctx context.Context, opts ListOptions) ([]Tag, error) {
+	if opts.Page < 1 {
+		opts.Page = 1
+	}
+	var tags []Tag
+	for {
+		page, err := c.ListTagsPage(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, page.Data...)
+		if len(page.Data) == 0 || !page.HasNext() {
+			return tags, nil
+		}
+		opts.Page = page.CurrentPage + 1
+	}
+}
+
+// PixelPage is a single page of the pixel list.
+type PixelPage struct {
+	PageInfo
+	Data []Pixel `json:"data"`
+}
+
+// ListPixelsPage retrieves one page of pixels.
+func (c *Client) ListPixelsPage(ctx context.Context, opts ListOptions) (*PixelPage, error) {
+	page := &PixelPage{}
+	info, err := c.getPage(ctx, "/api/v1/link/pixel", opts, &page.Data)
+	if err != nil {
+		return nil, err
+	}
+	page.PageInfo = info
+	return page, nil
+}
+
+// ListAllPixels follows pagination from opts.Page (or the first page) and
+// returns every pixel.
+func (c *Client) ListAllPixels(ctx context.Context, opts ListOptions) ([]Pixel, error) {
+	if opts.Page < 1 {
+		opts.Page = 1
+	}
+	var pixels []Pixel
+	for {
+		page, err := c.ListPixelsPage(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		pixels = append(pixels, page.Data...)
+		if len(page.Data) == 0 || !page.HasNext() {
+			return pixels, nil
+		}
+		opts.Page = page.CurrentPage + 1
+	}
+}
@@ -0,0 +1,100 @@
+package tly_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+	"github.com/timleland/t.ly-go-url-shortener-api/tlytest"
+)
+
+func TestListAllTags(t *testing.T) {
+	ctx := context.Background()
+	srv := tlytest.NewServer(tlytest.Options{PerPage: 10})
+	defer srv.Close()
+	c := srv.Client()
+	for i := 1; i <= 25; i++ {
+		if _, err := c.CreateTag(fmt.Sprintf("tag-%02d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name     string
+		opts     tly.ListOptions
+		first    string
+		tags     int
+		requests int
+	}{
+		{"default page size", tly.ListOptions{}, "tag-01", 25, 3},
+		{"larger pages", tly.ListOptions{PerPage: 20}, "tag-01", 25, 2},
+		{"exact fit", tly.ListOptions{PerPage: 25}, "tag-01", 25, 1},
+		{"from a later page", tly.ListOptions{Page: 2, PerPage: 10}, "tag-11", 15, 2},
+		{"past the end", tly.ListOptions{Page: 9}, "", 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := srv.Requests("GET /api/v1/link/tag")
+			tags, err := c.ListAllTags(ctx, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tags) != tt.tags {
+				t.Fatalf("got %d tags, want %d", len(tags), tt.tags)
+			}
+			if len(tags) > 0 && tags[0].Tag != tt.first {
+				t.Errorf("first tag = %q, want %q", tags[0].Tag, tt.first)
+			}
+			if n := srv.Requests("GET /api/v1/link/tag") - before; n != tt.requests {
+				t.Errorf("made %d requests, want %d", n, tt.requests)
+			}
+		})
+	}
+}
+
+func TestListTagsPage(t *testing.T) {
+	srv := tlytest.NewServer(tlytest.Options{PerPage: 2})
+	defer srv.Close()
+	c := srv.Client()
+	for _, name := range []string{"a", "b", "c"} {
+		if _, err := c.CreateTag(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	page, err := c.ListTagsPage(context.Background(), tly.ListOptions{Page: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := tly.PageInfo{CurrentPage: 2, LastPage: 2, PerPage: 2, Total: 3}
+	if page.PageInfo != want || len(page.Data) != 1 || page.Data[0].Tag != "c" {
+		t.Errorf("page 2 = %+v, want %+v with tag c", page, want)
+	}
+	if page.HasNext() {
+		t.Error("HasNext on the last page = true")
+	}
+}
+
+func TestListPixelsPageBareArray(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id":1,"name":"a"},{"id":2,"name":"b"}]`)
+	}))
+	defer srv.Close()
+	c := tly.NewClient("test-key")
+	c.BaseURL = srv.URL
+	page, err := c.ListPixelsPage(context.Background(), tly.ListOptions{Page: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Data) != 2 || page.CurrentPage != 1 || page.HasNext() {
+		t.Errorf("bare array page = %+v, want a single page of 2 pixels", page)
+	}
+	pixels, err := c.ListAllPixels(context.Background(), tly.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pixels) != 2 {
+		t.Errorf("ListAllPixels = %d pixels, want 2", len(pixels))
+	}
+}
@@ -0,0 +1,61 @@
+package tly
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultConcurrency bounds fan-out helpers when the caller does not.
+const defaultConcurrency = 4
+
+// errorText is err's message, or empty when err is nil. Batch results
+// carry it in an Error field so that JSON reports keep the failure reason.
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// forEachLimit calls fn for every index in [0, n) using at most limit
+// goroutines. It stops scheduling new work after the first error, cancels
+// the context passed to fn and returns that error.
+func forEachLimit(ctx context.Context, n, limit int, fn func(ctx context.Context, i int) error) error {
+	if limit < 1 {
+		limit = defaultConcurrency
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, limit)
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(ctx, i); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,62 @@
+package tly
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// Password is the password of a password-protected link. It prints as
+// "[redacted]" with the fmt verbs so that logging a request or options
+// struct does not leak it; convert it to string to read the value.
+type Password string
+
+// String implements fmt.Stringer.
+func (p Password) String() string {
+	if p == "" {
+		return ""
+	}
+	return "[redacted]"
+}
+
+// GoString implements fmt.GoStringer.
+func (p Password) GoString() string {
+	return `"` + p.String() + `"`
+}
+
+// redactPassword scrubs pw from err, which may carry it in a request URL
+// or an echoed response body.
+func redactPassword(err error, pw Password) error {
+	if err == nil || pw == "" {
+		return err
+	}
+	scrub := func(s string) string {
+		s = strings.Replace(s, url.QueryEscape(string(pw)), "[redacted]", -1)
+		return strings.Replace(s, string(pw), "[redacted]", -1)
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return &APIError{StatusCode: apiErr.StatusCode, Body: scrub(apiErr.Body)}
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return &url.Error{Op: urlErr.Op, URL: scrub(urlErr.URL), Err: urlErr.Err}
+	}
+	if msg := err.Error(); strings.Contains(msg, string(pw)) {
+		return errors.New(scrub(msg))
+	}
+	return err
+}
+
+// ExpandWithPassword expands a password-protected short link. The password
+// is sent in the request body and scrubbed from any returned error.
+func (c *Client) ExpandWithPassword(ctx context.Context, shortURL string, password Password) (*ExpandResponse, error) {
+	body := map[string]string{"short_url": shortURL, "password": string(password)}
+	var resp ExpandResponse
+	err := c.doRequestContext(ctx, "POST", "/api/v1/link/expand", "", body, &resp)
+	if err != nil {
+		return nil, redactPassword(err, password)
+	}
+	return &resp, nil
+}
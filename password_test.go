@@ -0,0 +1,100 @@
+package tly_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+	"github.com/timleland/t.ly-go-url-shortener-api/tlytest"
+)
+
+func TestPasswordStaysOutOfURLs(t *testing.T) {
+	const pw = "s3cret&pw"
+	var urls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		urls = append(urls, r.URL.String())
+		if r.Method != http.MethodPost {
+			t.Errorf("%s %s: password sent without a POST, where a body may be dropped", r.Method, r.URL.Path)
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["password"] != pw {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprintf(w, `{"message":"wrong password %q"}`, body["password"])
+			return
+		}
+		fmt.Fprint(w, `{"clicks":3,"long_url":"https://example.com"}`)
+	}))
+	defer srv.Close()
+	c := tly.NewClient("test-key")
+	c.BaseURL = srv.URL
+	ctx := context.Background()
+
+	stats, err := c.GetStatsWithOptions(ctx, "https://t.ly/secret", tly.StatsOptions{Password: pw})
+	if err != nil || stats.Clicks != 3 {
+		t.Fatalf("GetStatsWithOptions = %+v, %v", stats, err)
+	}
+	if _, err := c.ExpandWithPassword(ctx, "https://t.ly/secret", pw); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.GetStatsWithOptions(ctx, "https://t.ly/secret", tly.StatsOptions{Password: "wrong " + pw})
+	if err == nil || strings.Contains(err.Error(), pw) {
+		t.Errorf("error with a wrong password = %v, want one without the password", err)
+	}
+	for _, u := range urls {
+		if strings.Contains(u, "s3cret") {
+			t.Errorf("request URL %s carries the password", u)
+		}
+	}
+}
+
+func TestPasswordRedacted(t *testing.T) {
+	opts := tly.StatsOptions{Password: "hunter2"}
+	for _, s := range []string{fmt.Sprint(opts.Password), fmt.Sprintf("%v", opts), fmt.Sprintf("%+v", opts), fmt.Sprintf("%#v", opts)} {
+		if strings.Contains(s, "hunter2") {
+			t.Errorf("formatted options %s show the password", s)
+		}
+	}
+}
+
+func TestStatsPassword(t *testing.T) {
+	srv := tlytest.NewServer(tlytest.Options{})
+	defer srv.Close()
+	c := srv.Client()
+	pw := "s3cret"
+	link, err := c.CreateShortLink(tly.ShortLinkCreateRequest{LongURL: "https://example.com", Password: &pw})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Click(link.ShortURL, tlytest.Visit{Visitor: "1.2.3.4"}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	tests := []struct {
+		password tly.Password
+		status   int
+	}{
+		{"s3cret", 0},
+		{"", http.StatusUnauthorized},
+		{"wrong", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		stats, err := c.GetStatsWithOptions(ctx, link.ShortURL, tly.StatsOptions{Password: tt.password, StartDate: time.Now().AddDate(0, 0, -1)})
+		var apiErr *tly.APIError
+		switch {
+		case tt.status == 0 && err != nil:
+			t.Errorf("password %q: %v", string(tt.password), err)
+		case tt.status == 0 && stats.Clicks != 1:
+			t.Errorf("password %q: %d clicks, want 1", string(tt.password), stats.Clicks)
+		case tt.status != 0 && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.status):
+			t.Errorf("password %q: error %v, want status %d", string(tt.password), err, tt.status)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package tly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPixelNotFound is returned when no pixel matches a lookup.
+var ErrPixelNotFound = errors.New("tly: pixel not found")
+
+// PixelResolver caches the pixel list for a TTL and looks pixels up by name
+// and by platform pixel ID. A lookup that misses the cache refreshes it once
+// before failing, so pixels created elsewhere are picked up without waiting
+// for the TTL.
+type PixelResolver struct {
+	client *Client
+	cache  nameCache
+}
+
+// NewPixelResolver creates a resolver whose cache expires after ttl. A zero
+// ttl keeps the cache until Refresh is called or a lookup misses.
+func NewPixelResolver(client *Client, ttl time.Duration) *PixelResolver {
+	r := &PixelResolver{client: client}
+	r.cache = nameCache{ttl: ttl, load: r.load}
+	return r
+}
+
+// WithPixelResolver makes the client resolve pixel names through a shared
+// PixelResolver with the given ttl, so name-based helpers such as
+// AttachPixelToLinkByName avoid listing pixels on every call.
+func WithPixelResolver(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.pixels = NewPixelResolver(c, ttl)
+	}
+}
+
+// Refresh reloads the pixel list.
+func (r *PixelResolver) Refresh(ctx context.Context) error {
+	return r.cache.refresh(ctx)
+}
+
+// Add records a pixel in the cache, replacing any older entry for the same
+// pixel, e.g. right after creating or renaming it.
+func (r *PixelResolver) Add(pixel Pixel) {
+	r.cache.add(pixelEntry(pixel))
+}
+
+// Remove drops the pixel with the given ID from the cache, e.g. after
+// deleting it.
+func (r *PixelResolver) Remove(id int) {
+	r.cache.evict(id)
+}
+
+// load lists the pixels for the cache.
+func (r *PixelResolver) load(ctx context.Context) ([]nameEntry, error) {
+	pixels, err := r.client.listPixels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]nameEntry, len(pixels))
+	for i, p := range pixels {
+		entries[i] = pixelEntry(p)
+	}
+	return entries, nil
+}
+
+// pixelEntry is the cache entry for p, keyed by name and by platform pixel
+// ID.
+func pixelEntry(p Pixel) nameEntry {
+	return nameEntry{id: p.ID, keys: []string{pixelNameKey(p.Name), pixelPlatformKey(p.PixelType, p.PixelID)}, value: p}
+}
+
+// pixelNameKey is the cache key for a pixel name.
+func pixelNameKey(name string) string {
+	return "name:" + name
+}
+
+// pixelPlatformKey is the cache key for a platform pixel ID.
+func pixelPlatformKey(pixelType PixelType, pixelID string) string {
+	return "platform:" + string(pixelType) + ":" + pixelID
+}
+
+// ByName returns the pixel named name. If several pixels share the name,
+// the first one listed wins.
+func (r *PixelResolver) ByName(ctx context.Context, name string) (Pixel, error) {
+	v, ok, err := r.cache.key(ctx, pixelNameKey(name))
+	if err == nil && !ok {
+		err = ErrPixelNotFound
+	}
+	if err != nil {
+		return Pixel{}, fmt.Errorf("resolve pixel %q: %w", name, err)
+	}
+	return v.(Pixel), nil
+}
+
+// ByPlatformID returns the pixel with the given type and platform pixel ID.
+func (r *PixelResolver) ByPlatformID(ctx context.Context, pixelType PixelType, pixelID string) (Pixel, error) {
+	v, ok, err := r.cache.key(ctx, pixelPlatformKey(pixelType, pixelID))
+	if err == nil && !ok {
+		err = ErrPixelNotFound
+	}
+	if err != nil {
+		return Pixel{}, fmt.Errorf("resolve %s pixel %q: %w", pixelType, pixelID, err)
+	}
+	return v.(Pixel), nil
+}
+
+// pixelByName returns the pixel named name, through the client's
+// PixelResolver when one is configured.
+func (c *Client) pixelByName(ctx context.Context, name string) (Pixel, error) {
+	if c.pixels != nil {
+		return c.pixels.ByName(ctx, name)
+	}
+	pixels, err := c.listPixels(ctx)
+	if err != nil {
+		return Pixel{}, err
+	}
+	for _, p := range pixels {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return Pixel{}, fmt.Errorf("resolve pixel %q: %w", name, ErrPixelNotFound)
+}
+
+// AttachPixelToLinkByName is AttachPixelToLink with the pixel given by
+// name.
+func (c *Client) AttachPixelToLinkByName(ctx context.Context, shortURL, pixelName string) (*ShortLink, error) {
+	p, err := c.pixelByName(ctx, pixelName)
+	if err != nil {
+		return nil, err
+	}
+	return c.AttachPixelToLink(ctx, shortURL, p.ID)
+}
+
+// DetachPixelFromLinkByName is DetachPixelFromLink with the pixel given by
+// name.
+func (c *Client) DetachPixelFromLinkByName(ctx context.Context, shortURL, pixelName string) (*ShortLink, error) {
+	p, err := c.pixelByName(ctx, pixelName)
+	if err != nil {
+		return nil, err
+	}
+	return c.DetachPixelFromLink(ctx, shortURL, p.ID)
+}
@@ -0,0 +1,54 @@
+package tly_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+	"github.com/timleland/t.ly-go-url-shortener-api/tlytest"
+)
+
+func TestPixelResolverForgetsOldNames(t *testing.T) {
+	ctx := context.Background()
+	srv := tlytest.NewServer(tlytest.Options{})
+	defer srv.Close()
+	c := srv.Client(tly.WithPixelResolver(0))
+	link, err := c.CreateShortLink(tly.ShortLinkCreateRequest{LongURL: "https://example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	renamed, err := c.CreatePixel(tly.PixelCreateRequest{Name: "old", PixelID: "1", PixelType: tly.PixelTypeFacebook})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleted, err := c.CreatePixel(tly.PixelCreateRequest{Name: "gone", PixelID: "2", PixelType: tly.PixelTypeFacebook})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Load the resolver cache before changing the pixels.
+	if _, err := c.AttachPixelToLinkByName(ctx, link.ShortURL, "old"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.RenamePixel(ctx, renamed.ID, "new"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeletePixel(deleted.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want error
+	}{
+		{"new", nil},
+		{"old", tly.ErrPixelNotFound},
+		{"gone", tly.ErrPixelNotFound},
+	}
+	for _, tt := range tests {
+		_, err := c.AttachPixelToLinkByName(ctx, link.ShortURL, tt.name)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("AttachPixelToLinkByName(%q) error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package tly
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ExportFormat is the encoding of an export document.
+type ExportFormat string
+
+// Supported export formats.
+const (
+	ExportJSON ExportFormat = "json"
+	ExportCSV  ExportFormat = "csv"
+)
+
+// PixelExport is the JSON document written by ExportPixels. IDs are
+// informational; imports match pixels by type and platform pixel ID.
+type PixelExport struct {
+	Version int     `json:"version"`
+	Pixels  []Pixel `json:"pixels"`
+}
+
+// pixelCSVHeader is the header row of CSV pixel exports.
+var pixelCSVHeader = []string{"id", "name", "pixel_type", "pixel_id"}
+
+// ExportPixels writes every pixel in the account to w, as a JSON
+// PixelExport or as CSV with the columns id, name, pixel_type and pixel_id.
+// The format defaults to JSON.
+func (c *Client) ExportPixels(ctx context.Context, w io.Writer, format ExportFormat) error {
+	pixels, err := c.listPixels(ctx)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case "", ExportJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(PixelExport{Version: 1, Pixels: pixels})
+	case ExportCSV:
+		cw := csv.NewWriter(w)
+		rows := [][]string{pixelCSVHeader}
+		for _, p := range pixels {
+			rows = append(rows, []string{strconv.Itoa(p.ID), p.Name, string(p.PixelType), p.PixelID})
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	}
+	return fmt.Errorf("tly: unsupported export format %q", format)
+}
+
+// readPixelExport decodes pixels written by ExportPixels. CSV columns are
+// located by header name, so the id column may be omitted.
+func readPixelExport(r io.Reader, format ExportFormat) ([]Pixel, error) {
+	switch format {
+	case "", ExportJSON:
+		var doc PixelExport
+		if err := json.NewDecoder(r).Decode(&doc); err != nil {
+			return nil, err
+		}
+		return doc.Pixels, nil
+	case ExportCSV:
+		records, err := csv.NewReader(r).ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			return nil, nil
+		}
+		col := map[string]int{}
+		for i, name := range records[0] {
+			col[strings.TrimSpace(strings.ToLower(name))] = i
+		}
+		for _, name := range pixelCSVHeader[1:] {
+			if _, ok := col[name]; !ok {
+				return nil, fmt.Errorf("tly: pixel CSV is missing the %s column", name)
+			}
+		}
+		pixels := make([]Pixel, 0, len(records)-1)
+		for _, rec := range records[1:] {
+			pixels = append(pixels, Pixel{
+				Name:      rec[col["name"]],
+				PixelType: PixelType(rec[col["pixel_type"]]),
+				PixelID:   rec[col["pixel_id"]],
+			})
+		}
+		return pixels, nil
+	}
+	return nil, fmt.Errorf("tly: unsupported export format %q", format)
+}
+
+// ImportPixelsOptions configures ImportPixels.
+type ImportPixelsOptions struct {
+	// Format of the input. Defaults to JSON.
+	Format ExportFormat
+	// DryRun reports what would be created without creating anything.
+	DryRun bool
+}
+
+// PixelConflict is an imported pixel whose type and platform pixel ID
+// already exist in the account or earlier in the import.
+type PixelConflict struct {
+	Pixel    Pixel `json:"pixel"`
+	Existing Pixel `json:"existing"`
+}
+
+// PixelImportFailure is an imported pixel that could not be created.
+type PixelImportFailure struct {
+	Pixel Pixel  `json:"pixel"`
+	Err   error  `json:"-"`
+	Error string `json:"error"`
+}
+
+// PixelImportReport describes the outcome of ImportPixels. In a dry run
+// Created holds the pixels that would be created, without IDs.
+type PixelImportReport struct {
+	DryRun    bool                 `json:"dry_run"`
+	Created   []Pixel              `json:"created"`
+	Conflicts []PixelConflict      `json:"conflicts"`
+	Failed    []PixelImportFailure `json:"failed"`
+}
+
+// ImportPixels reads pixels written by ExportPixels from r and creates
+// every pixel whose type and platform pixel ID do not already exist in the
+// account. Collisions are reported as conflicts and left untouched, even
+// when the names differ; creation and validation failures are reported and
+// do not stop the import.
+func (c *Client) ImportPixels(ctx context.Context, r io.Reader, opts ImportPixelsOptions) (*PixelImportReport, error) {
+	imported, err := readPixelExport(r, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := c.listPixels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := &PixelImportReport{DryRun: opts.DryRun}
+	for _, p := range imported {
+		p.ID = 0
+		if found, ok := findPixel(existing, p.PixelType, p.PixelID); ok {
+			report.Conflicts = append(report.Conflicts, PixelConflict{Pixel: p, Existing: *found})
+			continue
+		}
+		if err := validatePixel(p.Name, p.PixelID, p.PixelType); err != nil {
+			report.Failed = append(report.Failed, PixelImportFailure{Pixel: p, Err: err, Error: err.Error()})
+			continue
+		}
+		if opts.DryRun {
+			report.Created = append(report.Created, p)
+			existing = append(existing, p)
+			continue
+		}
+		created, err := c.createPixel(ctx, PixelCreateRequest{Name: p.Name, PixelID: p.PixelID, PixelType: p.PixelType})
+		if err != nil {
+			report.Failed = append(report.Failed, PixelImportFailure{Pixel: p, Err: err, Error: err.Error()})
+			continue
+		}
+		report.Created = append(report.Created, *created)
+		existing = append(existing, *created)
+	}
+	return report, nil
+}
@@ -0,0 +1,155 @@
+package tly
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// pixelIDFormats are the platform pixel ID formats checked by
+// ValidatePixelID. Types without an entry accept any non-empty ID.
+var pixelIDFormats = map[PixelType]*regexp.Regexp{
+	PixelTypeFacebook:         regexp.MustCompile(`^\d{10,20}$`),
+	PixelTypeGoogleAds:        regexp.MustCompile(`^AW-\d+$`),
+	PixelTypeGoogleAnalytics:  regexp.MustCompile(`^(G-[A-Z0-9]+|UA-\d+-\d+)$`),
+	PixelTypeGoogleTagManager: regexp.MustCompile(`^GTM-[A-Z0-9]+$`),
+	PixelTypeLinkedIn:         regexp.MustCompile(`^\d+$`),
+	PixelTypeTikTok:           regexp.MustCompile(`^[A-Z0-9]{10,30}$`),
+	PixelTypePinterest:        regexp.MustCompile(`^\d+$`),
+	PixelTypeSnapchat:         regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`),
+	PixelTypeQuora:            regexp.MustCompile(`^[0-9a-f]{32}$`),
+	PixelTypeBing:             regexp.MustCompile(`^\d+$`),
+}
+
+// ValidatePixelID checks pixelID against the known ID format of its
+// platform, for example digits for Facebook or a "GTM-" prefix for Google
+// Tag Manager. It returns a *PixelValidationError on mismatch.
+func ValidatePixelID(pixelType PixelType, pixelID string) error {
+	if pixelID == "" {
+		return &PixelValidationError{Field: "pixel_id", Value: pixelID, Reason: "is required"}
+	}
+	if !pixelType.Valid() {
+		return &PixelValidationError{Field: "pixel_type", Value: string(pixelType), Reason: "is not a supported pixel type"}
+	}
+	if re, ok := pixelIDFormats[pixelType]; ok && !re.MatchString(pixelID) {
+		return &PixelValidationError{
+			Field:  "pixel_id",
+			Value:  pixelID,
+			Reason: fmt.Sprintf("does not match the %s pixel ID format", pixelType),
+		}
+	}
+	return nil
+}
+
+// PixelVerifyStatus is the outcome of VerifyPixel.
+type PixelVerifyStatus string
+
+// Pixel verification outcomes.
+const (
+	// PixelInvalidFormat means the pixel ID does not match its platform.
+	PixelInvalidFormat PixelVerifyStatus = "invalid_format"
+	// PixelFormatValid means the ID format is valid; firing was not
+	// checked.
+	PixelFormatValid PixelVerifyStatus = "format_valid"
+	// PixelNotFired means the test link resolved without the pixel ID in
+	// its markup.
+	PixelNotFired PixelVerifyStatus = "not_fired"
+	// PixelFired means the test link served markup containing the pixel
+	// ID.
+	PixelFired PixelVerifyStatus = "fired"
+)
+
+// VerifyPixelOptions configures VerifyPixel.
+type VerifyPixelOptions struct {
+	// Fire creates a test short link with the pixel attached, resolves it
+	// and looks for the pixel ID in the served markup. Without it only the
+	// ID format is checked.
+	Fire bool
+	// LongURL is the destination of the test link. Defaults to
+	// "https://t.ly".
+	LongURL string
+	// KeepLink leaves the test link in place instead of deleting it.
+	KeepLink bool
+}
+
+// PixelVerification is the result of VerifyPixel. FormatErr is set when
+// the status is PixelInvalidFormat.
+type PixelVerification struct {
+	Pixel     *Pixel            `json:"pixel"`
+	Status    PixelVerifyStatus `json:"status"`
+	FormatErr error             `json:"-"`
+	ShortURL  string            `json:"short_url,omitempty"`
+}
+
+// maxVerifyBody caps how much of the resolved page VerifyPixel reads.
+const maxVerifyBody = 1 << 20
+
+// VerifyPixel checks that a pixel is usable. It always validates the pixel
+// ID format; with opts.Fire it also creates a test link carrying the pixel,
+// requests the link without following redirects and reports whether the
+// served page mentions the pixel ID. Links whose pixels are fired by a
+// redirect page rather than inline markup report PixelNotFired, so treat
+// that status as a hint rather than proof. The test link is deleted
+// afterwards unless opts.KeepLink is set.
+func (c *Client) VerifyPixel(ctx context.Context, pixelID int, opts VerifyPixelOptions) (*PixelVerification, error) {
+	pixel, err := c.getPixel(ctx, pixelID)
+	if err != nil {
+		return nil, err
+	}
+	v := &PixelVerification{Pixel: pixel, Status: PixelFormatValid}
+	if err := ValidatePixelID(pixel.PixelType, pixel.PixelID); err != nil {
+		v.Status, v.FormatErr = PixelInvalidFormat, err
+		return v, nil
+	}
+	if !opts.Fire {
+		return v, nil
+	}
+	if opts.LongURL == "" {
+		opts.LongURL = "https://t.ly"
+	}
+	link, err := c.createShortLink(ctx, ShortLinkCreateRequest{LongURL: opts.LongURL, Pixels: []int{pixel.ID}})
+	if err != nil {
+		return nil, err
+	}
+	v.ShortURL = link.ShortURL
+	if !opts.KeepLink {
+		defer c.deleteShortLink(context.Background(), link.ShortURL)
+	}
+	fired, err := c.pageMentions(ctx, link.ShortURL, pixel.PixelID)
+	if err != nil {
+		return nil, err
+	}
+	v.Status = PixelNotFired
+	if fired {
+		v.Status = PixelFired
+	}
+	return v, nil
+}
+
+// pageMentions requests rawURL without following redirects and reports
+// whether the response body contains needle.
+func (c *Client) pageMentions(ctx context.Context, rawURL, needle string) (bool, error) {
+	hc := http.Client{}
+	if c.Client != nil {
+		hc = *c.Client
+	}
+	hc.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxVerifyBody))
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(string(body), needle), nil
+}
@@ -0,0 +1,384 @@
+package tly
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// PixelService is the pixel part of the API.
+type PixelService interface {
+	ListPixels() ([]Pixel, error)
+	ListPixelsPage(ctx context.Context, opts ListOptions) (*PixelPage, error)
+	ListAllPixels(ctx context.Context, opts ListOptions) ([]Pixel, error)
+	CreatePixel(reqData PixelCreateRequest) (*Pixel, error)
+	GetPixel(id int) (*Pixel, error)
+	UpdatePixel(reqData PixelUpdateRequest) (*Pixel, error)
+	DeletePixel(id int) error
+}
+
+var _ PixelService = (*Client)(nil)
+
+// PixelType identifies the ad or analytics platform of a pixel.
+type PixelType string
+
+// Pixel types supported by the API.
+const (
+	PixelTypeFacebook         PixelType = "facebook"
+	PixelTypeGoogleAds        PixelType = "googleAds"
+	PixelTypeGoogleAnalytics  PixelType = "googleAnalytics"
+	PixelTypeGoogleTagManager PixelType = "googleTagManager"
+	PixelTypeLinkedIn         PixelType = "linkedin"
+	PixelTypeTikTok           PixelType = "tiktok"
+	PixelTypeTwitter          PixelType = "twitter"
+	PixelTypePinterest        PixelType = "pinterest"
+	PixelTypeSnapchat         PixelType = "snapchat"
+	PixelTypeQuora            PixelType = "quora"
+	PixelTypeAdRoll           PixelType = "adroll"
+	PixelTypeBing             PixelType = "bing"
+	PixelTypeReddit           PixelType = "reddit"
+)
+
+// pixelTypes is the set of supported pixel types.
+var pixelTypes = map[PixelType]bool{
+	PixelTypeFacebook:         true,
+	PixelTypeGoogleAds:        true,
+	PixelTypeGoogleAnalytics:  true,
+	PixelTypeGoogleTagManager: true,
+	PixelTypeLinkedIn:         true,
+	PixelTypeTikTok:           true,
+	PixelTypeTwitter:          true,
+	PixelTypePinterest:        true,
+	PixelTypeSnapchat:         true,
+	PixelTypeQuora:            true,
+	PixelTypeAdRoll:           true,
+	PixelTypeBing:             true,
+	PixelTypeReddit:           true,
+}
+
+// PixelTypes returns the supported pixel types in alphabetical order.
+func PixelTypes() []PixelType {
+	types := make([]PixelType, 0, len(pixelTypes))
+	for t := range pixelTypes {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// Valid reports whether t is a supported pixel type.
+func (t PixelType) Valid() bool {
+	return pixelTypes[t]
+}
+
+// PixelValidationError is returned when pixel fields fail pre-flight
+// validation.
+type PixelValidationError struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e *PixelValidationError) Error() string {
+	return fmt.Sprintf("tly: invalid pixel %s %q: %s", e.Field, e.Value, e.Reason)
+}
+
+// validatePixel checks the fields shared by pixel create and update
+// requests. CreatePixel and UpdatePixel run it before calling the API.
+func validatePixel(name, pixelID string, pixelType PixelType) error {
+	if name == "" {
+		return &PixelValidationError{Field: "name", Value: name, Reason: "is required"}
+	}
+	if pixelID == "" {
+		return &PixelValidationError{Field: "pixel_id", Value: pixelID, Reason: "is required"}
+	}
+	if !pixelType.Valid() {
+		return &PixelValidationError{Field: "pixel_type", Value: string(pixelType), Reason: "is not a supported pixel type"}
+	}
+	return nil
+}
+
+// Age returns how long ago the pixel was created, or zero if the API did
+// not report a creation time.
+func (p Pixel) Age(now time.Time) time.Duration {
+	if p.CreatedAt.IsZero() {
+		return 0
+	}
+	return now.Sub(p.CreatedAt.Time)
+}
+
+// LastUpdated returns when the pixel was last changed, falling back to its
+// creation time when the API did not report an update.
+func (p Pixel) LastUpdated() time.Time {
+	if !p.UpdatedAt.IsZero() {
+		return p.UpdatedAt.Time
+	}
+	return p.CreatedAt.Time
+}
+
+// SinceUpdate returns how long ago the pixel was last changed, or zero if
+// neither timestamp is known.
+func (p Pixel) SinceUpdate(now time.Time) time.Duration {
+	last := p.LastUpdated()
+	if last.IsZero() {
+		return 0
+	}
+	return now.Sub(last)
+}
+
+// listPixels retrieves every pixel across all pages.
+func (c *Client) listPixels(ctx context.Context) ([]Pixel, error) {
+	return c.ListAllPixels(ctx, ListOptions{})
+}
+
+// ListPixelsByType returns every pixel of the given type. The pixel list
+// endpoint has no type filter, so pixels are filtered client-side.
+func (c *Client) ListPixelsByType(ctx context.Context, pixelType PixelType) ([]Pixel, error) {
+	pixels, err := c.listPixels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var matched []Pixel
+	for _, p := range pixels {
+		if p.PixelType == pixelType {
+			matched = append(matched, p)
+		}
+	}
+	return matched, nil
+}
+
+// findPixel returns the pixel with the given type and platform pixel ID.
+func findPixel(pixels []Pixel, pixelType PixelType, pixelID string) (*Pixel, bool) {
+	for i := range pixels {
+		if pixels[i].PixelType == pixelType && pixels[i].PixelID == pixelID {
+			return &pixels[i], true
+		}
+	}
+	return nil, false
+}
+
+// GetOrCreatePixel returns the pixel with reqData's type and platform pixel
+// ID, creating it if none exists. The name of an existing pixel is left
+// unchanged. If the API rejects a concurrent duplicate, the existing pixel
+// is fetched and returned instead.
+func (c *Client) GetOrCreatePixel(ctx context.Context, reqData PixelCreateRequest) (*Pixel, error) {
+	pixels, err := c.listPixels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := findPixel(pixels, reqData.PixelType, reqData.PixelID); ok {
+		return p, nil
+	}
+	pixel, err := c.createPixel(ctx, reqData)
+	if err == nil && c.pixels != nil {
+		c.pixels.Add(*pixel)
+	}
+	if err == nil || !isConflict(err) {
+		return pixel, err
+	}
+	pixels, listErr := c.listPixels(ctx)
+	if listErr != nil {
+		return nil, err
+	}
+	if p, ok := findPixel(pixels, reqData.PixelType, reqData.PixelID); ok {
+		return p, nil
+	}
+	return nil, err
+}
+
+// CreatePixelsOptions configures CreatePixels.
+type CreatePixelsOptions struct {
+	// Concurrency bounds the parallel requests. Defaults to 4.
+	Concurrency int
+}
+
+// PixelCreateResult is the outcome of creating one pixel. Existing is set
+// when a pixel with the same type and platform pixel ID was already in the
+// account or earlier in the batch, in which case Pixel is that pixel.
+type PixelCreateResult struct {
+	Pixel    *Pixel `json:"pixel,omitempty"`
+	Created  bool   `json:"created"`
+	Existing bool   `json:"existing"`
+	Err      error  `json:"-"`
+	Error    string `json:"error,omitempty"`
+}
+
+// pixelKey identifies a pixel by its type and platform pixel ID.
+type pixelKey struct {
+	Type PixelType
+	ID   string
+}
+
+// CreatePixels creates several pixels in parallel, skipping any whose type
+// and platform pixel ID already exist in the account or repeat earlier in
+// reqs. A failure on one pixel is recorded in its result and does not stop
+// the others. The results are index-aligned with reqs; the error is set if
+// the existing pixels cannot be listed or ctx is done, and in the latter
+// case it is also recorded in every result whose request never ran.
+func (c *Client) CreatePixels(ctx context.Context, reqs []PixelCreateRequest, opts CreatePixelsOptions) ([]PixelCreateResult, error) {
+	pixels, err := c.listPixels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]PixelCreateResult, len(reqs))
+	first := map[pixelKey]int{}
+	var pending []int
+	for i, req := range reqs {
+		key := pixelKey{req.PixelType, req.PixelID}
+		if p, ok := findPixel(pixels, key.Type, key.ID); ok {
+			results[i] = PixelCreateResult{Pixel: p, Existing: true}
+			continue
+		}
+		if _, ok := first[key]; ok {
+			continue
+		}
+		first[key] = i
+		pending = append(pending, i)
+	}
+	err = forEachLimit(ctx, len(pending), opts.Concurrency, func(ctx context.Context, n int) error {
+		i := pending[n]
+		pixel, err := c.createPixel(ctx, reqs[i])
+		if err != nil {
+			results[i].Err, results[i].Error = err, err.Error()
+			return nil
+		}
+		results[i] = PixelCreateResult{Pixel: pixel, Created: true}
+		return nil
+	})
+	if err != nil {
+		for _, i := range pending {
+			if results[i].Pixel == nil && results[i].Err == nil {
+				results[i].Err, results[i].Error = err, err.Error()
+			}
+		}
+	}
+	for i, req := range reqs {
+		j, ok := first[pixelKey{req.PixelType, req.PixelID}]
+		if !ok || j == i {
+			continue
+		}
+		results[i] = PixelCreateResult{Pixel: results[j].Pixel, Existing: results[j].Pixel != nil, Err: results[j].Err, Error: results[j].Error}
+	}
+	return results, err
+}
+
+// PixelUsage returns every link the pixel is attached to, following
+// pagination, so a pixel can be checked before it is retired.
+func (c *Client) PixelUsage(ctx context.Context, pixelID int) ([]ShortLink, error) {
+	return c.ListAllShortLinks(ctx, ShortLinkListOptions{PixelIDs: []int{pixelID}})
+}
+
+// PixelLinkCount is a pixel and the number of links carrying it.
+type PixelLinkCount struct {
+	Pixel Pixel `json:"pixel"`
+	Links int   `json:"links"`
+}
+
+// PixelUsageCounts counts the links carrying each pixel, with one filtered
+// list request per pixel, and returns the pixels by descending count, then
+// name.
+func (c *Client) PixelUsageCounts(ctx context.Context, opts UsageOptions) ([]PixelLinkCount, error) {
+	pixels, err := c.listPixels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := c.countLinks(ctx, len(pixels), opts.Concurrency, func(i int) ShortLinkListOptions {
+		return ShortLinkListOptions{PixelIDs: []int{pixels[i].ID}}
+	})
+	if err != nil {
+		return nil, err
+	}
+	usage := make([]PixelLinkCount, len(pixels))
+	for i, p := range pixels {
+		usage[i] = PixelLinkCount{Pixel: p, Links: counts[i]}
+	}
+	sort.SliceStable(usage, func(i, j int) bool {
+		if usage[i].Links != usage[j].Links {
+			return usage[i].Links > usage[j].Links
+		}
+		return usage[i].Pixel.Name < usage[j].Pixel.Name
+	})
+	return usage, nil
+}
+
+// PixelInUseError is returned by DeletePixelSafely when the pixel is still
+// attached to links and neither Force nor DetachFirst was requested.
+type PixelInUseError struct {
+	PixelID int
+	Links   int
+}
+
+func (e *PixelInUseError) Error() string {
+	return fmt.Sprintf("tly: pixel %d is still attached to %d links", e.PixelID, e.Links)
+}
+
+// DeletePixelOptions configures DeletePixelSafely.
+type DeletePixelOptions struct {
+	// Force deletes the pixel even if links still use it, dropping it
+	// from those links.
+	Force bool
+	// DetachFirst removes the pixel from every link that uses it before
+	// deleting it.
+	DetachFirst bool
+	// Concurrency bounds the parallel detach requests. Defaults to 4.
+	Concurrency int
+}
+
+// DeletePixelSafely deletes a pixel without silently dropping tracking from
+// the links that carry it: unless opts.Force or opts.DetachFirst is set it
+// refuses with a *PixelInUseError. With DetachFirst the pixel is deleted
+// only after it has been removed from every link.
+func (c *Client) DeletePixelSafely(ctx context.Context, id int, opts DeletePixelOptions) error {
+	switch {
+	case opts.Force:
+	case opts.DetachFirst:
+		links, err := c.PixelUsage(ctx, id)
+		if err != nil {
+			return err
+		}
+		err = forEachLimit(ctx, len(links), opts.Concurrency, func(ctx context.Context, i int) error {
+			_, err := c.DetachPixelFromLink(ctx, links[i].ShortURL, id)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	default:
+		page, err := c.ListShortLinksPage(ctx, ShortLinkListOptions{PixelIDs: []int{id}})
+		if err != nil {
+			return err
+		}
+		if n := page.count(); n > 0 {
+			return &PixelInUseError{PixelID: id, Links: n}
+		}
+	}
+	return c.deletePixel(ctx, id)
+}
+
+// RenamePixel changes the display name of a pixel. The update endpoint
+// requires the pixel ID and type as well, so they are read from the
+// current pixel and sent back unchanged. Renaming a pixel to its current
+// name is a no-op that returns the pixel without updating it.
+func (c *Client) RenamePixel(ctx context.Context, id int, newName string) (*Pixel, error) {
+	pixel, err := c.getPixel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pixel.Name == newName {
+		return pixel, nil
+	}
+	renamed, err := c.updatePixel(ctx, PixelUpdateRequest{
+		ID:        pixel.ID,
+		Name:      newName,
+		PixelID:   pixel.PixelID,
+		PixelType: pixel.PixelType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if c.pixels != nil {
+		c.pixels.Add(*renamed)
+	}
+	return renamed, nil
+}
@@ -0,0 +1,109 @@
+package tly_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+	"github.com/timleland/t.ly-go-url-shortener-api/tlytest"
+)
+
+func TestPixelValidation(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	defer srv.Close()
+	c := tly.NewClient("test-key")
+	c.BaseURL = srv.URL
+
+	tests := []struct {
+		name  string
+		call  func() error
+		field string // of the PixelValidationError, or "" for a sent request
+	}{
+		{"create supported type", func() error {
+			_, err := c.CreatePixel(tly.PixelCreateRequest{Name: "fb", PixelID: "123", PixelType: tly.PixelTypeFacebook})
+			return err
+		}, ""},
+		{"create unknown type", func() error {
+			_, err := c.CreatePixel(tly.PixelCreateRequest{Name: "x", PixelID: "123", PixelType: "threads"})
+			return err
+		}, "pixel_type"},
+		{"create without name", func() error {
+			_, err := c.CreatePixel(tly.PixelCreateRequest{PixelID: "123", PixelType: tly.PixelTypeFacebook})
+			return err
+		}, "name"},
+		{"update supported type", func() error {
+			_, err := c.UpdatePixel(tly.PixelUpdateRequest{ID: 1, Name: "x", PixelID: "123", PixelType: tly.PixelTypeReddit})
+			return err
+		}, ""},
+		{"update unknown type", func() error {
+			_, err := c.UpdatePixel(tly.PixelUpdateRequest{ID: 1, Name: "x", PixelID: "123", PixelType: "threads"})
+			return err
+		}, "pixel_type"},
+		{"update without pixel ID", func() error {
+			_, err := c.UpdatePixel(tly.PixelUpdateRequest{ID: 1, Name: "x", PixelType: tly.PixelTypeReddit})
+			return err
+		}, "pixel_id"},
+		{"validate unknown type", func() error {
+			return tly.ValidatePixelID("threads", "123")
+		}, "pixel_type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = 0
+			err := tt.call()
+			var invalid *tly.PixelValidationError
+			switch {
+			case tt.field == "" && (err != nil || requests != 1):
+				t.Errorf("err = %v after %d requests, want the request sent", err, requests)
+			case tt.field != "" && (!errors.As(err, &invalid) || invalid.Field != tt.field || requests != 0):
+				t.Errorf("err = %v after %d requests, want a PixelValidationError for %s", err, requests, tt.field)
+			}
+		})
+	}
+}
+
+func TestCreatePixelsCancelled(t *testing.T) {
+	srv := tlytest.NewServer(tlytest.Options{})
+	defer srv.Close()
+	c := srv.Client()
+	srv.Inject("POST /api/v1/link/pixel", tlytest.Fault{Latency: 200 * time.Millisecond, Times: -1})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	reqs := []tly.PixelCreateRequest{
+		{Name: "a", PixelID: "1", PixelType: tly.PixelTypeFacebook},
+		{Name: "b", PixelID: "2", PixelType: tly.PixelTypeFacebook},
+		{Name: "c", PixelID: "3", PixelType: tly.PixelTypeFacebook},
+		{Name: "c again", PixelID: "3", PixelType: tly.PixelTypeFacebook},
+	}
+	results, err := c.CreatePixels(ctx, reqs, tly.CreatePixelsOptions{Concurrency: 1})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("CreatePixels error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	for i, r := range results {
+		if r.Pixel != nil || r.Err == nil {
+			t.Errorf("results[%d] = %+v, want a failure", i, r)
+			continue
+		}
+		data, err := json.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var report struct{ Error string }
+		if err := json.Unmarshal(data, &report); err != nil || report.Error != r.Err.Error() {
+			t.Errorf("results[%d] as JSON = %s, want the error %q", i, data, r.Err)
+		}
+	}
+}
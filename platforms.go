@@ -0,0 +1,149 @@
+package tly
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DeviceClass is the broad kind of device a click came from.
+type DeviceClass string
+
+// Device classes reported by PlatformStats.
+const (
+	DeviceMobile  DeviceClass = "mobile"
+	DeviceTablet  DeviceClass = "tablet"
+	DeviceDesktop DeviceClass = "desktop"
+	DeviceOther   DeviceClass = "other"
+)
+
+// platformRule maps platform names containing the words of match to an OS
+// family and device class.
+type platformRule struct {
+	match  string
+	family string
+	device DeviceClass
+}
+
+// platformRules is the normalization table for raw platform names. Rules
+// match whole words of the lowercased name, so "KaiOS" is not iOS, and are
+// checked in order, so more specific matches come first.
+var platformRules = []platformRule{
+	{"ipad", "iOS", DeviceTablet},
+	{"iphone", "iOS", DeviceMobile},
+	{"ipod", "iOS", DeviceMobile},
+	{"ios", "iOS", DeviceMobile},
+	{"android tablet", "Android", DeviceTablet},
+	{"kindle", "Fire OS", DeviceTablet},
+	{"fire os", "Fire OS", DeviceTablet},
+	{"android", "Android", DeviceMobile},
+	{"windows phone", "Windows Phone", DeviceMobile},
+	{"windows", "Windows", DeviceDesktop},
+	{"mac", "macOS", DeviceDesktop},
+	{"macos", "macOS", DeviceDesktop},
+	{"macintosh", "macOS", DeviceDesktop},
+	{"os x", "macOS", DeviceDesktop},
+	{"chrome os", "ChromeOS", DeviceDesktop},
+	{"chromeos", "ChromeOS", DeviceDesktop},
+	{"cros", "ChromeOS", DeviceDesktop},
+	{"linux", "Linux", DeviceDesktop},
+	{"ubuntu", "Linux", DeviceDesktop},
+	{"fedora", "Linux", DeviceDesktop},
+	{"debian", "Linux", DeviceDesktop},
+	{"freebsd", "BSD", DeviceDesktop},
+	{"openbsd", "BSD", DeviceDesktop},
+	{"blackberry", "BlackBerry", DeviceMobile},
+	{"tizen", "Tizen", DeviceMobile},
+	{"kaios", "KaiOS", DeviceMobile},
+}
+
+// NormalizePlatform maps a raw platform name to an OS family and device
+// class. Unknown platforms keep their name as the family and are classed
+// as DeviceOther.
+func NormalizePlatform(platform string) (family string, device DeviceClass) {
+	words := platformWords(platform)
+	for _, r := range platformRules {
+		if containsWords(words, platformWords(r.match)) {
+			return r.family, r.device
+		}
+	}
+	if platform == "" {
+		return "Unknown", DeviceOther
+	}
+	return platform, DeviceOther
+}
+
+// platformWords splits a platform name into lowercased words of letters and
+// digits.
+func platformWords(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsWords reports whether match appears as consecutive words of
+// words.
+func containsWords(words, match []string) bool {
+	for i := 0; i+len(match) <= len(words); i++ {
+		found := true
+		for j, w := range match {
+			if words[i+j] != w {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// PlatformClicks is one row of the platforms breakdown with its normalized
+// OS family and device class.
+type PlatformClicks struct {
+	Platform string      `json:"platform"`
+	OSFamily string      `json:"os_family"`
+	Device   DeviceClass `json:"device"`
+	Total    int         `json:"total"`
+}
+
+// PlatformStats returns the platforms breakdown as typed, normalized rows.
+func (s *Stats) PlatformStats() []PlatformClicks {
+	rows := s.PlatformBreakdown()
+	result := make([]PlatformClicks, 0, len(rows))
+	for _, row := range rows {
+		family, device := NormalizePlatform(row.Name)
+		result = append(result, PlatformClicks{Platform: row.Name, OSFamily: family, Device: device, Total: row.Total})
+	}
+	return result
+}
+
+// OSFamilyTotals sums the platforms breakdown by OS family, ordered by
+// descending clicks.
+func (s *Stats) OSFamilyTotals() []BreakdownItem {
+	totals := map[string]int{}
+	for _, p := range s.PlatformStats() {
+		totals[p.OSFamily] += p.Total
+	}
+	items := make([]BreakdownItem, 0, len(totals))
+	for name, total := range totals {
+		items = append(items, BreakdownItem{Name: name, Total: total})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Total == items[j].Total {
+			return items[i].Name < items[j].Name
+		}
+		return items[i].Total > items[j].Total
+	})
+	return items
+}
+
+// DeviceTotals sums the platforms breakdown by device class.
+func (s *Stats) DeviceTotals() map[DeviceClass]int {
+	totals := map[DeviceClass]int{}
+	for _, p := range s.PlatformStats() {
+		totals[p.Device] += p.Total
+	}
+	return totals
+}
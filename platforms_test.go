@@ -0,0 +1,37 @@
+package tly
+
+import "testing"
+
+func TestNormalizePlatform(t *testing.T) {
+	tests := []struct {
+		platform string
+		family   string
+		device   DeviceClass
+	}{
+		{"iOS", "iOS", DeviceMobile},
+		{"iPad", "iOS", DeviceTablet},
+		{"iPhone OS 16", "iOS", DeviceMobile},
+		{"KaiOS", "KaiOS", DeviceMobile},
+		{"Android", "Android", DeviceMobile},
+		{"Android Tablet", "Android", DeviceTablet},
+		{"Kindle Fire", "Fire OS", DeviceTablet},
+		{"Windows Phone", "Windows Phone", DeviceMobile},
+		{"Windows 10", "Windows", DeviceDesktop},
+		{"Mac OS X", "macOS", DeviceDesktop},
+		{"macOS", "macOS", DeviceDesktop},
+		{"Macintosh", "macOS", DeviceDesktop},
+		{"Chrome OS", "ChromeOS", DeviceDesktop},
+		{"CrOS", "ChromeOS", DeviceDesktop},
+		{"Microsoft", "Microsoft", DeviceOther},
+		{"Machine", "Machine", DeviceOther},
+		{"Ubuntu Linux", "Linux", DeviceDesktop},
+		{"webOS", "webOS", DeviceOther},
+		{"", "Unknown", DeviceOther},
+	}
+	for _, tt := range tests {
+		family, device := NormalizePlatform(tt.platform)
+		if family != tt.family || device != tt.device {
+			t.Errorf("NormalizePlatform(%q) = %q, %q; want %q, %q", tt.platform, family, device, tt.family, tt.device)
+		}
+	}
+}
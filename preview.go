@@ -0,0 +1,147 @@
+package tly
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// PreviewSource says where a Preview came from.
+type PreviewSource string
+
+// Preview sources.
+const (
+	// PreviewFromAPI means T.LY unfurled the page.
+	PreviewFromAPI PreviewSource = "api"
+	// PreviewScraped means the page was fetched and parsed locally.
+	PreviewScraped PreviewSource = "scraped"
+)
+
+// Preview is the unfurled title, description and image of a page.
+type Preview struct {
+	URL         string        `json:"url"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	Image       string        `json:"image"`
+	SiteName    string        `json:"site_name"`
+	Source      PreviewSource `json:"source"`
+}
+
+// maxPreviewBody caps how much of a page the scraping fallback reads. The
+// metadata lives in the head, so this is plenty.
+const maxPreviewBody = 1 << 20
+
+// GetLinkPreview unfurls destination, which may be a short link or any
+// other URL. It asks the API first; if the plan has no preview endpoint it
+// fetches the page and reads its Open Graph, Twitter card and plain HTML
+// metadata instead.
+func (c *Client) GetLinkPreview(ctx context.Context, destination string) (*Preview, error) {
+	query := url.Values{"url": {destination}}.Encode()
+	var preview Preview
+	err := c.doRequestContext(ctx, "GET", "/api/v1/link/preview", query, nil, &preview)
+	if IsNotFound(err) {
+		return c.scrapePreview(ctx, destination)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if preview.URL == "" {
+		preview.URL = destination
+	}
+	preview.Source = PreviewFromAPI
+	return &preview, nil
+}
+
+// scrapePreview fetches rawURL, following redirects, and builds a Preview
+// from the page's metadata.
+func (c *Client) scrapePreview(ctx context.Context, rawURL string) (*Preview, error) {
+	hc := http.DefaultClient
+	if c.Client != nil {
+		hc = c.Client
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("tly: preview of %s: %s", rawURL, resp.Status)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxPreviewBody))
+	if err != nil {
+		return nil, err
+	}
+	p := parsePreview(string(body))
+	p.URL = resp.Request.URL.String()
+	p.Source = PreviewScraped
+	if p.Image != "" {
+		if ref, err := url.Parse(p.Image); err == nil {
+			p.Image = resp.Request.URL.ResolveReference(ref).String()
+		}
+	}
+	return p, nil
+}
+
+var (
+	metaTagRe    = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	attrRe       = regexp.MustCompile(`(?s)([a-zA-Z:-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	titleTagRe   = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	whitespaceRe = regexp.MustCompile(`\s+`)
+)
+
+// parsePreview reads the preview metadata of an HTML page. Open Graph tags
+// win over Twitter card tags, which win over <title> and the description
+// meta tag.
+func parsePreview(page string) *Preview {
+	meta := map[string]string{}
+	for _, tag := range metaTagRe.FindAllString(page, -1) {
+		attrs := map[string]string{}
+		for _, m := range attrRe.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(m[1])] = m[2] + m[3] + m[4]
+		}
+		key := attrs["property"]
+		if key == "" {
+			key = attrs["name"]
+		}
+		key = strings.ToLower(key)
+		if _, seen := meta[key]; key != "" && !seen {
+			meta[key] = cleanText(attrs["content"])
+		}
+	}
+	first := func(keys ...string) string {
+		for _, k := range keys {
+			if v := meta[k]; v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+	p := &Preview{
+		Title:       first("og:title", "twitter:title"),
+		Description: first("og:description", "twitter:description", "description"),
+		Image:       first("og:image", "og:image:url", "twitter:image", "twitter:image:src"),
+		SiteName:    first("og:site_name", "application-name"),
+	}
+	if p.Title == "" {
+		if m := titleTagRe.FindStringSubmatch(page); m != nil {
+			p.Title = cleanText(m[1])
+		}
+	}
+	return p
+}
+
+// cleanText unescapes HTML entities and collapses whitespace.
+func cleanText(s string) string {
+	return strings.TrimSpace(whitespaceRe.ReplaceAllString(html.UnescapeString(s), " "))
+}
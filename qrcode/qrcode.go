@@ -0,0 +1,501 @@
+// Package qrcode encodes short text such as short links as QR codes and
+// renders them as PNG or SVG.
+//
+// It implements the subset of ISO/IEC 18004 needed for URLs: byte mode at
+// error correction level M (about 15% of the code may be damaged) in
+// versions 1 to 10, which holds up to 213 bytes. It has no dependencies
+// outside the standard library.
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// ErrTooLong is returned for text that does not fit in a version 10 code.
+var ErrTooLong = errors.New("qrcode: text too long")
+
+// Code is an encoded QR code.
+type Code struct {
+	// Version is the QR version, 1 to 10; the code has 17+4*Version
+	// modules per side.
+	Version int
+	size    int
+	modules [][]bool
+}
+
+// quietZone is the light border, in modules, that readers need around a
+// code.
+const quietZone = 4
+
+// Size returns the number of modules per side, without the quiet zone.
+func (c *Code) Size() int { return c.size }
+
+// Dark reports whether the module at column x and row y is dark.
+func (c *Code) Dark(x, y int) bool { return c.modules[y][x] }
+
+// versionInfo is the block structure of a version at level M.
+type versionInfo struct {
+	codewords  int // total codewords
+	blocks     int // error correction blocks
+	ecPerBlock int // error correction codewords per block
+	align      []int
+}
+
+// versions describes versions 1 to 10 at error correction level M.
+var versions = []versionInfo{
+	1:  {26, 1, 10, nil},
+	2:  {44, 1, 16, []int{6, 18}},
+	3:  {70, 1, 26, []int{6, 22}},
+	4:  {100, 2, 18, []int{6, 26}},
+	5:  {134, 2, 24, []int{6, 30}},
+	6:  {172, 4, 16, []int{6, 34}},
+	7:  {196, 4, 18, []int{6, 22, 38}},
+	8:  {242, 4, 22, []int{6, 24, 42}},
+	9:  {292, 5, 22, []int{6, 26, 46}},
+	10: {346, 5, 26, []int{6, 28, 50}},
+}
+
+// dataCodewords returns the number of data codewords of v.
+func (v versionInfo) dataCodewords() int {
+	return v.codewords - v.blocks*v.ecPerBlock
+}
+
+// Encode encodes text in the smallest version that holds it.
+func Encode(text string) (*Code, error) {
+	data := []byte(text)
+	for version := 1; version < len(versions); version++ {
+		countBits := 8
+		if version >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+8*len(data) <= 8*versions[version].dataCodewords() {
+			return encode(data, version, countBits), nil
+		}
+	}
+	return nil, ErrTooLong
+}
+
+// bitBuffer accumulates bits most significant first.
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, value>>uint(i)&1 == 1)
+	}
+}
+
+// encode builds the code of data in version.
+func encode(data []byte, version, countBits int) *Code {
+	info := versions[version]
+	capacity := 8 * info.dataCodewords()
+	var bits bitBuffer
+	bits.append(0x4, 4) // byte mode
+	bits.append(len(data), countBits)
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	for i := 0; i < 4 && len(bits) < capacity; i++ {
+		bits = append(bits, false)
+	}
+	for len(bits)%8 != 0 {
+		bits = append(bits, false)
+	}
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+	codewords := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			codewords[i/8] |= 0x80 >> uint(i%8)
+		}
+	}
+
+	c := &Code{Version: version, size: 17 + 4*version}
+	m := newMatrix(c.size)
+	m.drawFunctionPatterns(version, info.align)
+	m.drawCodewords(interleave(codewords, info))
+	best, bestPenalty := -1, 0
+	for mask := 0; mask < 8; mask++ {
+		m.applyMask(mask)
+		m.drawFormatBits(mask)
+		if p := m.penalty(); best < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		m.applyMask(mask)
+	}
+	m.applyMask(best)
+	m.drawFormatBits(best)
+	c.modules = m.dark
+	return c
+}
+
+// interleave splits data into the blocks of info, appends each block's
+// error correction codewords and interleaves the result.
+func interleave(data []byte, info versionInfo) []byte {
+	short := len(data) / info.blocks
+	long := len(data) % info.blocks
+	gen := rsGenerator(info.ecPerBlock)
+	var blocks, ecs [][]byte
+	for i, k := 0, 0; i < info.blocks; i++ {
+		n := short
+		if i >= info.blocks-long {
+			n++
+		}
+		blocks = append(blocks, data[k:k+n])
+		ecs = append(ecs, rsRemainder(data[k:k+n], gen))
+		k += n
+	}
+	var out []byte
+	for i := 0; i <= short; i++ {
+		for _, b := range blocks {
+			if i < len(b) {
+				out = append(out, b[i])
+			}
+		}
+	}
+	for i := 0; i < info.ecPerBlock; i++ {
+		for _, ec := range ecs {
+			out = append(out, ec[i])
+		}
+	}
+	return out
+}
+
+// gfMul multiplies in GF(2^8) modulo the QR polynomial 0x11D.
+func gfMul(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>uint(i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+// rsGenerator returns the Reed-Solomon generator polynomial of degree n,
+// highest coefficient first, without its leading 1.
+func rsGenerator(n int) []byte {
+	gen := make([]byte, n)
+	gen[n-1] = 1
+	root := byte(1)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			gen[j] = gfMul(gen[j], root)
+			if j+1 < n {
+				gen[j] ^= gen[j+1]
+			}
+		}
+		root = gfMul(root, 2)
+	}
+	return gen
+}
+
+// rsRemainder returns the error correction codewords of data.
+func rsRemainder(data, gen []byte) []byte {
+	rem := make([]byte, len(gen))
+	for _, b := range data {
+		factor := b ^ rem[0]
+		copy(rem, rem[1:])
+		rem[len(rem)-1] = 0
+		for i, g := range gen {
+			rem[i] ^= gfMul(g, factor)
+		}
+	}
+	return rem
+}
+
+// matrix is a code being built. Function modules (finders, timing,
+// alignment, format and version information) are not masked.
+type matrix struct {
+	size     int
+	dark     [][]bool
+	function [][]bool
+}
+
+func newMatrix(size int) *matrix {
+	m := &matrix{size: size}
+	for i := 0; i < size; i++ {
+		m.dark = append(m.dark, make([]bool, size))
+		m.function = append(m.function, make([]bool, size))
+	}
+	return m
+}
+
+// setFunction sets the function module at column x and row y.
+func (m *matrix) setFunction(x, y int, dark bool) {
+	m.dark[y][x] = dark
+	m.function[y][x] = true
+}
+
+func (m *matrix) drawFunctionPatterns(version int, align []int) {
+	for i := 0; i < m.size; i++ {
+		m.setFunction(6, i, i%2 == 0)
+		m.setFunction(i, 6, i%2 == 0)
+	}
+	m.drawFinder(3, 3)
+	m.drawFinder(m.size-4, 3)
+	m.drawFinder(3, m.size-4)
+	last := len(align) - 1
+	for i, x := range align {
+		for j, y := range align {
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue
+			}
+			m.drawAlignment(x, y)
+		}
+	}
+	m.drawFormatBits(0)
+	if version >= 7 {
+		rem := version
+		for i := 0; i < 12; i++ {
+			rem = rem<<1 ^ (rem>>11)*0x1F25
+		}
+		bits := version<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := bits>>uint(i)&1 == 1
+			a, b := m.size-11+i%3, i/3
+			m.setFunction(a, b, dark)
+			m.setFunction(b, a, dark)
+		}
+	}
+}
+
+// drawFinder draws a finder pattern and its separator centred on x, y.
+func (m *matrix) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= m.size || yy < 0 || yy >= m.size {
+				continue
+			}
+			d := max(abs(dx), abs(dy))
+			m.setFunction(xx, yy, d != 2 && d != 4)
+		}
+	}
+}
+
+// drawAlignment draws an alignment pattern centred on x, y.
+func (m *matrix) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			m.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// drawFormatBits draws both copies of the format information for level M
+// and mask.
+func (m *matrix) drawFormatBits(mask int) {
+	data := mask // level M is 00
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>uint(i)&1 == 1 }
+	for i := 0; i <= 5; i++ {
+		m.setFunction(8, i, bit(i))
+	}
+	m.setFunction(8, 7, bit(6))
+	m.setFunction(8, 8, bit(7))
+	m.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		m.setFunction(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		m.setFunction(m.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.setFunction(8, m.size-15+i, bit(i))
+	}
+	m.setFunction(8, m.size-8, true)
+}
+
+// drawCodewords places data in the zigzag order of the standard, two
+// columns at a time from the bottom right, skipping function modules.
+func (m *matrix) drawCodewords(data []byte) {
+	i := 0
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < m.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if upward {
+					y = m.size - 1 - vert
+				}
+				if !m.function[y][x] && i < len(data)*8 {
+					m.dark[y][x] = data[i/8]>>uint(7-i%8)&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask inverts the data modules selected by mask. Applying a mask
+// twice undoes it.
+func (m *matrix) applyMask(mask int) {
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !m.function[y][x] {
+				m.dark[y][x] = !m.dark[y][x]
+			}
+		}
+	}
+}
+
+// finderLike is the 1:1:3:1:1 pattern with four light modules that the
+// third penalty rule looks for, in both directions.
+var finderLike = [][]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+// penalty scores how hard the code is to read; the mask with the lowest
+// score is used.
+func (m *matrix) penalty() int {
+	n := m.size
+	at := func(x, y int, transpose bool) bool {
+		if transpose {
+			return m.dark[x][y]
+		}
+		return m.dark[y][x]
+	}
+	score, dark := 0, 0
+	for _, transpose := range []bool{false, true} {
+		for y := 0; y < n; y++ {
+			run := 1
+			for x := 1; x <= n; x++ {
+				if x < n && at(x, y, transpose) == at(x-1, y, transpose) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += 3 + run - 5
+				}
+				run = 1
+			}
+			for x := 0; x+11 <= n; x++ {
+				for _, p := range finderLike {
+					match := true
+					for k, d := range p {
+						if at(x+k, y, transpose) != d {
+							match = false
+							break
+						}
+					}
+					if match {
+						score += 40
+					}
+				}
+			}
+		}
+	}
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			if m.dark[y][x] {
+				dark++
+			}
+			if x+1 < n && y+1 < n {
+				d := m.dark[y][x]
+				if m.dark[y][x+1] == d && m.dark[y+1][x] == d && m.dark[y+1][x+1] == d {
+					score += 3
+				}
+			}
+		}
+	}
+	percent := dark * 100 / (n * n)
+	score += abs(percent-50) / 5 * 10
+	return score
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Image returns the code with its quiet zone as a size by size pixel
+// image. Modules are scaled by a whole number of pixels and centred, so
+// the border may be slightly wider than the quiet zone. size is raised to
+// one pixel per module if smaller.
+func (c *Code) Image(size int) image.Image {
+	total := c.size + 2*quietZone
+	if size < total {
+		size = total
+	}
+	scale := size / total
+	offset := (size-scale*total)/2 + quietZone*scale
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{color.White, color.Black})
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if !c.modules[y][x] {
+				continue
+			}
+			for py := 0; py < scale; py++ {
+				for px := 0; px < scale; px++ {
+					img.SetColorIndex(offset+x*scale+px, offset+y*scale+py, 1)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// WritePNG writes the code as a size by size PNG image.
+func (c *Code) WritePNG(w io.Writer, size int) error {
+	return png.Encode(w, c.Image(size))
+}
+
+// WriteSVG writes the code as an SVG image size pixels wide. SVG scales
+// without loss, so size only sets the default display size.
+func (c *Code) WriteSVG(w io.Writer, size int) error {
+	total := c.size + 2*quietZone
+	var path strings.Builder
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if c.modules[y][x] {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x+quietZone, y+quietZone)
+			}
+		}
+	}
+	_, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+
+		`<rect width="100%%" height="100%%" fill="#fff"/><path fill="#000" d="%s"/></svg>`+"\n",
+		size, size, total, total, path.String())
+	return err
+}
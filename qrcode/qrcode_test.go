@@ -0,0 +1,275 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestEncodeVersion(t *testing.T) {
+	tests := []struct {
+		length  int
+		version int
+	}{
+		{0, 1},
+		{14, 1},
+		{15, 2},
+		{26, 2},
+		{27, 3},
+		{180, 9},
+		{181, 10},
+		{213, 10},
+	}
+	for _, tt := range tests {
+		c, err := Encode(strings.Repeat("a", tt.length))
+		if err != nil {
+			t.Errorf("Encode(%d bytes): %v", tt.length, err)
+			continue
+		}
+		if c.Version != tt.version || c.Size() != 17+4*tt.version {
+			t.Errorf("Encode(%d bytes) = version %d, size %d; want version %d", tt.length, c.Version, c.Size(), tt.version)
+		}
+	}
+	if _, err := Encode(strings.Repeat("a", 214)); err != ErrTooLong {
+		t.Errorf("Encode(214 bytes) error = %v, want ErrTooLong", err)
+	}
+}
+
+func TestReedSolomon(t *testing.T) {
+	// The "HELLO WORLD" example of a version 1-M code from the standard.
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsGenerator(10)); !bytes.Equal(got, want) {
+		t.Errorf("error correction = %v, want %v", got, want)
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"https://t.ly/abc",
+		"https://example.com/a/rather/long/path?with=query&and=more#fragment",
+		strings.Repeat("0123456789", 21) + "xyz",
+	} {
+		c, err := Encode(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := decode(t, c); got != text {
+			t.Errorf("decoded version %d code = %q, want %q", c.Version, got, text)
+		}
+	}
+}
+
+// decode reads the text back from c, checking the fixed patterns, the
+// format information and the error correction on the way.
+func decode(t *testing.T, c *Code) string {
+	t.Helper()
+	n := c.Size()
+	for _, corner := range [][2]int{{0, 0}, {n - 7, 0}, {0, n - 7}} {
+		for dy := 0; dy < 7; dy++ {
+			for dx := 0; dx < 7; dx++ {
+				d := max(abs(dx-3), abs(dy-3))
+				if c.Dark(corner[0]+dx, corner[1]+dy) != (d != 2) {
+					t.Fatalf("finder at %v is broken at %d,%d", corner, dx, dy)
+				}
+			}
+		}
+	}
+	for i := 8; i < n-8; i++ {
+		if c.Dark(i, 6) != (i%2 == 0) || c.Dark(6, i) != (i%2 == 0) {
+			t.Fatalf("timing pattern is broken at %d", i)
+		}
+	}
+	if !c.Dark(8, n-8) {
+		t.Fatal("dark module is missing")
+	}
+
+	var first, second int
+	for i := 0; i < 15; i++ {
+		var a, b bool
+		switch {
+		case i <= 5:
+			a = c.Dark(8, i)
+		case i == 6:
+			a = c.Dark(8, 7)
+		case i == 7:
+			a = c.Dark(8, 8)
+		case i == 8:
+			a = c.Dark(7, 8)
+		default:
+			a = c.Dark(14-i, 8)
+		}
+		if i < 8 {
+			b = c.Dark(n-1-i, 8)
+		} else {
+			b = c.Dark(8, n-15+i)
+		}
+		if a {
+			first |= 1 << uint(i)
+		}
+		if b {
+			second |= 1 << uint(i)
+		}
+	}
+	if first != second {
+		t.Fatalf("format copies differ: %015b and %015b", first, second)
+	}
+	mask := (first ^ 0x5412) >> 10
+	if mask > 7 {
+		t.Fatalf("format %015b is not level M", first)
+	}
+
+	info := versions[c.Version]
+	m := newMatrix(n)
+	m.drawFunctionPatterns(c.Version, info.align)
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			if !m.function[y][x] {
+				m.dark[y][x] = c.Dark(x, y)
+			}
+		}
+	}
+	m.applyMask(mask)
+	var bits bitBuffer
+	for right := n - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < n; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = n - 1 - vert
+				}
+				if !m.function[y][x] {
+					bits = append(bits, m.dark[y][x])
+				}
+			}
+		}
+	}
+	stream := make([]byte, info.codewords)
+	for i := range stream {
+		for _, bit := range bits[8*i : 8*i+8] {
+			stream[i] <<= 1
+			if bit {
+				stream[i] |= 1
+			}
+		}
+	}
+
+	short := info.dataCodewords() / info.blocks
+	long := info.dataCodewords() % info.blocks
+	blocks := make([][]byte, info.blocks)
+	k := 0
+	for i := 0; i <= short; i++ {
+		for b := range blocks {
+			if i < short || b >= info.blocks-long {
+				blocks[b] = append(blocks[b], stream[k])
+				k++
+			}
+		}
+	}
+	gen := rsGenerator(info.ecPerBlock)
+	var data []byte
+	for b, block := range blocks {
+		ec := make([]byte, info.ecPerBlock)
+		for i := range ec {
+			ec[i] = stream[k+i*info.blocks+b]
+		}
+		if !bytes.Equal(rsRemainder(block, gen), ec) {
+			t.Fatalf("error correction of block %d does not match", b)
+		}
+		data = append(data, block...)
+	}
+
+	pos := 0
+	read := func(n int) int {
+		v := 0
+		for i := 0; i < n; i++ {
+			v <<= 1
+			if data[pos/8]>>uint(7-pos%8)&1 == 1 {
+				v |= 1
+			}
+			pos++
+		}
+		return v
+	}
+	if mode := read(4); mode != 0x4 {
+		t.Fatalf("mode = %#x, want byte mode", mode)
+	}
+	countBits := 8
+	if c.Version >= 10 {
+		countBits = 16
+	}
+	text := make([]byte, read(countBits))
+	for i := range text {
+		text[i] = byte(read(8))
+	}
+	return string(text)
+}
+
+func TestImage(t *testing.T) {
+	c, err := Encode("https://t.ly/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := c.Size() + 2*quietZone
+	tests := []struct {
+		size, want, scale int
+	}{
+		{0, total, 1},
+		{total, total, 1},
+		{3 * total, 3 * total, 3},
+		{3*total + 2, 3*total + 2, 3},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := c.WritePNG(&buf, tt.size); err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b := img.Bounds(); b.Dx() != tt.want || b.Dy() != tt.want {
+			t.Errorf("size %d: image is %v, want %dx%d", tt.size, b, tt.want, tt.want)
+			continue
+		}
+		offset := (tt.want-tt.scale*total)/2 + quietZone*tt.scale
+		if gray(img.At(0, 0)) != 0xFFFF || gray(img.At(offset-1, offset)) != 0xFFFF {
+			t.Errorf("size %d: quiet zone is not white", tt.size)
+		}
+		corner := offset + tt.scale - 1
+		if gray(img.At(offset, offset)) != 0 || gray(img.At(corner, corner)) != 0 {
+			t.Errorf("size %d: top left module is not black", tt.size)
+		}
+	}
+}
+
+func gray(c color.Color) uint32 {
+	r, _, _, _ := c.RGBA()
+	return r
+}
+
+func TestWriteSVG(t *testing.T) {
+	c, err := Encode("t.ly/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := c.WriteSVG(&buf, 256); err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+	for _, want := range []string{`width="256"`, `viewBox="0 0 29 29"`, "M4 4h1v1h-1z"} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG lacks %s:\n%s", want, svg)
+		}
+	}
+	if dark := strings.Count(svg, "h1v1h-1z"); dark == 0 || dark >= 21*21 {
+		t.Errorf("SVG has %d dark modules", dark)
+	}
+}
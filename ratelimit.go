@@ -0,0 +1,85 @@
+package tly
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit is the API rate limit state reported by the most recent
+// response. Reset is zero when the API did not report it.
+type RateLimit struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+	// Observed is when the response carrying these values arrived.
+	Observed time.Time `json:"observed"`
+}
+
+// Delay suggests how long to wait before the next request to spread the
+// remaining requests evenly until the window resets. When the limit is
+// exhausted and no reset time is known, it waits one minute, the API's
+// window length.
+func (r RateLimit) Delay(now time.Time) time.Duration {
+	if r.Limit == 0 {
+		return 0
+	}
+	reset := r.Reset
+	if reset.IsZero() {
+		if r.Remaining > 0 {
+			return 0
+		}
+		reset = r.Observed.Add(time.Minute)
+	}
+	left := reset.Sub(now)
+	if left <= 0 {
+		return 0
+	}
+	if r.Remaining <= 0 {
+		return left
+	}
+	return left / time.Duration(r.Remaining)
+}
+
+// observeRateLimit records the rate limit headers of a response, if any.
+func (c *Client) observeRateLimit(h http.Header, now time.Time) {
+	limit, err := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	r := RateLimit{Limit: limit, Observed: now}
+	r.Remaining, _ = strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		r.Reset = time.Unix(reset, 0)
+	} else if after, err := strconv.Atoi(h.Get("Retry-After")); err == nil {
+		r.Reset = now.Add(time.Duration(after) * time.Second)
+	}
+	c.rateMu.Lock()
+	c.rate = r
+	c.rateMu.Unlock()
+}
+
+// LastRateLimit returns the rate limit state seen on the most recent
+// response, without making a request. ok is false until a response with
+// rate limit headers has been received.
+func (c *Client) LastRateLimit() (r RateLimit, ok bool) {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+	return c.rate, c.rate.Limit > 0
+}
+
+// RateLimitStatus returns the current rate limit state. It reuses the
+// state of the last response when one was seen within the last second and
+// otherwise fetches the account profile to read fresh headers, which
+// itself counts against the limit.
+func (c *Client) RateLimitStatus(ctx context.Context) (RateLimit, error) {
+	if r, ok := c.LastRateLimit(); ok && time.Since(r.Observed) < time.Second {
+		return r, nil
+	}
+	if _, err := c.GetAccount(ctx); err != nil {
+		return RateLimit{}, err
+	}
+	r, _ := c.LastRateLimit()
+	return r, nil
+}
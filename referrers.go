@@ -0,0 +1,110 @@
+package tly
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// DefaultReferrerAliases maps registrable domains of link shorteners and
+// alternate brands to the site they belong to.
+var DefaultReferrerAliases = map[string]string{
+	"t.co":        "twitter.com",
+	"x.com":       "twitter.com",
+	"fb.me":       "facebook.com",
+	"fb.com":      "facebook.com",
+	"lnkd.in":     "linkedin.com",
+	"youtu.be":    "youtube.com",
+	"instagr.am":  "instagram.com",
+	"redd.it":     "reddit.com",
+	"pin.it":      "pinterest.com",
+	"goo.gl":      "google.com",
+	"g.co":        "google.com",
+	"bing.net":    "bing.com",
+	"whatsapp.me": "whatsapp.com",
+	"wa.me":       "whatsapp.com",
+}
+
+// multiLabelSuffixes are common public suffixes with more than one label.
+// Hosts under them keep one extra label in their registrable domain. The
+// list covers widely used country-code second-level domains rather than
+// the full Public Suffix List.
+var multiLabelSuffixes = map[string]bool{
+	"co.uk": true, "org.uk": true, "ac.uk": true, "gov.uk": true, "me.uk": true,
+	"com.au": true, "net.au": true, "org.au": true, "edu.au": true, "gov.au": true,
+	"co.nz": true, "org.nz": true, "co.jp": true, "ne.jp": true, "or.jp": true,
+	"co.kr": true, "or.kr": true, "co.in": true, "net.in": true, "org.in": true,
+	"com.br": true, "net.br": true, "org.br": true, "com.mx": true, "com.ar": true,
+	"com.cn": true, "net.cn": true, "org.cn": true, "com.hk": true, "com.tw": true,
+	"com.sg": true, "com.my": true, "co.za": true, "com.tr": true, "co.il": true,
+	"com.ua": true, "co.id": true, "com.ph": true, "com.vn": true, "com.pk": true,
+}
+
+// RegistrableDomain returns the registrable domain (eTLD+1) of a host or
+// URL, such as "twitter.com" for "https://mobile.twitter.com/home". It
+// returns the lowercased input unchanged when no host can be found.
+func RegistrableDomain(hostOrURL string) string {
+	host := strings.ToLower(strings.TrimSpace(hostOrURL))
+	if strings.Contains(host, "://") {
+		if u, err := url.Parse(host); err == nil {
+			host = u.Hostname()
+		}
+	} else if i := strings.IndexAny(host, "/:?"); i >= 0 {
+		host = host[:i]
+	}
+	host = strings.TrimSuffix(host, ".")
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 {
+		return host
+	}
+	n := 2
+	if len(labels) > 2 && multiLabelSuffixes[strings.Join(labels[len(labels)-2:], ".")] {
+		n = 3
+	}
+	return strings.Join(labels[len(labels)-n:], ".")
+}
+
+// ReferrerDomain is the clicks from every referrer under one registrable
+// domain.
+type ReferrerDomain struct {
+	Domain    string   `json:"domain"`
+	Total     int      `json:"total"`
+	Referrers []string `json:"referrers"`
+}
+
+// GroupReferrerDomains groups referrer rows by registrable domain, mapping
+// domains through aliases (which may be nil). Groups are ordered by
+// descending total.
+func GroupReferrerDomains(rows []BreakdownItem, aliases map[string]string) []ReferrerDomain {
+	groups := map[string]*ReferrerDomain{}
+	for _, row := range rows {
+		domain := RegistrableDomain(row.Name)
+		if alias, ok := aliases[domain]; ok {
+			domain = alias
+		}
+		g, ok := groups[domain]
+		if !ok {
+			g = &ReferrerDomain{Domain: domain}
+			groups[domain] = g
+		}
+		g.Total += row.Total
+		g.Referrers = append(g.Referrers, row.Name)
+	}
+	result := make([]ReferrerDomain, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total == result[j].Total {
+			return result[i].Domain < result[j].Domain
+		}
+		return result[i].Total > result[j].Total
+	})
+	return result
+}
+
+// ReferrerDomains groups the referrer breakdown by registrable domain using
+// DefaultReferrerAliases.
+func (s *Stats) ReferrerDomains() []ReferrerDomain {
+	return GroupReferrerDomains(s.ReferrerBreakdown(), DefaultReferrerAliases)
+}
@@ -0,0 +1,78 @@
+package tly
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegistrableDomain(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"https://mobile.twitter.com/home", "twitter.com"},
+		{"news.bbc.co.uk", "bbc.co.uk"},
+		{"bbc.co.uk", "bbc.co.uk"},
+		{"co.uk", "co.uk"},
+		{"WWW.Example.COM.", "example.com"},
+		{"example.com:8080/path", "example.com"},
+		{"http://localhost:3000", "localhost"},
+		{"  t.co/abc  ", "t.co"},
+		{"Direct", "direct"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := RegistrableDomain(tt.in); got != tt.want {
+			t.Errorf("RegistrableDomain(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGroupReferrerDomains(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    []BreakdownItem
+		aliases map[string]string
+		want    []ReferrerDomain
+	}{
+		{"no referrers", nil, DefaultReferrerAliases, []ReferrerDomain{}},
+		{
+			name: "aliases and subdomains",
+			rows: []BreakdownItem{
+				{"https://t.co/xyz", 2},
+				{"https://mobile.twitter.com/", 3},
+				{"https://www.google.com/", 4},
+				{"x.com", 1},
+			},
+			aliases: DefaultReferrerAliases,
+			want: []ReferrerDomain{
+				{"twitter.com", 6, []string{"https://t.co/xyz", "https://mobile.twitter.com/", "x.com"}},
+				{"google.com", 4, []string{"https://www.google.com/"}},
+			},
+		},
+		{
+			name:    "without aliases",
+			rows:    []BreakdownItem{{"https://t.co/xyz", 2}, {"https://twitter.com/", 2}},
+			aliases: nil,
+			want: []ReferrerDomain{
+				{"t.co", 2, []string{"https://t.co/xyz"}},
+				{"twitter.com", 2, []string{"https://twitter.com/"}},
+			},
+		},
+		{
+			name:    "unknown and empty referrers keep their own groups",
+			rows:    []BreakdownItem{{"", 5}, {"Unknown", 1}, {"unknown", 1}},
+			aliases: DefaultReferrerAliases,
+			want: []ReferrerDomain{
+				{"", 5, []string{""}},
+				{"unknown", 2, []string{"Unknown", "unknown"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GroupReferrerDomains(tt.rows, tt.aliases); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GroupReferrerDomains() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
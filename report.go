@@ -0,0 +1,89 @@
+package tly
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// StatsReportOptions configures a CSV stats report.
+type StatsReportOptions struct {
+	Stats StatsOptions
+	// Comma is the field delimiter. Defaults to ','; use ';' for
+	// spreadsheets in locales that use a decimal comma.
+	Comma rune
+	// TopN limits the countries and referrers sections. Defaults to 10.
+	TopN int
+}
+
+// WriteStatsReport writes a multi-section CSV report for one link: a
+// summary, the daily series, top countries and top referrers. Sections are
+// separated by a blank row and start with a title row followed by a header
+// row. Numbers are written without grouping separators and with '.' as the
+// decimal point so they parse the same in every locale.
+func WriteStatsReport(w io.Writer, shortURL string, stats *Stats, opts StatsReportOptions) error {
+	if opts.TopN < 1 {
+		opts.TopN = 10
+	}
+	cw := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		cw.Comma = opts.Comma
+	}
+	rows := [][]string{
+		{"Summary"},
+		{"Metric", "Value"},
+		{"Short URL", shortURL},
+		{"Clicks", strconv.Itoa(stats.Clicks)},
+		{"Unique clicks", strconv.Itoa(stats.UniqueClicks)},
+		{"Unique ratio", formatFloat(stats.UniqueRatio())},
+		{"Average clicks per day", formatFloat(stats.AverageClicksPerDay())},
+		{},
+		{"Daily clicks"},
+		{"Date", "Clicks"},
+	}
+	for _, d := range stats.DailySeries() {
+		rows = append(rows, []string{d.Date, strconv.Itoa(d.Total)})
+	}
+	rows = append(rows, []string{}, []string{"Top countries"}, []string{"Country", "Code", "Clicks"})
+	for i, c := range stats.GeoStats().Countries {
+		if i == opts.TopN {
+			break
+		}
+		rows = append(rows, []string{c.Name, c.Code, strconv.Itoa(c.Total)})
+	}
+	rows = append(rows, []string{}, []string{"Top referrers"}, []string{"Referrer", "Clicks"})
+	for i, r := range topItems(stats.ReferrerBreakdown()) {
+		if i == opts.TopN {
+			break
+		}
+		rows = append(rows, []string{r.Name, strconv.Itoa(r.Total)})
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// GenerateStatsReport fetches the stats of shortURL and writes them to w
+// with WriteStatsReport.
+func (c *Client) GenerateStatsReport(ctx context.Context, shortURL string, opts StatsReportOptions, w io.Writer) error {
+	stats, err := c.GetStatsWithOptions(ctx, shortURL, opts.Stats)
+	if err != nil {
+		return err
+	}
+	return WriteStatsReport(w, shortURL, stats, opts)
+}
+
+// formatFloat formats v with two decimals and a '.' decimal point.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// topItems returns a copy of rows sorted by descending total.
+func topItems(rows []BreakdownItem) []BreakdownItem {
+	sorted := append([]BreakdownItem(nil), rows...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Total > sorted[j].Total })
+	return sorted
+}
@@ -0,0 +1,55 @@
+package tly
+
+import (
+	"fmt"
+	"time"
+)
+
+// RollUpPeriod is the calendar period RollUp aggregates into.
+type RollUpPeriod int
+
+// Supported roll-up periods.
+const (
+	// ByWeek rolls up into ISO 8601 weeks, which start on Monday and
+	// belong to the year containing their Thursday.
+	ByWeek RollUpPeriod = iota
+	// ByMonth rolls up into calendar months.
+	ByMonth
+)
+
+// RollUpPoint is the clicks of one calendar week or month. End is the
+// exclusive end of the period.
+type RollUpPoint struct {
+	Label string    `json:"label"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Total int       `json:"total"`
+}
+
+// RollUp aggregates the click series of stats into calendar weeks or months
+// in loc (UTC when nil). Week labels use the ISO form "2024-W05" and month
+// labels "2024-02". See Stats.BucketsIn for how the series is converted.
+func RollUp(stats *Stats, period RollUpPeriod, loc *time.Location) ([]RollUpPoint, error) {
+	g := GranularityWeek
+	if period == ByMonth {
+		g = GranularityMonth
+	}
+	buckets, err := stats.BucketsIn(g, loc)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]RollUpPoint, 0, len(buckets))
+	for _, b := range buckets {
+		p := RollUpPoint{Start: b.Start, Total: b.Total}
+		if period == ByMonth {
+			p.End = b.Start.AddDate(0, 1, 0)
+			p.Label = b.Start.Format("2006-01")
+		} else {
+			p.End = b.Start.AddDate(0, 0, 7)
+			year, week := b.Start.ISOWeek()
+			p.Label = fmt.Sprintf("%d-W%02d", year, week)
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
@@ -0,0 +1,103 @@
+package tly
+
+import (
+	"testing"
+	"time"
+)
+
+// dailyStats returns stats whose daily series has a row per date.
+func dailyStats(days map[string]int) *Stats {
+	rows := make([]interface{}, 0, len(days))
+	for date, total := range days {
+		rows = append(rows, map[string]interface{}{"date": date, "total": float64(total)})
+	}
+	return &Stats{DailyClicks: rows}
+}
+
+func TestRollUp(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse(dateLayout, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	tests := []struct {
+		name   string
+		days   map[string]int
+		period RollUpPeriod
+		want   []RollUpPoint
+	}{
+		{
+			name:   "weeks start on Monday",
+			days:   map[string]int{"2024-01-28": 1, "2024-01-29": 2, "2024-02-01": 3, "2024-02-05": 4},
+			period: ByWeek,
+			want: []RollUpPoint{
+				{Label: "2024-W04", Start: date("2024-01-22"), End: date("2024-01-29"), Total: 1},
+				{Label: "2024-W05", Start: date("2024-01-29"), End: date("2024-02-05"), Total: 5},
+				{Label: "2024-W06", Start: date("2024-02-05"), End: date("2024-02-12"), Total: 4},
+			},
+		},
+		{
+			name:   "weeks belong to the year of their Thursday",
+			days:   map[string]int{"2020-12-31": 1, "2021-01-03": 2, "2024-12-31": 3},
+			period: ByWeek,
+			want: []RollUpPoint{
+				{Label: "2020-W53", Start: date("2020-12-28"), End: date("2021-01-04"), Total: 3},
+				{Label: "2025-W01", Start: date("2024-12-30"), End: date("2025-01-06"), Total: 3},
+			},
+		},
+		{
+			name:   "months",
+			days:   map[string]int{"2024-01-31": 1, "2024-02-01": 2, "2024-02-29": 3, "2024-03-01": 4},
+			period: ByMonth,
+			want: []RollUpPoint{
+				{Label: "2024-01", Start: date("2024-01-01"), End: date("2024-02-01"), Total: 1},
+				{Label: "2024-02", Start: date("2024-02-01"), End: date("2024-03-01"), Total: 5},
+				{Label: "2024-03", Start: date("2024-03-01"), End: date("2024-04-01"), Total: 4},
+			},
+		},
+		{
+			name:   "unparseable dates are skipped",
+			days:   map[string]int{"yesterday": 7, "2024-02-01": 2},
+			period: ByMonth,
+			want: []RollUpPoint{
+				{Label: "2024-02", Start: date("2024-02-01"), End: date("2024-03-01"), Total: 2},
+			},
+		},
+		{
+			name:   "no clicks",
+			period: ByWeek,
+			want:   []RollUpPoint{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RollUp(dailyStats(tt.days), tt.period, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d points %+v, want %d", len(got), got, len(tt.want))
+			}
+			for i, p := range got {
+				w := tt.want[i]
+				if p.Label != w.Label || !p.Start.Equal(w.Start) || !p.End.Equal(w.End) || p.Total != w.Total {
+					t.Errorf("point %d = %+v, want %+v", i, p, w)
+				}
+			}
+		})
+	}
+}
+
+func TestRollUpLocation(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*3600)
+	got, err := RollUp(dailyStats(map[string]int{"2024-02-01": 2}), ByMonth, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2024, 2, 1, 0, 0, 0, 0, loc)
+	if len(got) != 1 || !got[0].Start.Equal(want) || got[0].Label != "2024-02" {
+		t.Errorf("RollUp in %s = %+v, want one point starting %s", loc, got, want)
+	}
+}
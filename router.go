@@ -0,0 +1,121 @@
+package tly
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// WithDefaultDomain makes the client create short links on domain when a
+// request does not name one, e.g. "https://go.acme.com/".
+func WithDefaultDomain(domain string) ClientOption {
+	return func(c *Client) {
+		c.domain = domain
+	}
+}
+
+// ErrUnknownTenant is returned by Router for names it has no tenant for.
+var ErrUnknownTenant = errors.New("tly: unknown tenant")
+
+// Tenant is the configuration of one logical brand or customer: the key to
+// authenticate with, the workspace to act in and the domain to create
+// links on. Zero Workspace and Domain use the account defaults.
+type Tenant struct {
+	APIKey    string
+	Workspace int
+	Domain    string
+	// Options are applied after the router's shared options.
+	Options []ClientOption
+}
+
+// Router maps tenant names to clients scoped to each tenant's credentials,
+// workspace and default domain. Clients are built on first use and reused,
+// so resolvers enabled with WithTagResolver or WithPixelResolver are per
+// tenant. A Cache given to WithCache is shared by every tenant, but its
+// keys include the tenant's credentials and workspace, so no tenant is
+// served another's responses:
+//
+//	router := tly.NewRouter(map[string]tly.Tenant{
+//		"acme":   {APIKey: acmeKey, Domain: "https://go.acme.com/"},
+//		"globex": {APIKey: agencyKey, Workspace: 42},
+//	})
+//	client, err := router.Client("acme")
+//	link, err := client.CreateShortLink(req)
+type Router struct {
+	opts []ClientOption
+
+	mu      sync.Mutex
+	tenants map[string]Tenant
+	clients map[string]*Client
+}
+
+// NewRouter creates a router for tenants. opts are applied to every
+// tenant's client, e.g. WithCache or WithTagResolver; instances they
+// capture, such as the Cache, are shared between tenants.
+func NewRouter(tenants map[string]Tenant, opts ...ClientOption) *Router {
+	r := &Router{
+		opts:    opts,
+		tenants: make(map[string]Tenant, len(tenants)),
+		clients: map[string]*Client{},
+	}
+	for name, t := range tenants {
+		r.tenants[name] = t
+	}
+	return r
+}
+
+// Add registers or replaces a tenant. A replaced tenant gets a new client
+// on its next use.
+func (r *Router) Add(name string, t Tenant) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tenants[name] = t
+	delete(r.clients, name)
+}
+
+// Names returns the tenant names in sorted order.
+func (r *Router) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.tenants))
+	for name := range r.tenants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Client returns the client of the named tenant.
+func (r *Router) Client(name string) (*Client, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c, ok := r.clients[name]; ok {
+		return c, nil
+	}
+	t, ok := r.tenants[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownTenant, name)
+	}
+	opts := append([]ClientOption(nil), r.opts...)
+	if t.Workspace != 0 {
+		opts = append(opts, WithWorkspace(t.Workspace))
+	}
+	if t.Domain != "" {
+		opts = append(opts, WithDefaultDomain(t.Domain))
+	}
+	opts = append(opts, t.Options...)
+	c := NewClient(t.APIKey, opts...)
+	r.clients[name] = c
+	return c, nil
+}
+
+// MustClient is like Client but panics for unknown tenants. It suits
+// tenant names that are constants in the calling code.
+func (r *Router) MustClient(name string) *Client {
+	c, err := r.Client(name)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
@@ -0,0 +1,64 @@
+package tly_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+func TestRouterCacheIsolation(t *testing.T) {
+	// The server answers with a long URL naming the key and workspace of the
+	// request, so a response served to the wrong tenant shows.
+	var mu sync.Mutex
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		json.NewEncoder(w).Encode(tly.ShortLink{
+			ShortURL: r.URL.Query().Get("short_url"),
+			LongURL:  "https://" + key + ".example/" + r.Header.Get("X-Workspace-Id"),
+		})
+	}))
+	defer ts.Close()
+	server := func(c *tly.Client) { c.BaseURL = ts.URL }
+	router := tly.NewRouter(map[string]tly.Tenant{
+		"acme":    {APIKey: "acme", Options: []tly.ClientOption{server}},
+		"globex":  {APIKey: "agency", Workspace: 42, Options: []tly.ClientOption{server}},
+		"initech": {APIKey: "agency", Workspace: 7, Options: []tly.ClientOption{server}},
+	}, tly.WithCache(tly.NewMemoryCache(0)))
+
+	tests := []struct {
+		tenant   string
+		want     string
+		requests int
+	}{
+		{"acme", "https://acme.example/", 1},
+		{"globex", "https://agency.example/42", 2},
+		{"initech", "https://agency.example/7", 3},
+		{"acme", "https://acme.example/", 3},
+		{"globex", "https://agency.example/42", 3},
+		{"initech", "https://agency.example/7", 3},
+	}
+	for i, tt := range tests {
+		link, err := router.MustClient(tt.tenant).GetShortLink("https://t.ly/same")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if link.LongURL != tt.want {
+			t.Errorf("call %d: %s got %s, want %s", i+1, tt.tenant, link.LongURL, tt.want)
+		}
+		mu.Lock()
+		got := requests
+		mu.Unlock()
+		if got != tt.requests {
+			t.Errorf("call %d: %d requests so far, want %d", i+1, got, tt.requests)
+		}
+	}
+}
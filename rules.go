@@ -0,0 +1,177 @@
+package tly
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// RulesService is the redirect rule part of the API.
+type RulesService interface {
+	ListRules(ctx context.Context, shortURL string) ([]Rule, error)
+	CreateRule(ctx context.Context, reqData RuleCreateRequest) (*Rule, error)
+	UpdateRule(ctx context.Context, reqData RuleUpdateRequest) (*Rule, error)
+	DeleteRule(ctx context.Context, id int) error
+}
+
+var _ RulesService = (*Client)(nil)
+
+// RuleConditionType is what a redirect rule matches on.
+type RuleConditionType string
+
+// Redirect rule condition types.
+const (
+	// RuleCountry matches ISO 3166-1 alpha-2 country codes.
+	RuleCountry RuleConditionType = "country"
+	// RuleDevice matches DeviceMobile, DeviceTablet or DeviceDesktop.
+	RuleDevice RuleConditionType = "device"
+	// RuleLanguage matches primary language subtags such as "en" or "de".
+	RuleLanguage RuleConditionType = "language"
+)
+
+// RuleCondition matches a click when its country, device or language is
+// one of Values.
+type RuleCondition struct {
+	Type   RuleConditionType `json:"type"`
+	Values []string          `json:"values"`
+}
+
+// Rule redirects the clicks of a short link matching Condition to
+// Destination instead of the link's long URL. Rules are evaluated by
+// ascending Priority and the first match wins.
+type Rule struct {
+	ID          int           `json:"id"`
+	ShortURL    string        `json:"short_url"`
+	Condition   RuleCondition `json:"condition"`
+	Destination string        `json:"destination"`
+	Priority    int           `json:"priority"`
+	CreatedAt   Timestamp     `json:"created_at"`
+	UpdatedAt   Timestamp     `json:"updated_at"`
+}
+
+// RuleCreateRequest is used to add a redirect rule to a short link.
+type RuleCreateRequest struct {
+	ShortURL    string        `json:"short_url"`
+	Condition   RuleCondition `json:"condition"`
+	Destination string        `json:"destination"`
+	Priority    int           `json:"priority,omitempty"`
+}
+
+// RuleUpdateRequest is used to change a redirect rule.
+type RuleUpdateRequest struct {
+	ID          int           `json:"id"`
+	Condition   RuleCondition `json:"condition"`
+	Destination string        `json:"destination"`
+	Priority    int           `json:"priority,omitempty"`
+}
+
+// RuleValidationError is returned when a rule fails pre-flight validation.
+type RuleValidationError struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e *RuleValidationError) Error() string {
+	return fmt.Sprintf("tly: invalid rule %s %q: %s", e.Field, e.Value, e.Reason)
+}
+
+// normalize validates the condition and returns it with country names
+// converted to codes and values lower-cased where the API expects it.
+func (rc RuleCondition) normalize() (RuleCondition, error) {
+	if len(rc.Values) == 0 {
+		return rc, &RuleValidationError{Field: "condition.values", Reason: "needs at least one value"}
+	}
+	out := RuleCondition{Type: rc.Type, Values: make([]string, 0, len(rc.Values))}
+	for _, v := range rc.Values {
+		switch rc.Type {
+		case RuleCountry:
+			code := CountryCode(v)
+			if code == "" {
+				return rc, &RuleValidationError{Field: "condition.values", Value: v, Reason: "is not a known country"}
+			}
+			v = code
+		case RuleDevice:
+			v = strings.ToLower(v)
+			switch DeviceClass(v) {
+			case DeviceMobile, DeviceTablet, DeviceDesktop:
+			default:
+				return rc, &RuleValidationError{Field: "condition.values", Value: v, Reason: "is not mobile, tablet or desktop"}
+			}
+		case RuleLanguage:
+			v = strings.ToLower(strings.TrimSpace(v))
+			if len(v) < 2 || len(v) > 3 {
+				return rc, &RuleValidationError{Field: "condition.values", Value: v, Reason: "is not a language code"}
+			}
+		default:
+			return rc, &RuleValidationError{Field: "condition.type", Value: string(rc.Type), Reason: "is not a supported condition"}
+		}
+		out.Values = append(out.Values, v)
+	}
+	return out, nil
+}
+
+// validateDestination checks that a rule destination is an absolute URL.
+func validateDestination(dest string) error {
+	u, err := url.Parse(dest)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return &RuleValidationError{Field: "destination", Value: dest, Reason: "must be an absolute URL"}
+	}
+	return nil
+}
+
+// ListRules retrieves the redirect rules of a short link.
+func (c *Client) ListRules(ctx context.Context, shortURL string) ([]Rule, error) {
+	query := url.Values{"short_url": {shortURL}}.Encode()
+	var rules []Rule
+	err := c.doRequestContext(ctx, "GET", "/api/v1/link/rule", query, nil, &rules)
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// CreateRule adds a redirect rule to a short link. Country conditions
+// accept names as well as codes; they are sent as codes.
+func (c *Client) CreateRule(ctx context.Context, reqData RuleCreateRequest) (*Rule, error) {
+	cond, err := reqData.Condition.normalize()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateDestination(reqData.Destination); err != nil {
+		return nil, err
+	}
+	reqData.Condition = cond
+	var rule Rule
+	err = c.doRequestContext(ctx, "POST", "/api/v1/link/rule", "", reqData, &rule)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// UpdateRule changes the condition, destination or priority of a rule.
+func (c *Client) UpdateRule(ctx context.Context, reqData RuleUpdateRequest) (*Rule, error) {
+	cond, err := reqData.Condition.normalize()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateDestination(reqData.Destination); err != nil {
+		return nil, err
+	}
+	reqData.Condition = cond
+	path := fmt.Sprintf("/api/v1/link/rule/%d", reqData.ID)
+	var rule Rule
+	err = c.doRequestContext(ctx, "PUT", path, "", reqData, &rule)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// DeleteRule removes a redirect rule by its ID.
+func (c *Client) DeleteRule(ctx context.Context, id int) error {
+	path := fmt.Sprintf("/api/v1/link/rule/%d", id)
+	return c.doRequestContext(ctx, "DELETE", path, "", nil, nil)
+}
@@ -0,0 +1,101 @@
+package tly
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Verdict is the outcome of screening a destination URL.
+type Verdict string
+
+// Screening verdicts.
+const (
+	VerdictSafe       Verdict = "safe"
+	VerdictSuspicious Verdict = "suspicious"
+	VerdictMalicious  Verdict = "malicious"
+	// VerdictUnknown means the screener could not classify the URL.
+	VerdictUnknown Verdict = "unknown"
+)
+
+// DestinationCheck is the screening result of a destination URL. Threats
+// names what was found, e.g. "phishing" or "malware".
+type DestinationCheck struct {
+	URL     string   `json:"url"`
+	Verdict Verdict  `json:"verdict"`
+	Threats []string `json:"threats,omitempty"`
+	// Source identifies the screener that produced the verdict.
+	Source string `json:"source,omitempty"`
+}
+
+// DestinationScreener classifies destination URLs. Plug in a third-party
+// reputation service with WithDestinationScreener.
+type DestinationScreener interface {
+	Screen(ctx context.Context, longURL string) (*DestinationCheck, error)
+}
+
+// ScreenerFunc adapts a function to a DestinationScreener.
+type ScreenerFunc func(ctx context.Context, longURL string) (*DestinationCheck, error)
+
+// Screen implements DestinationScreener.
+func (f ScreenerFunc) Screen(ctx context.Context, longURL string) (*DestinationCheck, error) {
+	return f(ctx, longURL)
+}
+
+// WithDestinationScreener makes CheckDestination use s instead of the T.LY
+// screening endpoint.
+func WithDestinationScreener(s DestinationScreener) ClientOption {
+	return func(c *Client) {
+		c.screener = s
+	}
+}
+
+// CheckDestination screens longURL for malware and phishing before it is
+// shortened, using the client's DestinationScreener or, by default, T.LY's
+// own screening.
+func (c *Client) CheckDestination(ctx context.Context, longURL string) (*DestinationCheck, error) {
+	if c.screener != nil {
+		return c.screener.Screen(ctx, longURL)
+	}
+	query := url.Values{"url": {longURL}}.Encode()
+	var check DestinationCheck
+	err := c.doRequestContext(ctx, "GET", "/api/v1/link/check", query, nil, &check)
+	if err != nil {
+		return nil, err
+	}
+	if check.URL == "" {
+		check.URL = longURL
+	}
+	if check.Verdict == "" {
+		check.Verdict = VerdictUnknown
+	}
+	if check.Source == "" {
+		check.Source = "t.ly"
+	}
+	return &check, nil
+}
+
+// UnsafeDestinationError is returned by CreateScreenedShortLink when the
+// destination was not judged safe.
+type UnsafeDestinationError struct {
+	Check *DestinationCheck
+}
+
+func (e *UnsafeDestinationError) Error() string {
+	return fmt.Sprintf("tly: destination %s screened as %s", e.Check.URL, e.Check.Verdict)
+}
+
+// CreateScreenedShortLink screens the destination with CheckDestination
+// and creates the short link only if it is safe. Any other verdict,
+// including VerdictUnknown when allowUnknown is false, returns an
+// *UnsafeDestinationError.
+func (c *Client) CreateScreenedShortLink(ctx context.Context, reqData ShortLinkCreateRequest, allowUnknown bool) (*ShortLink, error) {
+	check, err := c.CheckDestination(ctx, reqData.LongURL)
+	if err != nil {
+		return nil, err
+	}
+	if check.Verdict != VerdictSafe && !(allowUnknown && check.Verdict == VerdictUnknown) {
+		return nil, &UnsafeDestinationError{Check: check}
+	}
+	return c.createShortLink(ctx, reqData)
+}
@@ -0,0 +1,536 @@
+package tly
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// StatsService is the click statistics part of the API.
+type StatsService interface {
+	GetStats(shortURL string) (*Stats, error)
+	GetStatsWithOptions(ctx context.Context, shortURL string, opts StatsOptions) (*Stats, error)
+	GetStatsMulti(ctx context.Context, shortURLs []string, opts StatsOptions) (map[string]*Stats, error)
+	GetStatsByTag(ctx context.Context, tagID int, opts StatsOptions) (*TagStats, error)
+}
+
+var _ StatsService = (*Client)(nil)
+
+// Keys naming each row in the raw stats breakdowns.
+const (
+	browserKey  = "browser"
+	countryKey  = "countryName"
+	referrerKey = "referrer"
+	platformKey = "platform"
+	dateKey     = "date"
+	totalKey    = "total"
+)
+
+// BreakdownItem is one row of a stats breakdown such as browsers or countries.
+type BreakdownItem struct {
+	Name  string `json:"name"`
+	Total int    `json:"total"`
+}
+
+// DailyClicks is one day of the daily click series.
+type DailyClicks struct {
+	Date  string `json:"date"`
+	Total int    `json:"total"`
+}
+
+// BrowserBreakdown returns the browsers breakdown as typed rows.
+func (s *Stats) BrowserBreakdown() []BreakdownItem { return breakdown(s.Browsers, browserKey) }
+
+// CountryBreakdown returns the countries breakdown as typed rows.
+func (s *Stats) CountryBreakdown() []BreakdownItem { return breakdown(s.Countries, countryKey) }
+
+// ReferrerBreakdown returns the referrers breakdown as typed rows.
+func (s *Stats) ReferrerBreakdown() []BreakdownItem { return breakdown(s.Referrers, referrerKey) }
+
+// PlatformBreakdown returns the platforms breakdown as typed rows.
+func (s *Stats) PlatformBreakdown() []BreakdownItem { return breakdown(s.Platforms, platformKey) }
+
+// DailySeries returns the daily click series as typed rows.
+func (s *Stats) DailySeries() []DailyClicks {
+	var days []DailyClicks
+	for _, row := range breakdown(s.DailyClicks, dateKey) {
+		days = append(days, DailyClicks{Date: row.Name, Total: row.Total})
+	}
+	return days
+}
+
+// breakdown decodes raw breakdown rows, reading the row name from nameKey.
+// Rows that are not JSON objects are skipped.
+func breakdown(rows []interface{}, nameKey string) []BreakdownItem {
+	items := make([]BreakdownItem, 0, len(rows))
+	for _, r := range rows {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := m[nameKey].(string)
+		items = append(items, BreakdownItem{Name: name, Total: toInt(m[totalKey])})
+	}
+	return items
+}
+
+// toInt converts a decoded JSON number (or numeric string) to an int.
+func toInt(v interface{}) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	case json.Number:
+		i, _ := n.Int64()
+		return int(i)
+	case string:
+		i, _ := strconv.Atoi(n)
+		return i
+	}
+	return 0
+}
+
+// Keys in Stats.Data that carry the bot/human split.
+var (
+	botClickKeys   = []string{"bot_clicks", "bots"}
+	humanClickKeys = []string{"human_clicks", "humans"}
+)
+
+// UnmarshalJSON decodes the raw payload and fills the bot/human split from
+// the Data map when present.
+func (s *Stats) UnmarshalJSON(data []byte) error {
+	type raw Stats
+	if err := json.Unmarshal(data, (*raw)(s)); err != nil {
+		return err
+	}
+	bots, hasBots := lookupInt(s.Data, botClickKeys)
+	humans, hasHumans := lookupInt(s.Data, humanClickKeys)
+	switch {
+	case hasBots && hasHumans:
+		s.BotClicks, s.HumanClicks = bots, humans
+	case hasBots:
+		s.BotClicks, s.HumanClicks = bots, s.Clicks-bots
+	case hasHumans:
+		s.BotClicks, s.HumanClicks = s.Clicks-humans, humans
+	}
+	s.HasBotData = hasBots || hasHumans
+	return nil
+}
+
+// lookupInt returns the first of keys present in m as an int.
+func lookupInt(m map[string]interface{}, keys []string) (int, bool) {
+	for _, k := range keys {
+		if v, ok := m[k]; ok && v != nil {
+			return toInt(v), true
+		}
+	}
+	return 0, false
+}
+
+// MergeStats combines several stats payloads into one, summing clicks and
+// every breakdown by name. Unique clicks are summed as well, which
+// over-counts visitors who clicked more than one of the links.
+func MergeStats(stats ...*Stats) *Stats {
+	merged := &Stats{}
+	var browsers, countries, referrers, platforms, daily [][]interface{}
+	for _, s := range stats {
+		if s == nil {
+			continue
+		}
+		merged.Clicks += s.Clicks
+		merged.UniqueClicks += s.UniqueClicks
+		merged.BotClicks += s.BotClicks
+		merged.HumanClicks += s.HumanClicks
+		merged.HasBotData = merged.HasBotData || s.HasBotData
+		browsers = append(browsers, s.Browsers)
+		countries = append(countries, s.Countries)
+		referrers = append(referrers, s.Referrers)
+		platforms = append(platforms, s.Platforms)
+		daily = append(daily, s.DailyClicks)
+	}
+	merged.Browsers = mergeRows(browsers, browserKey, false)
+	merged.Countries = mergeRows(countries, countryKey, false)
+	merged.Referrers = mergeRows(referrers, referrerKey, false)
+	merged.Platforms = mergeRows(platforms, platformKey, false)
+	merged.DailyClicks = mergeRows(daily, dateKey, true)
+	return merged
+}
+
+// mergeRows sums raw breakdown rows by name and returns them in the raw
+// shape, ordered by name when byName is set and by descending total otherwise.
+func mergeRows(sets [][]interface{}, nameKey string, byName bool) []interface{} {
+	totals := map[string]int{}
+	for _, rows := range sets {
+		for _, item := range breakdown(rows, nameKey) {
+			totals[item.Name] += item.Total
+		}
+	}
+	items := make([]BreakdownItem, 0, len(totals))
+	for name, total := range totals {
+		items = append(items, BreakdownItem{Name: name, Total: total})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if byName || items[i].Total == items[j].Total {
+			return items[i].Name < items[j].Name
+		}
+		return items[i].Total > items[j].Total
+	})
+	rows := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, map[string]interface{}{nameKey: item.Name, totalKey: float64(item.Total)})
+	}
+	return rows
+}
+
+// TagStats is the stats rollup for every link carrying a tag.
+type TagStats struct {
+	TagID int
+	// Total merges the stats of every link; see MergeStats.
+	Total *Stats
+	// Links holds the per-link stats keyed by short URL.
+	Links map[string]*Stats
+}
+
+// GetStatsByTag resolves every link carrying tagID and returns their merged
+// stats along with a per-link breakdown.
+func (c *Client) GetStatsByTag(ctx context.Context, tagID int, opts StatsOptions) (*TagStats, error) {
+	links, err := c.ListAllShortLinks(ctx, ShortLinkListOptions{TagIDs: []int{tagID}})
+	if err != nil {
+		return nil, err
+	}
+	stats, err := c.fetchStats(ctx, links, opts, defaultConcurrency)
+	if err != nil {
+		return nil, err
+	}
+	result := &TagStats{TagID: tagID, Links: make(map[string]*Stats, len(links))}
+	for i, link := range links {
+		result.Links[link.ShortURL] = stats[i]
+	}
+	result.Total = MergeStats(stats...)
+	return result, nil
+}
+
+// fetchStats retrieves the stats of every link using at most concurrency
+// parallel requests. The result is index-aligned with links.
+func (c *Client) fetchStats(ctx context.Context, links []ShortLink, opts StatsOptions, concurrency int) ([]*Stats, error) {
+	urls := make([]string, len(links))
+	for i, link := range links {
+		urls[i] = link.ShortURL
+	}
+	return c.fetchStatsURLs(ctx, urls, opts, concurrency)
+}
+
+// fetchStatsURLs is fetchStats for bare short URLs.
+func (c *Client) fetchStatsURLs(ctx context.Context, urls []string, opts StatsOptions, concurrency int) ([]*Stats, error) {
+	stats := make([]*Stats, len(urls))
+	err := forEachLimit(ctx, len(urls), concurrency, func(ctx context.Context, i int) error {
+		s, err := c.GetStatsWithOptions(ctx, urls[i], opts)
+		if err != nil {
+			return fmt.Errorf("stats for %s: %w", urls[i], err)
+		}
+		stats[i] = s
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetStatsMulti retrieves the stats of several short links, keyed by short
+// URL. The stats endpoint accepts one link per request, so the requests are
+// fanned out with bounded concurrency; the first error aborts the call.
+func (c *Client) GetStatsMulti(ctx context.Context, shortURLs []string, opts StatsOptions) (map[string]*Stats, error) {
+	seen := make(map[string]bool, len(shortURLs))
+	var urls []string
+	for _, u := range shortURLs {
+		if !seen[u] {
+			seen[u] = true
+			urls = append(urls, u)
+		}
+	}
+	stats, err := c.fetchStatsURLs(ctx, urls, opts, defaultConcurrency)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*Stats, len(urls))
+	for i, u := range urls {
+		result[u] = stats[i]
+	}
+	return result, nil
+}
+
+// AccountStatsOptions configures AccountStats.
+type AccountStatsOptions struct {
+	StatsOptions
+	// Concurrency bounds the parallel stats requests. Defaults to 4.
+	Concurrency int
+	// TopLinks is the number of links and domains reported in
+	// AccountStats.TopLinks and TopDomains. Defaults to 10.
+	TopLinks int
+}
+
+// LinkClicks is the click count of a single short link.
+type LinkClicks struct {
+	ShortURL     string `json:"short_url"`
+	Clicks       int    `json:"clicks"`
+	UniqueClicks int    `json:"unique_clicks"`
+}
+
+// AccountStats summarizes clicks across every link in the account.
+type AccountStats struct {
+	Clicks       int             `json:"clicks"`
+	UniqueClicks int             `json:"unique_clicks"`
+	Links        int             `json:"links"`
+	TopLinks     []LinkClicks    `json:"top_links"`
+	TopDomains   []BreakdownItem `json:"top_domains"`
+	Trend        []DailyClicks   `json:"trend"`
+}
+
+// AccountStats summarizes clicks across the whole account for the period in
+// opts: total clicks, the top links and domains and the daily trend. Like
+// every call it is scoped to the client's workspace (see WithWorkspace), so
+// it also reports organization-wide stats. It asks the API's account-level
+// stats endpoint; plans without it answer 404, in which case the summary is
+// aggregated from the stats of every link.
+func (c *Client) AccountStats(ctx context.Context, opts AccountStatsOptions) (*AccountStats, error) {
+	if opts.TopLinks < 1 {
+		opts.TopLinks = 10
+	}
+	v := url.Values{"top": {strconv.Itoa(opts.TopLinks)}}
+	if !opts.StartDate.IsZero() {
+		v.Set("start_date", opts.StartDate.Format(dateLayout))
+	}
+	if !opts.EndDate.IsZero() {
+		v.Set("end_date", opts.EndDate.Format(dateLayout))
+	}
+	var result AccountStats
+	err := c.doRequestContext(ctx, "GET", "/api/v1/stats", v.Encode(), nil, &result)
+	if IsNotFound(err) {
+		return c.aggregateAccountStats(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// aggregateAccountStats builds the AccountStats summary from the stats of
+// every link, fetched opts.Concurrency at a time.
+func (c *Client) aggregateAccountStats(ctx context.Context, opts AccountStatsOptions) (*AccountStats, error) {
+	links, err := c.ListAllShortLinks(ctx, ShortLinkListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	stats, err := c.fetchStats(ctx, links, opts.StatsOptions, opts.Concurrency)
+	if err != nil {
+		return nil, err
+	}
+	merged := MergeStats(stats...)
+	result := &AccountStats{
+		Clicks:       merged.Clicks,
+		UniqueClicks: merged.UniqueClicks,
+		Links:        len(links),
+		Trend:        merged.DailySeries(),
+	}
+	top := make([]LinkClicks, len(links))
+	domains := map[string]int{}
+	for i, link := range links {
+		top[i] = LinkClicks{ShortURL: link.ShortURL, Clicks: stats[i].Clicks, UniqueClicks: stats[i].UniqueClicks}
+		domains[bareHost(link.Domain)] += stats[i].Clicks
+	}
+	sort.SliceStable(top, func(i, j int) bool { return top[i].Clicks > top[j].Clicks })
+	if len(top) > opts.TopLinks {
+		top = top[:opts.TopLinks]
+	}
+	result.TopLinks = top
+	for name, total := range domains {
+		result.TopDomains = append(result.TopDomains, BreakdownItem{Name: name, Total: total})
+	}
+	sort.Slice(result.TopDomains, func(i, j int) bool {
+		a, b := result.TopDomains[i], result.TopDomains[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.Name < b.Name
+	})
+	if len(result.TopDomains) > opts.TopLinks {
+		result.TopDomains = result.TopDomains[:opts.TopLinks]
+	}
+	return result, nil
+}
+
+// UniqueRatio returns unique clicks divided by total clicks, or 0 when the
+// link has no clicks.
+func (s *Stats) UniqueRatio() float64 {
+	if s.Clicks == 0 {
+		return 0
+	}
+	return float64(s.UniqueClicks) / float64(s.Clicks)
+}
+
+// AverageClicksPerDay returns the mean clicks per day over the span of the
+// daily series, counting days missing from the series as zero.
+func (s *Stats) AverageClicksPerDay() float64 {
+	first, last, total, ok := s.dailySpan()
+	if !ok {
+		return 0
+	}
+	days := int(last.Sub(first).Hours()/24) + 1
+	return float64(total) / float64(days)
+}
+
+// DaysSinceLastClick returns the number of whole days between the last day
+// with clicks in the daily series and now. It reports false when the series
+// has no clicks.
+func (s *Stats) DaysSinceLastClick(now time.Time) (int, bool) {
+	var last time.Time
+	for _, d := range s.DailySeries() {
+		t, err := parseDate(d.Date)
+		if err != nil || d.Total == 0 {
+			continue
+		}
+		if t.After(last) {
+			last = t
+		}
+	}
+	if last.IsZero() {
+		return 0, false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return int(today.Sub(last).Hours() / 24), true
+}
+
+// dailySpan returns the first and last dates of the daily series and the
+// clicks it contains.
+func (s *Stats) dailySpan() (first, last time.Time, total int, ok bool) {
+	for _, d := range s.DailySeries() {
+		t, err := parseDate(d.Date)
+		if err != nil {
+			continue
+		}
+		if !ok || t.Before(first) {
+			first = t
+		}
+		if !ok || t.After(last) {
+			last = t
+		}
+		total += d.Total
+		ok = true
+	}
+	return first, last, total, ok
+}
+
+// parseDate parses the date part of an API date or datetime string.
+func parseDate(v string) (time.Time, error) {
+	if len(v) > len(dateLayout) {
+		v = v[:len(dateLayout)]
+	}
+	return time.Parse(dateLayout, v)
+}
+
+// Granularity is the bucket width of a click time series.
+type Granularity string
+
+// Supported granularities.
+const (
+	GranularityHour  Granularity = "hour"
+	GranularityDay   Granularity = "day"
+	GranularityWeek  Granularity = "week"
+	GranularityMonth Granularity = "month"
+)
+
+// ErrGranularityUnavailable is returned by Stats.Buckets when the payload
+// does not contain the data needed for the requested granularity.
+var ErrGranularityUnavailable = errors.New("tly: granularity not available in stats payload")
+
+// ClickBucket is one bucket of a click time series.
+type ClickBucket struct {
+	Start time.Time `json:"start"`
+	Total int       `json:"total"`
+}
+
+// Buckets returns the click series at granularity g in UTC, the timezone the
+// API reports in. It is shorthand for BucketsIn(g, time.UTC).
+func (s *Stats) Buckets(g Granularity) ([]ClickBucket, error) {
+	return s.BucketsIn(g, time.UTC)
+}
+
+// BucketsIn returns the click series at granularity g with buckets aligned
+// to local days in loc, sorted by start time. Weeks are ISO weeks starting
+// Monday.
+//
+// When the stats were fetched with GranularityHour the hourly series is
+// converted to loc and re-bucketed, which is exact. Otherwise the daily
+// series is used and each API day is relabeled as the same calendar day in
+// loc, which is only an approximation for locations far from UTC. Hourly
+// buckets always require the hourly series.
+func (s *Stats) BucketsIn(g Granularity, loc *time.Location) ([]ClickBucket, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	var start func(time.Time) time.Time
+	switch g {
+	case GranularityHour:
+		start = func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+		}
+	case GranularityDay, "":
+		start = dayStart
+	case GranularityWeek:
+		start = weekStart
+	case GranularityMonth:
+		start = func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		}
+	default:
+		return nil, fmt.Errorf("tly: unknown granularity %q", g)
+	}
+	if s.HourlyClicks != nil {
+		return bucketRows(breakdown(s.HourlyClicks, dateKey), func(t time.Time) time.Time {
+			return start(t.In(loc))
+		}), nil
+	}
+	if g == GranularityHour {
+		return nil, ErrGranularityUnavailable
+	}
+	return bucketRows(breakdown(s.DailyClicks, dateKey), func(t time.Time) time.Time {
+		return start(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc))
+	}), nil
+}
+
+// bucketRows sums rows into buckets keyed by start(row time). Rows whose
+// timestamp cannot be parsed are skipped.
+func bucketRows(rows []BreakdownItem, start func(time.Time) time.Time) []ClickBucket {
+	totals := map[time.Time]int{}
+	for _, row := range rows {
+		t, err := ParseTimestamp(row.Name)
+		if err != nil || t.IsZero() {
+			continue
+		}
+		totals[start(t.UTC())] += row.Total
+	}
+	buckets := make([]ClickBucket, 0, len(totals))
+	for t, total := range totals {
+		buckets = append(buckets, ClickBucket{Start: t, Total: total})
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Start.Before(buckets[j].Start) })
+	return buckets
+}
+
+// dayStart truncates t to midnight in its own location.
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// weekStart returns midnight on the Monday of t's ISO week.
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return dayStart(t).AddDate(0, 0, -offset)
+}
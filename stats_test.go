@@ -0,0 +1,155 @@
+package tly
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// decodeStats decodes a stats payload as the client does.
+func decodeStats(t *testing.T, payload string) *Stats {
+	t.Helper()
+	var s Stats
+	if err := json.Unmarshal([]byte(payload), &s); err != nil {
+		t.Fatal(err)
+	}
+	return &s
+}
+
+func TestMergeStats(t *testing.T) {
+	a := decodeStats(t, `{
+		"clicks": 5, "unique_clicks": 4,
+		"browsers": [{"browser": "Chrome", "total": 3}, {"browser": "Safari", "total": 2}],
+		"countries": [{"countryName": "France", "total": 5}],
+		"referrers": [],
+		"daily_clicks": [{"date": "2024-01-02", "total": 3}, {"date": "2024-01-01", "total": 2}],
+		"data": {"bot_clicks": 1}
+	}`)
+	b := decodeStats(t, `{
+		"clicks": 4, "unique_clicks": 4,
+		"browsers": [{"browser": "Safari", "total": 4}],
+		"countries": null,
+		"referrers": [{"referrer": "", "total": 4}],
+		"daily_clicks": [{"date": "2024-01-02", "total": 1}, {"date": "2024-01-03", "total": 3}]
+	}`)
+
+	tests := []struct {
+		name      string
+		stats     []*Stats
+		clicks    int
+		unique    int
+		bots      int
+		browsers  []BreakdownItem
+		countries []BreakdownItem
+		referrers []BreakdownItem
+		daily     []DailyClicks
+	}{
+		{"nothing", nil, 0, 0, 0, []BreakdownItem{}, []BreakdownItem{}, []BreakdownItem{}, nil},
+		{"nil stats", []*Stats{nil, nil}, 0, 0, 0, []BreakdownItem{}, []BreakdownItem{}, []BreakdownItem{}, nil},
+		{
+			name:   "overlapping days and breakdowns",
+			stats:  []*Stats{a, nil, b},
+			clicks: 9,
+			// Unique clicks are summed even though visitors may overlap.
+			unique:    8,
+			bots:      1,
+			browsers:  []BreakdownItem{{"Safari", 6}, {"Chrome", 3}},
+			countries: []BreakdownItem{{"France", 5}},
+			referrers: []BreakdownItem{{"", 4}},
+			daily:     []DailyClicks{{"2024-01-01", 2}, {"2024-01-02", 4}, {"2024-01-03", 3}},
+		},
+		{
+			name:      "one payload is reordered",
+			stats:     []*Stats{a},
+			clicks:    5,
+			unique:    4,
+			bots:      1,
+			browsers:  []BreakdownItem{{"Chrome", 3}, {"Safari", 2}},
+			countries: []BreakdownItem{{"France", 5}},
+			referrers: []BreakdownItem{},
+			daily:     []DailyClicks{{"2024-01-01", 2}, {"2024-01-02", 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := MergeStats(tt.stats...)
+			if m.Clicks != tt.clicks || m.UniqueClicks != tt.unique || m.BotClicks != tt.bots {
+				t.Errorf("clicks, unique, bots = %d, %d, %d, want %d, %d, %d", m.Clicks, m.UniqueClicks, m.BotClicks, tt.clicks, tt.unique, tt.bots)
+			}
+			if m.HasBotData != (tt.bots > 0) {
+				t.Errorf("HasBotData = %v, want %v", m.HasBotData, tt.bots > 0)
+			}
+			if got := m.BrowserBreakdown(); !reflect.DeepEqual(got, tt.browsers) {
+				t.Errorf("browsers = %v, want %v", got, tt.browsers)
+			}
+			if got := m.CountryBreakdown(); !reflect.DeepEqual(got, tt.countries) {
+				t.Errorf("countries = %v, want %v", got, tt.countries)
+			}
+			if got := m.ReferrerBreakdown(); !reflect.DeepEqual(got, tt.referrers) {
+				t.Errorf("referrers = %v, want %v", got, tt.referrers)
+			}
+			if got := m.DailySeries(); !reflect.DeepEqual(got, tt.daily) {
+				t.Errorf("daily = %v, want %v", got, tt.daily)
+			}
+		})
+	}
+}
+
+func TestBucketsIn(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	daily := `{"daily_clicks": [
+		{"date": "2024-01-31", "total": 1},
+		{"date": "2024-02-01", "total": 2},
+		{"date": "2024-02-05", "total": 4},
+		{"date": "not a date", "total": 8},
+		{"date": "", "total": 16}
+	]}`
+	hourly := `{"daily_clicks": [{"date": "2024-01-31", "total": 3}], "hourly_clicks": [
+		{"date": "2024-01-31 23:00:00", "total": 1},
+		{"date": "2024-02-01 04:00:00", "total": 2},
+		{"date": "2024-02-01 05:00:00", "total": 4}
+	]}`
+	tests := []struct {
+		name    string
+		payload string
+		g       Granularity
+		loc     *time.Location
+		want    []string // "start=total"
+		err     error
+	}{
+		{"empty series", `{"daily_clicks": []}`, GranularityDay, nil, []string{}, nil},
+		{"days skip bad dates", daily, GranularityDay, time.UTC, []string{"2024-01-31 00:00 UTC=1", "2024-02-01 00:00 UTC=2", "2024-02-05 00:00 UTC=4"}, nil},
+		{"days are relabeled in loc", daily, "", est, []string{"2024-01-31 00:00 EST=1", "2024-02-01 00:00 EST=2", "2024-02-05 00:00 EST=4"}, nil},
+		{"weeks start on Monday", daily, GranularityWeek, time.UTC, []string{"2024-01-29 00:00 UTC=3", "2024-02-05 00:00 UTC=4"}, nil},
+		{"months", daily, GranularityMonth, time.UTC, []string{"2024-01-01 00:00 UTC=1", "2024-02-01 00:00 UTC=6"}, nil},
+		{"hours need the hourly series", daily, GranularityHour, time.UTC, nil, ErrGranularityUnavailable},
+		{"unknown granularity", daily, "year", time.UTC, nil, errors.New("tly: unknown granularity \"year\"")},
+		{"hours in loc", hourly, GranularityHour, est, []string{"2024-01-31 18:00 EST=1", "2024-01-31 23:00 EST=2", "2024-02-01 00:00 EST=4"}, nil},
+		{"hourly series moves clicks across days", hourly, GranularityDay, est, []string{"2024-01-31 00:00 EST=3", "2024-02-01 00:00 EST=4"}, nil},
+		{"hourly series in UTC", hourly, GranularityDay, time.UTC, []string{"2024-01-31 00:00 UTC=1", "2024-02-01 00:00 UTC=6"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets, err := decodeStats(t, tt.payload).BucketsIn(tt.g, tt.loc)
+			if tt.err != nil {
+				if err == nil || err.Error() != tt.err.Error() {
+					t.Errorf("BucketsIn() error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, len(buckets))
+			for i, b := range buckets {
+				got[i] = fmt.Sprintf("%s=%d", b.Start.Format("2006-01-02 15:04 MST"), b.Total)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BucketsIn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
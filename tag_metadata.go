@@ -0,0 +1,204 @@
+package tly
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+// ErrNoTagMetadataStore is returned by the tag metadata calls when the
+// client was created without WithTagMetadataStore.
+var ErrNoTagMetadataStore = errors.New("tly: no tag metadata store configured")
+
+// TagMetadata is display information kept alongside a tag. The API does not
+// store tag colors or descriptions, so the SDK keeps them in a client-side
+// TagMetadataStore.
+type TagMetadata struct {
+	// Color is a CSS hex color such as "#ff8800".
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// Validate checks that Color, when set, is a CSS hex color.
+func (m TagMetadata) Validate() error {
+	if m.Color != "" && !hexColor.MatchString(m.Color) {
+		return fmt.Errorf("tly: invalid tag color %q", m.Color)
+	}
+	return nil
+}
+
+// TagMetadataStore persists TagMetadata by tag ID.
+type TagMetadataStore interface {
+	// Get returns the metadata of a tag, reporting false when none is set.
+	Get(ctx context.Context, tagID int) (TagMetadata, bool, error)
+	Set(ctx context.Context, tagID int, md TagMetadata) error
+	Delete(ctx context.Context, tagID int) error
+}
+
+// MemoryTagMetadataStore keeps tag metadata in memory.
+type MemoryTagMetadataStore struct {
+	mu sync.Mutex
+	m  map[int]TagMetadata
+}
+
+// NewMemoryTagMetadataStore creates an empty in-memory store.
+func NewMemoryTagMetadataStore() *MemoryTagMetadataStore {
+	return &MemoryTagMetadataStore{m: map[int]TagMetadata{}}
+}
+
+// Get implements TagMetadataStore.
+func (s *MemoryTagMetadataStore) Get(ctx context.Context, tagID int) (TagMetadata, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	md, ok := s.m[tagID]
+	return md, ok, nil
+}
+
+// Set implements TagMetadataStore.
+func (s *MemoryTagMetadataStore) Set(ctx context.Context, tagID int, md TagMetadata) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[tagID] = md
+	return nil
+}
+
+// Delete implements TagMetadataStore.
+func (s *MemoryTagMetadataStore) Delete(ctx context.Context, tagID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.m, tagID)
+	return nil
+}
+
+// FileTagMetadataStore keeps all tag metadata in one JSON file keyed by tag
+// ID, rewritten atomically on every change.
+type FileTagMetadataStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func (s *FileTagMetadataStore) load() (map[string]TagMetadata, error) {
+	m := map[string]TagMetadata{}
+	data, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m, json.Unmarshal(data, &m)
+}
+
+func (s *FileTagMetadataStore) save(m map[string]TagMetadata) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return err
+	}
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
+
+// Get implements TagMetadataStore.
+func (s *FileTagMetadataStore) Get(ctx context.Context, tagID int) (TagMetadata, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, err := s.load()
+	if err != nil {
+		return TagMetadata{}, false, err
+	}
+	md, ok := m[strconv.Itoa(tagID)]
+	return md, ok, nil
+}
+
+// Set implements TagMetadataStore.
+func (s *FileTagMetadataStore) Set(ctx context.Context, tagID int, md TagMetadata) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, err := s.load()
+	if err != nil {
+		return err
+	}
+	m[strconv.Itoa(tagID)] = md
+	return s.save(m)
+}
+
+// Delete implements TagMetadataStore.
+func (s *FileTagMetadataStore) Delete(ctx context.Context, tagID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, err := s.load()
+	if err != nil {
+		return err
+	}
+	delete(m, strconv.Itoa(tagID))
+	return s.save(m)
+}
+
+// WithTagMetadataStore enables tag colors and descriptions backed by store.
+// Metadata is removed when the SDK deletes the tag.
+func WithTagMetadataStore(store TagMetadataStore) ClientOption {
+	return func(c *Client) {
+		c.tagMetadata = store
+	}
+}
+
+// TagMetadata returns the metadata of a tag; the zero value when none is set.
+func (c *Client) TagMetadata(ctx context.Context, tagID int) (TagMetadata, error) {
+	if c.tagMetadata == nil {
+		return TagMetadata{}, ErrNoTagMetadataStore
+	}
+	md, _, err := c.tagMetadata.Get(ctx, tagID)
+	return md, err
+}
+
+// SetTagMetadata validates and stores the metadata of a tag.
+func (c *Client) SetTagMetadata(ctx context.Context, tagID int, md TagMetadata) error {
+	if c.tagMetadata == nil {
+		return ErrNoTagMetadataStore
+	}
+	if err := md.Validate(); err != nil {
+		return err
+	}
+	return c.tagMetadata.Set(ctx, tagID, md)
+}
+
+// TagWithMetadata is a tag together with its client-side metadata.
+type TagWithMetadata struct {
+	Tag
+	Metadata TagMetadata `json:"metadata"`
+}
+
+// ListTagsWithMetadata lists every tag joined with its metadata.
+func (c *Client) ListTagsWithMetadata(ctx context.Context) ([]TagWithMetadata, error) {
+	if c.tagMetadata == nil {
+		return nil, ErrNoTagMetadataStore
+	}
+	tags, err := c.listTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]TagWithMetadata, len(tags))
+	for i, t := range tags {
+		md, _, err := c.tagMetadata.Get(ctx, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = TagWithMetadata{Tag: t, Metadata: md}
+	}
+	return result, nil
+}
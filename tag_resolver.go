@@ -0,0 +1,139 @@
+package tly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TagResolver maps tag names to IDs and back, caching the tag list for a
+// TTL. A lookup that misses the cache refreshes it once before failing, so
+// tags created elsewhere are picked up without waiting for the TTL. When the
+// client has a TagNormalizer, names are matched after normalization.
+type TagResolver struct {
+	client *Client
+	cache  nameCache
+}
+
+// NewTagResolver creates a resolver whose cache expires after ttl. A zero
+// ttl keeps the cache until Refresh is called or a lookup misses.
+func NewTagResolver(client *Client, ttl time.Duration) *TagResolver {
+	r := &TagResolver{client: client}
+	r.cache = nameCache{ttl: ttl, load: r.load}
+	return r
+}
+
+// WithTagResolver makes the client resolve tag names through a shared
+// TagResolver with the given ttl, so name-based helpers such as
+// ListShortLinksByTag and CreateShortLinkWithTagNames avoid listing tags on
+// every call.
+func WithTagResolver(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.tags = NewTagResolver(c, ttl)
+	}
+}
+
+// Refresh reloads the tag list.
+func (r *TagResolver) Refresh(ctx context.Context) error {
+	return r.cache.refresh(ctx)
+}
+
+// Add records a tag in the cache, replacing any older entry for the same
+// tag, e.g. right after creating or renaming it.
+func (r *TagResolver) Add(tag Tag) {
+	r.cache.add(r.entry(tag))
+}
+
+// Remove drops the tag with the given ID from the cache, e.g. after
+// deleting it.
+func (r *TagResolver) Remove(id int) {
+	r.cache.evict(id)
+}
+
+// load lists the tags for the cache.
+func (r *TagResolver) load(ctx context.Context) ([]nameEntry, error) {
+	tags, err := r.client.listTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]nameEntry, len(tags))
+	for i, t := range tags {
+		entries[i] = r.entry(t)
+	}
+	return entries, nil
+}
+
+// entry is the cache entry for t.
+func (r *TagResolver) entry(t Tag) nameEntry {
+	return nameEntry{id: t.ID, keys: []string{r.key(t.Tag)}, value: t}
+}
+
+// key is the cache key for name.
+func (r *TagResolver) key(name string) string {
+	if r.client.normalizeTag != nil {
+		return r.client.normalizeTag(name)
+	}
+	return name
+}
+
+// ID returns the ID of the tag named name.
+func (r *TagResolver) ID(ctx context.Context, name string) (int, error) {
+	v, ok, err := r.cache.key(ctx, r.key(name))
+	if err == nil && !ok {
+		err = ErrTagNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("resolve tag %q: %w", name, err)
+	}
+	return v.(Tag).ID, nil
+}
+
+// Name returns the name of the tag with the given ID.
+func (r *TagResolver) Name(ctx context.Context, id int) (string, error) {
+	v, ok, err := r.cache.id(ctx, id)
+	if err == nil && !ok {
+		err = ErrTagNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("resolve tag %d: %w", id, err)
+	}
+	return v.(Tag).Tag, nil
+}
+
+// IDs resolves several names, failing on the first unknown one.
+func (r *TagResolver) IDs(ctx context.Context, names []string) ([]int, error) {
+	ids := make([]int, 0, len(names))
+	for _, name := range names {
+		id, err := r.ID(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// CreateShortLinkWithTagNames creates a short link tagged with the named
+// tags, creating any tag that does not exist yet. Tag IDs already in
+// reqData.Tags are kept.
+func (c *Client) CreateShortLinkWithTagNames(ctx context.Context, reqData ShortLinkCreateRequest, tagNames []string) (*ShortLink, error) {
+	for _, name := range tagNames {
+		id, err := c.resolveTag(ctx, TagByName(name))
+		if errors.Is(err, ErrTagNotFound) {
+			var tag *Tag
+			tag, err = c.GetOrCreateTag(ctx, name)
+			if err == nil {
+				id = tag.ID
+				if c.tags != nil {
+					c.tags.Add(*tag)
+				}
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		reqData.Tags = append(reqData.Tags, id)
+	}
+	return c.createShortLink(ctx, reqData)
+}
@@ -0,0 +1,51 @@
+package tly_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+	"github.com/timleland/t.ly-go-url-shortener-api/tlytest"
+)
+
+func TestTagResolverForgetsOldNames(t *testing.T) {
+	ctx := context.Background()
+	srv := tlytest.NewServer(tlytest.Options{})
+	defer srv.Close()
+	c := srv.Client(tly.WithTagResolver(0))
+	renamed, err := c.CreateTag("old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleted, err := c.CreateTag("gone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Load the resolver cache before changing the tags. The stale names
+	// are looked up first, since a miss on "new" would reload the cache.
+	if _, err := c.ListShortLinksByTag(ctx, tly.TagByName("old"), tly.ShortLinkListOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.UpdateTag(renamed.ID, "new"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteTag(deleted.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want error
+	}{
+		{"old", tly.ErrTagNotFound},
+		{"gone", tly.ErrTagNotFound},
+		{"new", nil},
+	}
+	for _, tt := range tests {
+		_, err := c.ListShortLinksByTag(ctx, tly.TagByName(tt.name), tly.ShortLinkListOptions{})
+		if !errors.Is(err, tt.want) {
+			t.Errorf("ListShortLinksByTag(%q) error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
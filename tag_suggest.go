@@ -0,0 +1,185 @@
+package tly
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SuggestTagsOptions configures tag suggestions.
+type SuggestTagsOptions struct {
+	// Limit caps the number of suggestions. Defaults to 5.
+	Limit int
+	// MinSimilarity is the fuzzy match threshold between 0 and 1 for
+	// matching keywords to existing tags. Defaults to 0.8.
+	MinSimilarity float64
+	// ExistingOnly drops suggestions for tags that do not exist yet.
+	ExistingOnly bool
+}
+
+// TagSuggestion is a proposed tag for a destination URL. Existing is set
+// when the suggestion matches a tag already in the account.
+type TagSuggestion struct {
+	Name     string  `json:"name"`
+	Existing *Tag    `json:"existing,omitempty"`
+	Score    float64 `json:"score"`
+	Keyword  string  `json:"keyword"`
+}
+
+// suggestStopwords are URL tokens too generic to be useful tags.
+var suggestStopwords = map[string]bool{
+	"www": true, "com": true, "net": true, "org": true, "html": true, "htm": true,
+	"php": true, "aspx": true, "index": true, "the": true, "and": true, "for": true,
+	"http": true, "https": true, "amp": true, "utm": true, "ref": true, "page": true,
+}
+
+// urlKeywords extracts candidate keywords from a URL: the registrable
+// domain's name, then path segments, then UTM campaign values.
+func urlKeywords(longURL string) (domain string, keywords []string) {
+	u, err := url.Parse(longURL)
+	if err != nil {
+		return "", nil
+	}
+	seen := map[string]bool{}
+	add := func(word string) {
+		word = strings.ToLower(word)
+		if len(word) < 3 || suggestStopwords[word] || seen[word] || isDigits(word) {
+			return
+		}
+		seen[word] = true
+		keywords = append(keywords, word)
+	}
+	if reg := RegistrableDomain(u.Hostname()); reg != "" {
+		domain = strings.ToLower(strings.SplitN(reg, ".", 2)[0])
+		add(domain)
+	}
+	split := func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }
+	for _, w := range strings.FieldsFunc(u.Path, split) {
+		add(w)
+	}
+	utm := ParseUTM(longURL)
+	for _, v := range []string{utm.Campaign, utm.Source} {
+		for _, w := range strings.FieldsFunc(v, split) {
+			add(w)
+		}
+	}
+	return domain, keywords
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// SuggestTags proposes tags for longURL from its domain, path keywords and
+// UTM campaign, preferring existing tags that match a keyword exactly or
+// fuzzily. Suggestions are ordered by descending score.
+func SuggestTags(longURL string, existing []Tag, opts SuggestTagsOptions) []TagSuggestion {
+	if opts.Limit < 1 {
+		opts.Limit = 5
+	}
+	if opts.MinSimilarity <= 0 {
+		opts.MinSimilarity = 0.8
+	}
+	domain, keywords := urlKeywords(longURL)
+	best := map[string]TagSuggestion{}
+	for i, kw := range keywords {
+		// Earlier keywords (domain, then leading path segments) weigh more.
+		weight := 1 - float64(i)*0.05
+		if weight < 0.5 {
+			weight = 0.5
+		}
+		matched := false
+		for j := range existing {
+			sim := similarity(compactTag(existing[j].Tag), compactTag(kw))
+			if sim < opts.MinSimilarity {
+				continue
+			}
+			matched = true
+			s := TagSuggestion{Name: existing[j].Tag, Existing: &existing[j], Score: sim * weight, Keyword: kw}
+			if cur, ok := best[s.Name]; !ok || s.Score > cur.Score {
+				best[s.Name] = s
+			}
+		}
+		if !matched && !opts.ExistingOnly {
+			score := 0.4 * weight
+			if kw == domain {
+				score = 0.5
+			}
+			if _, ok := best[kw]; !ok {
+				best[kw] = TagSuggestion{Name: kw, Score: score, Keyword: kw}
+			}
+		}
+	}
+	result := make([]TagSuggestion, 0, len(best))
+	for _, s := range best {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score == result[j].Score {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].Score > result[j].Score
+	})
+	if len(result) > opts.Limit {
+		result = result[:opts.Limit]
+	}
+	return result
+}
+
+// SuggestTags proposes tags for longURL using the account's existing tags.
+func (c *Client) SuggestTags(ctx context.Context, longURL string, opts SuggestTagsOptions) ([]TagSuggestion, error) {
+	tags, err := c.listTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return SuggestTags(longURL, tags, opts), nil
+}
+
+// compactTag normalizes a name and drops its spaces for fuzzy comparison.
+func compactTag(name string) string {
+	return strings.Replace(DefaultTagNormalizer(name), " ", "", -1)
+}
+
+// similarity returns 1 minus the Levenshtein distance of a and b divided by
+// the longer length.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, minInt(cur[j-1]+1, prev[j-1]+cost))
+		}
+		prev, cur = cur, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(longest)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
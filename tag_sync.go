@@ -0,0 +1,92 @@
+package tly
+
+import (
+	"context"
+	"fmt"
+)
+
+// SyncTagsOptions configures SyncTags.
+type SyncTagsOptions struct {
+	// DeleteExtra deletes account tags that are not in the desired set.
+	DeleteExtra bool
+	// KeepInUse leaves extra tags that are still attached to links.
+	KeepInUse bool
+	// DryRun computes the plan without applying it.
+	DryRun bool
+	// Match controls how desired names are compared with account tags.
+	Match TagMatch
+}
+
+// TagSyncPlan is the set of changes SyncTags computed and, unless it was a
+// dry run, applied.
+type TagSyncPlan struct {
+	DryRun bool     `json:"dry_run"`
+	Keep   []Tag    `json:"keep"`
+	Create []string `json:"create"`
+	Delete []Tag    `json:"delete"`
+	// Created and Deleted list what was actually applied.
+	Created []Tag             `json:"created,omitempty"`
+	Deleted []TagDeleteResult `json:"deleted,omitempty"`
+}
+
+// SyncTags reconciles the account's tags with desired: missing tags are
+// created and, with DeleteExtra, tags not in desired are deleted. The
+// returned plan records what was done; on error it covers the changes made
+// before the failure.
+func (c *Client) SyncTags(ctx context.Context, desired []string, opts SyncTagsOptions) (*TagSyncPlan, error) {
+	tags, err := c.listTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	equal := c.tagEqual(opts.Match)
+	plan := &TagSyncPlan{DryRun: opts.DryRun}
+	wanted := map[int]bool{}
+	var planned []string
+	for _, name := range desired {
+		if found, err := findTag(tags, name, equal); err == nil {
+			if !wanted[found.ID] {
+				wanted[found.ID] = true
+				plan.Keep = append(plan.Keep, *found)
+			}
+			continue
+		}
+		dup := false
+		for _, p := range planned {
+			dup = dup || equal(p, name)
+		}
+		if !dup {
+			planned = append(planned, name)
+			plan.Create = append(plan.Create, name)
+		}
+	}
+	if opts.DeleteExtra {
+		for _, t := range tags {
+			if !wanted[t.ID] {
+				plan.Delete = append(plan.Delete, t)
+			}
+		}
+	}
+	if opts.DryRun {
+		return plan, nil
+	}
+	for _, name := range plan.Create {
+		tag, err := c.createTag(ctx, name)
+		if err != nil {
+			return plan, fmt.Errorf("create tag %q: %w", name, err)
+		}
+		plan.Created = append(plan.Created, *tag)
+	}
+	if len(plan.Delete) > 0 {
+		results, err := c.DeleteTags(ctx, tagIDs(plan.Delete), DeleteTagsOptions{SkipInUse: opts.KeepInUse})
+		plan.Deleted = results
+		if err != nil {
+			return plan, err
+		}
+		for _, r := range results {
+			if r.Err != nil {
+				return plan, fmt.Errorf("delete tag %d: %w", r.ID, r.Err)
+			}
+		}
+	}
+	return plan, nil
+}
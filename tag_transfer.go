@@ -0,0 +1,93 @@
+package tly
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// TagExport is the document written by ExportTags. IDs are informational;
+// imports match tags by name.
+type TagExport struct {
+	Version int   `json:"version"`
+	Tags    []Tag `json:"tags"`
+}
+
+// ExportTags writes every tag in the account to w as a JSON TagExport.
+func (c *Client) ExportTags(ctx context.Context, w io.Writer) error {
+	tags, err := c.listTags(ctx)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(TagExport{Version: 1, Tags: tags})
+}
+
+// ImportTagsOptions configures ImportTags.
+type ImportTagsOptions struct {
+	// DryRun reports what would be created without creating anything.
+	DryRun bool
+	// Match controls how imported names are compared with existing tags.
+	Match TagMatch
+}
+
+// TagConflict is an imported tag whose name already exists in the account.
+type TagConflict struct {
+	Name     string `json:"name"`
+	Existing Tag    `json:"existing"`
+}
+
+// TagImportFailure is an imported tag that could not be created.
+type TagImportFailure struct {
+	Name  string `json:"name"`
+	Err   error  `json:"-"`
+	Error string `json:"error"`
+}
+
+// TagImportReport describes the outcome of ImportTags. In a dry run Created
+// holds the tags that would be created, without IDs.
+type TagImportReport struct {
+	DryRun    bool               `json:"dry_run"`
+	Created   []Tag              `json:"created"`
+	Conflicts []TagConflict      `json:"conflicts"`
+	Failed    []TagImportFailure `json:"failed"`
+}
+
+// ImportTags reads a TagExport from r and creates every tag whose name does
+// not already exist in the account. Existing names are reported as
+// conflicts and left untouched; creation failures are reported and do not
+// stop the import.
+func (c *Client) ImportTags(ctx context.Context, r io.Reader, opts ImportTagsOptions) (*TagImportReport, error) {
+	var doc TagExport
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	existing, err := c.listTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := &TagImportReport{DryRun: opts.DryRun}
+	for _, t := range doc.Tags {
+		if found, err := findTag(existing, t.Tag, c.tagEqual(opts.Match)); err == nil {
+			report.Conflicts = append(report.Conflicts, TagConflict{Name: t.Tag, Existing: *found})
+			continue
+		} else if !errors.Is(err, ErrTagNotFound) {
+			return nil, err
+		}
+		if opts.DryRun {
+			report.Created = append(report.Created, Tag{Tag: t.Tag})
+			existing = append(existing, Tag{Tag: t.Tag})
+			continue
+		}
+		created, err := c.createTag(ctx, t.Tag)
+		if err != nil {
+			report.Failed = append(report.Failed, TagImportFailure{Name: t.Tag, Err: err, Error: err.Error()})
+			continue
+		}
+		report.Created = append(report.Created, *created)
+		existing = append(existing, *created)
+	}
+	return report, nil
+}
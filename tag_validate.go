@@ -0,0 +1,83 @@
+package tly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxTagLength is the longest tag name the API accepts, in characters.
+const MaxTagLength = 255
+
+// TagValidationReason classifies why a tag name was rejected.
+type TagValidationReason string
+
+// Tag validation reasons.
+const (
+	TagNameEmpty     TagValidationReason = "empty"
+	TagNameTooLong   TagValidationReason = "too_long"
+	TagNameInvalid   TagValidationReason = "invalid_characters"
+	TagNameDuplicate TagValidationReason = "duplicate"
+)
+
+// TagValidationError is returned when a tag name fails pre-flight
+// validation. Existing is set for TagNameDuplicate.
+type TagValidationError struct {
+	Name     string
+	Reason   TagValidationReason
+	Existing *Tag
+}
+
+func (e *TagValidationError) Error() string {
+	switch e.Reason {
+	case TagNameEmpty:
+		return "tly: the tag field is required"
+	case TagNameTooLong:
+		return fmt.Sprintf("tly: the tag may not be greater than %d characters", MaxTagLength)
+	case TagNameInvalid:
+		return fmt.Sprintf("tly: the tag %q contains invalid characters", e.Name)
+	case TagNameDuplicate:
+		return fmt.Sprintf("tly: the tag %q has already been taken", e.Name)
+	}
+	return fmt.Sprintf("tly: invalid tag %q", e.Name)
+}
+
+// ValidateTagName checks a tag name locally: it must be non-blank, at most
+// MaxTagLength characters, valid UTF-8 and free of control characters.
+// CreateTag and UpdateTag run this check before calling the API.
+func ValidateTagName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return &TagValidationError{Name: name, Reason: TagNameEmpty}
+	}
+	if !utf8.ValidString(name) {
+		return &TagValidationError{Name: name, Reason: TagNameInvalid}
+	}
+	if utf8.RuneCountInString(name) > MaxTagLength {
+		return &TagValidationError{Name: name, Reason: TagNameTooLong}
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return &TagValidationError{Name: name, Reason: TagNameInvalid}
+		}
+	}
+	return nil
+}
+
+// ValidateNewTag runs ValidateTagName and checks that no existing tag has
+// the same name after normalization with the client's TagNormalizer.
+func (c *Client) ValidateNewTag(ctx context.Context, name string) error {
+	if err := ValidateTagName(name); err != nil {
+		return err
+	}
+	existing, err := c.FindTagByName(ctx, name, MatchNormalized)
+	if errors.Is(err, ErrTagNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return &TagValidationError{Name: name, Reason: TagNameDuplicate, Existing: existing}
+}
@@ -0,0 +1,467 @@
+package tly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// TagService is the tag part of the API.
+type TagService interface {
+	ListTags() ([]Tag, error)
+	ListTagsPage(ctx context.Context, opts ListOptions) (*TagPage, error)
+	ListAllTags(ctx context.Context, opts ListOptions) ([]Tag, error)
+	CreateTag(tagValue string) (*Tag, error)
+	GetTag(id int) (*Tag, error)
+	UpdateTag(id int, tagValue string) (*Tag, error)
+	DeleteTag(id int) error
+}
+
+var _ TagService = (*Client)(nil)
+
+// ErrTagNotFound is returned when no tag matches a name.
+var ErrTagNotFound = errors.New("tly: tag not found")
+
+// TagMatch selects how tag names are compared.
+type TagMatch int
+
+// Supported tag name comparisons.
+const (
+	// MatchExact requires byte-for-byte equal names.
+	MatchExact TagMatch = iota
+	// MatchCaseInsensitive compares names with Unicode case folding.
+	MatchCaseInsensitive
+	// MatchNormalized compares names after passing both through the
+	// client's TagNormalizer (DefaultTagNormalizer unless configured with
+	// WithTagNormalizer).
+	MatchNormalized
+)
+
+// TagNormalizer maps a tag name to the canonical form used by
+// MatchNormalized.
+type TagNormalizer func(name string) string
+
+// DefaultTagNormalizer lowercases name, treats '-' and '_' as spaces and
+// collapses runs of whitespace, so "Summer-Sale", "summer sale" and
+// "SUMMER_SALE" all normalize to "summer sale".
+func DefaultTagNormalizer(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' {
+			return ' '
+		}
+		return unicode.ToLower(r)
+	}, name)
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// WithTagNormalizer sets the normalizer used by MatchNormalized and makes
+// name-based tag resolution (TagByName, the TagResolver) match normalized
+// names, so differently formatted spellings resolve to the same tag.
+func WithTagNormalizer(n TagNormalizer) ClientOption {
+	return func(c *Client) {
+		c.normalizeTag = n
+	}
+}
+
+// normalizer returns the configured TagNormalizer or the default one.
+func (c *Client) normalizer() TagNormalizer {
+	if c.normalizeTag != nil {
+		return c.normalizeTag
+	}
+	return DefaultTagNormalizer
+}
+
+// tagEqual returns the name comparison for match.
+func (c *Client) tagEqual(match TagMatch) func(a, b string) bool {
+	switch match {
+	case MatchCaseInsensitive:
+		return strings.EqualFold
+	case MatchNormalized:
+		n := c.normalizer()
+		return func(a, b string) bool { return n(a) == n(b) }
+	}
+	return func(a, b string) bool { return a == b }
+}
+
+// resolveMatch is the match used when resolving tag names: normalized when
+// a TagNormalizer is configured, exact otherwise.
+func (c *Client) resolveMatch() TagMatch {
+	if c.normalizeTag != nil {
+		return MatchNormalized
+	}
+	return MatchExact
+}
+
+// listTags retrieves every tag across all pages.
+func (c *Client) listTags(ctx context.Context) ([]Tag, error) {
+	return c.ListAllTags(ctx, ListOptions{})
+}
+
+// FindTagByName returns the tag named name, compared according to match.
+// When several tags match, an exact match is preferred, then the first one
+// listed. It returns ErrTagNotFound when none matches.
+func (c *Client) FindTagByName(ctx context.Context, name string, match TagMatch) (*Tag, error) {
+	tags, err := c.listTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return findTag(tags, name, c.tagEqual(match))
+}
+
+// findTag picks the tag named name from tags; see FindTagByName.
+func findTag(tags []Tag, name string, equal func(a, b string) bool) (*Tag, error) {
+	var found *Tag
+	for i := range tags {
+		if tags[i].Tag == name {
+			return &tags[i], nil
+		}
+		if found == nil && equal(tags[i].Tag, name) {
+			found = &tags[i]
+		}
+	}
+	if found == nil {
+		return nil, ErrTagNotFound
+	}
+	return found, nil
+}
+
+// createTag creates a tag with ctx.
+func (c *Client) createTag(ctx context.Context, name string) (*Tag, error) {
+	if err := ValidateTagName(name); err != nil {
+		return nil, err
+	}
+	reqBody := map[string]string{
+		"tag": name,
+	}
+	var tag Tag
+	err := c.doRequestContext(ctx, "POST", "/api/v1/link/tag", "", reqBody, &tag)
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// isConflict reports whether err is the API rejecting a duplicate resource.
+func isConflict(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) &&
+		(apiErr.StatusCode == http.StatusConflict || apiErr.StatusCode == http.StatusUnprocessableEntity)
+}
+
+// GetOrCreateTag returns the tag named name, creating it if it does not
+// exist. If a concurrent caller creates the tag first and the API rejects
+// the duplicate, the existing tag is fetched and returned instead.
+func (c *Client) GetOrCreateTag(ctx context.Context, name string) (*Tag, error) {
+	tag, err := c.FindTagByName(ctx, name, c.resolveMatch())
+	if err == nil {
+		return tag, nil
+	}
+	if !errors.Is(err, ErrTagNotFound) {
+		return nil, err
+	}
+	tag, err = c.createTag(ctx, name)
+	if err == nil {
+		return tag, nil
+	}
+	if !isConflict(err) {
+		return nil, err
+	}
+	existing, findErr := c.FindTagByName(ctx, name, MatchCaseInsensitive)
+	if findErr != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// TagExistsError is returned by RenameTag when another tag already has the
+// requested name and no merge was requested.
+type TagExistsError struct {
+	Existing Tag
+}
+
+func (e *TagExistsError) Error() string {
+	return fmt.Sprintf("tly: tag %q already exists with ID %d", e.Existing.Tag, e.Existing.ID)
+}
+
+// RenamePolicy decides what RenameTag does when the new name is taken.
+type RenamePolicy int
+
+// Supported rename policies.
+const (
+	// RenameFail returns a *TagExistsError.
+	RenameFail RenamePolicy = iota
+	// RenameMerge moves the renamed tag's links onto the existing tag and
+	// deletes the renamed tag.
+	RenameMerge
+)
+
+// RenameTagOptions configures RenameTag.
+type RenameTagOptions struct {
+	OnConflict RenamePolicy
+	// Match controls how the new name is compared with existing tags.
+	Match TagMatch
+}
+
+// RenameTag renames tag id to newName. If another tag already has that
+// name, opts.OnConflict decides whether to fail or merge into it. The
+// returned tag is the renamed tag, or the existing tag after a merge.
+func (c *Client) RenameTag(ctx context.Context, id int, newName string, opts RenameTagOptions) (*Tag, error) {
+	existing, err := c.FindTagByName(ctx, newName, opts.Match)
+	if err != nil && !errors.Is(err, ErrTagNotFound) {
+		return nil, err
+	}
+	if existing == nil || existing.ID == id {
+		return c.updateTag(ctx, id, newName)
+	}
+	if opts.OnConflict != RenameMerge {
+		return nil, &TagExistsError{Existing: *existing}
+	}
+	changes, err := c.retagLinks(ctx, []int{id}, existing.ID, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := firstChangeErr(changes); err != nil {
+		return nil, err
+	}
+	if err := c.deleteTag(ctx, id); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// TagRef identifies a tag either by ID or by name.
+type TagRef struct {
+	ID   int
+	Name string
+}
+
+// TagByID refers to a tag by its ID.
+func TagByID(id int) TagRef { return TagRef{ID: id} }
+
+// TagByName refers to a tag by its name.
+func TagByName(name string) TagRef { return TagRef{Name: name} }
+
+// resolveTag returns the ID of the referenced tag, looking names up through
+// the client's TagResolver when one is configured. Names match exactly, or
+// after normalization when a TagNormalizer is configured.
+func (c *Client) resolveTag(ctx context.Context, ref TagRef) (int, error) {
+	if ref.ID != 0 {
+		return ref.ID, nil
+	}
+	if c.tags != nil {
+		return c.tags.ID(ctx, ref.Name)
+	}
+	tag, err := c.FindTagByName(ctx, ref.Name, c.resolveMatch())
+	if err != nil {
+		return 0, fmt.Errorf("resolve tag %q: %w", ref.Name, err)
+	}
+	return tag.ID, nil
+}
+
+// ListShortLinksByTag returns every link carrying the referenced tag,
+// following pagination. Other filters in opts are applied as well; its
+// TagIDs are replaced by the referenced tag.
+func (c *Client) ListShortLinksByTag(ctx context.Context, tag TagRef, opts ShortLinkListOptions) ([]ShortLink, error) {
+	id, err := c.resolveTag(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+	opts.TagIDs = []int{id}
+	return c.ListAllShortLinks(ctx, opts)
+}
+
+// MergeTagsOptions configures MergeTags.
+type MergeTagsOptions struct {
+	// DryRun reports the changes without updating links or deleting tags.
+	DryRun bool
+}
+
+// MergeReport describes what MergeTags changed, or would change in a dry
+// run.
+type MergeReport struct {
+	DryRun      bool            `json:"dry_run"`
+	TargetID    int             `json:"target_id"`
+	Links       []LinkTagChange `json:"links"`
+	DeletedTags []int           `json:"deleted_tags"`
+}
+
+// MergeTags re-tags every link carrying one of sourceIDs with targetID and
+// then deletes the source tags. If any link fails to update, the sources
+// are kept and the error is returned along with the report.
+func (c *Client) MergeTags(ctx context.Context, sourceIDs []int, targetID int, opts MergeTagsOptions) (*MergeReport, error) {
+	var sources []int
+	for _, id := range sourceIDs {
+		if id != targetID {
+			sources = append(sources, id)
+		}
+	}
+	report := &MergeReport{DryRun: opts.DryRun, TargetID: targetID}
+	if len(sources) == 0 {
+		return report, nil
+	}
+	changes, err := c.retagLinks(ctx, sources, targetID, opts.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	report.Links = changes
+	if err := firstChangeErr(changes); err != nil {
+		return report, err
+	}
+	if opts.DryRun {
+		report.DeletedTags = sources
+		return report, nil
+	}
+	for _, id := range sources {
+		if err := c.deleteTag(ctx, id); err != nil {
+			return report, fmt.Errorf("delete tag %d: %w", id, err)
+		}
+		report.DeletedTags = append(report.DeletedTags, id)
+	}
+	return report, nil
+}
+
+// TagInUseError is returned by DeleteTagSafely when the tag is still
+// attached to links and no reassignment was requested.
+type TagInUseError struct {
+	TagID int
+	Links int
+}
+
+func (e *TagInUseError) Error() string {
+	return fmt.Sprintf("tly: tag %d is still attached to %d links", e.TagID, e.Links)
+}
+
+// DeleteTagOptions configures DeleteTagSafely.
+type DeleteTagOptions struct {
+	// ReassignTo moves the tag's links onto this tag before deleting.
+	// When zero, deleting a tag that is still in use fails.
+	ReassignTo int
+}
+
+// DeleteTagSafely deletes a tag without silently orphaning the links that
+// carry it: it either refuses with a *TagInUseError or first reassigns the
+// links to opts.ReassignTo, which must be another tag.
+func (c *Client) DeleteTagSafely(ctx context.Context, id int, opts DeleteTagOptions) error {
+	if opts.ReassignTo == 0 {
+		page, err := c.ListShortLinksPage(ctx, ShortLinkListOptions{TagIDs: []int{id}})
+		if err != nil {
+			return err
+		}
+		if n := page.count(); n > 0 {
+			return &TagInUseError{TagID: id, Links: n}
+		}
+		return c.deleteTag(ctx, id)
+	}
+	if opts.ReassignTo == id {
+		return fmt.Errorf("tly: cannot reassign the links of tag %d to itself", id)
+	}
+	_, err := c.MergeTags(ctx, []int{id}, opts.ReassignTo, MergeTagsOptions{})
+	return err
+}
+
+// TagLinkCount is a tag and the number of links carrying it.
+type TagLinkCount struct {
+	Tag   Tag `json:"tag"`
+	Links int `json:"links"`
+}
+
+// UsageOptions configures TagUsage and PixelUsageCounts.
+type UsageOptions struct {
+	// Concurrency bounds the parallel requests. Defaults to 4.
+	Concurrency int
+}
+
+// TagUsage counts the links carrying each tag, with one filtered list
+// request per tag, and returns the tags by descending count, then name.
+func (c *Client) TagUsage(ctx context.Context, opts UsageOptions) ([]TagLinkCount, error) {
+	tags, err := c.listTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := c.countLinks(ctx, len(tags), opts.Concurrency, func(i int) ShortLinkListOptions {
+		return ShortLinkListOptions{TagIDs: []int{tags[i].ID}}
+	})
+	if err != nil {
+		return nil, err
+	}
+	usage := make([]TagLinkCount, len(tags))
+	for i, t := range tags {
+		usage[i] = TagLinkCount{Tag: t, Links: counts[i]}
+	}
+	sort.SliceStable(usage, func(i, j int) bool {
+		if usage[i].Links != usage[j].Links {
+			return usage[i].Links > usage[j].Links
+		}
+		return usage[i].Tag.Tag < usage[j].Tag.Tag
+	})
+	return usage, nil
+}
+
+// countLinks counts the links matching filter(i) for every i in [0, n),
+// requesting one page per filter.
+func (c *Client) countLinks(ctx context.Context, n, concurrency int, filter func(i int) ShortLinkListOptions) ([]int, error) {
+	counts := make([]int, n)
+	err := forEachLimit(ctx, n, concurrency, func(ctx context.Context, i int) error {
+		page, err := c.ListShortLinksPage(ctx, filter(i))
+		if err != nil {
+			return err
+		}
+		counts[i] = page.count()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// DeleteTagsOptions configures DeleteTags.
+type DeleteTagsOptions struct {
+	// Concurrency bounds the parallel requests. Defaults to 4.
+	Concurrency int
+	// SkipInUse leaves tags that are still attached to links in place.
+	SkipInUse bool
+}
+
+// TagDeleteResult is the outcome of deleting one tag.
+type TagDeleteResult struct {
+	ID      int    `json:"id"`
+	Deleted bool   `json:"deleted"`
+	Skipped bool   `json:"skipped"`
+	Links   int    `json:"links,omitempty"`
+	Err     error  `json:"-"`
+	Error   string `json:"error,omitempty"`
+}
+
+// DeleteTags deletes several tags in parallel. A failure on one tag is
+// recorded in its result and does not stop the others. The results are
+// index-aligned with ids; the error is only set if ctx is done.
+func (c *Client) DeleteTags(ctx context.Context, ids []int, opts DeleteTagsOptions) ([]TagDeleteResult, error) {
+	results := make([]TagDeleteResult, len(ids))
+	err := forEachLimit(ctx, len(ids), opts.Concurrency, func(ctx context.Context, i int) error {
+		r := TagDeleteResult{ID: ids[i]}
+		defer func() { results[i] = r }()
+		if opts.SkipInUse {
+			page, err := c.ListShortLinksPage(ctx, ShortLinkListOptions{TagIDs: []int{ids[i]}})
+			if err != nil {
+				r.Err, r.Error = err, err.Error()
+				return nil
+			}
+			if r.Links = page.count(); r.Links > 0 {
+				r.Skipped = true
+				return nil
+			}
+		}
+		if err := c.deleteTag(ctx, ids[i]); err != nil {
+			r.Err, r.Error = err, err.Error()
+			return nil
+		}
+		r.Deleted = true
+		return nil
+	})
+	return results, err
+}
@@ -0,0 +1,51 @@
+package tly_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+	"github.com/timleland/t.ly-go-url-shortener-api/tlytest"
+)
+
+func TestDeleteTagSafely(t *testing.T) {
+	ctx := context.Background()
+	srv := tlytest.NewServer(tlytest.Options{})
+	defer srv.Close()
+	c := srv.Client()
+	old, err := c.GetOrCreateTag(ctx, "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := c.GetOrCreateTag(ctx, "next")
+	if err != nil {
+		t.Fatal(err)
+	}
+	link, err := c.CreateShortLink(tly.ShortLinkCreateRequest{LongURL: "https://example.com", Tags: []int{old.ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var inUse *tly.TagInUseError
+	if err := c.DeleteTagSafely(ctx, old.ID, tly.DeleteTagOptions{}); !errors.As(err, &inUse) || inUse.Links != 1 {
+		t.Fatalf("DeleteTagSafely of a tag in use = %v, want a TagInUseError for 1 link", err)
+	}
+	if err := c.DeleteTagSafely(ctx, old.ID, tly.DeleteTagOptions{ReassignTo: old.ID}); err == nil {
+		t.Fatal("DeleteTagSafely reassigning a tag to itself succeeded")
+	}
+	if len(srv.Tags()) != 2 {
+		t.Fatalf("tags = %v, want both kept", srv.Tags())
+	}
+
+	if err := c.DeleteTagSafely(ctx, old.ID, tly.DeleteTagOptions{ReassignTo: next.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if tags := srv.Tags(); len(tags) != 1 || tags[0].ID != next.ID {
+		t.Errorf("tags = %v, want only %q", tags, next.Tag)
+	}
+	got, _ := srv.Link(link.ShortURL)
+	if len(got.Tags) != 1 || got.Tags[0].ID != next.ID {
+		t.Errorf("link tags = %v, want only %q", got.Tags, next.Tag)
+	}
+}
@@ -0,0 +1,82 @@
+package tly
+
+import (
+	"context"
+	"fmt"
+)
+
+// Valid reports whether r is a known role.
+func (r TeamRole) Valid() bool {
+	switch r {
+	case RoleOwner, RoleAdmin, RoleMember, RoleViewer:
+		return true
+	}
+	return false
+}
+
+// TeamMember is a member of a workspace. Pending is set for invitations
+// that have not been accepted yet.
+type TeamMember struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      TeamRole  `json:"role"`
+	Pending   bool      `json:"pending"`
+	CreatedAt Timestamp `json:"created_at"`
+}
+
+// TeamInviteRequest is used to invite a member to a workspace.
+type TeamInviteRequest struct {
+	Email string   `json:"email"`
+	Role  TeamRole `json:"role"`
+}
+
+// ListTeamMembers retrieves the members and pending invitations of the
+// client's workspace.
+func (c *Client) ListTeamMembers(ctx context.Context) ([]TeamMember, error) {
+	var members []TeamMember
+	err := c.doRequestContext(ctx, "GET", "/api/v1/team/member", "", nil, &members)
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// InviteTeamMember invites someone to the client's workspace by email. The
+// returned member is pending until the invitation is accepted.
+func (c *Client) InviteTeamMember(ctx context.Context, reqData TeamInviteRequest) (*TeamMember, error) {
+	if reqData.Email == "" {
+		return nil, fmt.Errorf("tly: team member email is required")
+	}
+	if !reqData.Role.Valid() {
+		return nil, fmt.Errorf("tly: unknown team role %q", reqData.Role)
+	}
+	var member TeamMember
+	err := c.doRequestContext(ctx, "POST", "/api/v1/team/member", "", reqData, &member)
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// UpdateTeamMemberRole changes the role of a member.
+func (c *Client) UpdateTeamMemberRole(ctx context.Context, id int, role TeamRole) (*TeamMember, error) {
+	if !role.Valid() {
+		return nil, fmt.Errorf("tly: unknown team role %q", role)
+	}
+	path := fmt.Sprintf("/api/v1/team/member/%d", id)
+	body := map[string]TeamRole{"role": role}
+	var member TeamMember
+	err := c.doRequestContext(ctx, "PUT", path, "", body, &member)
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// RemoveTeamMember removes a member from the workspace, or withdraws a
+// pending invitation.
+func (c *Client) RemoveTeamMember(ctx context.Context, id int) error {
+	path := fmt.Sprintf("/api/v1/team/member/%d", id)
+	return c.doRequestContext(ctx, "DELETE", path, "", nil, nil)
+}
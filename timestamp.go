@@ -0,0 +1,68 @@
+package tly
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Timestamp is a time.Time that decodes the timestamp formats the API
+// returns ("2006-01-02 15:04:05", RFC 3339 with or without fractional
+// seconds; see ParseTimestamp) as well as null. It encodes as RFC 3339,
+// or null when zero.
+type Timestamp struct {
+	time.Time
+}
+
+// timestampLayouts are the timestamp formats the API returns, in
+// payloads and in the stats series alike. RFC3339Nano also accepts
+// RFC 3339 without fractional seconds.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02 15",
+	dateLayout,
+}
+
+// ParseTimestamp parses a timestamp in one of the formats the API returns.
+// An empty string is the zero time.
+func ParseTimestamp(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range timestampLayouts {
+		if parsed, err := time.Parse(layout, s); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("tly: unrecognized timestamp %q", s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		t.Time = time.Time{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := ParseTimestamp(s)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.Format(time.RFC3339))
+}
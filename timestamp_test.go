@@ -0,0 +1,108 @@
+package tly
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2026, 10, 16, 17, 34, 39, 0, time.UTC)
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2026-10-16 17:34:39", want, false},
+		{"2026-10-16T17:34:39", want, false},
+		{"2026-10-16T17:34:39Z", want, false},
+		{"2026-10-16T17:34:39.000000Z", want, false},
+		{"2026-10-16T19:34:39+02:00", want, false},
+		{"2026-10-16", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), false},
+		{"", time.Time{}, false},
+		{"yesterday", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimestamp(tt.in)
+		if (err != nil) != tt.wantErr || !got.Equal(tt.want) {
+			t.Errorf("ParseTimestamp(%q) = %v, %v; want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestTimestampJSON(t *testing.T) {
+	var v struct {
+		At   Timestamp `json:"at"`
+		Null Timestamp `json:"null"`
+	}
+	if err := json.Unmarshal([]byte(`{"at":"2026-10-16 17:34:39","null":null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if !v.At.Equal(time.Date(2026, 10, 16, 17, 34, 39, 0, time.UTC)) || !v.Null.IsZero() {
+		t.Fatalf("decoded %v and %v", v.At, v.Null)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `{"at":"2026-10-16T17:34:39Z","null":null}`; got != want {
+		t.Errorf("encoded %s, want %s", got, want)
+	}
+	if err := json.Unmarshal([]byte(`{"at":"soon"}`), &v); err == nil {
+		t.Error("decoding an unknown format succeeded")
+	}
+}
+
+func TestTagAndLinkTimes(t *testing.T) {
+	data := []byte(`{"created_at":"2025-01-02 03:04:05","updated_at":null}`)
+	want := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	var tag Tag
+	if err := json.Unmarshal(data, &tag); err != nil {
+		t.Fatal(err)
+	}
+	if !tag.CreatedAt.Equal(want) || !tag.UpdatedAt.IsZero() {
+		t.Errorf("Tag times = %v, %v, want %v and the zero time", tag.CreatedAt, tag.UpdatedAt, want)
+	}
+	var link ShortLink
+	if err := json.Unmarshal(data, &link); err != nil {
+		t.Fatal(err)
+	}
+	if !link.CreatedAt.Equal(want) || !link.UpdatedAt.IsZero() {
+		t.Errorf("ShortLink times = %v, %v, want %v and the zero time", link.CreatedAt, link.UpdatedAt, want)
+	}
+	if err := json.Unmarshal([]byte(`{"created_at":"not a time"}`), &tag); err == nil {
+		t.Error("decoding a tag with an unknown timestamp format succeeded")
+	}
+}
+
+func TestPixelAge(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	tests := []struct {
+		created, updated string
+		age, sinceUpdate time.Duration
+	}{
+		{"2026-10-06 12:00:00", "2026-10-15 12:00:00", 10 * day, day},
+		{"2026-10-06 12:00:00", "", 10 * day, 10 * day},
+		{"", "2026-10-15T12:00:00Z", 0, day},
+		{"", "", 0, 0},
+	}
+	for _, tt := range tests {
+		p := Pixel{CreatedAt: mustTimestamp(t, tt.created), UpdatedAt: mustTimestamp(t, tt.updated)}
+		if got := p.Age(now); got != tt.age {
+			t.Errorf("Age() of %q = %v, want %v", tt.created, got, tt.age)
+		}
+		if got := p.SinceUpdate(now); got != tt.sinceUpdate {
+			t.Errorf("SinceUpdate() of %q, %q = %v, want %v", tt.created, tt.updated, got, tt.sinceUpdate)
+		}
+	}
+}
+
+func mustTimestamp(t *testing.T, s string) Timestamp {
+	t.Helper()
+	parsed, err := ParseTimestamp(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Timestamp{parsed}
+}
@@ -0,0 +1,154 @@
+package tlytest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fault is a failure injected into the responses of an endpoint by Inject.
+type Fault struct {
+	// Latency delays the response. On its own the request is then handled
+	// normally; the delay is cut short if the client gives up.
+	Latency time.Duration
+	// Status answers with this status, such as 429 or 503, instead of
+	// handling the request.
+	Status int
+	// RetryAfter sets the Retry-After header of a Status answer. A 429 also
+	// gets rate limit headers announcing the reset.
+	RetryAfter time.Duration
+	// MalformedJSON answers 200 with a truncated JSON body instead of
+	// handling the request.
+	MalformedJSON bool
+	// Times is how many consecutive requests the fault applies to.
+	// Defaults to 1; a negative value applies it until ClearFaults.
+	Times int
+}
+
+// injected is a queued fault and the requests it has left.
+type injected struct {
+	pattern string
+	fault   Fault
+	left    int
+}
+
+// Inject queues faults for the requests matching pattern. Each fault
+// applies to its Times requests in turn, after which requests are handled
+// normally again, so a sequence such as two 503s followed by success is
+// written as
+//
+//	srv.Inject("POST /api/v1/link/shorten", tlytest.Fault{Status: 503, Times: 2})
+//
+// The pattern is "METHOD /path", a path matching every method, or "*" for
+// every request; a trailing "*" matches any path with that prefix, as in
+// "GET /api/v1/link/tag/*". Faults are applied before the rate limit and
+// do not count against it.
+func (s *Server) Inject(pattern string, faults ...Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, f := range faults {
+		left := f.Times
+		if left == 0 {
+			left = 1
+		}
+		s.faults = append(s.faults, &injected{pattern: pattern, fault: f, left: left})
+	}
+}
+
+// ClearFaults removes every queued fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns how many authenticated requests matching pattern the
+// server has received, including those answered with a fault or a 429.
+// The pattern is matched as by Inject.
+func (s *Server) Requests(pattern string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, route := range s.log {
+		if matchRoute(pattern, route) {
+			n++
+		}
+	}
+	return n
+}
+
+// matchRoute reports whether route, "METHOD /path", matches pattern.
+func matchRoute(pattern, route string) bool {
+	if pattern == "*" {
+		return true
+	}
+	if !strings.Contains(pattern, " ") {
+		route = route[strings.Index(route, " ")+1:]
+	}
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(route, strings.TrimSuffix(pattern, "*"))
+	}
+	return route == pattern
+}
+
+// nextFault logs the request and takes the first queued fault matching it,
+// if any.
+func (s *Server) nextFault(route string) (Fault, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.log = append(s.log, route)
+	for i, f := range s.faults {
+		if !matchRoute(f.pattern, route) {
+			continue
+		}
+		if f.left > 0 {
+			f.left--
+			if f.left == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return f.fault, true
+	}
+	return Fault{}, false
+}
+
+// fault applies the next fault queued for r and reports whether it
+// answered the request.
+func (s *Server) fault(w http.ResponseWriter, r *http.Request) bool {
+	f, ok := s.nextFault(r.Method + " " + r.URL.Path)
+	if !ok {
+		return false
+	}
+	if f.Latency > 0 {
+		t := time.NewTimer(f.Latency)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-r.Context().Done():
+			return true
+		}
+	}
+	switch {
+	case f.MalformedJSON:
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"short_url":"https://t.ly/`))
+		return true
+	case f.Status != 0:
+		if f.RetryAfter > 0 {
+			secs := int((f.RetryAfter + time.Second - 1) / time.Second)
+			w.Header().Set("Retry-After", strconv.Itoa(secs))
+			if f.Status == http.StatusTooManyRequests {
+				limit := s.opts.RateLimit
+				if limit <= 0 {
+					limit = 60
+				}
+				w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+				w.Header().Set("X-RateLimit-Remaining", "0")
+			}
+		}
+		writeError(w, f.Status, http.StatusText(f.Status))
+		return true
+	}
+	return false
+}
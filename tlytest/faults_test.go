@@ -0,0 +1,109 @@
+package tlytest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+	"github.com/timleland/t.ly-go-url-shortener-api/tlytest"
+)
+
+func TestInject(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		pattern string
+		faults  []tlytest.Fault
+		want    []int // status of each request, 0 for success, -1 for a decode error
+	}{
+		{"one failure", "GET /api/v1/link/tag", []tlytest.Fault{{Status: 503}}, []int{503, 0}},
+		{"times", "GET /api/v1/link/tag", []tlytest.Fault{{Status: 503, Times: 2}}, []int{503, 503, 0}},
+		{"sequence", "GET /api/v1/link/tag", []tlytest.Fault{{Status: 503}, {Status: 429}}, []int{503, 429, 0}},
+		{"until cleared", "GET /api/v1/link/tag", []tlytest.Fault{{Status: 500, Times: -1}}, []int{500, 500, 500}},
+		{"path for every method", "/api/v1/link/tag", []tlytest.Fault{{Status: 502}}, []int{502, 0}},
+		{"prefix", "GET /api/v1/link/*", []tlytest.Fault{{Status: 503}}, []int{503, 0}},
+		{"every request", "*", []tlytest.Fault{{Status: 503}}, []int{503, 0}},
+		{"other method", "POST /api/v1/link/tag", []tlytest.Fault{{Status: 503}}, []int{0, 0}},
+		{"other path", "GET /api/v1/link/pixel", []tlytest.Fault{{Status: 503}}, []int{0, 0}},
+		{"malformed JSON", "GET /api/v1/link/tag", []tlytest.Fault{{MalformedJSON: true}}, []int{-1, 0}},
+		{"latency only", "GET /api/v1/link/tag", []tlytest.Fault{{Latency: time.Millisecond}}, []int{0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := tlytest.NewServer(tlytest.Options{})
+			defer srv.Close()
+			c := srv.Client()
+			srv.Inject(tt.pattern, tt.faults...)
+			for i, want := range tt.want {
+				_, err := c.ListTagsPage(ctx, tly.ListOptions{})
+				got := status(err)
+				if err != nil && got == 0 {
+					got = -1
+				}
+				if got != want {
+					t.Errorf("request %d = %v, want %d", i+1, err, want)
+				}
+			}
+			if n := srv.Requests("GET /api/v1/link/tag"); n != len(tt.want) {
+				t.Errorf("Requests = %d, want %d", n, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestClearFaults(t *testing.T) {
+	srv := tlytest.NewServer(tlytest.Options{})
+	defer srv.Close()
+	c := srv.Client()
+	srv.Inject("*", tlytest.Fault{Status: 503, Times: -1})
+	if _, err := c.ListTagsPage(context.Background(), tly.ListOptions{}); status(err) != 503 {
+		t.Fatalf("request = %v, want 503", err)
+	}
+	srv.ClearFaults()
+	if _, err := c.ListTagsPage(context.Background(), tly.ListOptions{}); err != nil {
+		t.Errorf("request after ClearFaults = %v", err)
+	}
+}
+
+func TestInjectRetryAfter(t *testing.T) {
+	srv := tlytest.NewServer(tlytest.Options{RateLimit: 100})
+	defer srv.Close()
+	c := srv.Client()
+	srv.Inject("*", tlytest.Fault{Status: 429, RetryAfter: 1500 * time.Millisecond})
+	before := time.Now()
+	if _, err := c.ListTagsPage(context.Background(), tly.ListOptions{}); status(err) != 429 {
+		t.Fatalf("request = %v, want 429", err)
+	}
+	r, ok := c.LastRateLimit()
+	if !ok || r.Limit != 100 || r.Remaining != 0 {
+		t.Fatalf("LastRateLimit = %+v, %v; want limit 100 with none remaining", r, ok)
+	}
+	if wait := r.Reset.Sub(before); wait < time.Second || wait > 3*time.Second {
+		t.Errorf("reset in %s, want about 2s", wait)
+	}
+	if _, err := c.ListTagsPage(context.Background(), tly.ListOptions{}); err != nil {
+		t.Errorf("request after the fault = %v", err)
+	}
+	if r, _ := c.LastRateLimit(); r.Remaining != 99 {
+		t.Errorf("Remaining = %d, want 99: faults must not count against the limit", r.Remaining)
+	}
+}
+
+func TestInjectLatencyCancelled(t *testing.T) {
+	srv := tlytest.NewServer(tlytest.Options{})
+	defer srv.Close()
+	c := srv.Client()
+	srv.Inject("*", tlytest.Fault{Latency: time.Minute, Status: 503})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := c.ListTagsPage(ctx, tly.ListOptions{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("slow request = %v, want the deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("request took %s; the latency should end when the client gives up", elapsed)
+	}
+}
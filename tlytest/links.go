@@ -0,0 +1,502 @@
+package tlytest
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+// Visit is one click on a link. Empty fields are left out of the stats
+// breakdowns.
+type Visit struct {
+	// At is when the click happened. Defaults to the server's current time.
+	At time.Time
+	// Visitor identifies the visitor for unique click counting, e.g. an IP
+	// address.
+	Visitor  string
+	Referrer string
+	// Country is the country name, e.g. "Germany".
+	Country  string
+	Browser  string
+	Platform string
+}
+
+// link is a stored short link.
+type link struct {
+	tly.ShortLink
+	password string
+	tagIDs   []int
+	pixelIDs []int
+	created  time.Time
+	order    int
+	visits   []Visit
+}
+
+// linkRequest is the body of the create and update endpoints. Pointer
+// fields tell an absent field from an empty one.
+type linkRequest struct {
+	ShortURL         string      `json:"short_url"`
+	LongURL          *string     `json:"long_url"`
+	ShortID          *string     `json:"short_id"`
+	Domain           string      `json:"domain"`
+	ExpireAtDatetime *string     `json:"expire_at_datetime"`
+	ExpireAtViews    *int        `json:"expire_at_views"`
+	Description      *string     `json:"description"`
+	PublicStats      *bool       `json:"public_stats"`
+	Password         *string     `json:"password"`
+	Tags             *[]int      `json:"tags"`
+	Pixels           *[]int      `json:"pixels"`
+	Meta             interface{} `json:"meta"`
+}
+
+// slugPattern matches the short IDs the API accepts.
+var slugPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// errNoLink is returned by find when no link matches.
+var errNoLink = errors.New("link not found")
+
+// host returns the host of a domain or short URL, without scheme or path.
+func host(s string) string {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "https://"), "http://")
+	if i := strings.Index(s, "/"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.ToLower(s)
+}
+
+// key returns the map key of a short URL: its host and short ID.
+func key(shortURL string) string {
+	s := strings.TrimPrefix(strings.TrimPrefix(shortURL, "https://"), "http://")
+	if i := strings.Index(s, "/"); i >= 0 {
+		return strings.ToLower(s[:i]) + s[i:]
+	}
+	return s
+}
+
+// find returns the link of shortURL.
+func (s *Server) find(shortURL string) (*link, error) {
+	l, ok := s.links[key(shortURL)]
+	if !ok {
+		return nil, errNoLink
+	}
+	return l, nil
+}
+
+// Link returns the current state of the link of shortURL, for assertions.
+func (s *Server) Link(shortURL string) (tly.ShortLink, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, err := s.find(shortURL)
+	if err != nil {
+		return tly.ShortLink{}, false
+	}
+	return s.render(l), true
+}
+
+// Click records a visit to the link of shortURL as if it had been opened.
+func (s *Server) Click(shortURL string, v Visit) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, err := s.find(shortURL)
+	if err != nil {
+		return err
+	}
+	if v.At.IsZero() {
+		v.At = s.opts.Now()
+	}
+	l.visits = append(l.visits, v)
+	return nil
+}
+
+// render returns the API representation of l with its current tags and
+// pixels.
+func (s *Server) render(l *link) tly.ShortLink {
+	out := l.ShortLink
+	out.Tags, out.Pixels = nil, nil
+	for _, id := range l.tagIDs {
+		if t, ok := s.tags[id]; ok {
+			out.Tags = append(out.Tags, *t)
+		}
+	}
+	for _, id := range l.pixelIDs {
+		if p, ok := s.pixels[id]; ok {
+			out.Pixels = append(out.Pixels, *p)
+		}
+	}
+	return out
+}
+
+// validate checks the fields of req shared by create and update, writing a
+// 422 and returning false when one is invalid.
+func (s *Server) validate(w http.ResponseWriter, req *linkRequest) bool {
+	if req.LongURL == nil || *req.LongURL == "" {
+		writeInvalid(w, "long_url", "The long url field is required.")
+		return false
+	}
+	if u, err := url.Parse(*req.LongURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		writeInvalid(w, "long_url", "The long url must be a valid URL.")
+		return false
+	}
+	if req.ShortID != nil && *req.ShortID != "" && !slugPattern.MatchString(*req.ShortID) {
+		writeInvalid(w, "short_id", "The short id may only contain letters, numbers, dashes and underscores.")
+		return false
+	}
+	if req.ExpireAtDatetime != nil && *req.ExpireAtDatetime != "" {
+		if _, err := parseExpiry(*req.ExpireAtDatetime); err != nil {
+			writeInvalid(w, "expire_at_datetime", "The expire at datetime is not a valid date.")
+			return false
+		}
+	}
+	if req.ExpireAtViews != nil && *req.ExpireAtViews < 0 {
+		writeInvalid(w, "expire_at_views", "The expire at views must be at least 1.")
+		return false
+	}
+	if req.Tags != nil {
+		for _, id := range *req.Tags {
+			if _, ok := s.tags[id]; !ok {
+				writeInvalid(w, "tags", "The selected tags is invalid.")
+				return false
+			}
+		}
+	}
+	if req.Pixels != nil {
+		for _, id := range *req.Pixels {
+			if _, ok := s.pixels[id]; !ok {
+				writeInvalid(w, "pixels", "The selected pixels is invalid.")
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parseExpiry parses an expiry date in the formats the API accepts.
+func parseExpiry(v string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02 15:04:05", v); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+// apply copies the optional fields of req onto l.
+func (s *Server) apply(l *link, req *linkRequest) {
+	l.LongURL = *req.LongURL
+	if req.Description != nil {
+		l.Description = *req.Description
+	}
+	if req.ExpireAtDatetime != nil {
+		l.ExpireAtDatetime = nil
+		if *req.ExpireAtDatetime != "" {
+			l.ExpireAtDatetime = *req.ExpireAtDatetime
+		}
+	}
+	if req.ExpireAtViews != nil {
+		l.ExpireAtViews = nil
+		if *req.ExpireAtViews > 0 {
+			l.ExpireAtViews = *req.ExpireAtViews
+		}
+	}
+	if req.PublicStats != nil {
+		l.PublicStats = *req.PublicStats
+	}
+	if req.Password != nil {
+		l.password = *req.Password
+	}
+	if req.Tags != nil {
+		l.tagIDs = append([]int(nil), *req.Tags...)
+	}
+	if req.Pixels != nil {
+		l.pixelIDs = append([]int(nil), *req.Pixels...)
+	}
+	if req.Meta != nil {
+		l.Meta = req.Meta
+	}
+	l.UpdatedAt = timestamp(s.opts.Now())
+}
+
+func (s *Server) createLink(w http.ResponseWriter, r *http.Request) {
+	var req linkRequest
+	if !decode(w, r, &req) || !s.validate(w, &req) {
+		return
+	}
+	domain := req.Domain
+	if domain == "" {
+		domain = s.opts.Domain
+	}
+	h := host(domain)
+	if h == "" {
+		writeInvalid(w, "domain", "The domain is invalid.")
+		return
+	}
+	id := ""
+	if req.ShortID != nil {
+		id = *req.ShortID
+	}
+	if id != "" {
+		if _, taken := s.links[h+"/"+id]; taken {
+			writeInvalid(w, "short_id", "The short id has already been taken.")
+			return
+		}
+	} else {
+		for id == "" || s.links[h+"/"+id] != nil {
+			id = s.slug()
+		}
+	}
+	now := s.opts.Now()
+	s.created++
+	l := &link{created: now, order: s.created}
+	l.ShortURL = "https://" + h + "/" + id
+	l.ShortID = id
+	l.Domain = "https://" + h + "/"
+	l.CreatedAt = timestamp(now)
+	s.apply(l, &req)
+	s.links[h+"/"+id] = l
+	writeJSON(w, http.StatusOK, s.render(l))
+}
+
+func (s *Server) getLink(w http.ResponseWriter, r *http.Request) {
+	l, err := s.find(r.URL.Query().Get("short_url"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Link not found.")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.render(l))
+}
+
+func (s *Server) updateLink(w http.ResponseWriter, r *http.Request) {
+	var req linkRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	l, err := s.find(req.ShortURL)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Link not found.")
+		return
+	}
+	if !s.validate(w, &req) {
+		return
+	}
+	if req.ShortID != nil && *req.ShortID != "" && *req.ShortID != l.ShortID {
+		h := host(l.Domain)
+		if _, taken := s.links[h+"/"+*req.ShortID]; taken {
+			writeInvalid(w, "short_id", "The short id has already been taken.")
+			return
+		}
+		delete(s.links, h+"/"+l.ShortID)
+		l.ShortID = *req.ShortID
+		l.ShortURL = "https://" + h + "/" + l.ShortID
+		s.links[h+"/"+l.ShortID] = l
+	}
+	s.apply(l, &req)
+	writeJSON(w, http.StatusOK, s.render(l))
+}
+
+func (s *Server) deleteLink(w http.ResponseWriter, r *http.Request) {
+	var req linkRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if _, err := s.find(req.ShortURL); err != nil {
+		writeError(w, http.StatusNotFound, "Link not found.")
+		return
+	}
+	delete(s.links, key(req.ShortURL))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listLinks(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	search := strings.ToLower(q.Get("search"))
+	tagIDs := intsParam(q, "tag_ids[]")
+	pixelIDs := intsParam(q, "pixel_ids[]")
+	start, _ := time.Parse("2006-01-02", q.Get("start_date"))
+	end, _ := time.Parse("2006-01-02", q.Get("end_date"))
+	var matched []*link
+	for _, l := range s.links {
+		if search != "" && !strings.Contains(strings.ToLower(l.ShortURL+" "+l.LongURL+" "+l.Description), search) {
+			continue
+		}
+		if !containsAny(l.tagIDs, tagIDs) || !containsAny(l.pixelIDs, pixelIDs) {
+			continue
+		}
+		day := l.created.UTC().Truncate(24 * time.Hour)
+		if !start.IsZero() && day.Before(start) || !end.IsZero() && day.After(end) {
+			continue
+		}
+		matched = append(matched, l)
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].order > matched[j].order })
+	page, perPage := s.pageParams(q)
+	writeJSON(w, http.StatusOK, paginate(page, perPage, len(matched), func(from, to int) interface{} {
+		data := []tly.ShortLink{}
+		for _, l := range matched[from:to] {
+			data = append(data, s.render(l))
+		}
+		return data
+	}))
+}
+
+// intsParam returns the integer values of the query parameter name.
+func intsParam(q url.Values, name string) []int {
+	var ids []int
+	for _, v := range q[name] {
+		if id, err := strconv.Atoi(v); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// containsAny reports whether have holds one of want, or want is empty.
+func containsAny(have, want []int) bool {
+	if len(want) == 0 {
+		return true
+	}
+	for _, w := range want {
+		for _, h := range have {
+			if h == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// expired reports whether l no longer redirects at now.
+func (l *link) expired(now time.Time) bool {
+	if views, ok := l.ExpireAtViews.(int); ok && len(l.visits) >= views {
+		return true
+	}
+	if at, ok := l.ExpireAtDatetime.(string); ok {
+		if t, err := parseExpiry(at); err == nil && !now.Before(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// expand resolves a short URL and, unless the link has expired, counts the
+// request as a click by the caller's address and referrer.
+func (s *Server) expand(w http.ResponseWriter, r *http.Request) {
+	var req linkRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	l, err := s.find(req.ShortURL)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Link not found.")
+		return
+	}
+	if l.password != "" && (req.Password == nil || *req.Password != l.password) {
+		writeError(w, http.StatusUnauthorized, "The password is incorrect.")
+		return
+	}
+	now := s.opts.Now()
+	if l.expired(now) {
+		writeJSON(w, http.StatusOK, tly.ExpandResponse{LongURL: l.LongURL, Expired: true})
+		return
+	}
+	visitor := r.RemoteAddr
+	if i := strings.LastIndex(visitor, ":"); i >= 0 {
+		visitor = visitor[:i]
+	}
+	l.visits = append(l.visits, Visit{At: now, Visitor: visitor, Referrer: r.Referer()})
+	writeJSON(w, http.StatusOK, tly.ExpandResponse{LongURL: l.LongURL})
+}
+
+// stats answers with the clicks of a link within the requested dates,
+// broken down the way the API does. The parameters of a POST, which
+// unlocks a password-protected link, are in its JSON body.
+func (s *Server) stats(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if r.Method == http.MethodPost {
+		var body map[string]string
+		if !decode(w, r, &body) {
+			return
+		}
+		q = url.Values{}
+		for k, v := range body {
+			q.Set(k, v)
+		}
+	}
+	l, err := s.find(q.Get("short_url"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Link not found.")
+		return
+	}
+	if l.password != "" && q.Get("password") != l.password {
+		writeError(w, http.StatusUnauthorized, "The password is incorrect.")
+		return
+	}
+	start, _ := time.Parse("2006-01-02", q.Get("start_date"))
+	end, _ := time.Parse("2006-01-02", q.Get("end_date"))
+	hourly := q.Get("granularity") == "hour"
+	visitors := map[string]bool{}
+	counts := map[string]map[string]int{}
+	count := func(series, name string) {
+		if name == "" {
+			return
+		}
+		if counts[series] == nil {
+			counts[series] = map[string]int{}
+		}
+		counts[series][name]++
+	}
+	clicks := 0
+	for _, v := range l.visits {
+		at := v.At.UTC()
+		day := at.Truncate(24 * time.Hour)
+		if !start.IsZero() && day.Before(start) || !end.IsZero() && day.After(end) {
+			continue
+		}
+		clicks++
+		if v.Visitor != "" {
+			visitors[v.Visitor] = true
+		}
+		count("browser", v.Browser)
+		count("countryName", v.Country)
+		count("referrer", v.Referrer)
+		count("platform", v.Platform)
+		count("day", at.Format("2006-01-02"))
+		count("hour", at.Format("2006-01-02 15:00:00"))
+	}
+	body := map[string]interface{}{
+		"clicks":        clicks,
+		"unique_clicks": len(visitors),
+		"browsers":      rows(counts["browser"], "browser", false),
+		"countries":     rows(counts["countryName"], "countryName", false),
+		"referrers":     rows(counts["referrer"], "referrer", false),
+		"platforms":     rows(counts["platform"], "platform", false),
+		"daily_clicks":  rows(counts["day"], "date", true),
+		"data":          map[string]interface{}{},
+	}
+	if hourly {
+		body["hourly_clicks"] = rows(counts["hour"], "date", true)
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+// rows returns counts as breakdown rows named under nameKey, ordered by
+// name when byName is set and by descending total otherwise.
+func rows(counts map[string]int, nameKey string, byName bool) []map[string]interface{} {
+	out := []map[string]interface{}{}
+	for name, total := range counts {
+		out = append(out, map[string]interface{}{nameKey: name, "total": total})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i][nameKey].(string), out[j][nameKey].(string)
+		ta, tb := out[i]["total"].(int), out[j]["total"].(int)
+		if byName || ta == tb {
+			return a < b
+		}
+		return ta > tb
+	})
+	return out
+}
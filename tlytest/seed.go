@@ -0,0 +1,226 @@
+package tlytest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+// Fixture is a known set of resources for Seed to create. Tags and pixels
+// are referred to by their names in the fixture; Seed prefixes every name
+// and alias so runs do not collide and leftovers can be found.
+type Fixture struct {
+	// Prefix is prepended to every name and alias. Defaults to NewPrefix().
+	Prefix string
+	Tags   []string
+	Pixels []tly.PixelCreateRequest
+	Links  []FixtureLink
+}
+
+// FixtureLink is a link to create.
+type FixtureLink struct {
+	LongURL string
+	// Alias is the short ID before prefixing. Defaults to "link-<n>", n
+	// being the position of the link in the fixture, counting from 1.
+	Alias       string
+	Description string
+	// Tags and Pixels name tags and pixels of the fixture.
+	Tags   []string
+	Pixels []string
+}
+
+// Seeded holds the resources created by Seed, keyed by their fixture
+// names. Links are in fixture order.
+type Seeded struct {
+	Prefix string
+	Tags   map[string]tly.Tag
+	Pixels map[string]tly.Pixel
+	Links  []tly.ShortLink
+
+	c *tly.Client
+}
+
+// prefixes counts the prefixes returned by NewPrefix.
+var prefixes int64
+
+// NewPrefix returns a prefix unique to this run, made of the current time
+// and a counter.
+func NewPrefix() string {
+	n := atomic.AddInt64(&prefixes, 1)
+	return fmt.Sprintf("tlytest-%s-%d-", strconv.FormatInt(time.Now().UnixNano(), 36), n)
+}
+
+// Seed creates the resources of f with c. If one cannot be created, those
+// already created are deleted and the error is returned. Call Cleanup on
+// the result when done, or use MustSeed in tests.
+func Seed(ctx context.Context, c *tly.Client, f Fixture) (*Seeded, error) {
+	if f.Prefix == "" {
+		f.Prefix = NewPrefix()
+	}
+	s := &Seeded{Prefix: f.Prefix, Tags: map[string]tly.Tag{}, Pixels: map[string]tly.Pixel{}, c: c}
+	if err := s.create(ctx, f); err != nil {
+		s.Cleanup(ctx)
+		return nil, err
+	}
+	return s, nil
+}
+
+// create creates the resources of f, recording each in s as it goes.
+func (s *Seeded) create(ctx context.Context, f Fixture) error {
+	for _, name := range f.Tags {
+		tag, err := s.c.CreateTag(s.Prefix + name)
+		if err != nil {
+			return fmt.Errorf("tlytest: seeding tag %q: %w", name, err)
+		}
+		s.Tags[name] = *tag
+	}
+	for _, p := range f.Pixels {
+		name := p.Name
+		p.Name = s.Prefix + name
+		pixel, err := s.c.CreatePixel(p)
+		if err != nil {
+			return fmt.Errorf("tlytest: seeding pixel %q: %w", name, err)
+		}
+		s.Pixels[name] = *pixel
+	}
+	for i, l := range f.Links {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		alias := l.Alias
+		if alias == "" {
+			alias = fmt.Sprintf("link-%d", i+1)
+		}
+		req := tly.ShortLinkCreateRequest{LongURL: l.LongURL}
+		shortID := s.Prefix + alias
+		req.ShortID = &shortID
+		if l.Description != "" {
+			req.Description = &l.Description
+		}
+		for _, name := range l.Tags {
+			tag, ok := s.Tags[name]
+			if !ok {
+				return fmt.Errorf("tlytest: link %q refers to tag %q, which is not in the fixture", alias, name)
+			}
+			req.Tags = append(req.Tags, tag.ID)
+		}
+		for _, name := range l.Pixels {
+			pixel, ok := s.Pixels[name]
+			if !ok {
+				return fmt.Errorf("tlytest: link %q refers to pixel %q, which is not in the fixture", alias, name)
+			}
+			req.Pixels = append(req.Pixels, pixel.ID)
+		}
+		link, err := s.c.CreateShortLink(req)
+		if err != nil {
+			return fmt.Errorf("tlytest: seeding link %q: %w", alias, err)
+		}
+		s.Links = append(s.Links, *link)
+	}
+	return nil
+}
+
+// Cleanup deletes the seeded links, pixels and tags. It tries every one and
+// returns the first error; resources already gone are not an error.
+func (s *Seeded) Cleanup(ctx context.Context) error {
+	var d deleter
+	for _, l := range s.Links {
+		d.do(l.ShortURL, func() error { return s.c.DeleteShortLink(l.ShortURL) })
+	}
+	for _, p := range s.Pixels {
+		id := p.ID
+		d.do(p.Name, func() error { return s.c.DeletePixel(id) })
+	}
+	for _, t := range s.Tags {
+		id := t.ID
+		d.do(t.Tag, func() error { return s.c.DeleteTag(id) })
+	}
+	return d.err()
+}
+
+// MustSeed seeds f for a test, failing it if seeding fails, and deletes
+// the resources when the test and its subtests have finished.
+func MustSeed(tb testing.TB, c *tly.Client, f Fixture) *Seeded {
+	tb.Helper()
+	s, err := Seed(context.Background(), c, f)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() {
+		if err := s.Cleanup(context.Background()); err != nil {
+			tb.Error(err)
+		}
+	})
+	return s
+}
+
+// CleanupPrefix deletes every link whose short ID starts with prefix and
+// every pixel and tag whose name does, such as the leftovers of seeded runs
+// that crashed before cleaning up.
+func CleanupPrefix(ctx context.Context, c *tly.Client, prefix string) error {
+	if prefix == "" {
+		return fmt.Errorf("tlytest: refusing to clean up an empty prefix")
+	}
+	links, err := c.ListAllShortLinks(ctx, tly.ShortLinkListOptions{})
+	if err != nil {
+		return err
+	}
+	pixels, err := c.ListAllPixels(ctx, tly.ListOptions{})
+	if err != nil {
+		return err
+	}
+	tags, err := c.ListAllTags(ctx, tly.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var d deleter
+	for _, l := range links {
+		if strings.HasPrefix(l.ShortID, prefix) {
+			shortURL := l.ShortURL
+			d.do(shortURL, func() error { return c.DeleteShortLink(shortURL) })
+		}
+	}
+	for _, p := range pixels {
+		if strings.HasPrefix(p.Name, prefix) {
+			id := p.ID
+			d.do(p.Name, func() error { return c.DeletePixel(id) })
+		}
+	}
+	for _, t := range tags {
+		if strings.HasPrefix(t.Tag, prefix) {
+			id := t.ID
+			d.do(t.Tag, func() error { return c.DeleteTag(id) })
+		}
+	}
+	return d.err()
+}
+
+// deleter runs deletions, counting the failures and keeping the first.
+type deleter struct {
+	failed int
+	first  error
+}
+
+// do runs del for the resource name. A 404 counts as deleted.
+func (d *deleter) do(name string, del func() error) {
+	if err := del(); err != nil && !tly.IsNotFound(err) {
+		if d.first == nil {
+			d.first = fmt.Errorf("tlytest: deleting %s: %w", name, err)
+		}
+		d.failed++
+	}
+}
+
+// err returns the first failure, noting how many others there were.
+func (d *deleter) err() error {
+	if d.failed > 1 {
+		return fmt.Errorf("%w (and %d more)", d.first, d.failed-1)
+	}
+	return d.first
+}
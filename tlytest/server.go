@@ -0,0 +1,257 @@
+// Package tlytest provides an in-memory fake of the T.LY API for tests.
+//
+// A Server behaves like the real API closely enough to exercise error
+// paths as well as happy ones: taken slugs and invalid input are rejected
+// with 422, lists are paginated, requests beyond the rate limit get 429,
+// and expanding a link counts as a click that shows up in its stats.
+// Latency, error statuses and malformed bodies can be injected per
+// endpoint with Inject to test retries and other resilience features.
+//
+//	srv := tlytest.NewServer(tlytest.Options{RateLimit: 60})
+//	defer srv.Close()
+//	client := srv.Client()
+//	link, err := client.CreateShortLink(tly.ShortLinkCreateRequest{LongURL: "https://example.com"})
+package tlytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+// Options configures a Server. Zero values use the defaults.
+type Options struct {
+	// APIKey is the key requests must carry. Defaults to "test-key".
+	APIKey string
+	// Domain is the domain links are created on when a request does not
+	// name one. Defaults to "https://t.ly/".
+	Domain string
+	// PerPage is the page size of paginated lists. Defaults to 10.
+	PerPage int
+	// RateLimit is the number of requests allowed per minute. Further
+	// requests are answered with 429 until the minute is over. Zero
+	// disables rate limiting.
+	RateLimit int
+	// Now returns the current time, for expiry, timestamps, daily stats
+	// and the rate limit window. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Server is a fake T.LY API served over HTTP on a local address. It is
+// safe for concurrent use.
+type Server struct {
+	*httptest.Server
+	opts Options
+
+	mu      sync.Mutex
+	links   map[string]*link // by short URL
+	tags    map[int]*tly.Tag
+	pixels  map[int]*tly.Pixel
+	nextID  int
+	seq     int
+	created int
+	window  time.Time
+	used    int
+	faults  []*injected
+	log     []string
+}
+
+// NewServer starts a Server. Close it when done.
+func NewServer(opts Options) *Server {
+	if opts.APIKey == "" {
+		opts.APIKey = "test-key"
+	}
+	if opts.Domain == "" {
+		opts.Domain = "https://t.ly/"
+	}
+	if opts.PerPage <= 0 {
+		opts.PerPage = 10
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	s := &Server{
+		opts:   opts,
+		links:  map[string]*link{},
+		tags:   map[int]*tly.Tag{},
+		pixels: map[int]*tly.Pixel{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns a client authenticated against the server.
+func (s *Server) Client(opts ...tly.ClientOption) *tly.Client {
+	c := tly.NewClient(s.opts.APIKey, opts...)
+	c.BaseURL = s.URL
+	return c
+}
+
+// apiError is the error body of a failed request, in the shape the API
+// uses: a message and, for validation failures, the messages per field.
+type apiError struct {
+	Message string              `json:"message"`
+	Errors  map[string][]string `json:"errors,omitempty"`
+}
+
+// writeJSON writes v with status.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error body with status.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, apiError{Message: message})
+}
+
+// writeInvalid writes a 422 validation failure of field.
+func writeInvalid(w http.ResponseWriter, field, message string) {
+	writeJSON(w, http.StatusUnprocessableEntity, apiError{
+		Message: message,
+		Errors:  map[string][]string{field: {message}},
+	})
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+s.opts.APIKey {
+		writeError(w, http.StatusUnauthorized, "Unauthenticated.")
+		return
+	}
+	if s.fault(w, r) {
+		return
+	}
+	if !s.allow(w) {
+		writeError(w, http.StatusTooManyRequests, "Too Many Attempts.")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	route := r.Method + " " + r.URL.Path
+	switch {
+	case route == "POST /api/v1/link/shorten":
+		s.createLink(w, r)
+	case route == "GET /api/v1/link":
+		s.getLink(w, r)
+	case route == "PUT /api/v1/link":
+		s.updateLink(w, r)
+	case route == "DELETE /api/v1/link":
+		s.deleteLink(w, r)
+	case route == "GET /api/v1/link/list":
+		s.listLinks(w, r)
+	case route == "POST /api/v1/link/expand":
+		s.expand(w, r)
+	case route == "GET /api/v1/link/stats" || route == "POST /api/v1/link/stats":
+		s.stats(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/v1/link/tag"):
+		s.serveTags(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/v1/link/pixel"):
+		s.servePixels(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "Not Found.")
+	}
+}
+
+// allow counts a request against the rate limit, sets the rate limit
+// headers and reports whether the request may proceed.
+func (s *Server) allow(w http.ResponseWriter) bool {
+	if s.opts.RateLimit <= 0 {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.opts.Now()
+	if now.Sub(s.window) >= time.Minute {
+		s.window, s.used = now, 0
+	}
+	reset := s.window.Add(time.Minute)
+	h := w.Header()
+	h.Set("X-RateLimit-Limit", strconv.Itoa(s.opts.RateLimit))
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	if s.used >= s.opts.RateLimit {
+		h.Set("X-RateLimit-Remaining", "0")
+		h.Set("Retry-After", strconv.Itoa(int(reset.Sub(now).Seconds()+0.999)))
+		return false
+	}
+	s.used++
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(s.opts.RateLimit-s.used))
+	return true
+}
+
+// decode reads the JSON request body into v, answering 400 when it is
+// malformed.
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "Malformed JSON body.")
+		return false
+	}
+	return true
+}
+
+// pageParams returns the page and page size requested by q.
+func (s *Server) pageParams(q url.Values) (page, perPage int) {
+	page, _ = strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ = strconv.Atoi(q.Get("per_page"))
+	if perPage < 1 {
+		perPage = s.opts.PerPage
+	}
+	return page, perPage
+}
+
+// paginate returns the items of page as a paginated list body.
+func paginate(page, perPage, total int, items func(from, to int) interface{}) map[string]interface{} {
+	last := (total + perPage - 1) / perPage
+	if last < 1 {
+		last = 1
+	}
+	from := (page - 1) * perPage
+	if from > total {
+		from = total
+	}
+	to := from + perPage
+	if to > total {
+		to = total
+	}
+	return map[string]interface{}{
+		"current_page": page,
+		"last_page":    last,
+		"per_page":     perPage,
+		"total":        total,
+		"data":         items(from, to),
+	}
+}
+
+// idFromPath returns the numeric ID following prefix in path.
+func idFromPath(path, prefix string) (int, bool) {
+	id, err := strconv.Atoi(strings.TrimPrefix(path, prefix))
+	return id, err == nil
+}
+
+// timestamp returns t at the second precision the API reports.
+func timestamp(t time.Time) tly.Timestamp {
+	return tly.Timestamp{Time: t.UTC().Truncate(time.Second)}
+}
+
+// newID returns the next tag or pixel ID.
+func (s *Server) newID() int {
+	s.nextID++
+	return s.nextID
+}
+
+// slug returns a fresh five-character short ID.
+func (s *Server) slug() string {
+	s.seq++
+	return fmt.Sprintf("%05s", strconv.FormatInt(int64(s.seq)+36*36*36*36, 36))
+}
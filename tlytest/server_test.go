@@ -0,0 +1,214 @@
+package tlytest_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+	"github.com/timleland/t.ly-go-url-shortener-api/tlytest"
+)
+
+// status returns the HTTP status of an API error, or 0.
+func status(err error) int {
+	var apiErr *tly.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// clock is a settable time source for Options.Now.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func str(s string) *string { return &s }
+
+func TestUnauthenticated(t *testing.T) {
+	srv := tlytest.NewServer(tlytest.Options{APIKey: "right"})
+	defer srv.Close()
+	c := tly.NewClient("wrong")
+	c.BaseURL = srv.URL
+	if _, err := c.GetShortLink("https://t.ly/abc"); status(err) != 401 {
+		t.Errorf("request with the wrong key = %v, want 401", err)
+	}
+	if n := srv.Requests("*"); n != 0 {
+		t.Errorf("Requests counted %d unauthenticated requests", n)
+	}
+}
+
+func TestCreateValidation(t *testing.T) {
+	srv := tlytest.NewServer(tlytest.Options{})
+	defer srv.Close()
+	c := srv.Client()
+	if _, err := c.CreateShortLink(tly.ShortLinkCreateRequest{LongURL: "https://example.com", ShortID: str("taken")}); err != nil {
+		t.Fatal(err)
+	}
+	views := -1
+	tests := []struct {
+		name string
+		req  tly.ShortLinkCreateRequest
+		want int
+	}{
+		{"valid", tly.ShortLinkCreateRequest{LongURL: "https://example.com/a"}, 0},
+		{"custom domain", tly.ShortLinkCreateRequest{LongURL: "https://example.com", Domain: "https://go.example/"}, 0},
+		{"no long URL", tly.ShortLinkCreateRequest{}, 422},
+		{"not a URL", tly.ShortLinkCreateRequest{LongURL: "example"}, 422},
+		{"not HTTP", tly.ShortLinkCreateRequest{LongURL: "ftp://example.com"}, 422},
+		{"taken slug", tly.ShortLinkCreateRequest{LongURL: "https://example.com", ShortID: str("taken")}, 422},
+		{"taken on another domain", tly.ShortLinkCreateRequest{LongURL: "https://example.com", ShortID: str("taken"), Domain: "https://go.example/"}, 0},
+		{"bad slug", tly.ShortLinkCreateRequest{LongURL: "https://example.com", ShortID: str("a b")}, 422},
+		{"bad expiry", tly.ShortLinkCreateRequest{LongURL: "https://example.com", ExpireAtDatetime: str("tomorrow")}, 422},
+		{"negative views", tly.ShortLinkCreateRequest{LongURL: "https://example.com", ExpireAtViews: &views}, 422},
+		{"unknown tag", tly.ShortLinkCreateRequest{LongURL: "https://example.com", Tags: []int{99}}, 422},
+		{"unknown pixel", tly.ShortLinkCreateRequest{LongURL: "https://example.com", Pixels: []int{99}}, 422},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := c.CreateShortLink(tt.req)
+			if got := status(err); got != tt.want || tt.want == 0 && err != nil {
+				t.Errorf("CreateShortLink = %v, want status %d", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkLifecycle(t *testing.T) {
+	srv := tlytest.NewServer(tlytest.Options{})
+	defer srv.Close()
+	c := srv.Client()
+	link, err := c.CreateShortLink(tly.ShortLinkCreateRequest{LongURL: "https://example.com", ShortID: str("docs")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link.ShortURL != "https://t.ly/docs" {
+		t.Errorf("ShortURL = %q, want https://t.ly/docs", link.ShortURL)
+	}
+	if _, err := c.UpdateShortLink(tly.ShortLinkUpdateRequest{ShortURL: link.ShortURL, LongURL: "https://example.org", Description: str("moved")}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.GetShortLink(link.ShortURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LongURL != "https://example.org" || got.Description != "moved" {
+		t.Errorf("updated link = %+v", got)
+	}
+
+	expanded, err := c.ExpandShortLink(tly.ExpandRequest{ShortURL: link.ShortURL})
+	if err != nil || expanded.LongURL != "https://example.org" {
+		t.Fatalf("ExpandShortLink = %+v, %v", expanded, err)
+	}
+	if err := srv.Click(link.ShortURL, tlytest.Visit{Visitor: "10.0.0.9", Browser: "Firefox"}); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := c.GetStats(link.ShortURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Clicks != 2 || stats.UniqueClicks != 2 {
+		t.Errorf("stats = %d clicks, %d unique; want 2, 2", stats.Clicks, stats.UniqueClicks)
+	}
+	if b := stats.BrowserBreakdown(); len(b) != 1 || b[0].Name != "Firefox" || b[0].Total != 1 {
+		t.Errorf("browsers = %+v, want Firefox once", b)
+	}
+
+	if err := c.DeleteShortLink(link.ShortURL); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetShortLink(link.ShortURL); !tly.IsNotFound(err) {
+		t.Errorf("GetShortLink after delete = %v, want 404", err)
+	}
+}
+
+func TestExpandProtection(t *testing.T) {
+	// The clock only moves forward, so cases run in order.
+	clk := &clock{now: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+	srv := tlytest.NewServer(tlytest.Options{Now: clk.Now})
+	defer srv.Close()
+	c := srv.Client()
+	one := 1
+	tests := []struct {
+		name     string
+		req      tly.ShortLinkCreateRequest
+		password *string
+		advance  time.Duration
+		status   int
+		expired  []bool
+	}{
+		{"plain", tly.ShortLinkCreateRequest{}, nil, 0, 0, []bool{false, false}},
+		{"one view", tly.ShortLinkCreateRequest{ExpireAtViews: &one}, nil, 0, 0, []bool{false, true}},
+		{"before expiry date", tly.ShortLinkCreateRequest{ExpireAtDatetime: str("2024-03-01 13:00:00")}, nil, 0, 0, []bool{false}},
+		{"expiry date", tly.ShortLinkCreateRequest{ExpireAtDatetime: str("2024-03-01 13:00:00")}, nil, 2 * time.Hour, 0, []bool{true}},
+		{"right password", tly.ShortLinkCreateRequest{Password: str("s3cret")}, str("s3cret"), 0, 0, []bool{false}},
+		{"wrong password", tly.ShortLinkCreateRequest{Password: str("s3cret")}, str("guess"), 0, 401, nil},
+		{"no password", tly.ShortLinkCreateRequest{Password: str("s3cret")}, nil, 0, 401, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.LongURL = "https://example.com"
+			link, err := c.CreateShortLink(tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			clk.Add(tt.advance)
+			if tt.status != 0 {
+				_, err := c.ExpandShortLink(tly.ExpandRequest{ShortURL: link.ShortURL, Password: tt.password})
+				if status(err) != tt.status {
+					t.Errorf("ExpandShortLink = %v, want status %d", err, tt.status)
+				}
+				return
+			}
+			for i, want := range tt.expired {
+				got, err := c.ExpandShortLink(tly.ExpandRequest{ShortURL: link.ShortURL, Password: tt.password})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Expired != want {
+					t.Errorf("expand %d: Expired = %v, want %v", i+1, got.Expired, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	clk := &clock{now: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+	srv := tlytest.NewServer(tlytest.Options{RateLimit: 2, Now: clk.Now})
+	defer srv.Close()
+	c := srv.Client()
+	for i := 0; i < 2; i++ {
+		if _, err := c.ListTagsPage(context.Background(), tly.ListOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.ListTagsPage(context.Background(), tly.ListOptions{}); status(err) != 429 {
+		t.Fatalf("request over the limit = %v, want 429", err)
+	}
+	if r, ok := c.LastRateLimit(); !ok || r.Limit != 2 || r.Remaining != 0 {
+		t.Errorf("LastRateLimit = %+v, %v; want limit 2 with none remaining", r, ok)
+	}
+	clk.Add(time.Minute)
+	if _, err := c.ListTagsPage(context.Background(), tly.ListOptions{}); err != nil {
+		t.Errorf("request in the next window = %v", err)
+	}
+	if n := srv.Requests("GET /api/v1/link/tag"); n != 4 {
+		t.Errorf("Requests = %d, want 4 including the rejected one", n)
+	}
+}
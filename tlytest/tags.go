@@ -0,0 +1,237 @@
+package tlytest
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+// paginated reports whether a list request asked for a page. Without page
+// parameters the tag and pixel lists answer with a bare array, as the API
+// does.
+func paginated(q url.Values) bool {
+	return q.Get("page") != "" || q.Get("per_page") != ""
+}
+
+// sortedTags returns the stored tags by ID.
+func (s *Server) sortedTags() []tly.Tag {
+	tags := []tly.Tag{}
+	for _, t := range s.tags {
+		tags = append(tags, *t)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].ID < tags[j].ID })
+	return tags
+}
+
+// Tags returns the stored tags by ID, for assertions.
+func (s *Server) Tags() []tly.Tag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sortedTags()
+}
+
+// tagTaken reports whether another tag than id is named name.
+func (s *Server) tagTaken(name string, id int) bool {
+	for _, t := range s.tags {
+		if t.ID != id && strings.EqualFold(t.Tag, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) serveTags(w http.ResponseWriter, r *http.Request) {
+	const prefix = "/api/v1/link/tag"
+	if r.URL.Path == prefix {
+		switch r.Method {
+		case "GET":
+			tags := s.sortedTags()
+			q := r.URL.Query()
+			if !paginated(q) {
+				writeJSON(w, http.StatusOK, tags)
+				return
+			}
+			page, perPage := s.pageParams(q)
+			writeJSON(w, http.StatusOK, paginate(page, perPage, len(tags), func(from, to int) interface{} {
+				return tags[from:to]
+			}))
+		case "POST":
+			var req struct {
+				Tag string `json:"tag"`
+			}
+			if !decode(w, r, &req) {
+				return
+			}
+			if strings.TrimSpace(req.Tag) == "" {
+				writeInvalid(w, "tag", "The tag field is required.")
+				return
+			}
+			if s.tagTaken(req.Tag, 0) {
+				writeInvalid(w, "tag", "The tag has already been taken.")
+				return
+			}
+			now := timestamp(s.opts.Now())
+			t := &tly.Tag{ID: s.newID(), Tag: req.Tag, CreatedAt: now, UpdatedAt: now}
+			s.tags[t.ID] = t
+			writeJSON(w, http.StatusOK, t)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		}
+		return
+	}
+	id, ok := idFromPath(r.URL.Path, prefix+"/")
+	t := s.tags[id]
+	if !ok || t == nil {
+		writeError(w, http.StatusNotFound, "Tag not found.")
+		return
+	}
+	switch r.Method {
+	case "GET":
+		writeJSON(w, http.StatusOK, t)
+	case "PUT":
+		var req struct {
+			Tag string `json:"tag"`
+		}
+		if !decode(w, r, &req) {
+			return
+		}
+		if strings.TrimSpace(req.Tag) == "" {
+			writeInvalid(w, "tag", "The tag field is required.")
+			return
+		}
+		if s.tagTaken(req.Tag, id) {
+			writeInvalid(w, "tag", "The tag has already been taken.")
+			return
+		}
+		t.Tag = req.Tag
+		t.UpdatedAt = timestamp(s.opts.Now())
+		writeJSON(w, http.StatusOK, t)
+	case "DELETE":
+		delete(s.tags, id)
+		for _, l := range s.links {
+			l.tagIDs = without(l.tagIDs, id)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+	}
+}
+
+// without returns ids with id removed.
+func without(ids []int, id int) []int {
+	out := ids[:0]
+	for _, v := range ids {
+		if v != id {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// sortedPixels returns the stored pixels by ID.
+func (s *Server) sortedPixels() []tly.Pixel {
+	pixels := []tly.Pixel{}
+	for _, p := range s.pixels {
+		pixels = append(pixels, *p)
+	}
+	sort.Slice(pixels, func(i, j int) bool { return pixels[i].ID < pixels[j].ID })
+	return pixels
+}
+
+// Pixels returns the stored pixels by ID, for assertions.
+func (s *Server) Pixels() []tly.Pixel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sortedPixels()
+}
+
+// pixelRequest is the body of the pixel create and update endpoints.
+type pixelRequest struct {
+	Name      string        `json:"name"`
+	PixelID   string        `json:"pixel_id"`
+	PixelType tly.PixelType `json:"pixel_type"`
+}
+
+// validatePixel checks req, writing a 422 and returning false when it is
+// invalid or another pixel than id tracks the same platform pixel.
+func (s *Server) validatePixel(w http.ResponseWriter, req pixelRequest, id int) bool {
+	switch {
+	case strings.TrimSpace(req.Name) == "":
+		writeInvalid(w, "name", "The name field is required.")
+		return false
+	case strings.TrimSpace(req.PixelID) == "":
+		writeInvalid(w, "pixel_id", "The pixel id field is required.")
+		return false
+	}
+	if !req.PixelType.Valid() {
+		writeInvalid(w, "pixel_type", "The selected pixel type is invalid.")
+		return false
+	}
+	for _, p := range s.pixels {
+		if p.ID != id && p.PixelType == req.PixelType && p.PixelID == req.PixelID {
+			writeInvalid(w, "pixel_id", "The pixel id has already been taken.")
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) servePixels(w http.ResponseWriter, r *http.Request) {
+	const prefix = "/api/v1/link/pixel"
+	if r.URL.Path == prefix {
+		switch r.Method {
+		case "GET":
+			pixels := s.sortedPixels()
+			q := r.URL.Query()
+			if !paginated(q) {
+				writeJSON(w, http.StatusOK, pixels)
+				return
+			}
+			page, perPage := s.pageParams(q)
+			writeJSON(w, http.StatusOK, paginate(page, perPage, len(pixels), func(from, to int) interface{} {
+				return pixels[from:to]
+			}))
+		case "POST":
+			var req pixelRequest
+			if !decode(w, r, &req) || !s.validatePixel(w, req, 0) {
+				return
+			}
+			now := timestamp(s.opts.Now())
+			p := &tly.Pixel{ID: s.newID(), Name: req.Name, PixelID: req.PixelID, PixelType: req.PixelType, CreatedAt: now, UpdatedAt: now}
+			s.pixels[p.ID] = p
+			writeJSON(w, http.StatusOK, p)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		}
+		return
+	}
+	id, ok := idFromPath(r.URL.Path, prefix+"/")
+	p := s.pixels[id]
+	if !ok || p == nil {
+		writeError(w, http.StatusNotFound, "Pixel not found.")
+		return
+	}
+	switch r.Method {
+	case "GET":
+		writeJSON(w, http.StatusOK, p)
+	case "PUT":
+		var req pixelRequest
+		if !decode(w, r, &req) || !s.validatePixel(w, req, id) {
+			return
+		}
+		p.Name, p.PixelID, p.PixelType = req.Name, req.PixelID, req.PixelType
+		p.UpdatedAt = timestamp(s.opts.Now())
+		writeJSON(w, http.StatusOK, p)
+	case "DELETE":
+		delete(s.pixels, id)
+		for _, l := range s.links {
+			l.pixelIDs = without(l.pixelIDs, id)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+	}
+}
@@ -0,0 +1,95 @@
+package tly
+
+import (
+	"context"
+	"net/url"
+	"sort"
+)
+
+// UTM holds the UTM parameters of a destination URL.
+type UTM struct {
+	Source   string `json:"utm_source,omitempty"`
+	Medium   string `json:"utm_medium,omitempty"`
+	Campaign string `json:"utm_campaign,omitempty"`
+	Term     string `json:"utm_term,omitempty"`
+	Content  string `json:"utm_content,omitempty"`
+}
+
+// ParseUTM extracts the UTM parameters from longURL. Unparseable URLs yield
+// an empty UTM.
+func ParseUTM(longURL string) UTM {
+	u, err := url.Parse(longURL)
+	if err != nil {
+		return UTM{}
+	}
+	q := u.Query()
+	return UTM{
+		Source:   q.Get("utm_source"),
+		Medium:   q.Get("utm_medium"),
+		Campaign: q.Get("utm_campaign"),
+		Term:     q.Get("utm_term"),
+		Content:  q.Get("utm_content"),
+	}
+}
+
+// CampaignAttribution is the merged stats of every link sharing a UTM
+// campaign, source and medium.
+type CampaignAttribution struct {
+	Campaign string   `json:"campaign"`
+	Source   string   `json:"source"`
+	Medium   string   `json:"medium"`
+	Links    []string `json:"links"`
+	Stats    *Stats   `json:"stats"`
+}
+
+// AttributeByUTM groups link stats by the UTM campaign, source and medium of
+// each link's long URL. stats is keyed by short URL, as GetStatsMulti
+// returns it; a link without stats is listed in its group but adds no
+// clicks. Links without UTM parameters are grouped under empty values.
+// Groups are ordered by descending clicks.
+func AttributeByUTM(links []ShortLink, stats map[string]*Stats) []CampaignAttribution {
+	type key struct{ campaign, source, medium string }
+	groups := map[key]*CampaignAttribution{}
+	members := map[key][]*Stats{}
+	var order []key
+	for _, link := range links {
+		utm := ParseUTM(link.LongURL)
+		k := key{utm.Campaign, utm.Source, utm.Medium}
+		g, ok := groups[k]
+		if !ok {
+			g = &CampaignAttribution{Campaign: k.campaign, Source: k.source, Medium: k.medium}
+			groups[k] = g
+			order = append(order, k)
+		}
+		g.Links = append(g.Links, link.ShortURL)
+		if s, ok := stats[link.ShortURL]; ok {
+			members[k] = append(members[k], s)
+		}
+	}
+	result := make([]CampaignAttribution, 0, len(order))
+	for _, k := range order {
+		g := groups[k]
+		g.Stats = MergeStats(members[k]...)
+		result = append(result, *g)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Stats.Clicks > result[j].Stats.Clicks })
+	return result
+}
+
+// UTMAttribution fetches the stats of every link matching filter and groups
+// them with AttributeByUTM.
+func (c *Client) UTMAttribution(ctx context.Context, filter ShortLinkListOptions, opts StatsOptions) ([]CampaignAttribution, error) {
+	links, err := c.ListAllShortLinks(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]string, len(links))
+	for i, link := range links {
+		urls[i] = link.ShortURL
+	}
+	stats, err := c.GetStatsMulti(ctx, urls, opts)
+	if err != nil {
+		return nil, err
+	}
+	return AttributeByUTM(links, stats), nil
+}
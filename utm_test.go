@@ -0,0 +1,41 @@
+package tly_test
+
+import (
+	"reflect"
+	"testing"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+func TestAttributeByUTM(t *testing.T) {
+	links := []tly.ShortLink{
+		{ShortURL: "https://t.ly/a", LongURL: "https://example.com/?utm_campaign=spring&utm_source=news&utm_medium=email"},
+		{ShortURL: "https://t.ly/b", LongURL: "https://example.com/"},
+		{ShortURL: "https://t.ly/c", LongURL: "https://example.com/x?utm_medium=email&utm_source=news&utm_campaign=spring"},
+		{ShortURL: "https://t.ly/d", LongURL: "https://example.com/?utm_campaign=spring&utm_source=news&utm_medium=email"},
+	}
+	// https://t.ly/d has no stats and https://t.ly/z is not among the links.
+	stats := map[string]*tly.Stats{
+		"https://t.ly/c": {Clicks: 2},
+		"https://t.ly/a": {Clicks: 3},
+		"https://t.ly/b": {Clicks: 4},
+		"https://t.ly/z": {Clicks: 100},
+	}
+
+	type group struct {
+		Campaign, Source, Medium string
+		Links                    []string
+		Clicks                   int
+	}
+	want := []group{
+		{"spring", "news", "email", []string{"https://t.ly/a", "https://t.ly/c", "https://t.ly/d"}, 5},
+		{"", "", "", []string{"https://t.ly/b"}, 4},
+	}
+	var got []group
+	for _, a := range tly.AttributeByUTM(links, stats) {
+		got = append(got, group{a.Campaign, a.Source, a.Medium, a.Links, a.Stats.Clicks})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AttributeByUTM() = %+v, want %+v", got, want)
+	}
+}
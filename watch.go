@@ -0,0 +1,71 @@
+package tly
+
+import (
+	"context"
+	"time"
+)
+
+// WatchOptions configures WatchStats.
+type WatchOptions struct {
+	// Interval is the time between polls. Defaults to 30 seconds.
+	Interval time.Duration
+	// Stats narrows the fetched stats.
+	Stats StatsOptions
+	// Concurrency bounds the parallel requests of a poll. Defaults to 4.
+	Concurrency int
+}
+
+// StatsUpdate is the result of polling one watched link. Delta is the
+// change since the previous poll; on the first poll it holds the whole
+// stats and Delta.Initial is set. Err is set when the poll failed, in which
+// case Stats and Delta are nil.
+type StatsUpdate struct {
+	ShortURL string      `json:"short_url"`
+	Stats    *Stats      `json:"stats,omitempty"`
+	Delta    *StatsDelta `json:"delta,omitempty"`
+	Err      error       `json:"-"`
+	Error    string      `json:"error,omitempty"`
+	At       time.Time   `json:"at"`
+}
+
+// WatchStats polls the stats of shortURLs immediately and then every
+// interval, sending one update per link per poll, until ctx is done. The
+// channel is closed when watching stops. A failed poll of one link is sent
+// as an update with Err set and does not stop the others. Receive promptly:
+// a poll waits for its updates to be taken before the next one starts.
+func (c *Client) WatchStats(ctx context.Context, shortURLs []string, opts WatchOptions) <-chan StatsUpdate {
+	if opts.Interval <= 0 {
+		opts.Interval = 30 * time.Second
+	}
+	tracker := NewStatsDeltaTracker(c, NewMemoryCheckpointStore())
+	tracker.Stats = opts.Stats
+	updates := make(chan StatsUpdate)
+	go func() {
+		defer close(updates)
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+		for {
+			forEachLimit(ctx, len(shortURLs), opts.Concurrency, func(ctx context.Context, i int) error {
+				u := StatsUpdate{ShortURL: shortURLs[i]}
+				u.Delta, u.Err = tracker.Next(ctx, shortURLs[i])
+				if u.Err == nil {
+					u.Stats, _ = tracker.store.Load(ctx, shortURLs[i])
+				} else {
+					u.Delta, u.Error = nil, u.Err.Error()
+				}
+				u.At = time.Now()
+				select {
+				case updates <- u:
+				case <-ctx.Done():
+				}
+				return nil
+			})
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return updates
+}
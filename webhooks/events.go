@@ -0,0 +1,151 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	tly "github.com/timleland/t.ly-go-url-shortener-api"
+)
+
+// EventType names a webhook event.
+type EventType string
+
+// Webhook event types.
+const (
+	EventLinkCreated EventType = "link.created"
+	EventLinkUpdated EventType = "link.updated"
+	EventLinkDeleted EventType = "link.deleted"
+	EventLinkExpired EventType = "link.expired"
+	EventLinkClicked EventType = "link.clicked"
+)
+
+// Event is the metadata every webhook delivery carries. ID is the same
+// across retries of one delivery.
+type Event struct {
+	ID        string        `json:"id"`
+	Type      EventType     `json:"type"`
+	CreatedAt tly.Timestamp `json:"created_at"`
+}
+
+// Meta returns the delivery metadata. Every typed event embeds Event, so
+// they all implement Payload through it.
+func (e Event) Meta() Event {
+	return e
+}
+
+// Payload is a decoded webhook event: one of *LinkCreated, *LinkUpdated,
+// *LinkDeleted, *LinkExpired, *LinkClicked or, for types this package does
+// not know yet, *UnknownEvent. Use a type switch to access its fields.
+type Payload interface {
+	Meta() Event
+}
+
+// LinkCreated is sent when a short link is created.
+type LinkCreated struct {
+	Event
+	Link tly.ShortLink `json:"link"`
+}
+
+// LinkUpdated is sent when a short link is changed. Previous holds the
+// link as it was before the change.
+type LinkUpdated struct {
+	Event
+	Link     tly.ShortLink `json:"link"`
+	Previous tly.ShortLink `json:"previous"`
+}
+
+// LinkDeleted is sent when a short link is deleted.
+type LinkDeleted struct {
+	Event
+	ShortURL string `json:"short_url"`
+	ShortID  string `json:"short_id"`
+}
+
+// LinkExpired is sent when a short link reaches its expiry date or view
+// limit. Reason is "datetime" or "views".
+type LinkExpired struct {
+	Event
+	Link   tly.ShortLink `json:"link"`
+	Reason string        `json:"reason"`
+}
+
+// LinkClicked is sent for each click on a short link.
+type LinkClicked struct {
+	Event
+	ShortURL  string        `json:"short_url"`
+	ShortID   string        `json:"short_id"`
+	Country   string        `json:"country"`
+	Browser   string        `json:"browser"`
+	Platform  string        `json:"platform"`
+	Referrer  string        `json:"referrer"`
+	ClickedAt tly.Timestamp `json:"clicked_at"`
+}
+
+// UnknownEvent is an event type this package has no struct for. Data is
+// the raw event data.
+type UnknownEvent struct {
+	Event
+	Data json.RawMessage
+}
+
+// eventTypes maps event types to constructors of their payloads.
+var eventTypes = map[EventType]func() Payload{
+	EventLinkCreated: func() Payload { return &LinkCreated{} },
+	EventLinkUpdated: func() Payload { return &LinkUpdated{} },
+	EventLinkDeleted: func() Payload { return &LinkDeleted{} },
+	EventLinkExpired: func() Payload { return &LinkExpired{} },
+	EventLinkClicked: func() Payload { return &LinkClicked{} },
+}
+
+// envelope is the wire form of a delivery.
+type envelope struct {
+	Event
+	Data json.RawMessage `json:"data"`
+}
+
+// ErrMalformedEvent is returned by UnmarshalEvent for deliveries that are
+// not valid events.
+var ErrMalformedEvent = errors.New("webhooks: malformed event")
+
+// UnmarshalEvent decodes a delivery body into its typed payload. Unknown
+// event types decode to *UnknownEvent rather than failing, so new T.LY
+// events do not break existing consumers.
+func UnmarshalEvent(body []byte) (Payload, error) {
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedEvent, err)
+	}
+	if env.ID == "" || env.Type == "" {
+		return nil, fmt.Errorf("%w: missing id or type", ErrMalformedEvent)
+	}
+	newPayload, ok := eventTypes[env.Type]
+	if !ok {
+		return &UnknownEvent{Event: env.Event, Data: env.Data}, nil
+	}
+	p := newPayload()
+	if len(env.Data) > 0 {
+		if err := json.Unmarshal(env.Data, p); err != nil {
+			return nil, fmt.Errorf("%w: %s data: %v", ErrMalformedEvent, env.Type, err)
+		}
+	}
+	setMeta(p, env.Event)
+	return p, nil
+}
+
+// setMeta stores the envelope metadata in p, overriding anything the data
+// object may have set.
+func setMeta(p Payload, meta Event) {
+	switch e := p.(type) {
+	case *LinkCreated:
+		e.Event = meta
+	case *LinkUpdated:
+		e.Event = meta
+	case *LinkDeleted:
+		e.Event = meta
+	case *LinkExpired:
+		e.Event = meta
+	case *LinkClicked:
+		e.Event = meta
+	}
+}
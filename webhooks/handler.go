@@ -0,0 +1,192 @@
+// Package webhooks receives T.LY webhook deliveries.
+//
+// A Handler validates each delivery, decodes it into a typed event and
+// passes it to the callbacks registered for its type:
+//
+//	h := webhooks.NewHandler(webhooks.HandlerOptions{Secret: secret})
+//	h.OnLinkClicked(func(ctx context.Context, e *webhooks.LinkClicked) error {
+//		return record(e.ShortURL, e.Country)
+//	})
+//	http.Handle("/hooks/tly", h)
+//
+// With a Secret, deliveries whose signature does not verify are rejected
+// before they are decoded. A delivery is acknowledged with 200 once every
+// callback succeeded. If a callback fails the handler answers 500 so T.LY
+// delivers it again; retried deliveries of an event already handled are
+// acknowledged without calling the callbacks twice.
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HandlerOptions configures a Handler.
+type HandlerOptions struct {
+	// Secret is the webhook signing secret. When set, deliveries without
+	// a valid SignatureHeader are rejected with 401.
+	Secret string
+	// Tolerance is the accepted age of a signature. Defaults to
+	// DefaultTolerance.
+	Tolerance time.Duration
+	// MaxBodyBytes caps the size of a delivery. Defaults to 1 MiB.
+	MaxBodyBytes int64
+	// DedupeSize is how many recent event IDs are remembered to skip
+	// retried deliveries. Defaults to 1024; negative disables it.
+	DedupeSize int
+	// OnError, when set, is called with callback and decoding errors, e.g.
+	// for logging.
+	OnError func(r *http.Request, err error)
+}
+
+// Handler is an http.Handler that dispatches webhook deliveries to typed
+// callbacks. Register callbacks before serving requests.
+type Handler struct {
+	opts     HandlerOptions
+	handlers map[EventType][]func(ctx context.Context, p Payload) error
+	any      []func(ctx context.Context, p Payload) error
+
+	mu   sync.Mutex
+	seen map[string]bool
+	ring []string
+	next int
+}
+
+// NewHandler creates a Handler with no callbacks.
+func NewHandler(opts HandlerOptions) *Handler {
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = 1 << 20
+	}
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = DefaultTolerance
+	}
+	if opts.DedupeSize == 0 {
+		opts.DedupeSize = 1024
+	}
+	h := &Handler{
+		opts:     opts,
+		handlers: map[EventType][]func(context.Context, Payload) error{},
+	}
+	if opts.DedupeSize > 0 {
+		h.seen = make(map[string]bool, opts.DedupeSize)
+		h.ring = make([]string, opts.DedupeSize)
+	}
+	return h
+}
+
+// On registers fn for events of type t, including types without a typed
+// helper, which arrive as *UnknownEvent.
+func (h *Handler) On(t EventType, fn func(ctx context.Context, p Payload) error) {
+	h.handlers[t] = append(h.handlers[t], fn)
+}
+
+// OnAny registers fn for every event, after the type-specific callbacks.
+func (h *Handler) OnAny(fn func(ctx context.Context, p Payload) error) {
+	h.any = append(h.any, fn)
+}
+
+// OnLinkCreated registers fn for link.created events.
+func (h *Handler) OnLinkCreated(fn func(ctx context.Context, e *LinkCreated) error) {
+	h.On(EventLinkCreated, func(ctx context.Context, p Payload) error { return fn(ctx, p.(*LinkCreated)) })
+}
+
+// OnLinkUpdated registers fn for link.updated events.
+func (h *Handler) OnLinkUpdated(fn func(ctx context.Context, e *LinkUpdated) error) {
+	h.On(EventLinkUpdated, func(ctx context.Context, p Payload) error { return fn(ctx, p.(*LinkUpdated)) })
+}
+
+// OnLinkDeleted registers fn for link.deleted events.
+func (h *Handler) OnLinkDeleted(fn func(ctx context.Context, e *LinkDeleted) error) {
+	h.On(EventLinkDeleted, func(ctx context.Context, p Payload) error { return fn(ctx, p.(*LinkDeleted)) })
+}
+
+// OnLinkExpired registers fn for link.expired events.
+func (h *Handler) OnLinkExpired(fn func(ctx context.Context, e *LinkExpired) error) {
+	h.On(EventLinkExpired, func(ctx context.Context, p Payload) error { return fn(ctx, p.(*LinkExpired)) })
+}
+
+// OnLinkClicked registers fn for link.clicked events.
+func (h *Handler) OnLinkClicked(fn func(ctx context.Context, e *LinkClicked) error) {
+	h.On(EventLinkClicked, func(ctx context.Context, p Payload) error { return fn(ctx, p.(*LinkClicked)) })
+}
+
+// ServeHTTP implements http.Handler. Deliveries of types without callbacks
+// are acknowledged and dropped.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, h.opts.MaxBodyBytes))
+	if err != nil {
+		h.fail(w, r, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+	if h.opts.Secret != "" {
+		err := VerifySignatureAt(body, r.Header.Get(SignatureHeader), h.opts.Secret, h.opts.Tolerance, time.Now())
+		if err != nil {
+			h.fail(w, r, http.StatusUnauthorized, err)
+			return
+		}
+	}
+	p, err := UnmarshalEvent(body)
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, err)
+		return
+	}
+	meta := p.Meta()
+	if h.handled(meta.ID) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	callbacks := append(append([]func(context.Context, Payload) error(nil), h.handlers[meta.Type]...), h.any...)
+	for _, fn := range callbacks {
+		if err := fn(r.Context(), p); err != nil {
+			h.fail(w, r, http.StatusInternalServerError, fmt.Errorf("webhooks: %s %s: %w", meta.Type, meta.ID, err))
+			return
+		}
+	}
+	h.markHandled(meta.ID)
+	w.WriteHeader(http.StatusOK)
+}
+
+// fail reports err and answers with status.
+func (h *Handler) fail(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if h.opts.OnError != nil {
+		h.opts.OnError(r, err)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
+
+// handled reports whether the event ID was already processed.
+func (h *Handler) handled(id string) bool {
+	if h.seen == nil {
+		return false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.seen[id]
+}
+
+// markHandled remembers id, evicting the oldest remembered ID when full.
+func (h *Handler) markHandled(id string) {
+	if h.seen == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.seen[id] {
+		return
+	}
+	if old := h.ring[h.next]; old != "" {
+		delete(h.seen, old)
+	}
+	h.ring[h.next] = id
+	h.seen[id] = true
+	h.next = (h.next + 1) % len(h.ring)
+}
@@ -0,0 +1,90 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler(t *testing.T) {
+	const secret = "secret"
+	clicked := `{"id":"evt_1","type":"link.clicked","data":{"short_url":"https://t.ly/a"}}`
+	signed := func(body string) func(r *http.Request) {
+		return func(r *http.Request) { r.Header.Set(SignatureHeader, Sign([]byte(body), secret, time.Now())) }
+	}
+	type delivery struct {
+		method string
+		body   string
+		sign   func(r *http.Request)
+		status int
+	}
+	tests := []struct {
+		name       string
+		deliveries []delivery
+		failFirst  bool  // the callback fails on its first call
+		calls      int   // callback calls over all deliveries
+		err        error // reported to OnError, if any
+	}{
+		{"valid", []delivery{{"POST", clicked, signed(clicked), 200}}, false, 1, nil},
+		{"bad signature", []delivery{
+			{"POST", clicked, func(r *http.Request) { r.Header.Set(SignatureHeader, Sign([]byte(clicked), "other", time.Now())) }, 401},
+		}, false, 0, ErrInvalidSignature},
+		{"expired signature", []delivery{
+			{"POST", clicked, func(r *http.Request) {
+				r.Header.Set(SignatureHeader, Sign([]byte(clicked), secret, time.Now().Add(-time.Hour)))
+			}, 401},
+		}, false, 0, ErrTimestampExpired},
+		{"missing signature", []delivery{{"POST", clicked, func(*http.Request) {}, 401}}, false, 0, ErrMissingSignature},
+		{"missing event ID", []delivery{
+			{"POST", `{"type":"link.clicked","data":{}}`, signed(`{"type":"link.clicked","data":{}}`), 400},
+		}, false, 0, ErrMalformedEvent},
+		{"replayed event", []delivery{
+			{"POST", clicked, signed(clicked), 200},
+			{"POST", clicked, signed(clicked), 200},
+		}, false, 1, nil},
+		{"retry after a failed callback", []delivery{
+			{"POST", clicked, signed(clicked), 500},
+			{"POST", clicked, signed(clicked), 200},
+		}, true, 2, nil},
+		{"wrong method", []delivery{{"GET", "", func(*http.Request) {}, 405}}, false, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reported error
+			h := NewHandler(HandlerOptions{
+				Secret:  secret,
+				OnError: func(r *http.Request, err error) { reported = err },
+			})
+			calls := 0
+			h.OnLinkClicked(func(ctx context.Context, e *LinkClicked) error {
+				calls++
+				if e.ShortURL != "https://t.ly/a" {
+					t.Errorf("ShortURL = %q, want https://t.ly/a", e.ShortURL)
+				}
+				if tt.failFirst && calls == 1 {
+					return errors.New("store unavailable")
+				}
+				return nil
+			})
+			for i, d := range tt.deliveries {
+				r := httptest.NewRequest(d.method, "/hooks/tly", strings.NewReader(d.body))
+				d.sign(r)
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, r)
+				if w.Code != d.status {
+					t.Errorf("delivery %d: status = %d, want %d", i, w.Code, d.status)
+				}
+			}
+			if calls != tt.calls {
+				t.Errorf("callback calls = %d, want %d", calls, tt.calls)
+			}
+			if tt.err != nil && !errors.Is(reported, tt.err) {
+				t.Errorf("OnError got %v, want %v", reported, tt.err)
+			}
+		})
+	}
+}
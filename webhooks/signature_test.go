@@ -0,0 +1,46 @@
+package webhooks
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifySignatureAt(t *testing.T) {
+	payload := []byte(`{"event":"link.clicked"}`)
+	now := time.Unix(1700000000, 0)
+	valid := Sign(payload, "secret", now)
+	v1 := valid[strings.Index(valid, "v1="):]
+	tests := []struct {
+		name      string
+		payload   []byte
+		header    string
+		secret    string
+		tolerance time.Duration
+		want      error
+	}{
+		{"valid", payload, valid, "secret", DefaultTolerance, nil},
+		{"wrong secret", payload, valid, "other", DefaultTolerance, ErrInvalidSignature},
+		{"tampered payload", []byte(`{"event":"link.deleted"}`), valid, "secret", DefaultTolerance, ErrInvalidSignature},
+		{"too old", payload, Sign(payload, "secret", now.Add(-6*time.Minute)), "secret", DefaultTolerance, ErrTimestampExpired},
+		{"too far ahead", payload, Sign(payload, "secret", now.Add(6*time.Minute)), "secret", DefaultTolerance, ErrTimestampExpired},
+		{"within tolerance", payload, Sign(payload, "secret", now.Add(-4*time.Minute)), "secret", DefaultTolerance, nil},
+		{"no tolerance", payload, Sign(payload, "secret", now.Add(-24*time.Hour)), "secret", 0, nil},
+		{"rotated secret", payload, Sign(payload, "old", now) + "," + v1, "secret", DefaultTolerance, nil},
+		{"unknown keys ignored", payload, valid + ",v0=abc,scheme", "secret", DefaultTolerance, nil},
+		{"spaces", payload, strings.Replace(valid, ",", " , ", 1), "secret", DefaultTolerance, nil},
+		{"timestamp changed", payload, "t=1700000001," + v1, "secret", DefaultTolerance, ErrInvalidSignature},
+		{"empty header", payload, "", "secret", DefaultTolerance, ErrMissingSignature},
+		{"no timestamp", payload, v1, "secret", DefaultTolerance, ErrMissingSignature},
+		{"malformed timestamp", payload, "t=soon," + v1, "secret", DefaultTolerance, ErrMissingSignature},
+		{"no signature", payload, "t=1700000000", "secret", DefaultTolerance, ErrMissingSignature},
+		{"signature not hex", payload, "t=1700000000,v1=zz", "secret", DefaultTolerance, ErrMissingSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifySignatureAt(tt.payload, tt.header, tt.secret, tt.tolerance, now); err != tt.want {
+				t.Errorf("VerifySignatureAt(%q) = %v, want %v", tt.header, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package tly
+
+import "context"
+
+// workspaceHeader carries the workspace a request is scoped to.
+const workspaceHeader = "X-Workspace-Id"
+
+// TeamRole is a member's role in a workspace.
+type TeamRole string
+
+// Workspace roles.
+const (
+	RoleOwner  TeamRole = "owner"
+	RoleAdmin  TeamRole = "admin"
+	RoleMember TeamRole = "member"
+	RoleViewer TeamRole = "viewer"
+)
+
+// Workspace is a team workspace the account belongs to. Links, tags,
+// pixels and domains are kept per workspace.
+type Workspace struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Role      TeamRole  `json:"role"`
+	Personal  bool      `json:"personal"`
+	CreatedAt Timestamp `json:"created_at"`
+}
+
+// WithWorkspace scopes every request of the client to the workspace with
+// the given ID. Without it requests use the account's current workspace.
+func WithWorkspace(id int) ClientOption {
+	return func(c *Client) {
+		c.workspace = id
+	}
+}
+
+// Workspace returns the ID of the workspace the client is scoped to, or 0
+// when it uses the account's current workspace.
+func (c *Client) Workspace() int {
+	return c.workspace
+}
+
+// ListWorkspaces retrieves the workspaces the account can access.
+func (c *Client) ListWorkspaces(ctx context.Context) ([]Workspace, error) {
+	var workspaces []Workspace
+	err := c.doRequestContext(ctx, "GET", "/api/v1/team", "", nil, &workspaces)
+	if err != nil {
+		return nil, err
+	}
+	return workspaces, nil
+}